@@ -9,6 +9,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	router "github.com/gorilla/mux"
 	"github.com/journeymidnight/yig/api"
+	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam"
 	"github.com/journeymidnight/yig/iam/common"
@@ -46,6 +47,36 @@ type usageJson struct {
 	Usage int64
 }
 
+type uploadJson struct {
+	Bucket    string
+	Key       string
+	UploadId  string
+	OwnerId   string
+	Initiated time.Time
+}
+
+type uploadsJson struct {
+	Uploads []uploadJson
+}
+
+type clusterJson struct {
+	Fsid              string
+	Pool              string
+	Weight            int
+	Status            string
+	Healthy           bool
+	UsedSpacePercent  int
+	UsageError        string `json:",omitempty"`
+	ConsecutiveErrors int
+	LatencyMs         float64
+}
+
+type clustersJson struct {
+	Clusters []clusterJson
+}
+
+const MAX_STALE_UPLOADS = 1000
+
 var adminServer *adminServerConfig
 
 type handlerFunc func(http.Handler) http.Handler
@@ -80,6 +111,51 @@ func getBucketInfo(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// setBucketPlacement pins data of new objects in a bucket to clusters and
+// pools, an empty placement makes the bucket use all clusters again
+func setBucketPlacement(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(jwt.MapClaims)
+	bucketName, _ := claims["bucket"].(string)
+
+	var placement meta.Placement
+	if claims["placement"] != nil {
+		b, err := json.Marshal(claims["placement"])
+		if err != nil {
+			api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+			return
+		}
+		err = json.Unmarshal(b, &placement)
+		if err != nil {
+			api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+			return
+		}
+	}
+	if placement.Validate() != nil {
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	for _, fsid := range placement.Clusters {
+		if _, ok := adminServer.Yig.DataStorage[fsid]; !ok {
+			api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+			return
+		}
+	}
+	helper.Logger.Info("Set bucket", bucketName, "placement to", placement)
+	err := adminServer.Yig.SetBucketPlacement(bucketName, placement)
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	bucket, err := adminServer.Yig.MetaStorage.GetBucketInfo(bucketName)
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	b, err := json.Marshal(bucketJson{Bucket: *bucket})
+	w.Write(b)
+	return
+}
+
 func getUserInfo(w http.ResponseWriter, r *http.Request) {
 	claims := r.Context().Value("claims").(jwt.MapClaims)
 	uid := claims["uid"].(string)
@@ -118,6 +194,151 @@ func getObjectInfo(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// getStaleUploads lists multipart uploads initiated `days` days ago across
+// all buckets, oldest first
+func getStaleUploads(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(jwt.MapClaims)
+	// list all uploads if days is not specified
+	days, _ := claims["days"].(float64)
+	initiatedBefore := time.Now().UTC().AddDate(0, 0, -int(days))
+	uploads, err := adminServer.Yig.MetaStorage.ListStaleMultipartUploads(initiatedBefore, MAX_STALE_UPLOADS)
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	result := uploadsJson{Uploads: make([]uploadJson, 0, len(uploads))}
+	for _, upload := range uploads {
+		result.Uploads = append(result.Uploads, uploadJson{
+			Bucket:    upload.BucketName,
+			Key:       upload.ObjectName,
+			UploadId:  upload.UploadId,
+			OwnerId:   upload.Metadata.OwnerId,
+			Initiated: upload.InitialTime.UTC(),
+		})
+	}
+	b, err := json.Marshal(result)
+	w.Write(b)
+	return
+}
+
+// writeClusters writes all clusters with their usage and health seen by
+// this yig instance
+func writeClusters(w http.ResponseWriter, r *http.Request) {
+	// read from meta store directly, cached clusters may be stale
+	clusters, err := adminServer.Yig.MetaStorage.Client.GetClusters()
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	topology := adminServer.Yig.Topology.Snapshot()
+	result := clustersJson{Clusters: make([]clusterJson, 0, len(clusters))}
+	for _, c := range clusters {
+		cluster := clusterJson{
+			Fsid:   c.Fsid,
+			Pool:   c.Pool,
+			Weight: c.Weight,
+			Status: c.Status.String(),
+		}
+		if health, ok := topology.Health[c.Fsid]; ok {
+			cluster.Healthy = health.Healthy
+			cluster.UsedSpacePercent = health.UsedSpacePercent
+			cluster.UsageError = health.UsageError
+			cluster.ConsecutiveErrors = health.ConsecutiveErrors
+			cluster.LatencyMs = health.Latency.Seconds() * 1000
+		} else {
+			cluster.UsageError = "cluster is not configured"
+		}
+		result.Clusters = append(result.Clusters, cluster)
+	}
+	b, err := json.Marshal(result)
+	w.Write(b)
+	return
+}
+
+func getClusters(w http.ResponseWriter, r *http.Request) {
+	writeClusters(w, r)
+}
+
+// setClusterStatus marks a cluster as draining, so no new data is written
+// into it and yig_drain moves existing data out, or as active again
+func setClusterStatus(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(jwt.MapClaims)
+	fsid, _ := claims["fsid"].(string)
+	statusName, _ := claims["status"].(string)
+	var status meta.ClusterStatus
+	switch statusName {
+	case meta.ClusterStatusActive.String():
+		status = meta.ClusterStatusActive
+	case meta.ClusterStatusDraining.String():
+		status = meta.ClusterStatusDraining
+	default:
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	if _, ok := adminServer.Yig.DataStorage[fsid]; !ok {
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	helper.Logger.Info("Set cluster", fsid, "status to", statusName)
+	err := adminServer.Yig.MetaStorage.SetClusterStatus(fsid, status)
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	adminServer.Yig.Topology.Refresh()
+	writeClusters(w, r)
+}
+
+// putCluster adds a pool of cluster or changes its weight, set weight to 0
+// to stop writing new data into the pool
+func putCluster(w http.ResponseWriter, r *http.Request) {
+	claims := r.Context().Value("claims").(jwt.MapClaims)
+	fsid, _ := claims["fsid"].(string)
+	pool, _ := claims["pool"].(string)
+	weight, ok := claims["weight"].(float64)
+	if !ok || weight < 0 {
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	if _, ok := adminServer.Yig.DataStorage[fsid]; !ok || pool == "" {
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	// pools other than rabbit, tiger and turtle are added after they're set
+	// in bucket placements
+	known, err := adminServer.Yig.IsPlacementPool(pool)
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	if !known {
+		api.WriteErrorResponse(w, r, ErrInvalidRequestBody)
+		return
+	}
+	helper.Logger.Info("Set cluster", fsid, "pool", pool, "weight to", weight)
+	err = adminServer.Yig.MetaStorage.PutCluster(meta.Cluster{
+		Fsid:   fsid,
+		Pool:   pool,
+		Weight: int(weight),
+	})
+	if err != nil {
+		api.WriteErrorResponse(w, r, err)
+		return
+	}
+	adminServer.Yig.Topology.Refresh()
+	writeClusters(w, r)
+}
+
+// removeClustersCache makes all yig instances read clusters from meta
+// store at next topology refresh, e.g. after the cluster table is changed
+// by hand. This instance refreshes at once.
+func removeClustersCache(w http.ResponseWriter, r *http.Request) {
+	helper.Logger.Info("enter removeClustersCache")
+	adminServer.Yig.MetaStorage.RemoveClustersCache()
+	adminServer.Yig.Topology.Refresh()
+	writeClusters(w, r)
+}
+
 func getCacheHitRatio(w http.ResponseWriter, r *http.Request) {
 	helper.Logger.Info("enter getCacheHitRatio")
 
@@ -147,12 +368,19 @@ func configureAdminHandler() http.Handler {
 	admin.Methods("GET").Path("/usage").HandlerFunc(SetJwtMiddlewareFunc(getUsage))
 	admin.Methods("GET").Path("/user").HandlerFunc(SetJwtMiddlewareFunc(getUserInfo))
 	admin.Methods("GET").Path("/bucket").HandlerFunc(SetJwtMiddlewareFunc(getBucketInfo))
+	admin.Methods("PUT").Path("/bucket/placement").HandlerFunc(SetJwtMiddlewareFunc(setBucketPlacement))
 	admin.Methods("GET").Path("/object").HandlerFunc(SetJwtMiddlewareFunc(getObjectInfo))
 	admin.Methods("GET").Path("/cachehit").HandlerFunc(SetJwtMiddlewareFunc(getCacheHitRatio))
+	admin.Methods("GET").Path("/multipart").HandlerFunc(SetJwtMiddlewareFunc(getStaleUploads))
+	admin.Methods("GET").Path("/cluster").HandlerFunc(SetJwtMiddlewareFunc(getClusters))
+	admin.Methods("PUT").Path("/cluster").HandlerFunc(SetJwtMiddlewareFunc(putCluster))
+	admin.Methods("PUT").Path("/cluster/status").HandlerFunc(SetJwtMiddlewareFunc(setClusterStatus))
+	admin.Methods("DELETE").Path("/cluster/cache").HandlerFunc(SetJwtMiddlewareFunc(removeClustersCache))
 
 	metrics := NewMetrics("yig")
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(metrics)
+	registry.MustRegister(adminServer.Yig.Topology)
 
 	apiRouter.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
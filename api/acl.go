@@ -2,14 +2,33 @@ package api
 
 import (
 	. "github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	meta "github.com/journeymidnight/yig/meta/types"
 	"net/http"
 )
 
-func getAclFromHeader(h http.Header) (acl Acl, err error) {
+// getAclFromHeader returns canned ACL in x-amz-acl header, public ACLs are
+// rejected if blocked on bucket, or in config for new buckets(bucket is nil)
+func getAclFromHeader(h http.Header, bucket *meta.Bucket) (acl Acl, err error) {
 	acl.CannedAcl = h.Get("x-amz-acl")
 	if acl.CannedAcl == "" {
 		acl.CannedAcl = "private"
 	}
 	err = IsValidCannedAcl(acl)
+	if err != nil {
+		return
+	}
+	err = checkPublicAcl(acl, bucket)
 	return
 }
+
+func checkPublicAcl(acl Acl, bucket *meta.Bucket) error {
+	var config PublicAccessBlockConfiguration
+	if bucket != nil {
+		config = bucket.PublicAccessBlock
+	}
+	if IsPublicAcl(acl) && config.Effective().BlockPublicAcls {
+		return ErrPublicAccessBlocked
+	}
+	return nil
+}
@@ -65,6 +65,20 @@ func SetObjectHeaders(w http.ResponseWriter, object *meta.Object, contentRange *
 	if object.Type == meta.ObjectTypeAppendable {
 		w.Header().Set("X-Amz-Next-Append-Position", strconv.FormatInt(object.Size, 10))
 	}
+	if len(object.Tagging) != 0 {
+		w.Header().Set("X-Amz-Tagging-Count", strconv.Itoa(len(object.Tagging)))
+	}
+	if object.ReplicationStatus != "" {
+		w.Header().Set("X-Amz-Replication-Status", object.ReplicationStatus)
+	}
+	if object.ObjectLockMode != "" {
+		w.Header().Set("X-Amz-Object-Lock-Mode", object.ObjectLockMode)
+		w.Header().Set("X-Amz-Object-Lock-Retain-Until-Date",
+			object.RetainUntilDate.UTC().Format(meta.CREATE_TIME_LAYOUT))
+	}
+	if object.LegalHold {
+		w.Header().Set("X-Amz-Object-Lock-Legal-Hold", LegalHoldOn)
+	}
 
 	// for providing ranged content
 	if contentRange != nil && contentRange.OffsetBegin > -1 {
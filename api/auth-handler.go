@@ -1,12 +1,16 @@
 package api
 
 import (
+	"io"
 	"net"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/journeymidnight/yig/api/datatype/policy"
+	"github.com/journeymidnight/yig/api/datatype/policy/condition"
 	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam/common"
@@ -36,38 +40,121 @@ func checkRequestAuth(r *http.Request, action policy.Action) (c common.Credentia
 			return c, err
 		} else {
 			helper.Logger.Info("Credential:", c)
+			err = checkCredentialPolicies(c, r, action, ctx.BucketName, ctx.ObjectName)
+			if err != nil {
+				return c, err
+			}
 			// check bucket policy
-			isAllow, err := IsBucketPolicyAllowed(c.UserId, ctx.BucketInfo, r, action, ctx.ObjectName)
+			isAllow, err := IsBucketPolicyAllowed(c, ctx.BucketInfo, r, action, ctx.ObjectName)
 			c.AllowOtherUserAccess = isAllow
 			return c, err
 		}
 	case signature.AuthTypeAnonymous:
-		isAllow, err := IsBucketPolicyAllowed(c.UserId, ctx.BucketInfo, r, action, ctx.ObjectName)
+		isAllow, err := IsBucketPolicyAllowed(c, ctx.BucketInfo, r, action, ctx.ObjectName)
 		c.AllowOtherUserAccess = isAllow
 		return c, err
 	}
 	return c, ErrAccessDenied
 }
 
-func IsBucketPolicyAllowed(userId string, bucket *meta.Bucket, r *http.Request, action policy.Action, objectName string) (allow bool, err error) {
+// checkCredentialPolicies evaluates identity-based policies of credential c
+// and session policy of temporary credentials, in the way of AWS:
+// - an explicit deny in any policy denies the request
+// - with identity-based policies, the request has to be allowed by one of them,
+//   even on buckets of its own user. Policies of other users' buckets still
+//   have to grant access, they're checked in IsBucketPolicyAllowed
+// - session policy could only narrow down permissions, it has to allow the request
+// Credentials without policies have full permissions of their user.
+func checkCredentialPolicies(c common.Credential, r *http.Request, action policy.Action,
+	bucketName, objectName string) error {
+
+	if len(c.Policies) == 0 && c.SessionPolicy == nil {
+		return nil
+	}
+	args := policy.Args{
+		AccountName:     c.UserId,
+		Action:          action,
+		BucketName:      bucketName,
+		ConditionValues: getConditionValues(r, ""),
+		IsOwner:         false,
+		ObjectName:      objectName,
+		UserName:        c.DisplayName,
+		PrincipalArn:    c.PrincipalArn(),
+	}
+	if len(c.Policies) != 0 {
+		allowed := false
+		for _, p := range c.Policies {
+			switch p.IsAllowed(args) {
+			case policy.PolicyDeny:
+				return ErrAccessDenied
+			case policy.PolicyAllow:
+				allowed = true
+			}
+		}
+		if !allowed {
+			return ErrAccessDenied
+		}
+	}
+	if c.SessionPolicy != nil && c.SessionPolicy.IsAllowed(args) != policy.PolicyAllow {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// isReqAuthenticated is signature.IsReqAuthenticated with credential policies
+// checked for `action` on requested bucket and object
+func isReqAuthenticated(r *http.Request, action policy.Action) (c common.Credential, err error) {
+	c, err = signature.IsReqAuthenticated(r)
+	if err != nil {
+		return
+	}
+	ctx := getRequestContext(r)
+	return c, checkCredentialPolicies(c, r, action, ctx.BucketName, ctx.ObjectName)
+}
+
+// verifyUpload is signature.VerifyUpload with credential policies checked for
+// `action` on requested bucket and object
+func verifyUpload(r *http.Request, action policy.Action) (c common.Credential,
+	dataReader io.ReadCloser, err error) {
+
+	c, dataReader, err = signature.VerifyUpload(r)
+	if err != nil {
+		return
+	}
+	ctx := getRequestContext(r)
+	return c, dataReader, checkCredentialPolicies(c, r, action, ctx.BucketName, ctx.ObjectName)
+}
+
+// IsBucketPolicyAllowed returns whether the request of c to bucket, which is not
+// owned by c, is allowed by bucket policy. With Block Public Access settings:
+// - RestrictPublicBuckets makes public bucket policy grant nothing
+// - IgnorePublicAcls denies anonymous requests not allowed by bucket policy,
+//   since only public ACLs could allow them later. Other users are denied
+//   by ACL checks in storage, where public ACLs are ignored too
+func IsBucketPolicyAllowed(c common.Credential, bucket *meta.Bucket, r *http.Request, action policy.Action, objectName string) (allow bool, err error) {
 	if bucket == nil {
 		return false, ErrAccessDenied
 	}
-	if bucket.OwnerId == userId {
+	if bucket.OwnerId == c.UserId {
 		return false, nil
 	}
 	policyResult := bucket.Policy.IsAllowed(policy.Args{
-		// TODO: Add IAM policy. Current account name is always useless.
-		AccountName:     userId,
+		AccountName:     c.UserId,
 		Action:          action,
 		BucketName:      bucket.Name,
 		ConditionValues: getConditionValues(r, ""),
 		IsOwner:         false,
 		ObjectName:      objectName,
+		UserName:        c.DisplayName,
+		PrincipalArn:    c.PrincipalArn(),
 	})
-	if policyResult == policy.PolicyAllow {
+	publicAccessBlock := bucket.PublicAccessBlock.Effective()
+	if policyResult == policy.PolicyDeny {
+		return false, ErrAccessDenied
+	} else if policyResult == policy.PolicyAllow &&
+		!(publicAccessBlock.RestrictPublicBuckets && bucket.Policy.IsPublic()) {
 		return true, nil
-	} else if policyResult == policy.PolicyDeny {
+	} else if c.UserId == "" && publicAccessBlock.IgnorePublicAcls {
 		return false, ErrAccessDenied
 	} else {
 		return false, nil
@@ -96,6 +183,34 @@ func getConditionValues(request *http.Request, locationConstraint string) map[st
 
 	args["SourceIp"] = []string{GetSourceIP(request)}
 
+	// following keys are set by yig only, never taken from headers or query parameters
+	for key := range args {
+		if strings.HasPrefix(key, existingObjectTagPrefix) {
+			delete(args, key)
+		}
+	}
+	delete(args, "UserAgent")
+	delete(args, "signatureversion")
+
+	now := time.Now().UTC()
+	args["CurrentTime"] = []string{now.Format(time.RFC3339)}
+	args["EpochTime"] = []string{strconv.FormatInt(now.Unix(), 10)}
+	args["SecureTransport"] = []string{strconv.FormatBool(isSecureTransport(request))}
+	if userAgent := request.UserAgent(); userAgent != "" {
+		args["UserAgent"] = []string{userAgent}
+	}
+	switch signature.GetRequestAuthType(request) {
+	case signature.AuthTypeSignedV2, signature.AuthTypePresignedV2:
+		args["signatureversion"] = []string{"AWS"}
+	case signature.AuthTypeSignedV4, signature.AuthTypePresignedV4, signature.AuthTypeStreamingSigned:
+		args["signatureversion"] = []string{"AWS4-HMAC-SHA256"}
+	}
+	if object := getRequestContext(request).ObjectInfo; object != nil {
+		for key, value := range object.Tagging {
+			args[existingObjectTagPrefix+key] = []string{value}
+		}
+	}
+
 	if locationConstraint != "" {
 		args["LocationConstraint"] = []string{locationConstraint}
 	}
@@ -103,6 +218,41 @@ func getConditionValues(request *http.Request, locationConstraint string) map[st
 	return args
 }
 
+// name of condition key s3:ExistingObjectTag/<tag key> in condition values
+var existingObjectTagPrefix = condition.Key(condition.S3ExistingObjectTagPrefix).Name()
+
+// isSecureTransport returns true if request is sent through SSL, to yig or
+// to the proxy in front of it. X-Forwarded-Proto is honoured only if the
+// request comes from one of trusted_proxies, otherwise clients could fake it
+func isSecureTransport(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return isTrustedProxy(r.RemoteAddr) && strings.EqualFold(r.Header.Get(xForwardedProto), "https")
+}
+
+// isTrustedProxy returns whether remoteAddr is one of trusted_proxies in config
+func isTrustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range helper.CONFIG.TrustedProxies {
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if ip.Equal(net.ParseIP(proxy)) {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	// De-facto standard header keys.
 	xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
@@ -112,6 +262,8 @@ var (
 	// existing use of X-Forwarded-* headers.
 	// e.g. Forwarded: for=192.0.2.60;proto=https;by=203.0.113.43
 	forwarded = http.CanonicalHeaderKey("Forwarded")
+	// Scheme of the request sent to proxies
+	xForwardedProto = http.CanonicalHeaderKey("X-Forwarded-Proto")
 	// Allows for a sub-match of the first value after 'for=' to the next
 	// comma, semi-colon or space. The match is case-insensitive.
 	forRegex = regexp.MustCompile(`(?i)(?:for=)([^(;|,| )]+)(.*)`)
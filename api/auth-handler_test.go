@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws/credentials"
+	v4 "github.com/journeymidnight/aws-sdk-go/aws/signer/v4"
+	"github.com/journeymidnight/yig/api/datatype/policy"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/cache"
+	"github.com/journeymidnight/yig/iam/common"
+	"github.com/journeymidnight/yig/log"
+	meta "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/signature"
+)
+
+func identityPolicy(t *testing.T, document string) policy.Policy {
+	p, err := policy.ParseIdentityPolicy(strings.NewReader(document))
+	if err != nil {
+		t.Fatal("ParseIdentityPolicy err:", err, document)
+	}
+	return *p
+}
+
+func bucketPolicy(t *testing.T, bucketName, document string) policy.Policy {
+	p, err := policy.ParseConfig(strings.NewReader(document), bucketName)
+	if err != nil {
+		t.Fatal("ParseConfig err:", err, document)
+	}
+	return *p
+}
+
+// authGetObject checks GET object request of bucket, signed by c, with checkRequestAuth
+func authGetObject(t *testing.T, c common.Credential, bucket *meta.Bucket) (common.Credential, error) {
+	r, err := http.NewRequest("GET", "http://s3.test.com:8080/"+bucket.Name+"/a", nil)
+	if err != nil {
+		t.Fatal("NewRequest err:", err)
+	}
+	signer := v4.NewSigner(credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""))
+	_, err = signer.Sign(r, strings.NewReader(""), "s3", "us-east-1", time.Now())
+	if err != nil {
+		t.Fatal("Sign err:", err)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), RequestContextKey, RequestContext{
+		RequestID:  "auth-test",
+		Logger:     helper.Logger,
+		BucketName: bucket.Name,
+		ObjectName: "a",
+		BucketInfo: bucket,
+		AuthType:   signature.AuthTypeSignedV4,
+	}))
+	return checkRequestAuth(r, policy.GetObjectAction)
+}
+
+// identity-based policies and bucket policies are evaluated together: an
+// explicit deny in any of them denies, buckets of the same user need allow of
+// identity-based policies only, buckets of other users need allow of both
+func TestCheckCredentialPolicies(t *testing.T) {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	cache.InitializeIamCache()
+
+	allowAll := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],` +
+		`"Resource":"arn:aws:s3:::*"}]}`
+	allowHehe := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject",` +
+		`"Resource":"arn:aws:s3:::hehe/*"}]}`
+	allowHaha := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject",` +
+		`"Resource":"arn:aws:s3:::haha/*"}]}`
+	denyGet := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:GetObject",` +
+		`"Resource":"arn:aws:s3:::*"}]}`
+	bucketAllowHehe := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["hehe"]},` +
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::haha/*"}]}`
+	bucketAllowRoot := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},` +
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::haha/*",` +
+		`"Condition":{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}}]}`
+	bucketDenyHehe := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":["hehe"]},` +
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::haha/*"}]}`
+
+	for i, c := range []struct {
+		name                 string
+		policies             []string
+		bucketOwner          string
+		bucketPolicy         string
+		err                  error
+		allowOtherUserAccess bool
+	}{
+		{"no identity policy on own bucket", nil, "hehe", "", nil, false},
+		{"identity allow with no bucket policy", []string{allowHehe}, "hehe", "", nil, false},
+		{"identity allow of other resource", []string{allowHaha}, "hehe", "", ErrAccessDenied, false},
+		{"identity deny on own bucket", []string{allowAll, denyGet}, "hehe", "", ErrAccessDenied, false},
+		{"identity deny overriding bucket allow", []string{allowAll, denyGet}, "haha", bucketAllowHehe,
+			ErrAccessDenied, false},
+		{"cross-account without bucket allow", []string{allowAll}, "haha", "", nil, false},
+		{"cross-account without identity allow", []string{allowHehe}, "haha", bucketAllowHehe,
+			ErrAccessDenied, false},
+		{"cross-account allowed by both", []string{allowHaha}, "haha", bucketAllowHehe, nil, true},
+		{"cross-account without identity policy", nil, "haha", bucketAllowHehe, nil, true},
+		{"cross-account allowed by principal ARN", nil, "haha", bucketAllowRoot, nil, true},
+		{"bucket deny overriding identity allow", []string{allowAll}, "haha", bucketDenyHehe,
+			ErrAccessDenied, false},
+	} {
+		credential := common.Credential{
+			UserId:          "hehe",
+			DisplayName:     "hehe",
+			AccessKeyID:     "authkey" + strconv.Itoa(i),
+			SecretAccessKey: "authsecret",
+		}
+		for _, document := range c.policies {
+			credential.Policies = append(credential.Policies, identityPolicy(t, document))
+		}
+		cache.IamCache.Set(credential.AccessKeyID, credential)
+		bucket := &meta.Bucket{Name: c.bucketOwner, OwnerId: c.bucketOwner}
+		if c.bucketPolicy != "" {
+			bucket.Policy = bucketPolicy(t, bucket.Name, c.bucketPolicy)
+		}
+
+		got, err := authGetObject(t, credential, bucket)
+		if err != c.err || got.AllowOtherUserAccess != c.allowOtherUserAccess {
+			t.Fatal(c.name, "checkRequestAuth:", err, got.AllowOtherUserAccess)
+		}
+	}
+}
+
+// deleting a specific version needs s3:DeleteObjectVersion instead of
+// s3:DeleteObject, both for single and multiple object deletes
+func TestDeleteObjectVersionPolicy(t *testing.T) {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	allowDelete := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:DeleteObject",` +
+		`"Resource":"arn:aws:s3:::hehe/*"}]}`
+	allowVersion := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:DeleteObjectVersion",` +
+		`"Resource":"arn:aws:s3:::hehe/*"}]}`
+	credential := common.Credential{
+		UserId:   "hehe",
+		Policies: []policy.Policy{identityPolicy(t, allowDelete)},
+	}
+	r, err := http.NewRequest(http.MethodPost, "http://hehe.s3.test.com/?delete", nil)
+	if err != nil {
+		t.Fatal("NewRequest err:", err)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), RequestContextKey, RequestContext{
+		RequestID:  "delete-test",
+		Logger:     helper.Logger,
+		BucketName: "hehe",
+	}))
+	err = checkCredentialPolicies(credential, r, deleteObjectAction(""), "hehe", "a")
+	if err != nil {
+		t.Fatal("Delete object denied:", err)
+	}
+	err = checkCredentialPolicies(credential, r, deleteObjectAction("v1"), "hehe", "a")
+	if err != ErrAccessDenied {
+		t.Fatal("Delete object version not denied:", err)
+	}
+	credential.Policies = append(credential.Policies, identityPolicy(t, allowVersion))
+	err = checkCredentialPolicies(credential, r, deleteObjectAction("v1"), "hehe", "a")
+	if err != nil {
+		t.Fatal("Delete object version denied:", err)
+	}
+}
+
+func TestIsSecureTransport(t *testing.T) {
+	helper.CONFIG.TrustedProxies = []string{"10.0.0.1", "192.168.0.0/16"}
+	defer func() { helper.CONFIG.TrustedProxies = nil }()
+
+	for _, c := range []struct {
+		remoteAddr string
+		tls        bool
+		proto      string
+		secure     bool
+	}{
+		{"1.2.3.4:5678", true, "", true},
+		{"1.2.3.4:5678", false, "", false},
+		{"1.2.3.4:5678", false, "https", false},
+		{"10.0.0.1:5678", false, "https", true},
+		{"10.0.0.1:5678", false, "HTTPS", true},
+		{"10.0.0.1:5678", false, "http", false},
+		{"10.0.0.2:5678", false, "https", false},
+		{"192.168.1.1:5678", false, "https", true},
+		{"192.168.1.1:5678", false, "", false},
+		{"not an address", false, "https", false},
+	} {
+		r, err := http.NewRequest("GET", "http://s3.test.com:8080/hehe/a", nil)
+		if err != nil {
+			t.Fatal("NewRequest err:", err)
+		}
+		r.RemoteAddr = c.remoteAddr
+		if c.tls {
+			r.TLS = &tls.ConnectionState{}
+		}
+		if c.proto != "" {
+			r.Header.Set("X-Forwarded-Proto", c.proto)
+		}
+		if isSecureTransport(r) != c.secure {
+			t.Fatal("isSecureTransport:", c)
+		}
+	}
+}
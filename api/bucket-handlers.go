@@ -26,9 +26,11 @@ import (
 
 	"github.com/gorilla/mux"
 	. "github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/api/datatype/policy"
 	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam/common"
+	meta "github.com/journeymidnight/yig/meta/types"
 	"github.com/journeymidnight/yig/signature"
 )
 
@@ -51,7 +53,7 @@ func (api ObjectAPIHandlers) GetBucketLocationHandler(w http.ResponseWriter, r *
 		break
 	case signature.AuthTypeSignedV4, signature.AuthTypePresignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetBucketLocationAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -96,7 +98,7 @@ func (api ObjectAPIHandlers) ListMultipartUploadsHandler(w http.ResponseWriter,
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.ListBucketMultipartUploadsAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -148,7 +150,7 @@ func (api ObjectAPIHandlers) ListObjectsHandler(w http.ResponseWriter, r *http.R
 		break
 	case signature.AuthTypeSignedV4, signature.AuthTypePresignedV4,
 		signature.AuthTypeSignedV2, signature.AuthTypePresignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.ListBucketAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -193,7 +195,7 @@ func (api ObjectAPIHandlers) ListVersionedObjectsHandler(w http.ResponseWriter,
 		break
 	case signature.AuthTypeSignedV4, signature.AuthTypePresignedV4,
 		signature.AuthTypeSignedV2, signature.AuthTypePresignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.ListBucketVersionsAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -232,7 +234,7 @@ func (api ObjectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 	// List buckets does not support bucket policies.
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.ListAllMyBucketsAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -315,8 +317,13 @@ func (api ObjectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	var deletedObjects []ObjectIdentifier
 	// Loop through all the objects and delete them sequentially.
 	for _, object := range deleteObjects.Objects {
-		result, err := api.ObjectAPI.DeleteObject(bucket, object.ObjectName,
-			object.VersionId, credential)
+		var result DeleteObjectResult
+		err := checkCredentialPolicies(credential, r, deleteObjectAction(object.VersionId),
+			bucket, object.ObjectName)
+		if err == nil {
+			result, err = api.ObjectAPI.DeleteObject(bucket, object.ObjectName, object.VersionId,
+				isGovernanceBypassed(r, credential, object.ObjectName), credential)
+		}
 		if err == nil {
 			deletedObjects = append(deletedObjects, ObjectIdentifier{
 				ObjectName:   object.ObjectName,
@@ -367,7 +374,7 @@ func (api ObjectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	}
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.CreateBucketAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -378,7 +385,8 @@ func (api ObjectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	acl, err := getAclFromHeader(r.Header)
+	// the bucket is new, only settings in config apply
+	acl, err := getAclFromHeader(r.Header, nil)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -386,8 +394,9 @@ func (api ObjectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 
 	// TODO:the location value in the request body should match the Region in serverConfig.
 
+	objectLockEnabled := strings.EqualFold(r.Header.Get("X-Amz-Bucket-Object-Lock-Enabled"), "true")
 	// Make bucket.
-	err = api.ObjectAPI.MakeBucket(bucketName, acl, credential)
+	err = api.ObjectAPI.MakeBucket(bucketName, acl, credential, objectLockEnabled)
 	if err != nil {
 		logger.Error("Unable to create bucket", bucketName, "error:", err)
 		WriteErrorResponse(w, r, err)
@@ -407,7 +416,7 @@ func (api ObjectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *h
 	ctx := getRequestContext(r)
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutBucketLoggingAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -463,7 +472,7 @@ func (api ObjectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *h
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetBucketLoggingAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -509,27 +518,40 @@ func (api ObjectAPIHandlers) PutBucketLifeCycleHandler(w http.ResponseWriter, r
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutLifecycleConfigurationAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
 
-	var lc Lifecycle
-	lcBuffer, err := ioutil.ReadAll(io.LimitReader(r.Body, 4096))
+	lc, err := ParseLifecycleConfig(r.Body)
 	if err != nil {
-		logger.Error("Unable to read lifecycle body:", err)
-		WriteErrorResponse(w, r, ErrInvalidLc)
+		WriteErrorResponse(w, r, err)
 		return
 	}
-	err = xml.Unmarshal(lcBuffer, &lc)
-	if err != nil {
-		logger.Error("Unable to parse lifecycle XML body:", err)
-		WriteErrorResponse(w, r, ErrInternalError)
-		return
+	for _, rule := range lc.Rule {
+		var storageClasses []string
+		for _, transition := range rule.Transitions {
+			storageClasses = append(storageClasses, transition.StorageClass)
+		}
+		for _, transition := range rule.NoncurrentVersionTransitions {
+			storageClasses = append(storageClasses, transition.StorageClass)
+		}
+		for _, class := range storageClasses {
+			storageClass, err := meta.MatchStorageClassIndex(class)
+			if err != nil {
+				WriteErrorResponse(w, r, err)
+				return
+			}
+			// objects could not be transitioned back to STANDARD
+			if storageClass == meta.ObjectStorageClassStandard {
+				WriteErrorResponse(w, r, ErrInvalidLcTransition)
+				return
+			}
+		}
 	}
 
 	logger.Info("Setting lifecycle:", lc)
-	err = api.ObjectAPI.SetBucketLifecycle(bucket, lc, credential)
+	err = api.ObjectAPI.SetBucketLifecycle(bucket, *lc, credential)
 	if err != nil {
 		logger.Error(err, "Unable to set lifecycle for bucket:", err)
 		WriteErrorResponse(w, r, err)
@@ -556,7 +578,7 @@ func (api ObjectAPIHandlers) GetBucketLifeCycleHandler(w http.ResponseWriter, r
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetLifecycleConfigurationAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -591,7 +613,7 @@ func (api ObjectAPIHandlers) DelBucketLifeCycleHandler(w http.ResponseWriter, r
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutLifecycleConfigurationAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -614,7 +636,7 @@ func (api ObjectAPIHandlers) PutBucketAclHandler(w http.ResponseWriter, r *http.
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutBucketAclAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -622,8 +644,11 @@ func (api ObjectAPIHandlers) PutBucketAclHandler(w http.ResponseWriter, r *http.
 	var acl Acl
 	var policy AccessControlPolicy
 	if _, ok := r.Header["X-Amz-Acl"]; ok {
-		acl, err = getAclFromHeader(r.Header)
-		if err != nil {
+		acl, err = getAclFromHeader(r.Header, getRequestContext(r).BucketInfo)
+		if err == ErrPublicAccessBlocked {
+			WriteErrorResponse(w, r, err)
+			return
+		} else if err != nil {
 			logger.Error("Unable to read canned ACLs:", err)
 			WriteErrorResponse(w, r, ErrInvalidAcl)
 			return
@@ -670,7 +695,7 @@ func (api ObjectAPIHandlers) GetBucketAclHandler(w http.ResponseWriter, r *http.
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetBucketAclAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -705,7 +730,7 @@ func (api ObjectAPIHandlers) PutBucketCorsHandler(w http.ResponseWriter, r *http
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutBucketCORSAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -751,7 +776,7 @@ func (api ObjectAPIHandlers) DeleteBucketCorsHandler(w http.ResponseWriter, r *h
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutBucketCORSAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -773,7 +798,7 @@ func (api ObjectAPIHandlers) GetBucketCorsHandler(w http.ResponseWriter, r *http
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.GetBucketCORSAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -805,7 +830,7 @@ func (api ObjectAPIHandlers) GetBucketVersioningHandler(w http.ResponseWriter, r
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.GetBucketVersioningAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -837,7 +862,7 @@ func (api ObjectAPIHandlers) PutBucketVersioningHandler(w http.ResponseWriter, r
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.PutBucketVersioningAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -947,7 +972,7 @@ func (api ObjectAPIHandlers) HeadBucketHandler(w http.ResponseWriter, r *http.Re
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.ListBucketAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -971,7 +996,7 @@ func (api ObjectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 
 	var credential common.Credential
 	var err error
-	if credential, err = signature.IsReqAuthenticated(r); err != nil {
+	if credential, err = isReqAuthenticated(r, policy.DeleteBucketAction); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
@@ -55,7 +55,7 @@ func (api ObjectAPIHandlers) PutBucketPolicyHandler(w http.ResponseWriter, r *ht
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutBucketPolicyAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -112,7 +112,7 @@ func (api ObjectAPIHandlers) DeleteBucketPolicyHandler(w http.ResponseWriter, r
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.DeleteBucketPolicyAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -144,7 +144,7 @@ func (api ObjectAPIHandlers) GetBucketPolicyHandler(w http.ResponseWriter, r *ht
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetBucketPolicyAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -29,7 +29,7 @@ func (api ObjectAPIHandlers) PutBucketWebsiteHandler(w http.ResponseWriter, r *h
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutBucketWebsiteAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -80,7 +80,7 @@ func (api ObjectAPIHandlers) GetBucketWebsiteHandler(w http.ResponseWriter, r *h
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetBucketWebsiteAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -129,7 +129,7 @@ func (api ObjectAPIHandlers) DeleteBucketWebsiteHandler(w http.ResponseWriter, r
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.DeleteBucketWebsiteAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -199,7 +199,7 @@ func (api ObjectAPIHandlers) HandledByWebsite(w http.ResponseWriter, r *http.Req
 		if strings.HasSuffix(ctx.ObjectName, "/") || ctx.ObjectName == "" {
 			indexName := ctx.ObjectName + id.Suffix
 			credential := common.Credential{}
-			isAllow, err := IsBucketPolicyAllowed(credential.UserId, ctx.BucketInfo, r, policy.GetObjectAction, indexName)
+			isAllow, err := IsBucketPolicyAllowed(credential, ctx.BucketInfo, r, policy.GetObjectAction, indexName)
 			if err != nil {
 				WriteErrorResponse(w, r, err)
 				return true
@@ -252,7 +252,7 @@ func (api ObjectAPIHandlers) ReturnWebsiteErrorDocument(w http.ResponseWriter, r
 	if ed := website.ErrorDocument; ed != nil && ed.Key != "" {
 		indexName := ed.Key
 		credential := common.Credential{}
-		isAllow, err := IsBucketPolicyAllowed(credential.UserId, ctx.BucketInfo, r, policy.GetObjectAction, indexName)
+		isAllow, err := IsBucketPolicyAllowed(credential, ctx.BucketInfo, r, policy.GetObjectAction, indexName)
 		if err != nil {
 			WriteErrorResponse(w, r, err)
 			return true
@@ -1,19 +1,409 @@
 package datatype
 
 import (
+	"encoding/json"
 	"encoding/xml"
-	//	. "github.com/journeymidnight/yig/error"
-	//	"github.com/journeymidnight/yig/helper"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const (
+	MaxLifecycleConfigurationSize = 512 * humanize.KiByte
+	MaxLifecycleRulesCount        = 1000
+	MaxLifecycleRuleIdLength      = 255
 )
 
+const (
+	LifecycleRuleStatusEnabled  = "Enabled"
+	LifecycleRuleStatusDisabled = "Disabled"
+)
+
+// LifecycleAnd combines several conditions of a Filter, objects should
+// satisfy all of them
+type LifecycleAnd struct {
+	Prefix                string `xml:"Prefix,omitempty"`
+	Tags                  []Tag  `xml:"Tag,omitempty"`
+	ObjectSizeGreaterThan int64  `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64  `xml:"ObjectSizeLessThan,omitempty"`
+}
+
+// LifecycleFilter specifies objects a rule applies to, an empty Filter
+// applies to all objects in bucket
+type LifecycleFilter struct {
+	Prefix                *string       `xml:"Prefix"`
+	Tag                   *Tag          `xml:"Tag"`
+	ObjectSizeGreaterThan int64         `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64         `xml:"ObjectSizeLessThan,omitempty"`
+	And                   *LifecycleAnd `xml:"And"`
+}
+
+// LifecycleExpiration expires current object versions after `Days` since
+// object creation or on `Date`, or removes delete markers without any
+// noncurrent versions if `ExpiredObjectDeleteMarker` is set
+type LifecycleExpiration struct {
+	Days                      int    `xml:"Days,omitempty"`
+	Date                      string `xml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool   `xml:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+// LifecycleTransition moves objects into `StorageClass` after `Days` since
+// object creation, or on `Date`
+type LifecycleTransition struct {
+	Days         int    `xml:"Days,omitempty"`
+	Date         string `xml:"Date,omitempty"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// NoncurrentVersionExpiration removes object versions `NoncurrentDays` after
+// they become noncurrent
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays"`
+}
+
+// NoncurrentVersionTransition moves object versions into `StorageClass`
+// `NoncurrentDays` after they become noncurrent
+type NoncurrentVersionTransition struct {
+	NoncurrentDays int    `xml:"NoncurrentDays"`
+	StorageClass   string `xml:"StorageClass"`
+}
+
+// AbortIncompleteMultipartUpload aborts multipart uploads which are not
+// completed `DaysAfterInitiation` days after they were initiated
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
 type LifecycleRule struct {
-	ID         string `xml:"ID"`
-	Prefix     string `xml:"Prefix"`
-	Status     string `xml:"Status"`
-	Expiration string `xml:"Expiration>Days"`
+	ID string `xml:"ID,omitempty"`
+	// Prefix is deprecated by Filter, but still accepted
+	Prefix                       string                        `xml:"Prefix,omitempty"`
+	Filter                       *LifecycleFilter              `xml:"Filter,omitempty"`
+	Status                       string                        `xml:"Status"`
+	Expiration                   *LifecycleExpiration          `xml:"Expiration,omitempty"`
+	Transitions                  []LifecycleTransition         `xml:"Transition,omitempty"`
+	NoncurrentVersionExpiration  *NoncurrentVersionExpiration  `xml:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions []NoncurrentVersionTransition `xml:"NoncurrentVersionTransition,omitempty"`
+
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
 }
 
 type Lifecycle struct {
 	XMLName xml.Name        `xml:"LifecycleConfiguration"`
 	Rule    []LifecycleRule `xml:"Rule"`
 }
+
+// UnmarshalJSON also accepts expiration days as a string, which is how
+// lifecycle rules were saved before Expiration had Date and
+// ExpiredObjectDeleteMarker
+func (e *LifecycleExpiration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var days string
+		if err := json.Unmarshal(data, &days); err != nil {
+			return err
+		}
+		if days == "" {
+			return nil
+		}
+		var err error
+		e.Days, err = strconv.Atoi(days)
+		return err
+	}
+	type expiration LifecycleExpiration
+	return json.Unmarshal(data, (*expiration)(e))
+}
+
+// parseLifecycleDate parses date of Expiration and Transition, which should
+// be at midnight GMT in ISO 8601 format
+func parseLifecycleDate(date string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return t, ErrInvalidLcDate
+	}
+	t = t.UTC()
+	if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 || t.Nanosecond() != 0 {
+		return t, ErrInvalidLcDate
+	}
+	return t, nil
+}
+
+func (e LifecycleExpiration) ParseDate() (time.Time, error) {
+	return parseLifecycleDate(e.Date)
+}
+
+func (e LifecycleExpiration) Validate() error {
+	var count int
+	if e.Days != 0 {
+		count++
+	}
+	if e.Date != "" {
+		count++
+	}
+	if e.ExpiredObjectDeleteMarker {
+		count++
+	}
+	if count != 1 || e.Days < 0 {
+		return ErrInvalidLcExpiration
+	}
+	if e.Date != "" {
+		if _, err := e.ParseDate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseDate returns the transition date, which should be in ISO 8601 format
+func (t LifecycleTransition) ParseDate() (time.Time, error) {
+	return parseLifecycleDate(t.Date)
+}
+
+func (t LifecycleTransition) Validate() error {
+	// exactly one of Days and Date should be specified
+	if (t.Days == 0) == (t.Date == "") {
+		return ErrInvalidLcTransition
+	}
+	if t.Days < 0 {
+		return ErrInvalidLcTransition
+	}
+	if t.Date != "" {
+		if _, err := t.ParseDate(); err != nil {
+			return err
+		}
+	}
+	if t.StorageClass == "" {
+		return ErrInvalidLcTransition
+	}
+	return nil
+}
+
+func (f *LifecycleFilter) Validate() error {
+	var count int
+	if f.Prefix != nil {
+		count++
+	}
+	if f.Tag != nil {
+		count++
+		if err := validateTag(f.Tag.Key, f.Tag.Value); err != nil {
+			return err
+		}
+	}
+	if f.ObjectSizeGreaterThan != 0 {
+		count++
+	}
+	if f.ObjectSizeLessThan != 0 {
+		count++
+	}
+	if f.And != nil {
+		count++
+		for _, tag := range f.And.Tags {
+			if err := validateTag(tag.Key, tag.Value); err != nil {
+				return err
+			}
+		}
+		if f.And.ObjectSizeGreaterThan < 0 || f.And.ObjectSizeLessThan < 0 {
+			return ErrInvalidLcFilter
+		}
+		if f.And.ObjectSizeLessThan != 0 &&
+			f.And.ObjectSizeLessThan <= f.And.ObjectSizeGreaterThan {
+			return ErrInvalidLcFilter
+		}
+	}
+	if count > 1 || f.ObjectSizeGreaterThan < 0 || f.ObjectSizeLessThan < 0 {
+		return ErrInvalidLcFilter
+	}
+	return nil
+}
+
+func matchTags(tags map[string]string, filterTags []Tag) bool {
+	for _, tag := range filterTags {
+		if value, ok := tags[tag.Key]; !ok || value != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func matchSize(size, greaterThan, lessThan int64) bool {
+	if greaterThan != 0 && size <= greaterThan {
+		return false
+	}
+	if lessThan != 0 && size >= lessThan {
+		return false
+	}
+	return true
+}
+
+// Match reports whether object with name `name`, size `size` and tags `tags`
+// is selected by the rule
+func (r LifecycleRule) Match(name string, size int64, tags map[string]string) bool {
+	if r.Filter == nil {
+		return strings.HasPrefix(name, r.Prefix)
+	}
+	f := r.Filter
+	if f.Prefix != nil && !strings.HasPrefix(name, *f.Prefix) {
+		return false
+	}
+	if f.Tag != nil && !matchTags(tags, []Tag{*f.Tag}) {
+		return false
+	}
+	if !matchSize(size, f.ObjectSizeGreaterThan, f.ObjectSizeLessThan) {
+		return false
+	}
+	if f.And != nil {
+		if !strings.HasPrefix(name, f.And.Prefix) || !matchTags(tags, f.And.Tags) ||
+			!matchSize(size, f.And.ObjectSizeGreaterThan, f.And.ObjectSizeLessThan) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasObjectConditions reports whether the rule selects objects by tags or
+// size, which are unknown for multipart uploads and delete markers
+func (r LifecycleRule) HasObjectConditions() bool {
+	f := r.Filter
+	if f == nil {
+		return false
+	}
+	if f.Tag != nil || f.ObjectSizeGreaterThan != 0 || f.ObjectSizeLessThan != 0 {
+		return true
+	}
+	return f.And != nil && (len(f.And.Tags) != 0 ||
+		f.And.ObjectSizeGreaterThan != 0 || f.And.ObjectSizeLessThan != 0)
+}
+
+func (r LifecycleRule) IsEnabled() bool {
+	return r.Status != LifecycleRuleStatusDisabled
+}
+
+// ExpirationDays returns 0 if the rule has no expiration by days
+func (r LifecycleRule) ExpirationDays() int {
+	if r.Expiration == nil {
+		return 0
+	}
+	return r.Expiration.Days
+}
+
+func (r LifecycleRule) Validate() error {
+	if r.Status != LifecycleRuleStatusEnabled && r.Status != LifecycleRuleStatusDisabled {
+		return ErrInvalidLc
+	}
+	if r.Filter != nil {
+		if r.Prefix != "" {
+			return ErrInvalidLcFilter
+		}
+		if err := r.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Expiration == nil && len(r.Transitions) == 0 &&
+		r.NoncurrentVersionExpiration == nil && len(r.NoncurrentVersionTransitions) == 0 &&
+		r.AbortIncompleteMultipartUpload == nil {
+		return ErrInvalidLc
+	}
+	if r.Expiration != nil {
+		if err := r.Expiration.Validate(); err != nil {
+			return err
+		}
+		if r.Expiration.ExpiredObjectDeleteMarker && r.HasObjectConditions() {
+			return ErrInvalidLcExpiration
+		}
+	}
+	if r.AbortIncompleteMultipartUpload != nil {
+		if r.AbortIncompleteMultipartUpload.DaysAfterInitiation <= 0 || r.HasObjectConditions() {
+			return ErrInvalidLcAbortMultipartUpload
+		}
+	}
+	days := r.ExpirationDays()
+	storageClasses := make(map[string]bool)
+	for _, transition := range r.Transitions {
+		if err := transition.Validate(); err != nil {
+			return err
+		}
+		if storageClasses[transition.StorageClass] {
+			return ErrInvalidLcTransition
+		}
+		storageClasses[transition.StorageClass] = true
+		// objects should be transitioned before they expire
+		if days != 0 && transition.Days >= days {
+			return ErrInvalidLcTransition
+		}
+	}
+
+	var noncurrentDays int
+	if r.NoncurrentVersionExpiration != nil {
+		noncurrentDays = r.NoncurrentVersionExpiration.NoncurrentDays
+		if noncurrentDays <= 0 {
+			return ErrInvalidLcNoncurrentVersion
+		}
+	}
+	storageClasses = make(map[string]bool)
+	for _, transition := range r.NoncurrentVersionTransitions {
+		if transition.NoncurrentDays <= 0 {
+			return ErrInvalidLcNoncurrentVersion
+		}
+		if transition.StorageClass == "" || storageClasses[transition.StorageClass] {
+			return ErrInvalidLcTransition
+		}
+		storageClasses[transition.StorageClass] = true
+		if noncurrentDays != 0 && transition.NoncurrentDays >= noncurrentDays {
+			return ErrInvalidLcTransition
+		}
+	}
+	return nil
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLifecycleConfiguration.html
+func (lc *Lifecycle) Validate() error {
+	if len(lc.Rule) == 0 {
+		return ErrInvalidLc
+	}
+	if len(lc.Rule) > MaxLifecycleRulesCount {
+		return ErrLcRulesLimitExceeded
+	}
+	ids := make(map[string]bool)
+	for _, rule := range lc.Rule {
+		if len(rule.ID) > MaxLifecycleRuleIdLength {
+			return ErrInvalidLcRuleId
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrInvalidLcRuleId
+			}
+			ids[rule.ID] = true
+		}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ParseLifecycleConfig(reader io.Reader) (*Lifecycle, error) {
+	lc := new(Lifecycle)
+	lcBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxLifecycleConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read lifecycle body:", err)
+		return nil, ErrInvalidLc
+	}
+	if len(lcBuffer) > MaxLifecycleConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(lcBuffer, lc)
+	if err != nil {
+		helper.Logger.Error("Unable to parse lifecycle XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	err = lc.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
@@ -0,0 +1,318 @@
+package datatype
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const (
+	MaxNotificationConfigurationsCount = 100
+	MaxNotificationConfigurationSize   = 64 * humanize.KiByte
+	MaxNotificationFilterValueLength   = 1024
+)
+
+// Event types that could be subscribed in bucket notification configuration.
+// ObjectCreated:Append and ObjectCreated:Rename are YIG specific.
+const (
+	EventObjectCreatedAll                     = "s3:ObjectCreated:*"
+	EventObjectCreatedPut                     = "s3:ObjectCreated:Put"
+	EventObjectCreatedPost                    = "s3:ObjectCreated:Post"
+	EventObjectCreatedCopy                    = "s3:ObjectCreated:Copy"
+	EventObjectCreatedCompleteMultipartUpload = "s3:ObjectCreated:CompleteMultipartUpload"
+	EventObjectCreatedAppend                  = "s3:ObjectCreated:Append"
+	EventObjectCreatedRename                  = "s3:ObjectCreated:Rename"
+	EventObjectRemovedAll                     = "s3:ObjectRemoved:*"
+	EventObjectRemovedDelete                  = "s3:ObjectRemoved:Delete"
+	EventObjectRemovedDeleteMarkerCreated     = "s3:ObjectRemoved:DeleteMarkerCreated"
+	EventObjectRestoreAll                     = "s3:ObjectRestore:*"
+	EventObjectRestorePost                    = "s3:ObjectRestore:Post"
+	EventObjectRestoreCompleted               = "s3:ObjectRestore:Completed"
+)
+
+var supportedNotificationEvents = map[string]bool{
+	EventObjectCreatedAll:                     true,
+	EventObjectCreatedPut:                     true,
+	EventObjectCreatedPost:                    true,
+	EventObjectCreatedCopy:                    true,
+	EventObjectCreatedCompleteMultipartUpload: true,
+	EventObjectCreatedAppend:                  true,
+	EventObjectCreatedRename:                  true,
+	EventObjectRemovedAll:                     true,
+	EventObjectRemovedDelete:                  true,
+	EventObjectRemovedDeleteMarkerCreated:     true,
+	EventObjectRestoreAll:                     true,
+	EventObjectRestorePost:                    true,
+	EventObjectRestoreCompleted:               true,
+}
+
+const (
+	FilterRuleNamePrefix = "prefix"
+	FilterRuleNameSuffix = "suffix"
+)
+
+type FilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+type S3KeyFilter struct {
+	FilterRules []FilterRule `xml:"FilterRule"`
+}
+
+type NotificationFilter struct {
+	S3Key S3KeyFilter `xml:"S3Key"`
+}
+
+// NotificationRule holds the elements shared by queue, topic and
+// cloud function configurations
+type NotificationRule struct {
+	Id     string              `xml:"Id,omitempty"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type QueueConfiguration struct {
+	NotificationRule
+	Queue string `xml:"Queue"`
+}
+
+type TopicConfiguration struct {
+	NotificationRule
+	Topic string `xml:"Topic"`
+}
+
+type CloudFunctionConfiguration struct {
+	NotificationRule
+	CloudFunction string `xml:"CloudFunction"`
+}
+
+type NotificationConfiguration struct {
+	XMLName                     xml.Name                     `xml:"NotificationConfiguration"`
+	QueueConfigurations         []QueueConfiguration         `xml:"QueueConfiguration,omitempty"`
+	TopicConfigurations         []TopicConfiguration         `xml:"TopicConfiguration,omitempty"`
+	CloudFunctionConfigurations []CloudFunctionConfiguration `xml:"CloudFunctionConfiguration,omitempty"`
+}
+
+// DestinationTopic returns the message queue topic named by the resource part of
+// a destination ARN, e.g. "events" of "arn:aws:sqs:us-east-1:123456789012:events"
+func DestinationTopic(arn string) (string, bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[5] == "" {
+		return "", false
+	}
+	return parts[5], true
+}
+
+func (r *NotificationRule) validate(destination string) error {
+	if _, ok := DestinationTopic(destination); !ok {
+		return ErrInvalidNotificationDestination
+	}
+	if len(r.Events) == 0 {
+		return ErrInvalidNotificationEvent
+	}
+	for _, event := range r.Events {
+		if !supportedNotificationEvents[event] {
+			return ErrInvalidNotificationEvent
+		}
+	}
+	if r.Filter == nil {
+		return nil
+	}
+	var hasPrefix, hasSuffix bool
+	for i, rule := range r.Filter.S3Key.FilterRules {
+		if len(rule.Value) > MaxNotificationFilterValueLength {
+			return ErrInvalidNotificationFilter
+		}
+		switch strings.ToLower(rule.Name) {
+		case FilterRuleNamePrefix:
+			if hasPrefix {
+				return ErrInvalidNotificationFilter
+			}
+			hasPrefix = true
+		case FilterRuleNameSuffix:
+			if hasSuffix {
+				return ErrInvalidNotificationFilter
+			}
+			hasSuffix = true
+		default:
+			return ErrInvalidNotificationFilter
+		}
+		r.Filter.S3Key.FilterRules[i].Name = strings.ToLower(rule.Name)
+	}
+	return nil
+}
+
+// Match reports whether an event of type `eventName` on object `key`
+// is subscribed by this rule
+func (r *NotificationRule) Match(eventName, key string) bool {
+	matched := false
+	for _, event := range r.Events {
+		if event == eventName ||
+			(strings.HasSuffix(event, ":*") &&
+				strings.HasPrefix(eventName, strings.TrimSuffix(event, "*"))) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if r.Filter == nil {
+		return true
+	}
+	for _, rule := range r.Filter.S3Key.FilterRules {
+		switch rule.Name {
+		case FilterRuleNamePrefix:
+			if !strings.HasPrefix(key, rule.Value) {
+				return false
+			}
+		case FilterRuleNameSuffix:
+			if !strings.HasSuffix(key, rule.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NotificationTarget is a configured rule together with the ARN of
+// the queue, topic or cloud function its events go to
+type NotificationTarget struct {
+	NotificationRule
+	Destination string
+}
+
+// Targets returns all the configured rules regardless of their destination type
+func (n *NotificationConfiguration) Targets() []NotificationTarget {
+	targets := make([]NotificationTarget, 0,
+		len(n.QueueConfigurations)+len(n.TopicConfigurations)+len(n.CloudFunctionConfigurations))
+	for _, c := range n.QueueConfigurations {
+		targets = append(targets, NotificationTarget{c.NotificationRule, c.Queue})
+	}
+	for _, c := range n.TopicConfigurations {
+		targets = append(targets, NotificationTarget{c.NotificationRule, c.Topic})
+	}
+	for _, c := range n.CloudFunctionConfigurations {
+		targets = append(targets, NotificationTarget{c.NotificationRule, c.CloudFunction})
+	}
+	return targets
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketNotificationConfiguration.html
+func (n *NotificationConfiguration) Validate() error {
+	if len(n.QueueConfigurations)+len(n.TopicConfigurations)+
+		len(n.CloudFunctionConfigurations) > MaxNotificationConfigurationsCount {
+		return ErrExceededNotificationConfigurationsLimit
+	}
+	ids := make(map[string]bool)
+	checkId := func(id string) error {
+		if id == "" {
+			return nil
+		}
+		if ids[id] {
+			return ErrInvalidNotificationId
+		}
+		ids[id] = true
+		return nil
+	}
+	for i := range n.QueueConfigurations {
+		c := &n.QueueConfigurations[i]
+		if err := c.validate(c.Queue); err != nil {
+			return err
+		}
+		if err := checkId(c.Id); err != nil {
+			return err
+		}
+	}
+	for i := range n.TopicConfigurations {
+		c := &n.TopicConfigurations[i]
+		if err := c.validate(c.Topic); err != nil {
+			return err
+		}
+		if err := checkId(c.Id); err != nil {
+			return err
+		}
+	}
+	for i := range n.CloudFunctionConfigurations {
+		c := &n.CloudFunctionConfigurations[i]
+		if err := c.validate(c.CloudFunction); err != nil {
+			return err
+		}
+		if err := checkId(c.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ParseNotificationConfig(reader io.Reader) (*NotificationConfiguration, error) {
+	notification := new(NotificationConfiguration)
+	notificationBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxNotificationConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read notification config body:", err)
+		return nil, err
+	}
+	if len(notificationBuffer) > MaxNotificationConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(notificationBuffer, notification)
+	if err != nil {
+		helper.Logger.Error("Unable to parse notification config XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	err = notification.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// Event message format follows
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
+
+type EventIdentity struct {
+	PrincipalId string `json:"principalId"`
+}
+
+type EventBucket struct {
+	Name          string        `json:"name"`
+	OwnerIdentity EventIdentity `json:"ownerIdentity"`
+	Arn           string        `json:"arn"`
+}
+
+type EventObject struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size,omitempty"`
+	ETag      string `json:"eTag,omitempty"`
+	VersionId string `json:"versionId,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+type EventS3Entity struct {
+	SchemaVersion   string      `json:"s3SchemaVersion"`
+	ConfigurationId string      `json:"configurationId"`
+	Bucket          EventBucket `json:"bucket"`
+	Object          EventObject `json:"object"`
+}
+
+type EventRecord struct {
+	EventVersion      string            `json:"eventVersion"`
+	EventSource       string            `json:"eventSource"`
+	AwsRegion         string            `json:"awsRegion"`
+	EventTime         string            `json:"eventTime"`
+	EventName         string            `json:"eventName"`
+	UserIdentity      EventIdentity     `json:"userIdentity"`
+	RequestParameters map[string]string `json:"requestParameters"`
+	ResponseElements  map[string]string `json:"responseElements"`
+	S3                EventS3Entity     `json:"s3"`
+}
+
+type EventMessage struct {
+	Records []EventRecord `json:"Records"`
+}
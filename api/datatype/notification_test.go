@@ -0,0 +1,114 @@
+package datatype
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/journeymidnight/yig/error"
+)
+
+func TestNotificationRuleMatch(t *testing.T) {
+	filter := func(rules ...FilterRule) *NotificationFilter {
+		return &NotificationFilter{S3Key: S3KeyFilter{FilterRules: rules}}
+	}
+	testCases := []struct {
+		rule     NotificationRule
+		event    string
+		key      string
+		expected bool
+	}{
+		{NotificationRule{Events: []string{EventObjectCreatedPut}},
+			EventObjectCreatedPut, "a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedPut}},
+			EventObjectCreatedPost, "a.jpg", false},
+		{NotificationRule{Events: []string{EventObjectCreatedAll}},
+			EventObjectCreatedPost, "a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedAll}},
+			EventObjectRemovedDelete, "a.jpg", false},
+		{NotificationRule{Events: []string{EventObjectCreatedPut, EventObjectRemovedAll}},
+			EventObjectRemovedDeleteMarkerCreated, "a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNamePrefix, "images/"})},
+			EventObjectCreatedPut, "images/a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNamePrefix, "images/"})},
+			EventObjectCreatedPut, "docs/a.jpg", false},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNameSuffix, ".jpg"})},
+			EventObjectCreatedPut, "docs/a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNameSuffix, ".jpg"})},
+			EventObjectCreatedPut, "docs/a.png", false},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNamePrefix, "images/"},
+				FilterRule{FilterRuleNameSuffix, ".jpg"})},
+			EventObjectCreatedPut, "images/a.jpg", true},
+		{NotificationRule{Events: []string{EventObjectCreatedAll},
+			Filter: filter(FilterRule{FilterRuleNamePrefix, "images/"},
+				FilterRule{FilterRuleNameSuffix, ".jpg"})},
+			EventObjectCreatedPut, "images/a.png", false},
+	}
+	for i, testCase := range testCases {
+		if result := testCase.rule.Match(testCase.event, testCase.key); result != testCase.expected {
+			t.Errorf("case %v: expected %v, got %v", i+1, testCase.expected, result)
+		}
+	}
+}
+
+func TestParseNotificationConfig(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    error
+	}{
+		{`<NotificationConfiguration><QueueConfiguration><Id>a</Id>
+<Filter><S3Key><FilterRule><Name>Prefix</Name><Value>images/</Value></FilterRule></S3Key></Filter>
+<Queue>arn:yig:sqs:::events</Queue><Event>s3:ObjectCreated:*</Event></QueueConfiguration>
+</NotificationConfiguration>`, nil},
+		{`<NotificationConfiguration><TopicConfiguration>
+<Topic>events</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+</NotificationConfiguration>`, ErrInvalidNotificationDestination},
+		{`<NotificationConfiguration><TopicConfiguration>
+<Topic>arn:yig:sns:::</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+</NotificationConfiguration>`, ErrInvalidNotificationDestination},
+		{`<NotificationConfiguration><TopicConfiguration>
+<Topic>arn:yig:sns:::events</Topic><Event>s3:ObjectCreated:Bogus</Event></TopicConfiguration>
+</NotificationConfiguration>`, ErrInvalidNotificationEvent},
+		{`<NotificationConfiguration><TopicConfiguration>
+<Filter><S3Key><FilterRule><Name>prefix</Name><Value>a</Value></FilterRule>
+<FilterRule><Name>prefix</Name><Value>b</Value></FilterRule></S3Key></Filter>
+<Topic>arn:yig:sns:::events</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+</NotificationConfiguration>`, ErrInvalidNotificationFilter},
+		{`<NotificationConfiguration>
+<QueueConfiguration><Id>a</Id><Queue>arn:yig:sqs:::q</Queue><Event>s3:ObjectCreated:*</Event></QueueConfiguration>
+<TopicConfiguration><Id>a</Id><Topic>arn:yig:sns:::t</Topic><Event>s3:ObjectCreated:*</Event></TopicConfiguration>
+</NotificationConfiguration>`, ErrInvalidNotificationId},
+	}
+	for i, testCase := range testCases {
+		_, err := ParseNotificationConfig(strings.NewReader(testCase.config))
+		if err != testCase.err {
+			t.Errorf("case %v: expected %v, got %v", i+1, testCase.err, err)
+		}
+	}
+}
+
+func TestNotificationTargets(t *testing.T) {
+	config, err := ParseNotificationConfig(strings.NewReader(`<NotificationConfiguration>
+<QueueConfiguration><Queue>arn:yig:sqs:::q</Queue><Event>s3:ObjectCreated:*</Event></QueueConfiguration>
+<TopicConfiguration><Topic>arn:aws:sns:us-east-1:123456789012:t</Topic><Event>s3:ObjectRemoved:*</Event></TopicConfiguration>
+<CloudFunctionConfiguration><CloudFunction>arn:yig:lambda:::f</CloudFunction><Event>s3:ObjectRestore:*</Event></CloudFunctionConfiguration>
+</NotificationConfiguration>`))
+	if err != nil {
+		t.Fatal("ParseNotificationConfig:", err)
+	}
+	targets := config.Targets()
+	expected := []string{"q", "t", "f"}
+	if len(targets) != len(expected) {
+		t.Fatal("Targets:", targets)
+	}
+	for i, target := range targets {
+		topic, ok := DestinationTopic(target.Destination)
+		if !ok || topic != expected[i] {
+			t.Errorf("target %v: expected topic %v, got %v", i+1, expected[i], topic)
+		}
+	}
+}
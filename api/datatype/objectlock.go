@@ -0,0 +1,204 @@
+package datatype
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const (
+	MaxObjectLockConfigurationSize = 16 * humanize.KiByte
+	MaxObjectLockRetentionDays     = 36500
+	MaxObjectLockRetentionYears    = 100
+)
+
+const ObjectLockEnabled = "Enabled"
+
+// Retention modes, objects in GOVERNANCE mode could be deleted or have their
+// retention shortened by users allowed to bypass governance retention,
+// while objects in COMPLIANCE mode could not be deleted by anyone before
+// the retain until date
+const (
+	ObjectLockModeGovernance = "GOVERNANCE"
+	ObjectLockModeCompliance = "COMPLIANCE"
+)
+
+const (
+	LegalHoldOn  = "ON"
+	LegalHoldOff = "OFF"
+)
+
+type DefaultRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+type ObjectLockRule struct {
+	DefaultRetention DefaultRetention `xml:"DefaultRetention"`
+}
+
+type ObjectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *ObjectLockRule `xml:"Rule,omitempty"`
+}
+
+// ObjectRetention is the body of `?retention` sub-resource
+type ObjectRetention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Mode            string   `xml:"Mode,omitempty"`
+	RetainUntilDate string   `xml:"RetainUntilDate,omitempty"`
+}
+
+// ObjectLegalHold is the body of `?legal-hold` sub-resource
+type ObjectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+// ObjectLockRequest holds object lock settings of a write request,
+// from x-amz-object-lock-* headers
+type ObjectLockRequest struct {
+	Mode            string
+	RetainUntilDate time.Time
+	LegalHold       bool
+}
+
+func IsValidObjectLockMode(mode string) bool {
+	return mode == ObjectLockModeGovernance || mode == ObjectLockModeCompliance
+}
+
+func (c *ObjectLockConfiguration) IsEnabled() bool {
+	return c.ObjectLockEnabled == ObjectLockEnabled
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectLockConfiguration.html
+func (c *ObjectLockConfiguration) Validate() error {
+	if !c.IsEnabled() {
+		return ErrInvalidObjectLockConfiguration
+	}
+	if c.Rule == nil {
+		return nil
+	}
+	retention := c.Rule.DefaultRetention
+	if !IsValidObjectLockMode(retention.Mode) {
+		return ErrInvalidObjectLockConfiguration
+	}
+	// exactly one of Days and Years should be specified
+	if (retention.Days == 0) == (retention.Years == 0) {
+		return ErrInvalidObjectLockConfiguration
+	}
+	if retention.Days < 0 || retention.Days > MaxObjectLockRetentionDays ||
+		retention.Years < 0 || retention.Years > MaxObjectLockRetentionYears {
+		return ErrInvalidObjectLockConfiguration
+	}
+	return nil
+}
+
+// DefaultRetainUntilDate returns the retention that applies to objects
+// written at `now` without explicit retention settings
+func (c *ObjectLockConfiguration) DefaultRetainUntilDate(now time.Time) (mode string,
+	retainUntilDate time.Time) {
+
+	if !c.IsEnabled() || c.Rule == nil {
+		return "", time.Time{}
+	}
+	retention := c.Rule.DefaultRetention
+	return retention.Mode, now.AddDate(retention.Years, 0, retention.Days)
+}
+
+func ParseObjectLockConfig(reader io.Reader) (*ObjectLockConfiguration, error) {
+	config := new(ObjectLockConfiguration)
+	configBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxObjectLockConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read object lock config body:", err)
+		return nil, err
+	}
+	if len(configBuffer) > MaxObjectLockConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(configBuffer, config)
+	if err != nil {
+		helper.Logger.Error("Unable to parse object lock config XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectRetention.html
+// An empty retention clears the retention of object.
+func ParseObjectRetention(reader io.Reader) (mode string, retainUntilDate time.Time, err error) {
+	retention := new(ObjectRetention)
+	retentionBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxObjectLockConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read retention body:", err)
+		return
+	}
+	if len(retentionBuffer) > MaxObjectLockConfigurationSize {
+		return "", retainUntilDate, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(retentionBuffer, retention)
+	if err != nil {
+		helper.Logger.Error("Unable to parse retention XML body:", err)
+		return "", retainUntilDate, ErrMalformedXML
+	}
+	if retention.Mode == "" && retention.RetainUntilDate == "" {
+		return "", retainUntilDate, nil
+	}
+	return ParseRetention(retention.Mode, retention.RetainUntilDate)
+}
+
+// ParseRetention validates retention mode and retain until date in ISO 8601
+// format, the date should be in the future
+func ParseRetention(mode, date string) (string, time.Time, error) {
+	if !IsValidObjectLockMode(mode) {
+		return "", time.Time{}, ErrInvalidObjectRetention
+	}
+	retainUntilDate, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidObjectRetention
+	}
+	if !retainUntilDate.After(time.Now()) {
+		return "", time.Time{}, ErrPastObjectLockRetainDate
+	}
+	return mode, retainUntilDate.UTC(), nil
+}
+
+func ParseObjectLegalHold(reader io.Reader) (legalHold bool, err error) {
+	hold := new(ObjectLegalHold)
+	holdBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxObjectLockConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read legal hold body:", err)
+		return false, err
+	}
+	if len(holdBuffer) > MaxObjectLockConfigurationSize {
+		return false, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(holdBuffer, hold)
+	if err != nil {
+		helper.Logger.Error("Unable to parse legal hold XML body:", err)
+		return false, ErrMalformedXML
+	}
+	return ParseLegalHold(hold.Status)
+}
+
+func ParseLegalHold(status string) (bool, error) {
+	switch status {
+	case LegalHoldOn:
+		return true, nil
+	case LegalHoldOff:
+		return false, nil
+	default:
+		return false, ErrInvalidLegalHold
+	}
+}
@@ -44,6 +44,9 @@ const (
 	// DeleteObjectAction - DeleteObject Rest API action.
 	DeleteObjectAction = "s3:DeleteObject"
 
+	// DeleteObjectVersionAction - DeleteObject Rest API action with versionId.
+	DeleteObjectVersionAction = "s3:DeleteObjectVersion"
+
 	// GetBucketLocationAction - GetBucketLocation Rest API action.
 	GetBucketLocationAction = "s3:GetBucketLocation"
 
@@ -83,6 +86,112 @@ const (
 
 	// PutObjectAction - PutObject Rest API action.
 	PutObjectAction = "s3:PutObject"
+
+	// PutObjectTaggingAction - PutObjectTagging Rest API action.
+	PutObjectTaggingAction = "s3:PutObjectTagging"
+
+	// GetObjectTaggingAction - GetObjectTagging Rest API action.
+	GetObjectTaggingAction = "s3:GetObjectTagging"
+
+	// DeleteObjectTaggingAction - DeleteObjectTagging Rest API action.
+	DeleteObjectTaggingAction = "s3:DeleteObjectTagging"
+
+	// PutObjectRetentionAction - PutObjectRetention Rest API action.
+	PutObjectRetentionAction = "s3:PutObjectRetention"
+
+	// GetObjectRetentionAction - GetObjectRetention Rest API action.
+	GetObjectRetentionAction = "s3:GetObjectRetention"
+
+	// PutObjectLegalHoldAction - PutObjectLegalHold Rest API action.
+	PutObjectLegalHoldAction = "s3:PutObjectLegalHold"
+
+	// GetObjectLegalHoldAction - GetObjectLegalHold Rest API action.
+	GetObjectLegalHoldAction = "s3:GetObjectLegalHold"
+
+	// BypassGovernanceRetentionAction - permission to delete objects or shorten
+	// retentions in GOVERNANCE mode, with x-amz-bypass-governance-retention set.
+	BypassGovernanceRetentionAction = "s3:BypassGovernanceRetention"
+
+	// PutBucketAclAction - PutBucketAcl Rest API action.
+	PutBucketAclAction = "s3:PutBucketAcl"
+
+	// GetBucketAclAction - GetBucketAcl Rest API action.
+	GetBucketAclAction = "s3:GetBucketAcl"
+
+	// PutObjectAclAction - PutObjectAcl Rest API action.
+	PutObjectAclAction = "s3:PutObjectAcl"
+
+	// GetObjectAclAction - GetObjectAcl Rest API action.
+	GetObjectAclAction = "s3:GetObjectAcl"
+
+	// ListBucketVersionsAction - ListObjectVersions Rest API action.
+	ListBucketVersionsAction = "s3:ListBucketVersions"
+
+	// PutBucketVersioningAction - PutBucketVersioning Rest API action.
+	PutBucketVersioningAction = "s3:PutBucketVersioning"
+
+	// GetBucketVersioningAction - GetBucketVersioning Rest API action.
+	GetBucketVersioningAction = "s3:GetBucketVersioning"
+
+	// PutBucketCORSAction - PutBucketCors and DeleteBucketCors Rest API action.
+	PutBucketCORSAction = "s3:PutBucketCORS"
+
+	// GetBucketCORSAction - GetBucketCors Rest API action.
+	GetBucketCORSAction = "s3:GetBucketCORS"
+
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
+	// PutLifecycleConfigurationAction - PutBucketLifecycle and DeleteBucketLifecycle Rest API action.
+	PutLifecycleConfigurationAction = "s3:PutLifecycleConfiguration"
+
+	// GetLifecycleConfigurationAction - GetBucketLifecycle Rest API action.
+	GetLifecycleConfigurationAction = "s3:GetLifecycleConfiguration"
+
+	// PutBucketWebsiteAction - PutBucketWebsite Rest API action.
+	PutBucketWebsiteAction = "s3:PutBucketWebsite"
+
+	// GetBucketWebsiteAction - GetBucketWebsite Rest API action.
+	GetBucketWebsiteAction = "s3:GetBucketWebsite"
+
+	// DeleteBucketWebsiteAction - DeleteBucketWebsite Rest API action.
+	DeleteBucketWebsiteAction = "s3:DeleteBucketWebsite"
+
+	// PutEncryptionConfigurationAction - PutBucketEncryption and DeleteBucketEncryption Rest API action.
+	PutEncryptionConfigurationAction = "s3:PutEncryptionConfiguration"
+
+	// GetEncryptionConfigurationAction - GetBucketEncryption Rest API action.
+	GetEncryptionConfigurationAction = "s3:GetEncryptionConfiguration"
+
+	// PutReplicationConfigurationAction - PutBucketReplication and DeleteBucketReplication Rest API action.
+	PutReplicationConfigurationAction = "s3:PutReplicationConfiguration"
+
+	// GetReplicationConfigurationAction - GetBucketReplication Rest API action.
+	GetReplicationConfigurationAction = "s3:GetReplicationConfiguration"
+
+	// PutBucketTaggingAction - PutBucketTagging and DeleteBucketTagging Rest API action.
+	PutBucketTaggingAction = "s3:PutBucketTagging"
+
+	// GetBucketTaggingAction - GetBucketTagging Rest API action.
+	GetBucketTaggingAction = "s3:GetBucketTagging"
+
+	// PutBucketObjectLockConfigurationAction - PutObjectLockConfiguration Rest API action.
+	PutBucketObjectLockConfigurationAction = "s3:PutBucketObjectLockConfiguration"
+
+	// GetBucketObjectLockConfigurationAction - GetObjectLockConfiguration Rest API action.
+	GetBucketObjectLockConfigurationAction = "s3:GetBucketObjectLockConfiguration"
+
+	// PutBucketPublicAccessBlockAction - PutPublicAccessBlock and DeletePublicAccessBlock Rest API action.
+	PutBucketPublicAccessBlockAction = "s3:PutBucketPublicAccessBlock"
+
+	// GetBucketPublicAccessBlockAction - GetPublicAccessBlock Rest API action.
+	GetBucketPublicAccessBlockAction = "s3:GetBucketPublicAccessBlock"
+
+	// RestoreObjectAction - RestoreObject Rest API action.
+	RestoreObjectAction = "s3:RestoreObject"
 )
 
 // isObjectAction - returns whether action is object type or not.
@@ -91,6 +200,16 @@ func (action Action) isObjectAction() bool {
 	case AbortMultipartUploadAction, DeleteObjectAction, GetObjectAction:
 		fallthrough
 	case ListMultipartUploadPartsAction, PutObjectAction:
+		fallthrough
+	case PutObjectTaggingAction, GetObjectTaggingAction, DeleteObjectTaggingAction:
+		fallthrough
+	case PutObjectRetentionAction, GetObjectRetentionAction:
+		fallthrough
+	case PutObjectLegalHoldAction, GetObjectLegalHoldAction, BypassGovernanceRetentionAction:
+		fallthrough
+	case PutObjectAclAction, GetObjectAclAction, RestoreObjectAction:
+		fallthrough
+	case DeleteObjectVersionAction:
 		return true
 	}
 
@@ -113,6 +232,32 @@ func (action Action) IsValid() bool {
 	case ListMultipartUploadPartsAction, PutBucketNotificationAction:
 		fallthrough
 	case PutBucketPolicyAction, PutObjectAction:
+		fallthrough
+	case PutObjectTaggingAction, GetObjectTaggingAction, DeleteObjectTaggingAction:
+		fallthrough
+	case PutObjectRetentionAction, GetObjectRetentionAction:
+		fallthrough
+	case PutObjectLegalHoldAction, GetObjectLegalHoldAction, BypassGovernanceRetentionAction:
+		fallthrough
+	case PutBucketAclAction, GetBucketAclAction, PutObjectAclAction:
+		fallthrough
+	case GetObjectAclAction, ListBucketVersionsAction, PutBucketVersioningAction:
+		fallthrough
+	case GetBucketVersioningAction, PutBucketCORSAction, GetBucketCORSAction:
+		fallthrough
+	case PutBucketLoggingAction, GetBucketLoggingAction, PutLifecycleConfigurationAction:
+		fallthrough
+	case GetLifecycleConfigurationAction, PutBucketWebsiteAction, GetBucketWebsiteAction:
+		fallthrough
+	case DeleteBucketWebsiteAction, PutEncryptionConfigurationAction, GetEncryptionConfigurationAction:
+		fallthrough
+	case PutReplicationConfigurationAction, GetReplicationConfigurationAction, PutBucketTaggingAction:
+		fallthrough
+	case GetBucketTaggingAction, PutBucketObjectLockConfigurationAction, GetBucketObjectLockConfigurationAction:
+		fallthrough
+	case PutBucketPublicAccessBlockAction, GetBucketPublicAccessBlockAction:
+		fallthrough
+	case RestoreObjectAction, DeleteObjectVersionAction:
 		return true
 	}
 
@@ -156,6 +301,43 @@ func parseAction(s string) (Action, error) {
 	return action, fmt.Errorf("unsupported action '%v'", s)
 }
 
+// isConditionKeySupported - checks if condition key could be used with the action,
+// besides keys in actionConditionKeyMap, common keys are supported by all actions and
+// tags of existing object are supported by actions on existing objects.
+func (action Action) isConditionKeySupported(key condition.Key) bool {
+	if _, ok := actionConditionKeyMap[action][key]; ok {
+		return true
+	}
+	if _, ok := condition.CommonKeys[key]; ok {
+		return true
+	}
+	if key.IsExistingObjectTag() {
+		switch action {
+		case GetObjectAction, GetObjectAclAction, PutObjectAclAction:
+			fallthrough
+		case GetObjectTaggingAction, PutObjectTaggingAction, DeleteObjectTaggingAction:
+			fallthrough
+		case GetObjectRetentionAction, PutObjectRetentionAction:
+			fallthrough
+		case GetObjectLegalHoldAction, PutObjectLegalHoldAction:
+			return true
+		}
+	}
+
+	return false
+}
+
+// isConditionKeySupportedByOthers - checks if condition key could be used with any
+// action not in the set, i.e. actions which statements with NotAction apply to.
+func (actionSet ActionSet) isConditionKeySupportedByOthers(key condition.Key) bool {
+	for action := range actionConditionKeyMap {
+		if !actionSet.Contains(action) && action.isConditionKeySupported(key) {
+			return true
+		}
+	}
+	return false
+}
+
 // actionConditionKeyMap - holds mapping of supported condition key for an action.
 var actionConditionKeyMap = map[Action]condition.KeySet{
 	AbortMultipartUploadAction: condition.NewKeySet(
@@ -178,6 +360,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	DeleteObjectVersionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetBucketLocationAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -253,4 +440,179 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
+
+	PutObjectTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	DeleteObjectTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutObjectRetentionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectRetentionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutObjectLegalHoldAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectLegalHoldAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	BypassGovernanceRetentionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketAclAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketAclAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutObjectAclAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectAclAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	ListBucketVersionsAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketVersioningAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketVersioningAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketCORSAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketCORSAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketLoggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketLoggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutLifecycleConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetLifecycleConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	DeleteBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutEncryptionConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetEncryptionConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutReplicationConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetReplicationConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketObjectLockConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketObjectLockConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketPublicAccessBlockAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketPublicAccessBlockAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	RestoreObjectAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
 }
@@ -0,0 +1,109 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/journeymidnight/yig/api/datatype/policy/utils"
+)
+
+// arnMatch - checks whether ARN matches pattern, each one of the six colon-delimited
+// components of ARN is matched separately with '*' and '?' wildcards by
+// utils.MatchEscaped.
+func arnMatch(pattern, arn string) bool {
+	patternParts := strings.SplitN(pattern, ":", 6)
+	arnParts := strings.SplitN(arn, ":", 6)
+	if len(patternParts) != 6 || len(arnParts) != 6 {
+		return false
+	}
+
+	for i := range patternParts {
+		if !utils.MatchEscaped(patternParts[i], arnParts[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// arnFunc - ARN functions. It checks whether ARN by Key in given values map matches
+// one of condition values, e.g. aws:PrincipalArn. ArnEquals and ArnLike behave the
+// same as AWS does.
+// For example,
+//   - if values = ["arn:aws:iam::*:role/admin"], at evaluate() it returns whether
+//     ARN in value map for Key is role "admin" of any account.
+//
+// ArnNotEquals and ArnNotLike evaluate to true if ARN matches none of values.
+type arnFunc struct {
+	n      name
+	k      Key
+	values utils.StringSet
+}
+
+// evaluate() - evaluates to check whether ARN by Key in given values matches one
+// of condition values.
+func (f arnFunc) evaluate(values map[string][]string) bool {
+	matched := false
+	patterns := substitutePatterns(f.values, values)
+	for _, v := range values[f.k.Name()] {
+		if !patterns.FuncMatch(arnMatch, v).IsEmpty() {
+			matched = true
+		}
+	}
+
+	if f.n == arnNotEquals || f.n == arnNotLike {
+		return !matched
+	}
+
+	return matched
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f arnFunc) key() Key {
+	return f.k
+}
+
+// name() - returns condition name of this function.
+func (f arnFunc) name() name {
+	return f.n
+}
+
+func (f arnFunc) String() string {
+	return toStringLikeFuncString(f.n, f.k, f.values)
+}
+
+// toMap - returns map representation of this function.
+func (f arnFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values.ToSlice() {
+		values.Add(NewStringValue(value))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// newArnFunc - returns new ARN function of name n.
+func newArnFunc(n name, key Key, values ValueSet) (Function, error) {
+	if !ArnKeys.Contains(key) {
+		return nil, fmt.Errorf("%v condition is not applicable to key %v", n, key)
+	}
+
+	valueStrings, err := valuesToStringSlice(n, values)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range valueStrings {
+		if !strings.HasPrefix(s, "arn:") || len(strings.SplitN(s, ":", 6)) != 6 {
+			return nil, fmt.Errorf("invalid ARN '%v' for %v condition", s, n)
+		}
+	}
+
+	return &arnFunc{n, key, utils.CreateStringSet(valueStrings...)}, nil
+}
@@ -0,0 +1,71 @@
+package condition
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestArnFunc(t *testing.T) {
+	root := map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:root"}, "userid": {"hehe"}}
+	role := map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:role/admin"}, "userid": {"hehe"}}
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, root, true},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, role, false},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::*:role/admin"}}`, role, true},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, missing, false},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::${aws:userid}:root"}}`, root, true},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::${aws:userid}:root"}}`,
+			map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:root"}, "userid": {"haha"}}, false},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::hehe:role/*"}}`, role, true},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::hehe:role/*"}}`, root, false},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::he?e:*"}}`, root, true},
+		// wildcards don't match across components
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:*:::root"}}`, root, false},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:*::hehe:root"}}`, root, true},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::hehe:*"}}`,
+			map[string][]string{"PrincipalArn": {"not an arn"}}, false},
+		{`{"ArnNotEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, role, true},
+		{`{"ArnNotEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, root, false},
+		{`{"ArnNotEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, missing, true},
+		{`{"ArnNotLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`, root, true},
+		{`{"ArnNotLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`, role, false},
+		{`{"ArnLikeIfExists":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`, missing, true},
+		{`{"ArnLikeIfExists":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`, root, false},
+		{`{"ForAnyValue:ArnLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`,
+			map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:root", "arn:aws:iam::hehe:role/a"}}, true},
+		{`{"ForAllValues:ArnLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`,
+			map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:root", "arn:aws:iam::hehe:role/a"}}, false},
+		{`{"ForAllValues:ArnLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/*"}}`, missing, true},
+	})
+	testInvalid(t, []string{
+		`{"ArnEquals":{"aws:PrincipalArn":"hehe"}}`,
+		`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam"}}`,
+		`{"ArnLike":{"aws:PrincipalArn":"arn:aws:*:root"}}`,
+		// Arn* conditions apply to ARN keys only
+		`{"ArnEquals":{"aws:Referer":"arn:aws:iam::hehe:root"}}`,
+		`{"ArnLike":{"aws:SourceArn":"arn:aws:iam::hehe:root"}}`,
+	})
+}
+
+func TestArnFuncIsRestricting(t *testing.T) {
+	for _, c := range []struct {
+		condition   string
+		restricting bool
+	}{
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, true},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::hehe:role/reader"}}`, true},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::*:root"}}`, false},
+		{`{"ArnEquals":{"aws:PrincipalArn":"arn:aws:iam::${aws:userid}:root"}}`, false},
+		{`{"ArnNotEquals":{"aws:PrincipalArn":"arn:aws:iam::hehe:root"}}`, false},
+	} {
+		var functions Functions
+		err := json.Unmarshal([]byte(c.condition), &functions)
+		if err != nil {
+			t.Fatal("Unmarshal err:", err, c.condition)
+		}
+		if functions.IsRestricting() != c.restricting {
+			t.Fatal("IsRestricting:", c.condition)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestBooleanFunc(t *testing.T) {
+	secure := map[string][]string{"SecureTransport": {"true"}}
+	insecure := map[string][]string{"SecureTransport": {"false"}}
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		{`{"Bool":{"aws:SecureTransport":"true"}}`, secure, true},
+		{`{"Bool":{"aws:SecureTransport":true}}`, secure, true},
+		{`{"Bool":{"aws:SecureTransport":"true"}}`, insecure, false},
+		{`{"Bool":{"aws:SecureTransport":"false"}}`, insecure, true},
+		{`{"Bool":{"aws:SecureTransport":"false"}}`, secure, false},
+		{`{"Bool":{"aws:SecureTransport":"true"}}`, missing, false},
+		{`{"Bool":{"aws:SecureTransport":"false"}}`, missing, false},
+		{`{"Bool":{"aws:SecureTransport":"true"}}`,
+			map[string][]string{"SecureTransport": {"yes"}}, false},
+		{`{"BoolIfExists":{"aws:SecureTransport":"true"}}`, missing, true},
+		{`{"BoolIfExists":{"aws:SecureTransport":"true"}}`, insecure, false},
+		{`{"ForAnyValue:Bool":{"aws:SecureTransport":"true"}}`,
+			map[string][]string{"SecureTransport": {"false", "true"}}, true},
+		{`{"ForAllValues:Bool":{"aws:SecureTransport":"true"}}`,
+			map[string][]string{"SecureTransport": {"false", "true"}}, false},
+		{`{"ForAllValues:Bool":{"aws:SecureTransport":"true"}}`, missing, true},
+	})
+	testInvalid(t, []string{
+		`{"Bool":{"aws:SecureTransport":"yes"}}`,
+		`{"Bool":{"aws:SecureTransport":["true","false"]}}`,
+		`{"Bool":{"aws:SecureTransport":1}}`,
+	})
+}
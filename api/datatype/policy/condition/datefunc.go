@@ -0,0 +1,138 @@
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// parseDate - parses date of ISO 8601 format, e.g. "2019-07-01T00:00:00Z" or
+// "2019-07-01", or seconds since epoch, e.g. "1561939200".
+func parseDate(s string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", s)
+}
+
+// dateFunc - Date functions. It checks whether date by Key in given values map
+// compares to one of condition dates as required by name, e.g. aws:CurrentTime.
+// For example,
+//   - if name = "DateLessThan" and values = ["2019-07-01T00:00:00Z"], at evaluate()
+//     it returns whether date in value map for Key is before 2019-07-01T00:00:00Z.
+//
+// DateNotEquals evaluates to true if the date equals to none of values.
+type dateFunc struct {
+	n      name
+	k      Key
+	values []time.Time
+}
+
+func (f dateFunc) compare(requestValue, value time.Time) bool {
+	switch f.n {
+	case dateLessThan:
+		return requestValue.Before(value)
+	case dateLessThanEquals:
+		return !requestValue.After(value)
+	case dateGreaterThan:
+		return requestValue.After(value)
+	case dateGreaterThanEquals:
+		return !requestValue.Before(value)
+	}
+
+	return requestValue.Equal(value)
+}
+
+// evaluate() - evaluates to check whether date by Key in given values compares
+// to one of condition dates. Values not a date never match.
+func (f dateFunc) evaluate(values map[string][]string) bool {
+	matched := false
+	for _, s := range values[f.k.Name()] {
+		requestValue, err := parseDate(s)
+		if err != nil {
+			continue
+		}
+
+		for _, value := range f.values {
+			if f.compare(requestValue, value) {
+				matched = true
+			}
+		}
+	}
+
+	if f.n == dateNotEquals {
+		return !matched
+	}
+
+	return matched
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f dateFunc) key() Key {
+	return f.k
+}
+
+// name() - returns condition name of this function.
+func (f dateFunc) name() name {
+	return f.n
+}
+
+func (f dateFunc) dateStrings() []string {
+	dates := []string{}
+	for _, value := range f.values {
+		dates = append(dates, value.UTC().Format(time.RFC3339))
+	}
+	sort.Strings(dates)
+
+	return dates
+}
+
+func (f dateFunc) String() string {
+	return fmt.Sprintf("%v:%v:%v", f.n, f.k, f.dateStrings())
+}
+
+// toMap - returns map representation of this function.
+func (f dateFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, date := range f.dateStrings() {
+		values.Add(NewStringValue(date))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// newDateFunc - returns new Date function of name n.
+func newDateFunc(n name, key Key, values ValueSet) (Function, error) {
+	dates := []time.Time{}
+	for v := range values {
+		switch v.GetType() {
+		case reflect.Int:
+			i, _ := v.GetInt()
+			dates = append(dates, time.Unix(int64(i), 0))
+		case reflect.String:
+			s, _ := v.GetString()
+			date, err := parseDate(s)
+			if err != nil {
+				return nil, fmt.Errorf("value %v must be a date for %v condition", s, n)
+			}
+			dates = append(dates, date)
+		default:
+			return nil, fmt.Errorf("value %v must be a date for %v condition", v, n)
+		}
+	}
+
+	return &dateFunc{n, key, dates}, nil
+}
@@ -0,0 +1,46 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestDateFunc(t *testing.T) {
+	july := map[string][]string{"CurrentTime": {"2019-07-01T00:00:00Z"}}
+	epoch := map[string][]string{"EpochTime": {"1561939200"}} // 2019-07-01T00:00:00Z
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-01T00:00:00Z"}}`, july, true},
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-01"}}`, july, true},
+		{`{"DateEquals":{"aws:CurrentTime":"1561939200"}}`, july, true},
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-01T08:00:00+08:00"}}`, july, true},
+		{`{"DateEquals":{"aws:EpochTime":"2019-07-01T00:00:00Z"}}`, epoch, true},
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-02"}}`, july, false},
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-01"}}`, missing, false},
+		{`{"DateEquals":{"aws:CurrentTime":"2019-07-01"}}`,
+			map[string][]string{"CurrentTime": {"yesterday"}}, false},
+		{`{"DateNotEquals":{"aws:CurrentTime":"2019-07-02"}}`, july, true},
+		{`{"DateNotEquals":{"aws:CurrentTime":["2019-07-01","2019-07-02"]}}`, july, false},
+		{`{"DateNotEquals":{"aws:CurrentTime":"2019-07-02"}}`, missing, true},
+		{`{"DateLessThan":{"aws:CurrentTime":"2019-07-01T00:00:01Z"}}`, july, true},
+		{`{"DateLessThan":{"aws:CurrentTime":"2019-07-01"}}`, july, false},
+		{`{"DateLessThanEquals":{"aws:CurrentTime":"2019-07-01"}}`, july, true},
+		{`{"DateLessThanEquals":{"aws:CurrentTime":"2019-06-30"}}`, july, false},
+		{`{"DateGreaterThan":{"aws:CurrentTime":"2019-06-30"}}`, july, true},
+		{`{"DateGreaterThan":{"aws:CurrentTime":"2019-07-01"}}`, july, false},
+		{`{"DateGreaterThanEquals":{"aws:CurrentTime":"2019-07-01"}}`, july, true},
+		{`{"DateGreaterThanEquals":{"aws:CurrentTime":"2019-07-02"}}`, july, false},
+		{`{"DateGreaterThan":{"aws:EpochTime":1561939199}}`, epoch, true},
+		{`{"DateLessThanIfExists":{"aws:CurrentTime":"2019-06-30"}}`, missing, true},
+		{`{"DateLessThanIfExists":{"aws:CurrentTime":"2019-06-30"}}`, july, false},
+		{`{"ForAnyValue:DateLessThan":{"aws:CurrentTime":"2019-07-01"}}`,
+			map[string][]string{"CurrentTime": {"2019-07-01", "2019-06-01"}}, true},
+		{`{"ForAllValues:DateLessThan":{"aws:CurrentTime":"2019-07-01"}}`,
+			map[string][]string{"CurrentTime": {"2019-07-01", "2019-06-01"}}, false},
+		{`{"ForAllValues:DateLessThan":{"aws:CurrentTime":"2019-07-01"}}`, missing, true},
+	})
+	testInvalid(t, []string{
+		`{"DateEquals":{"aws:CurrentTime":"yesterday"}}`,
+		`{"DateEquals":{"aws:CurrentTime":"2019/07/01"}}`,
+		`{"DateEquals":{"aws:CurrentTime":true}}`,
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // Function - condition function interface.
@@ -66,6 +67,53 @@ func (functions Functions) Keys() KeySet {
 	return keySet
 }
 
+// IsRestricting - returns whether any of functions limits requests to fixed source
+// networks or users, so a statement with them does not grant access to everyone.
+func (functions Functions) IsRestricting() bool {
+	for _, f := range functions {
+		switch v := f.(type) {
+		case *ipAddressFunc:
+			restricting := len(v.values) != 0
+			for _, IPNet := range v.values {
+				if ones, _ := IPNet.Mask.Size(); ones == 0 {
+					restricting = false
+				}
+			}
+			if restricting {
+				return true
+			}
+		case *stringEqualsFunc:
+			if v.k != AWSUserID && v.k != AWSUsername {
+				continue
+			}
+			restricting := len(v.values) != 0
+			for s := range v.values {
+				if strings.Contains(s, "${") {
+					restricting = false
+				}
+			}
+			if restricting {
+				return true
+			}
+		case *arnFunc:
+			if v.n != arnEquals && v.n != arnLike {
+				continue
+			}
+			restricting := len(v.values) != 0
+			for s := range v.values {
+				if strings.ContainsAny(s, "*?") || strings.Contains(s, "${") {
+					restricting = false
+				}
+			}
+			if restricting {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // MarshalJSON - encodes Functions to  JSON data.
 func (functions Functions) MarshalJSON() ([]byte, error) {
 	nm := make(map[name]map[Key]ValueSet)
@@ -119,8 +167,9 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 				return err
 			}
 
+			qualifier, base, ifExists := n.split()
 			var f Function
-			switch n {
+			switch base {
 			case stringEquals:
 				if f, err = newStringEqualsFunc(key, values); err != nil {
 					return err
@@ -129,6 +178,14 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 				if f, err = newStringNotEqualsFunc(key, values); err != nil {
 					return err
 				}
+			case stringEqualsIgnoreCase:
+				if f, err = newStringEqualsIgnoreCaseFunc(key, values); err != nil {
+					return err
+				}
+			case stringNotEqualsIgnoreCase:
+				if f, err = newStringNotEqualsIgnoreCaseFunc(key, values); err != nil {
+					return err
+				}
 			case stringLike:
 				if f, err = newStringLikeFunc(key, values); err != nil {
 					return err
@@ -137,6 +194,24 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 				if f, err = newStringNotLikeFunc(key, values); err != nil {
 					return err
 				}
+			case numericEquals, numericNotEquals, numericLessThan, numericLessThanEquals,
+				numericGreaterThan, numericGreaterThanEquals:
+				if f, err = newNumericFunc(base, key, values); err != nil {
+					return err
+				}
+			case dateEquals, dateNotEquals, dateLessThan, dateLessThanEquals,
+				dateGreaterThan, dateGreaterThanEquals:
+				if f, err = newDateFunc(base, key, values); err != nil {
+					return err
+				}
+			case boolean:
+				if f, err = newBooleanFunc(key, values); err != nil {
+					return err
+				}
+			case arnEquals, arnNotEquals, arnLike, arnNotLike:
+				if f, err = newArnFunc(base, key, values); err != nil {
+					return err
+				}
 			case ipAddress:
 				if f, err = newIPAddressFunc(key, values); err != nil {
 					return err
@@ -153,6 +228,13 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 				return fmt.Errorf("%v is not handled", n)
 			}
 
+			if qualifier != "" {
+				f = &setQualifierFunc{f, n, qualifier == forAllValuesPrefix}
+			}
+			if ifExists {
+				f = &ifExistsFunc{f, n}
+			}
+
 			funcs = append(funcs, f)
 		}
 	}
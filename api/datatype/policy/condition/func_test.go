@@ -0,0 +1,39 @@
+package condition
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type evaluateCase struct {
+	condition string
+	values    map[string][]string
+	expected  bool
+}
+
+// testEvaluate parses condition of each case, and checks whether it evaluates
+// to expected with values
+func testEvaluate(t *testing.T, cases []evaluateCase) {
+	for i, c := range cases {
+		var functions Functions
+		err := json.Unmarshal([]byte(c.condition), &functions)
+		if err != nil {
+			t.Fatal("case", i, "Unmarshal err:", err, c.condition)
+		}
+		result := functions.Evaluate(c.values)
+		if result != c.expected {
+			t.Fatal("case", i, c.condition, "with", c.values, "evaluates to", result)
+		}
+	}
+}
+
+// testInvalid checks that each condition fails to be parsed
+func testInvalid(t *testing.T, conditions []string) {
+	for _, condition := range conditions {
+		var functions Functions
+		err := json.Unmarshal([]byte(condition), &functions)
+		if err == nil {
+			t.Fatal("Invalid condition is parsed:", condition, functions)
+		}
+	}
+}
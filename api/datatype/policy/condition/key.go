@@ -68,8 +68,49 @@ const (
 
 	// AWSSourceIP - key representing client's IP address (not intermittent proxies) of any API.
 	AWSSourceIP = "aws:SourceIp"
+
+	// AWSSecureTransport - key representing whether request is sent through SSL of any API.
+	AWSSecureTransport = "aws:SecureTransport"
+
+	// AWSCurrentTime - key representing time of request in ISO 8601 format of any API.
+	AWSCurrentTime = "aws:CurrentTime"
+
+	// AWSEpochTime - key representing time of request in seconds since epoch of any API.
+	AWSEpochTime = "aws:EpochTime"
+
+	// AWSUserAgent - key representing User-Agent header of any API.
+	AWSUserAgent = "aws:UserAgent"
+
+	// AWSUserID - key representing user id of requester of any API, absent for
+	// anonymous requests. It's also policy variable ${aws:userid}.
+	AWSUserID = "aws:userid"
+
+	// AWSUsername - key representing display name of requester of any API, absent
+	// for anonymous requests. It's also policy variable ${aws:username}.
+	AWSUsername = "aws:username"
+
+	// AWSPrincipalArn - key representing ARN of requester of any API, absent for
+	// anonymous requests. It's "arn:aws:iam::<user id>:root", or
+	// "arn:aws:iam::<user id>:role/<role name>" for temporary credentials issued
+	// by AssumeRole.
+	AWSPrincipalArn = "aws:PrincipalArn"
+
+	// S3SignatureVersion - key representing signature version of any API, "AWS" for
+	// signature V2 and "AWS4-HMAC-SHA256" for signature V4.
+	S3SignatureVersion = "s3:signatureversion"
+
+	// S3ExistingObjectTagPrefix - prefix of keys representing tags of existing object,
+	// e.g. "s3:ExistingObjectTag/<tag key>", applicable to object APIs only.
+	S3ExistingObjectTagPrefix = "s3:ExistingObjectTag/"
 )
 
+// CommonKeys - keys applicable to all APIs.
+var CommonKeys = NewKeySet(AWSReferer, AWSSourceIP, AWSSecureTransport, AWSCurrentTime,
+	AWSEpochTime, AWSUserAgent, AWSUserID, AWSUsername, AWSPrincipalArn, S3SignatureVersion)
+
+// ArnKeys - keys with ARN values, the only keys applicable to Arn* conditions.
+var ArnKeys = NewKeySet(AWSPrincipalArn)
+
 // IsValid - checks if key is valid or not.
 func (key Key) IsValid() bool {
 	switch key {
@@ -78,10 +119,20 @@ func (key Key) IsValid() bool {
 	case S3XAmzMetadataDirective, S3XAmzStorageClass, S3LocationConstraint, S3Prefix:
 		fallthrough
 	case S3Delimiter, S3MaxKeys, AWSReferer, AWSSourceIP:
+		fallthrough
+	case AWSSecureTransport, AWSCurrentTime, AWSEpochTime, AWSUserAgent, S3SignatureVersion:
+		fallthrough
+	case AWSUserID, AWSUsername, AWSPrincipalArn:
 		return true
 	}
 
-	return false
+	return key.IsExistingObjectTag()
+}
+
+// IsExistingObjectTag - checks if key represents a tag of existing object.
+func (key Key) IsExistingObjectTag() bool {
+	return strings.HasPrefix(string(key), S3ExistingObjectTagPrefix) &&
+		len(key) > len(S3ExistingObjectTagPrefix)
 }
 
 // MarshalJSON - encodes Key to JSON data.
@@ -155,6 +206,12 @@ func (set KeySet) Difference(sset KeySet) KeySet {
 	return nset
 }
 
+// Contains - checks if key is in key set.
+func (set KeySet) Contains(key Key) bool {
+	_, ok := set[key]
+	return ok
+}
+
 // IsEmpty - returns whether key set is empty or not.
 func (set KeySet) IsEmpty() bool {
 	return len(set) == 0
@@ -19,24 +19,92 @@ package condition
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type name string
 
 const (
-	stringEquals    name = "StringEquals"
-	stringNotEquals      = "StringNotEquals"
-	stringLike           = "StringLike"
-	stringNotLike        = "StringNotLike"
-	ipAddress            = "IpAddress"
-	notIPAddress         = "NotIpAddress"
-	null                 = "Null"
+	stringEquals              name = "StringEquals"
+	stringNotEquals                = "StringNotEquals"
+	stringEqualsIgnoreCase         = "StringEqualsIgnoreCase"
+	stringNotEqualsIgnoreCase      = "StringNotEqualsIgnoreCase"
+	stringLike                     = "StringLike"
+	stringNotLike                  = "StringNotLike"
+	numericEquals                  = "NumericEquals"
+	numericNotEquals               = "NumericNotEquals"
+	numericLessThan                = "NumericLessThan"
+	numericLessThanEquals          = "NumericLessThanEquals"
+	numericGreaterThan             = "NumericGreaterThan"
+	numericGreaterThanEquals       = "NumericGreaterThanEquals"
+	dateEquals                     = "DateEquals"
+	dateNotEquals                  = "DateNotEquals"
+	dateLessThan                   = "DateLessThan"
+	dateLessThanEquals             = "DateLessThanEquals"
+	dateGreaterThan                = "DateGreaterThan"
+	dateGreaterThanEquals          = "DateGreaterThanEquals"
+	boolean                        = "Bool"
+	arnEquals                      = "ArnEquals"
+	arnNotEquals                   = "ArnNotEquals"
+	arnLike                        = "ArnLike"
+	arnNotLike                     = "ArnNotLike"
+	ipAddress                      = "IpAddress"
+	notIPAddress                   = "NotIpAddress"
+	null                           = "Null"
 )
 
+const (
+	// ifExistsSuffix - condition name with this suffix evaluates to true if key is absent.
+	ifExistsSuffix = "IfExists"
+
+	// forAnyValuePrefix - condition name with this prefix evaluates to true if any one of
+	// values of key matches.
+	forAnyValuePrefix = "ForAnyValue:"
+
+	// forAllValuesPrefix - condition name with this prefix evaluates to true if every one
+	// of values of key matches, or key is absent.
+	forAllValuesPrefix = "ForAllValues:"
+)
+
+// split - splits name into set qualifier prefix, base name and whether it ends with
+// IfExists, e.g. "ForAnyValue:StringLikeIfExists" is "ForAnyValue:", "StringLike", true.
+func (n name) split() (qualifier string, base name, ifExists bool) {
+	s := string(n)
+	for _, prefix := range []string{forAnyValuePrefix, forAllValuesPrefix} {
+		if strings.HasPrefix(s, prefix) {
+			qualifier = prefix
+			s = strings.TrimPrefix(s, prefix)
+		}
+	}
+
+	if strings.HasSuffix(s, ifExistsSuffix) {
+		ifExists = true
+		s = strings.TrimSuffix(s, ifExistsSuffix)
+	}
+
+	return qualifier, name(s), ifExists
+}
+
 // IsValid - checks if name is valid or not.
 func (n name) IsValid() bool {
-	switch n {
-	case stringEquals, stringNotEquals, stringLike, stringNotLike, ipAddress, notIPAddress, null:
+	qualifier, base, ifExists := n.split()
+	switch base {
+	case null:
+		// Null checks presence of key, it takes neither set qualifier nor IfExists.
+		return qualifier == "" && !ifExists
+	case stringEquals, stringNotEquals, stringEqualsIgnoreCase, stringNotEqualsIgnoreCase:
+		fallthrough
+	case stringLike, stringNotLike, ipAddress, notIPAddress, boolean:
+		fallthrough
+	case numericEquals, numericNotEquals, numericLessThan, numericLessThanEquals:
+		fallthrough
+	case numericGreaterThan, numericGreaterThanEquals:
+		fallthrough
+	case dateEquals, dateNotEquals, dateLessThan, dateLessThanEquals:
+		fallthrough
+	case dateGreaterThan, dateGreaterThanEquals:
+		fallthrough
+	case arnEquals, arnNotEquals, arnLike, arnNotLike:
 		return true
 	}
 
@@ -0,0 +1,116 @@
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// numericFunc - Numeric functions. It checks whether value by Key in given values
+// map compares to one of condition values as required by name, e.g. s3:max-keys.
+// For example,
+//   - if name = "NumericLessThanEquals" and values = [100], at evaluate() it returns
+//     whether number in value map for Key is less than or equal to 100.
+//
+// NumericNotEquals evaluates to true if the number equals to none of values.
+type numericFunc struct {
+	n      name
+	k      Key
+	values []float64
+}
+
+func (f numericFunc) compare(requestValue, value float64) bool {
+	switch f.n {
+	case numericLessThan:
+		return requestValue < value
+	case numericLessThanEquals:
+		return requestValue <= value
+	case numericGreaterThan:
+		return requestValue > value
+	case numericGreaterThanEquals:
+		return requestValue >= value
+	}
+
+	return requestValue == value
+}
+
+// evaluate() - evaluates to check whether number by Key in given values compares
+// to one of condition values. Values not a number never match.
+func (f numericFunc) evaluate(values map[string][]string) bool {
+	matched := false
+	for _, s := range values[f.k.Name()] {
+		requestValue, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			continue
+		}
+
+		for _, value := range f.values {
+			if f.compare(requestValue, value) {
+				matched = true
+			}
+		}
+	}
+
+	if f.n == numericNotEquals {
+		return !matched
+	}
+
+	return matched
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f numericFunc) key() Key {
+	return f.k
+}
+
+// name() - returns condition name of this function.
+func (f numericFunc) name() name {
+	return f.n
+}
+
+func (f numericFunc) String() string {
+	values := append([]float64{}, f.values...)
+	sort.Float64s(values)
+
+	return fmt.Sprintf("%v:%v:%v", f.n, f.k, values)
+}
+
+// toMap - returns map representation of this function.
+func (f numericFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values {
+		values.Add(NewStringValue(strconv.FormatFloat(value, 'f', -1, 64)))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// newNumericFunc - returns new Numeric function of name n.
+func newNumericFunc(n name, key Key, values ValueSet) (Function, error) {
+	numbers := []float64{}
+	for v := range values {
+		switch v.GetType() {
+		case reflect.Int:
+			i, _ := v.GetInt()
+			numbers = append(numbers, float64(i))
+		case reflect.String:
+			s, _ := v.GetString()
+			number, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("value %v must be a number for %v condition", s, n)
+			}
+			numbers = append(numbers, number)
+		default:
+			return nil, fmt.Errorf("value %v must be a number for %v condition", v, n)
+		}
+	}
+
+	return &numericFunc{n, key, numbers}, nil
+}
@@ -0,0 +1,46 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestNumericFunc(t *testing.T) {
+	ten := map[string][]string{"max-keys": {"10"}}
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		{`{"NumericEquals":{"s3:max-keys":"10"}}`, ten, true},
+		{`{"NumericEquals":{"s3:max-keys":["5","10"]}}`, ten, true},
+		{`{"NumericEquals":{"s3:max-keys":"10.0"}}`, ten, true},
+		{`{"NumericEquals":{"s3:max-keys":"5"}}`, ten, false},
+		{`{"NumericEquals":{"s3:max-keys":"10"}}`, missing, false},
+		{`{"NumericEquals":{"s3:max-keys":"10"}}`, map[string][]string{"max-keys": {"ten"}}, false},
+		{`{"NumericNotEquals":{"s3:max-keys":"5"}}`, ten, true},
+		{`{"NumericNotEquals":{"s3:max-keys":["5","10"]}}`, ten, false},
+		{`{"NumericNotEquals":{"s3:max-keys":"5"}}`, missing, true},
+		{`{"NumericLessThan":{"s3:max-keys":"11"}}`, ten, true},
+		{`{"NumericLessThan":{"s3:max-keys":"10"}}`, ten, false},
+		{`{"NumericLessThan":{"s3:max-keys":"11"}}`, missing, false},
+		{`{"NumericLessThanEquals":{"s3:max-keys":"10"}}`, ten, true},
+		{`{"NumericLessThanEquals":{"s3:max-keys":"9"}}`, ten, false},
+		{`{"NumericGreaterThan":{"s3:max-keys":"9"}}`, ten, true},
+		{`{"NumericGreaterThan":{"s3:max-keys":"10"}}`, ten, false},
+		{`{"NumericGreaterThanEquals":{"s3:max-keys":"10"}}`, ten, true},
+		{`{"NumericGreaterThanEquals":{"s3:max-keys":"11"}}`, ten, false},
+		{`{"NumericLessThanEquals":{"s3:max-keys":100}}`, ten, true},
+		{`{"NumericLessThanIfExists":{"s3:max-keys":"5"}}`, missing, true},
+		{`{"NumericLessThanIfExists":{"s3:max-keys":"5"}}`, ten, false},
+		{`{"ForAnyValue:NumericLessThan":{"s3:max-keys":"5"}}`,
+			map[string][]string{"max-keys": {"10", "1"}}, true},
+		{`{"ForAnyValue:NumericLessThan":{"s3:max-keys":"5"}}`, missing, false},
+		{`{"ForAllValues:NumericLessThan":{"s3:max-keys":"5"}}`,
+			map[string][]string{"max-keys": {"10", "1"}}, false},
+		{`{"ForAllValues:NumericLessThan":{"s3:max-keys":"11"}}`,
+			map[string][]string{"max-keys": {"10", "1"}}, true},
+		{`{"ForAllValues:NumericLessThan":{"s3:max-keys":"5"}}`, missing, true},
+	})
+	testInvalid(t, []string{
+		`{"NumericEquals":{"s3:max-keys":"ten"}}`,
+		`{"NumericEquals":{"s3:max-keys":true}}`,
+		`{"NumericEqual":{"s3:max-keys":"10"}}`,
+	})
+}
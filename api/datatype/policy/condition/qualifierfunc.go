@@ -0,0 +1,80 @@
+package condition
+
+import (
+	"strings"
+)
+
+// qualifiedString - returns string representation of wrapped function f with
+// its name replaced by n.
+func qualifiedString(n name, f Function) string {
+	return string(n) + strings.TrimPrefix(f.String(), string(f.name()))
+}
+
+// setQualifierFunc - ForAnyValue or ForAllValues function. It evaluates wrapped
+// function against each one of values by Key in given values map.
+// For example,
+//   - if name = "ForAllValues:StringEquals" and values = ["a", "b"], at evaluate()
+//     it returns whether every value in value map for Key is "a" or "b".
+type setQualifierFunc struct {
+	Function
+	n   name
+	all bool // ForAllValues if true, ForAnyValue otherwise
+}
+
+// evaluate() - evaluates wrapped function against each one of values by Key.
+// ForAnyValue evaluates to false and ForAllValues evaluates to true if Key is absent.
+func (f setQualifierFunc) evaluate(values map[string][]string) bool {
+	keyName := f.key().Name()
+	// values of other keys are kept for policy variables
+	singleValues := make(map[string][]string, len(values))
+	for k, v := range values {
+		singleValues[k] = v
+	}
+	for _, v := range values[keyName] {
+		singleValues[keyName] = []string{v}
+		matched := f.Function.evaluate(singleValues)
+		if matched != f.all {
+			return matched
+		}
+	}
+
+	return f.all
+}
+
+// name() - returns condition name with set qualifier.
+func (f setQualifierFunc) name() name {
+	return f.n
+}
+
+func (f setQualifierFunc) String() string {
+	return qualifiedString(f.n, f.Function)
+}
+
+// ifExistsFunc - IfExists function. It evaluates wrapped function only if Key
+// is present in given values map.
+// For example,
+//   - if name = "StringEqualsIfExists" and values = ["a"], at evaluate() it returns
+//     whether value in value map for Key is "a", or true if there is no value for Key.
+type ifExistsFunc struct {
+	Function
+	n name
+}
+
+// evaluate() - evaluates to true if Key is absent in given values, otherwise
+// evaluates wrapped function.
+func (f ifExistsFunc) evaluate(values map[string][]string) bool {
+	if len(values[f.key().Name()]) == 0 {
+		return true
+	}
+
+	return f.Function.evaluate(values)
+}
+
+// name() - returns condition name ending with IfExists.
+func (f ifExistsFunc) name() name {
+	return f.n
+}
+
+func (f ifExistsFunc) String() string {
+	return qualifiedString(f.n, f.Function)
+}
@@ -0,0 +1,62 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestSetQualifierFunc(t *testing.T) {
+	tags := map[string][]string{"x-amz-storage-class": {"STANDARD", "GLACIER"}, "userid": {"hehe"}}
+	single := map[string][]string{"x-amz-storage-class": {"STANDARD"}}
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		// without qualifier, any one of values matching is enough
+		{`{"StringEquals":{"s3:x-amz-storage-class":"GLACIER"}}`, tags, true},
+		{`{"ForAnyValue:StringEquals":{"s3:x-amz-storage-class":"GLACIER"}}`, tags, true},
+		{`{"ForAnyValue:StringEquals":{"s3:x-amz-storage-class":"COLD"}}`, tags, false},
+		{`{"ForAnyValue:StringEquals":{"s3:x-amz-storage-class":"COLD"}}`, missing, false},
+		{`{"ForAllValues:StringEquals":{"s3:x-amz-storage-class":"STANDARD"}}`, tags, false},
+		{`{"ForAllValues:StringEquals":{"s3:x-amz-storage-class":"STANDARD"}}`, single, true},
+		{`{"ForAllValues:StringEquals":{"s3:x-amz-storage-class":["STANDARD","GLACIER"]}}`, tags, true},
+		{`{"ForAllValues:StringEquals":{"s3:x-amz-storage-class":"STANDARD"}}`, missing, true},
+		{`{"ForAllValues:StringLike":{"s3:x-amz-storage-class":"*A*"}}`, tags, true},
+		{`{"ForAnyValue:StringNotEquals":{"s3:x-amz-storage-class":"STANDARD"}}`, tags, true},
+		{`{"ForAllValues:StringNotEquals":{"s3:x-amz-storage-class":"COLD"}}`, tags, true},
+		{`{"ForAllValues:StringNotEquals":{"s3:x-amz-storage-class":"GLACIER"}}`, tags, false},
+		// policy variables of other keys are kept while evaluating each value
+		{`{"ForAnyValue:StringLike":{"s3:x-amz-storage-class":"${aws:userid}"}}`,
+			map[string][]string{"x-amz-storage-class": {"STANDARD", "hehe"}, "userid": {"hehe"}}, true},
+		{`{"ForAllValues:StringLike":{"s3:x-amz-storage-class":["${aws:userid}","STANDARD"]}}`,
+			map[string][]string{"x-amz-storage-class": {"STANDARD", "hehe"}, "userid": {"hehe"}}, true},
+		{`{"ForAnyValue:StringEqualsIfExists":{"s3:x-amz-storage-class":"COLD"}}`, missing, true},
+		{`{"ForAnyValue:StringEqualsIfExists":{"s3:x-amz-storage-class":"COLD"}}`, tags, false},
+		{`{"ForAllValues:StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"}}`, tags, false},
+	})
+	testInvalid(t, []string{
+		`{"ForAnyValue:Null":{"s3:x-amz-storage-class":"true"}}`,
+		`{"NullIfExists":{"s3:x-amz-storage-class":"true"}}`,
+		`{"ForEachValue:StringEquals":{"s3:x-amz-storage-class":"STANDARD"}}`,
+		`{"StringEqualsIfExist":{"s3:x-amz-storage-class":"STANDARD"}}`,
+	})
+}
+
+func TestIfExistsFunc(t *testing.T) {
+	missing := map[string][]string{}
+	empty := map[string][]string{"x-amz-storage-class": {}}
+	testEvaluate(t, []evaluateCase{
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"}}`, missing, true},
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"}}`, empty, true},
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"}}`,
+			map[string][]string{"x-amz-storage-class": {"STANDARD"}}, true},
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"}}`,
+			map[string][]string{"x-amz-storage-class": {"GLACIER"}}, false},
+		{`{"StringNotLikeIfExists":{"s3:x-amz-storage-class":"GLACIER"}}`, missing, true},
+		{`{"IpAddressIfExists":{"aws:SourceIp":"10.0.0.0/8"}}`, missing, true},
+		{`{"IpAddressIfExists":{"aws:SourceIp":"10.0.0.0/8"}}`,
+			map[string][]string{"SourceIp": {"192.168.0.1"}}, false},
+		// all conditions have to be true
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"},` +
+			`"NumericLessThan":{"s3:max-keys":"10"}}`, missing, false},
+		{`{"StringEqualsIfExists":{"s3:x-amz-storage-class":"STANDARD"},` +
+			`"NumericLessThan":{"s3:max-keys":"10"}}`, map[string][]string{"max-keys": {"1"}}, true},
+	})
+}
@@ -45,7 +45,7 @@ type stringEqualsFunc struct {
 // condition values.
 func (f stringEqualsFunc) evaluate(values map[string][]string) bool {
 	requestValue := values[f.k.Name()]
-	return !f.values.Intersection(utils.CreateStringSet(requestValue...)).IsEmpty()
+	return !substituteVariables(f.values, values).Intersection(utils.CreateStringSet(requestValue...)).IsEmpty()
 }
 
 // key() - returns condition key which is used by this condition function.
@@ -0,0 +1,105 @@
+package condition
+
+import (
+	"strings"
+
+	"github.com/journeymidnight/yig/api/datatype/policy/utils"
+)
+
+// stringEqualsIgnoreCaseFunc - String equals ignoring case function. It checks whether
+// value by Key in given values map is in condition values, ignoring case.
+// For example,
+//   - if values = ["MyBucket/Foo"], at evaluate() it returns whether string
+//     in value map for Key is in values, "mybucket/foo" is matched.
+type stringEqualsIgnoreCaseFunc struct {
+	k      Key
+	values utils.StringSet
+}
+
+// evaluate() - evaluates to check whether value by Key in given values is in
+// condition values, ignoring case.
+func (f stringEqualsIgnoreCaseFunc) evaluate(values map[string][]string) bool {
+	conditionValues := substituteVariables(f.values, values)
+	for _, v := range values[f.k.Name()] {
+		for s := range conditionValues {
+			if strings.EqualFold(s, v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f stringEqualsIgnoreCaseFunc) key() Key {
+	return f.k
+}
+
+// name() - returns "StringEqualsIgnoreCase" condition name.
+func (f stringEqualsIgnoreCaseFunc) name() name {
+	return stringEqualsIgnoreCase
+}
+
+func (f stringEqualsIgnoreCaseFunc) String() string {
+	return toStringEqualsFuncString(stringEqualsIgnoreCase, f.k, f.values)
+}
+
+// toMap - returns map representation of this function.
+func (f stringEqualsIgnoreCaseFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values.ToSlice() {
+		values.Add(NewStringValue(value))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// stringNotEqualsIgnoreCaseFunc - String not equals ignoring case function. It checks
+// whether value by Key in given values is NOT in condition values, ignoring case.
+type stringNotEqualsIgnoreCaseFunc struct {
+	stringEqualsIgnoreCaseFunc
+}
+
+// evaluate() - evaluates to check whether value by Key in given values is NOT in
+// condition values, ignoring case.
+func (f stringNotEqualsIgnoreCaseFunc) evaluate(values map[string][]string) bool {
+	return !f.stringEqualsIgnoreCaseFunc.evaluate(values)
+}
+
+// name() - returns "StringNotEqualsIgnoreCase" condition name.
+func (f stringNotEqualsIgnoreCaseFunc) name() name {
+	return stringNotEqualsIgnoreCase
+}
+
+func (f stringNotEqualsIgnoreCaseFunc) String() string {
+	return toStringEqualsFuncString(stringNotEqualsIgnoreCase, f.k, f.values)
+}
+
+// newStringEqualsIgnoreCaseFunc - returns new StringEqualsIgnoreCase function.
+func newStringEqualsIgnoreCaseFunc(key Key, values ValueSet) (Function, error) {
+	valueStrings, err := valuesToStringSlice(stringEqualsIgnoreCase, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stringEqualsIgnoreCaseFunc{key, utils.CreateStringSet(valueStrings...)}, nil
+}
+
+// newStringNotEqualsIgnoreCaseFunc - returns new StringNotEqualsIgnoreCase function.
+func newStringNotEqualsIgnoreCaseFunc(key Key, values ValueSet) (Function, error) {
+	valueStrings, err := valuesToStringSlice(stringNotEqualsIgnoreCase, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stringNotEqualsIgnoreCaseFunc{
+		stringEqualsIgnoreCaseFunc{key, utils.CreateStringSet(valueStrings...)},
+	}, nil
+}
@@ -0,0 +1,40 @@
+package condition
+
+import (
+	"testing"
+)
+
+func TestStringEqualsIgnoreCaseFunc(t *testing.T) {
+	prefix := map[string][]string{"prefix": {"MyBucket/Foo"}, "username": {"Hehe"}}
+	missing := map[string][]string{}
+	testEvaluate(t, []evaluateCase{
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/foo"}}`, prefix, true},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":["bar","MYBUCKET/FOO"]}}`, prefix, true},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/foo/"}}`, prefix, false},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/*"}}`, prefix, false},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/foo"}}`, missing, false},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/${aws:username}"}}`,
+			map[string][]string{"prefix": {"mybucket/HEHE"}, "username": {"hehe"}}, true},
+		{`{"StringEqualsIgnoreCase":{"s3:prefix":"mybucket/${aws:username}"}}`,
+			map[string][]string{"prefix": {"mybucket/"}}, false},
+		{`{"StringNotEqualsIgnoreCase":{"s3:prefix":"bar"}}`, prefix, true},
+		{`{"StringNotEqualsIgnoreCase":{"s3:prefix":"MYBUCKET/foo"}}`, prefix, false},
+		{`{"StringNotEqualsIgnoreCase":{"s3:prefix":"bar"}}`, missing, true},
+		{`{"StringEqualsIgnoreCaseIfExists":{"s3:prefix":"bar"}}`, missing, true},
+		{`{"StringEqualsIgnoreCaseIfExists":{"s3:prefix":"bar"}}`, prefix, false},
+		{`{"StringNotEqualsIgnoreCaseIfExists":{"s3:prefix":"mybucket/foo"}}`, missing, true},
+		{`{"StringNotEqualsIgnoreCaseIfExists":{"s3:prefix":"mybucket/foo"}}`, prefix, false},
+		{`{"ForAnyValue:StringEqualsIgnoreCase":{"s3:prefix":"A"}}`,
+			map[string][]string{"prefix": {"a", "b"}}, true},
+		{`{"ForAnyValue:StringEqualsIgnoreCase":{"s3:prefix":"A"}}`, missing, false},
+		{`{"ForAllValues:StringEqualsIgnoreCase":{"s3:prefix":"A"}}`,
+			map[string][]string{"prefix": {"a", "b"}}, false},
+		{`{"ForAllValues:StringEqualsIgnoreCase":{"s3:prefix":["A","B"]}}`,
+			map[string][]string{"prefix": {"a", "b"}}, true},
+		{`{"ForAllValues:StringEqualsIgnoreCase":{"s3:prefix":"A"}}`, missing, true},
+	})
+	testInvalid(t, []string{
+		`{"StringEqualsIgnoreCase":{"s3:prefix":1}}`,
+		`{"StringEqualsIgnoreCase":{"s3:unknown":"a"}}`,
+	})
+}
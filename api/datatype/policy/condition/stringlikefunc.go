@@ -44,8 +44,9 @@ type stringLikeFunc struct {
 // evaluate() - evaluates to check whether value by Key in given values is wildcard
 // matching in condition values.
 func (f stringLikeFunc) evaluate(values map[string][]string) bool {
+	patterns := substitutePatterns(f.values, values)
 	for _, v := range values[f.k.Name()] {
-		if !f.values.FuncMatch(utils.Match, v).IsEmpty() {
+		if !patterns.FuncMatch(utils.MatchEscaped, v).IsEmpty() {
 			return true
 		}
 	}
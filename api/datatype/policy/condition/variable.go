@@ -0,0 +1,102 @@
+package condition
+
+import (
+	"strings"
+
+	"github.com/journeymidnight/yig/api/datatype/policy/utils"
+)
+
+// policyVariables - keys which could be referred as ${key} in resources and
+// condition values of policies.
+var policyVariables = NewKeySet(AWSUserID, AWSUsername)
+
+// specialCharacters - ${*}, ${?} and ${$} stand for literal '*', '?' and '$'.
+var specialCharacters = map[string]string{"*": "*", "?": "?", "$": "$"}
+
+// SubstituteVariables - replaces policy variables in s, e.g. ${aws:userid}, by
+// their values in given values map. Returns false if a variable referred has
+// no value, e.g. ${aws:userid} of anonymous requests, or is unknown, s matches
+// nothing then.
+func SubstituteVariables(s string, values map[string][]string) (string, bool) {
+	return substitute(s, values, identity, identity)
+}
+
+// SubstitutePattern - like SubstituteVariables for wildcard patterns, which are
+// matched by utils.MatchEscaped then. Escaped characters and values of variables
+// are matched literally, e.g. "${*}" matches '*' only.
+func SubstitutePattern(s string, values map[string][]string) (string, bool) {
+	return substitute(s, values, utils.EscapeBackslashes, utils.EscapeWildcards)
+}
+
+func identity(s string) string {
+	return s
+}
+
+// substitute - replaces policy variables and escaped characters in s, text of s
+// is passed through quoteText and values replacing them through quoteValue.
+func substitute(s string, values map[string][]string,
+	quoteText, quoteValue func(string) string) (string, bool) {
+
+	if !strings.Contains(s, "${") {
+		return quoteText(s), true
+	}
+
+	var substituted strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start == -1 {
+			substituted.WriteString(quoteText(s))
+			break
+		}
+		substituted.WriteString(quoteText(s[:start]))
+		s = s[start:]
+
+		end := strings.Index(s, "}")
+		if end == -1 {
+			return "", false
+		}
+		variable := s[len("${"):end]
+		s = s[end+1:]
+
+		if character, ok := specialCharacters[variable]; ok {
+			substituted.WriteString(quoteValue(character))
+			continue
+		}
+		if !policyVariables.Contains(Key(variable)) {
+			return "", false
+		}
+		value := values[Key(variable).Name()]
+		if len(value) == 0 {
+			return "", false
+		}
+		substituted.WriteString(quoteValue(value[0]))
+	}
+
+	return substituted.String(), true
+}
+
+// substituteVariables - returns condition values with policy variables replaced,
+// values referring to variables without value are dropped.
+func substituteVariables(set utils.StringSet, values map[string][]string) utils.StringSet {
+	nset := utils.NewStringSet()
+	for s := range set {
+		if substituted, ok := SubstituteVariables(s, values); ok {
+			nset.Add(substituted)
+		}
+	}
+
+	return nset
+}
+
+// substitutePatterns - returns wildcard patterns with policy variables replaced,
+// patterns referring to variables without value are dropped.
+func substitutePatterns(set utils.StringSet, values map[string][]string) utils.StringSet {
+	nset := utils.NewStringSet()
+	for s := range set {
+		if substituted, ok := SubstitutePattern(s, values); ok {
+			nset.Add(substituted)
+		}
+	}
+
+	return nset
+}
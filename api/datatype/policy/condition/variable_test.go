@@ -0,0 +1,79 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/journeymidnight/yig/api/datatype/policy/utils"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	values := map[string][]string{"userid": {"hehe"}, "username": {"Hehe"}, "Referer": {"haha"}}
+	for _, c := range []struct {
+		s           string
+		values      map[string][]string
+		substituted string
+		ok          bool
+	}{
+		{"home/a", values, "home/a", true},
+		{"home/${aws:userid}/*", values, "home/hehe/*", true},
+		{"${aws:userid}/${aws:username}/${aws:userid}", values, "hehe/Hehe/hehe", true},
+		{"home/${aws:userid}/*", map[string][]string{}, "", false},
+		{"home/${aws:userid}/*", map[string][]string{"userid": {}}, "", false},
+		// escaped characters
+		{"${*}${?}${$}", values, "*?$", true},
+		{"home/${$}{aws:userid}", values, "home/${aws:userid}", true},
+		{"${$}{${aws:userid}}", values, "${hehe}", true},
+		{"home/$/{aws:userid}", values, "home/$/{aws:userid}", true},
+		// unknown variables have no value
+		{"home/${aws:foo}", values, "", false},
+		{"home/${aws:Referer}", values, "", false},
+		{"home/${Referer}", values, "", false},
+		{"home/${}", values, "", false},
+		{"home/${aws:userid", values, "", false},
+	} {
+		substituted, ok := SubstituteVariables(c.s, c.values)
+		if substituted != c.substituted || ok != c.ok {
+			t.Fatal("SubstituteVariables", c.s, c.values, ":", substituted, ok)
+		}
+	}
+}
+
+// escaped characters and values of variables are never wildcards in patterns
+func TestSubstitutePattern(t *testing.T) {
+	values := map[string][]string{"userid": {"he*"}, "username": {`he\?`}}
+	for _, c := range []struct {
+		pattern string
+		name    string
+		matched bool
+	}{
+		{"home/*", "home/a", true},
+		{"home/?", "home/a", true},
+		{`home\*`, `home\a`, true},
+		{`home\*`, "home*", false},
+		{"home/${*}", "home/*", true},
+		{"home/${*}", "home/a", false},
+		{"home/${?}", "home/?", true},
+		{"home/${?}", "home/a", false},
+		{"home/${aws:userid}/*", "home/he*/a", true},
+		{"home/${aws:userid}/*", "home/hehe/a", false},
+		{"home/${aws:username}", `home/he\?`, true},
+		{"home/${aws:username}", `home/he\a`, false},
+		{"home/${aws:foo}", "home/${aws:foo}", false},
+	} {
+		pattern, ok := SubstitutePattern(c.pattern, values)
+		if (ok && utils.MatchEscaped(pattern, c.name)) != c.matched {
+			t.Fatal("SubstitutePattern", c.pattern, "matching", c.name, ":", pattern, ok)
+		}
+	}
+
+	testEvaluate(t, []evaluateCase{
+		{`{"StringLike":{"s3:prefix":"home/${aws:userid}/*"}}`,
+			map[string][]string{"prefix": {"home/he*/a"}, "userid": {"he*"}}, true},
+		{`{"StringLike":{"s3:prefix":"home/${aws:userid}/*"}}`,
+			map[string][]string{"prefix": {"home/hehe/a"}, "userid": {"he*"}}, false},
+		{`{"StringLike":{"s3:prefix":"${*}/*"}}`, map[string][]string{"prefix": {"*/a"}}, true},
+		{`{"StringLike":{"s3:prefix":"${*}/*"}}`, map[string][]string{"prefix": {"a/a"}}, false},
+		{`{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::${aws:userid}:root"}}`,
+			map[string][]string{"PrincipalArn": {"arn:aws:iam::hehe:root"}, "userid": {"he*"}}, false},
+	})
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"github.com/journeymidnight/yig/api/datatype/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -28,6 +30,8 @@ const DefaultVersion = "2012-10-17"
 // Args - arguments to policy to check whether it is allowed
 type Args struct {
 	AccountName     string              `json:"account"`
+	UserName        string              `json:"username"`
+	PrincipalArn    string              `json:"principalarn"`
 	Action          Action              `json:"action"`
 	BucketName      string              `json:"bucket"`
 	ConditionValues map[string][]string `json:"conditions"`
@@ -35,6 +39,35 @@ type Args struct {
 	ObjectName      string              `json:"object"`
 }
 
+// conditionValues - returns ConditionValues with requester, which are values of
+// keys aws:userid, aws:username and aws:PrincipalArn, and policy variables
+// ${aws:userid} and ${aws:username}. They're absent for anonymous requests.
+func (args Args) conditionValues() map[string][]string {
+	values := make(map[string][]string, len(args.ConditionValues)+3)
+	for key, value := range args.ConditionValues {
+		values[key] = value
+	}
+
+	userIDKey := condition.Key(condition.AWSUserID).Name()
+	usernameKey := condition.Key(condition.AWSUsername).Name()
+	principalArnKey := condition.Key(condition.AWSPrincipalArn).Name()
+	// never taken from request headers or query parameters
+	delete(values, userIDKey)
+	delete(values, usernameKey)
+	delete(values, principalArnKey)
+	if args.AccountName != "" {
+		values[userIDKey] = []string{args.AccountName}
+	}
+	if args.UserName != "" {
+		values[usernameKey] = []string{args.UserName}
+	}
+	if args.PrincipalArn != "" {
+		values[principalArnKey] = []string{args.PrincipalArn}
+	}
+
+	return values
+}
+
 // Policy - bucket policy.
 type Policy struct {
 	ID         ID `json:"ID,omitempty"`
@@ -79,6 +112,17 @@ func (policy Policy) IsAllowed(args Args) IsPolicyAllowedResult {
 	return NoPolicy
 }
 
+// IsPublic - returns whether policy grants access to everyone by any statement.
+func (policy Policy) IsPublic() bool {
+	for _, statement := range policy.Statements {
+		if statement.isPublic() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (policy Policy) IsEmpty() bool {
 	return len(policy.Statements) == 0
@@ -183,3 +227,47 @@ func ParseConfig(reader io.Reader, bucketName string) (*Policy, error) {
 	err := policy.Validate(bucketName)
 	return &policy, err
 }
+
+// ParseIdentityPolicy - parses data in given reader to identity-based policy,
+// i.e. policy of a user or access key, or session policy of temporary
+// credentials. Statements of identity-based policies have no Principal,
+// they apply to the identity they're attached to only.
+func ParseIdentityPolicy(reader io.Reader) (*Policy, error) {
+	var sp struct {
+		ID         ID `json:"ID,omitempty"`
+		Version    string
+		Statements []map[string]json.RawMessage `json:"Statement"`
+	}
+
+	decoder := json.NewDecoder(reader)
+	if err := decoder.Decode(&sp); err != nil {
+		return nil, err
+	}
+
+	policy := Policy{
+		ID:      sp.ID,
+		Version: sp.Version,
+	}
+	for _, s := range sp.Statements {
+		for _, element := range []string{"Principal", "NotPrincipal"} {
+			if _, ok := s[element]; ok {
+				return nil, fmt.Errorf("%s is not allowed in identity-based policy", element)
+			}
+		}
+		s["Principal"] = json.RawMessage(`"*"`)
+		data, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		var statement Statement
+		if err := json.Unmarshal(data, &statement); err != nil {
+			return nil, err
+		}
+		policy.Statements = append(policy.Statements, statement)
+	}
+
+	if err := policy.isValid(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/journeymidnight/yig/api/datatype/policy/condition"
 	"github.com/journeymidnight/yig/api/datatype/policy/utils"
 )
 
@@ -46,9 +47,15 @@ func (r Resource) IsValid() bool {
 	return r.BucketName != "" && r.Pattern != ""
 }
 
-// Match - matches object name with resource pattern.
-func (r Resource) Match(resource string) bool {
-	return utils.Match(r.Pattern, resource)
+// Match - matches object name with resource pattern, policy variables in pattern
+// are replaced by their values in conditionValues.
+func (r Resource) Match(resource string, conditionValues map[string][]string) bool {
+	pattern, ok := condition.SubstitutePattern(r.Pattern, conditionValues)
+	if !ok {
+		return false
+	}
+
+	return utils.MatchEscaped(pattern, resource)
 }
 
 // MarshalJSON - encodes Resource to JSON data.
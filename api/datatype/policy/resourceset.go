@@ -79,10 +79,11 @@ func (resourceSet ResourceSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(resources)
 }
 
-// Match - matches object name with anyone of resource pattern in resource set.
-func (resourceSet ResourceSet) Match(resource string) bool {
+// Match - matches object name with anyone of resource pattern in resource set,
+// policy variables in patterns are replaced by their values in conditionValues.
+func (resourceSet ResourceSet) Match(resource string, conditionValues map[string][]string) bool {
 	for r := range resourceSet {
-		if r.Match(resource) {
+		if r.Match(resource, conditionValues) {
 			return true
 		}
 	}
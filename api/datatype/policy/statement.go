@@ -24,24 +24,37 @@ import (
 	"github.com/journeymidnight/yig/api/datatype/policy/condition"
 )
 
-// Statement - policy statement.
+// Statement - policy statement. Only one of Principal and NotPrincipal, Action and
+// NotAction, Resource and NotResource is set, NotPrincipal, NotAction and NotResource
+// match everything except those listed.
 type Statement struct {
-	SID        ID                  `json:"Sid,omitempty"`
-	Effect     Effect              `json:"Effect"`
-	Principal  Principal           `json:"Principal"`
-	Actions    ActionSet           `json:"Action"`
-	Resources  ResourceSet         `json:"Resource"`
-	Conditions condition.Functions `json:"Condition,omitempty"`
+	SID          ID                  `json:"Sid,omitempty"`
+	Effect       Effect              `json:"Effect"`
+	Principal    Principal           `json:"Principal"`
+	NotPrincipal Principal           `json:"NotPrincipal"`
+	Actions      ActionSet           `json:"Action"`
+	NotActions   ActionSet           `json:"NotAction"`
+	Resources    ResourceSet         `json:"Resource"`
+	NotResources ResourceSet         `json:"NotResource"`
+	Conditions   condition.Functions `json:"Condition,omitempty"`
 }
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (statement Statement) IsAllowed(args Args) bool {
 	check := func() bool {
-		if !statement.Principal.Match(args.AccountName) {
+		if statement.NotPrincipal.IsValid() {
+			if statement.NotPrincipal.Match(args.AccountName) {
+				return false
+			}
+		} else if !statement.Principal.Match(args.AccountName) {
 			return false
 		}
 
-		if !statement.Actions.Contains(args.Action) {
+		if len(statement.NotActions) != 0 {
+			if statement.NotActions.Contains(args.Action) {
+				return false
+			}
+		} else if !statement.Actions.Contains(args.Action) {
 			return false
 		}
 
@@ -54,49 +67,78 @@ func (statement Statement) IsAllowed(args Args) bool {
 			resource += args.ObjectName
 		}
 
-		if !statement.Resources.Match(resource) {
+		conditionValues := args.conditionValues()
+		if len(statement.NotResources) != 0 {
+			if statement.NotResources.Match(resource, conditionValues) {
+				return false
+			}
+		} else if !statement.Resources.Match(resource, conditionValues) {
 			return false
 		}
 
-		return statement.Conditions.Evaluate(args.ConditionValues)
+		return statement.Conditions.Evaluate(conditionValues)
 	}
 
 	return statement.Effect.IsAllowed(check())
 }
 
+// isPublic - returns whether statement allows everyone, i.e. anonymous users,
+// without limiting source networks or users by conditions.
+func (statement Statement) isPublic() bool {
+	if statement.Effect != Allow {
+		return false
+	}
+	if !statement.NotPrincipal.IsValid() && !statement.Principal.AWS.Contains("*") {
+		return false
+	}
+
+	return !statement.Conditions.IsRestricting()
+}
+
 // isValid - checks whether statement is valid or not.
 func (statement Statement) isValid() error {
 	if !statement.Effect.IsValid() {
 		return fmt.Errorf("invalid Effect %v", statement.Effect)
 	}
 
-	if !statement.Principal.IsValid() {
-		return fmt.Errorf("invalid Principal %v", statement.Principal)
+	if statement.Principal.IsValid() == statement.NotPrincipal.IsValid() {
+		return fmt.Errorf("one of Principal and NotPrincipal must be set")
+	}
+
+	if (len(statement.Actions) == 0) == (len(statement.NotActions) == 0) {
+		return fmt.Errorf("one of Action and NotAction must be set")
 	}
 
-	if len(statement.Actions) == 0 {
-		return fmt.Errorf("Action must not be empty")
+	if (len(statement.Resources) == 0) == (len(statement.NotResources) == 0) {
+		return fmt.Errorf("one of Resource and NotResource must be set")
 	}
 
-	if len(statement.Resources) == 0 {
-		return fmt.Errorf("Resource must not be empty")
+	// NotAction applies to all actions but those listed, resources are not
+	// checked against them, condition keys have to be supported by any other
+	for key := range statement.Conditions.Keys() {
+		if len(statement.NotActions) != 0 && !statement.NotActions.isConditionKeySupportedByOthers(key) {
+			return fmt.Errorf("unsupported condition key '%v' used for actions other than '%v'",
+				key, statement.NotActions)
+		}
 	}
 
 	for action := range statement.Actions {
-		if action.isObjectAction() {
-			if !statement.Resources.objectResourceExists() {
-				return fmt.Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
-			}
-		} else {
-			if !statement.Resources.bucketResourceExists() {
-				return fmt.Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+		if len(statement.Resources) != 0 {
+			if action.isObjectAction() {
+				if !statement.Resources.objectResourceExists() {
+					return fmt.Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+				}
+			} else {
+				if !statement.Resources.bucketResourceExists() {
+					return fmt.Errorf("unsupported Resource found %v for action %v", statement.Resources, action)
+				}
 			}
 		}
 
-		keys := statement.Conditions.Keys()
-		keyDiff := keys.Difference(actionConditionKeyMap[action])
-		if !keyDiff.IsEmpty() {
-			return fmt.Errorf("unsupported condition keys '%v' used for action '%v'", keyDiff, action)
+		for key := range statement.Conditions.Keys() {
+			if !action.isConditionKeySupported(key) {
+				return fmt.Errorf("unsupported condition key '%v' used for action '%v'", key, action)
+			}
 		}
 	}
 
@@ -109,9 +151,32 @@ func (statement Statement) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
-	// subtype to avoid recursive call to MarshalJSON()
-	type subStatement Statement
-	ss := subStatement(statement)
+	// subtype to avoid recursive call to MarshalJSON(), and to omit elements not set
+	type subStatement struct {
+		SID          ID                  `json:"Sid,omitempty"`
+		Effect       Effect              `json:"Effect"`
+		Principal    *Principal          `json:"Principal,omitempty"`
+		NotPrincipal *Principal          `json:"NotPrincipal,omitempty"`
+		Actions      ActionSet           `json:"Action,omitempty"`
+		NotActions   ActionSet           `json:"NotAction,omitempty"`
+		Resources    ResourceSet         `json:"Resource,omitempty"`
+		NotResources ResourceSet         `json:"NotResource,omitempty"`
+		Conditions   condition.Functions `json:"Condition,omitempty"`
+	}
+	ss := subStatement{
+		SID:          statement.SID,
+		Effect:       statement.Effect,
+		Actions:      statement.Actions,
+		NotActions:   statement.NotActions,
+		Resources:    statement.Resources,
+		NotResources: statement.NotResources,
+		Conditions:   statement.Conditions,
+	}
+	if statement.NotPrincipal.IsValid() {
+		ss.NotPrincipal = &statement.NotPrincipal
+	} else {
+		ss.Principal = &statement.Principal
+	}
 	return json.Marshal(ss)
 }
 
@@ -141,7 +206,11 @@ func (statement Statement) Validate(bucketName string) error {
 		return err
 	}
 
-	return statement.Resources.Validate(bucketName)
+	if err := statement.Resources.Validate(bucketName); err != nil {
+		return err
+	}
+
+	return statement.NotResources.Validate(bucketName)
 }
 
 // NewStatement - creates new statement.
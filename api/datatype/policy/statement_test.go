@@ -0,0 +1,183 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+type isAllowedCase struct {
+	name     string
+	args     Args
+	expected IsPolicyAllowedResult
+}
+
+// testIsAllowed parses bucket policy of bucket "hehe", and checks result of
+// IsAllowed for each case
+func testIsAllowed(t *testing.T, document string, cases []isAllowedCase) {
+	policy, err := ParseConfig(strings.NewReader(document), "hehe")
+	if err != nil {
+		t.Fatal("ParseConfig err:", err, document)
+	}
+	for _, c := range cases {
+		result := policy.IsAllowed(c.args)
+		if result != c.expected {
+			t.Fatal(c.name, "IsAllowed:", result, document)
+		}
+	}
+}
+
+func getObject(accountName, objectName string) Args {
+	return Args{
+		AccountName: accountName,
+		UserName:    accountName,
+		Action:      GetObjectAction,
+		BucketName:  "hehe",
+		ObjectName:  objectName,
+	}
+}
+
+func TestStatementVariables(t *testing.T) {
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/home/${aws:userid}/*"}]}`, []isAllowedCase{
+		{"own folder", getObject("haha", "home/haha/a"), PolicyAllow},
+		{"folder of other user", getObject("haha", "home/hehe/a"), NoPolicy},
+		{"anonymous", getObject("", "home//a"), NoPolicy},
+		{"literal variable", getObject("haha", "home/${aws:userid}/a"), NoPolicy},
+	})
+
+	listArgs := func(accountName, prefix string) Args {
+		return Args{
+			AccountName:     accountName,
+			UserName:        accountName,
+			Action:          ListBucketAction,
+			BucketName:      "hehe",
+			ConditionValues: map[string][]string{"prefix": {prefix}},
+		}
+	}
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:ListBucket","Resource":"arn:aws:s3:::hehe",`+
+		`"Condition":{"StringLike":{"s3:prefix":"home/${aws:username}/*"}}}]}`, []isAllowedCase{
+		{"own folder", listArgs("haha", "home/haha/a"), PolicyAllow},
+		{"folder of other user", listArgs("haha", "home/hehe/a"), NoPolicy},
+		{"anonymous", listArgs("", "home//a"), NoPolicy},
+	})
+
+	// userid and username are never taken from request
+	spoofed := getObject("", "home/haha/a")
+	spoofed.ConditionValues = map[string][]string{"userid": {"haha"}}
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/home/${aws:userid}/*"}]}`, []isAllowedCase{
+		{"spoofed userid", spoofed, NoPolicy},
+	})
+
+	// statements of Deny with variables without value don't match either
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/home/${aws:userid}/*"}]}`, []isAllowedCase{
+		{"own folder", getObject("haha", "home/haha/a"), PolicyDeny},
+		{"anonymous", getObject("", "home//a"), NoPolicy},
+	})
+}
+
+func TestStatementEscapedAndUnknownVariables(t *testing.T) {
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:GetObject","Resource":["arn:aws:s3:::hehe/star${*}","arn:aws:s3:::hehe/question${?}",`+
+		`"arn:aws:s3:::hehe/${$}{aws:userid}"]}]}`, []isAllowedCase{
+		{"literal *", getObject("haha", "star*"), PolicyAllow},
+		{"not wildcard *", getObject("haha", "stars"), NoPolicy},
+		{"literal ?", getObject("haha", "question?"), PolicyAllow},
+		{"not wildcard ?", getObject("haha", "questions"), NoPolicy},
+		{"literal variable", getObject("haha", "${aws:userid}"), PolicyAllow},
+		{"not variable", getObject("haha", "haha"), NoPolicy},
+	})
+
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},`+
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/${aws:foo}/*",`+
+		`"Condition":{"StringEquals":{"aws:Referer":"${aws:Referer}"}}}]}`, []isAllowedCase{
+		{"unknown variable in resource", getObject("haha", "${aws:foo}/a"), NoPolicy},
+		{"unknown variable with request value", Args{
+			AccountName:     "haha",
+			Action:          GetObjectAction,
+			BucketName:      "hehe",
+			ObjectName:      "bar/a",
+			ConditionValues: map[string][]string{"foo": {"bar"}, "Referer": {"${aws:Referer}"}},
+		}, NoPolicy},
+	})
+}
+
+func TestStatementNotPrincipal(t *testing.T) {
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[`+
+		`{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"},`+
+		`{"Effect":"Deny","NotPrincipal":{"AWS":["haha"]},"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"}]}`,
+		[]isAllowedCase{
+			{"excluded principal", getObject("haha", "a"), PolicyAllow},
+			{"other principal", getObject("heihei", "a"), PolicyDeny},
+			{"anonymous", getObject("", "a"), PolicyDeny},
+			{"other action", Args{AccountName: "heihei", Action: PutObjectAction,
+				BucketName: "hehe", ObjectName: "a"}, NoPolicy},
+		})
+
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":["haha"]},`+
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"}]}`, []isAllowedCase{
+		{"excluded principal", getObject("haha", "a"), NoPolicy},
+		{"other principal", getObject("heihei", "a"), PolicyAllow},
+		{"anonymous", getObject("", "a"), PolicyAllow},
+	})
+}
+
+func TestStatementNotAction(t *testing.T) {
+	putObject := Args{AccountName: "haha", Action: PutObjectAction, BucketName: "hehe", ObjectName: "a"}
+	deleteObject := Args{AccountName: "haha", Action: DeleteObjectAction, BucketName: "hehe", ObjectName: "a"}
+
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["haha"]},`+
+		`"NotAction":"s3:DeleteObject","Resource":"arn:aws:s3:::hehe/*"}]}`, []isAllowedCase{
+		{"other action", getObject("haha", "a"), PolicyAllow},
+		{"another action", putObject, PolicyAllow},
+		{"excluded action", deleteObject, NoPolicy},
+		{"other principal", getObject("heihei", "a"), NoPolicy},
+	})
+
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[`+
+		`{"Effect":"Allow","Principal":{"AWS":["haha"]},"Action":["s3:GetObject","s3:PutObject","s3:DeleteObject"],`+
+		`"Resource":"arn:aws:s3:::hehe/*"},`+
+		`{"Effect":"Deny","Principal":{"AWS":["haha"]},"NotAction":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"}]}`,
+		[]isAllowedCase{
+			{"excluded action", getObject("haha", "a"), PolicyAllow},
+			{"other action", putObject, PolicyDeny},
+			{"another action", deleteObject, PolicyDeny},
+		})
+
+	// condition keys have to be supported by any action not excluded
+	for document, valid := range map[string]bool{
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":["haha"]},` +
+			`"NotAction":"s3:GetObject","Resource":"arn:aws:s3:::hehe",` +
+			`"Condition":{"StringLike":{"s3:prefix":"home/*"}}}]}`: true,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":["haha"]},` +
+			`"NotAction":"s3:ListBucket","Resource":"arn:aws:s3:::hehe",` +
+			`"Condition":{"StringLike":{"s3:prefix":"home/*"}}}]}`: false,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":["haha"]},` +
+			`"NotAction":"s3:ListBucket","Resource":"arn:aws:s3:::hehe",` +
+			`"Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}}]}`: true,
+	} {
+		_, err := ParseConfig(strings.NewReader(document), "hehe")
+		if (err == nil) != valid {
+			t.Fatal("ParseConfig:", err, document)
+		}
+	}
+}
+
+func TestStatementNotResource(t *testing.T) {
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["haha"]},`+
+		`"Action":"s3:GetObject","NotResource":"arn:aws:s3:::hehe/private/*"}]}`, []isAllowedCase{
+		{"other resource", getObject("haha", "public/a"), PolicyAllow},
+		{"excluded resource", getObject("haha", "private/a"), NoPolicy},
+	})
+
+	testIsAllowed(t, `{"Version":"2012-10-17","Statement":[`+
+		`{"Effect":"Allow","Principal":{"AWS":["haha"]},"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"},`+
+		`{"Effect":"Deny","Principal":{"AWS":["haha"]},"Action":"s3:GetObject",`+
+		`"NotResource":"arn:aws:s3:::hehe/home/${aws:userid}/*"}]}`, []isAllowedCase{
+		{"excluded resource", getObject("haha", "home/haha/a"), PolicyAllow},
+		{"other resource", getObject("haha", "home/hehe/a"), PolicyDeny},
+		{"another resource", getObject("haha", "a"), PolicyDeny},
+	})
+}
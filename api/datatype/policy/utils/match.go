@@ -16,6 +16,10 @@
 
 package utils
 
+import (
+	"strings"
+)
+
 // MatchSimple - finds whether the text matches/satisfies the pattern string.
 // supports only '*' wildcard in the pattern.
 // considers a file system path as a flat name space.
@@ -35,7 +39,7 @@ func MatchSimple(pattern, name string) bool {
 		rpattern = append(rpattern, r)
 	}
 	simple := true // Does only wildcard '*' match.
-	return deepMatchRune(rname, rpattern, simple)
+	return deepMatchRune(rname, rpattern, simple, false)
 }
 
 // Match -  finds whether the text matches/satisfies the pattern string.
@@ -58,11 +62,48 @@ func Match(pattern, name string) (matched bool) {
 		rpattern = append(rpattern, r)
 	}
 	simple := false // Does extended wildcard '*' and '?' match.
-	return deepMatchRune(rname, rpattern, simple)
+	return deepMatchRune(rname, rpattern, simple, false)
+}
+
+// MatchEscaped - like Match, except that a rune following '\' in the pattern
+// string matches itself only, e.g. "a\*" matches "a*" but not "ab".
+func MatchEscaped(pattern, name string) bool {
+	rname := make([]rune, 0, len(name))
+	rpattern := make([]rune, 0, len(pattern))
+	for _, r := range name {
+		rname = append(rname, r)
+	}
+	for _, r := range pattern {
+		rpattern = append(rpattern, r)
+	}
+	return deepMatchRune(rname, rpattern, false, true)
+}
+
+// EscapeWildcards - returns s with '*', '?' and '\' escaped, so it's matched
+// literally by MatchEscaped.
+func EscapeWildcards(s string) string {
+	return wildcardEscaper.Replace(s)
+}
+
+// EscapeBackslashes - returns s with '\' escaped, so '*' and '?' in it are
+// still wildcards for MatchEscaped.
+func EscapeBackslashes(s string) string {
+	return strings.Replace(s, `\`, `\\`, -1)
 }
 
-func deepMatchRune(str, pattern []rune, simple bool) bool {
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`)
+
+func deepMatchRune(str, pattern []rune, simple, escaped bool) bool {
 	for len(pattern) > 0 {
+		if escaped && pattern[0] == '\\' && len(pattern) > 1 {
+			pattern = pattern[1:]
+			if len(str) == 0 || str[0] != pattern[0] {
+				return false
+			}
+			str = str[1:]
+			pattern = pattern[1:]
+			continue
+		}
 		switch pattern[0] {
 		default:
 			if len(str) == 0 || str[0] != pattern[0] {
@@ -73,8 +114,8 @@ func deepMatchRune(str, pattern []rune, simple bool) bool {
 				return false
 			}
 		case '*':
-			return deepMatchRune(str, pattern[1:], simple) ||
-				(len(str) > 0 && deepMatchRune(str[1:], pattern, simple))
+			return deepMatchRune(str, pattern[1:], simple, escaped) ||
+				(len(str) > 0 && deepMatchRune(str[1:], pattern, simple, escaped))
 		}
 		str = str[1:]
 		pattern = pattern[1:]
@@ -0,0 +1,84 @@
+package datatype
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const MaxPublicAccessBlockConfigurationSize = 16 * humanize.KiByte
+
+// PublicAccessBlockConfiguration is the body of `?publicAccessBlock` sub-resource
+// of buckets, settings in yig.toml apply to all buckets in addition to it.
+//   - BlockPublicAcls rejects requests setting public ACLs on bucket and objects
+//   - IgnorePublicAcls makes public ACLs of bucket and objects grant nothing to
+//     anonymous users and other users
+//   - BlockPublicPolicy rejects bucket policies granting access to everyone
+//   - RestrictPublicBuckets makes public bucket policy grant nothing to
+//     anonymous users and other users
+type PublicAccessBlockConfiguration struct {
+	XMLName               xml.Name `xml:"PublicAccessBlockConfiguration"`
+	BlockPublicAcls       bool     `xml:"BlockPublicAcls"`
+	IgnorePublicAcls      bool     `xml:"IgnorePublicAcls"`
+	BlockPublicPolicy     bool     `xml:"BlockPublicPolicy"`
+	RestrictPublicBuckets bool     `xml:"RestrictPublicBuckets"`
+}
+
+// IsEmpty returns whether none of the settings is enabled
+func (c PublicAccessBlockConfiguration) IsEmpty() bool {
+	return !c.BlockPublicAcls && !c.IgnorePublicAcls && !c.BlockPublicPolicy && !c.RestrictPublicBuckets
+}
+
+// Effective returns settings enforced on the bucket, a setting is enabled if
+// it's enabled either in bucket configuration or in yig.toml
+func (c PublicAccessBlockConfiguration) Effective() PublicAccessBlockConfiguration {
+	d := helper.CONFIG.PublicAccessBlock
+	c.BlockPublicAcls = c.BlockPublicAcls || d.BlockPublicAcls
+	c.IgnorePublicAcls = c.IgnorePublicAcls || d.IgnorePublicAcls
+	c.BlockPublicPolicy = c.BlockPublicPolicy || d.BlockPublicPolicy
+	c.RestrictPublicBuckets = c.RestrictPublicBuckets || d.RestrictPublicBuckets
+	return c
+}
+
+// IsPublicAcl returns whether acl grants access to all users or all
+// authenticated users
+func IsPublicAcl(acl Acl) bool {
+	switch acl.CannedAcl {
+	case ValidCannedAcl[CANNEDACL_PUBLIC_READ], ValidCannedAcl[CANNEDACL_PUBLIC_READ_WRITE],
+		ValidCannedAcl[CANNEDACL_AUTHENTICATED_READ]:
+		return true
+	}
+	return false
+}
+
+// CannedAcl returns canned ACL of acl in effect with settings c, public ACLs
+// are "private" if IgnorePublicAcls is enabled
+func (c PublicAccessBlockConfiguration) CannedAcl(acl Acl) string {
+	if c.IgnorePublicAcls && IsPublicAcl(acl) {
+		return ValidCannedAcl[CANNEDACL_PRIVATE]
+	}
+	return acl.CannedAcl
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutPublicAccessBlock.html
+func ParsePublicAccessBlockConfig(reader io.Reader) (*PublicAccessBlockConfiguration, error) {
+	config := new(PublicAccessBlockConfiguration)
+	configBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxPublicAccessBlockConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read public access block config body:", err)
+		return nil, err
+	}
+	if len(configBuffer) > MaxPublicAccessBlockConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(configBuffer, config)
+	if err != nil {
+		helper.Logger.Error("Unable to parse public access block config XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	return config, nil
+}
@@ -0,0 +1,144 @@
+package datatype
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const (
+	MaxReplicationRulesCount        = 1000
+	MaxReplicationConfigurationSize = 2 * humanize.MiByte
+	MaxReplicationRuleIdLength      = 255
+)
+
+const (
+	ReplicationRuleStatusEnabled  = "Enabled"
+	ReplicationRuleStatusDisabled = "Disabled"
+)
+
+// Values of x-amz-replication-status
+const (
+	ReplicationStatusPending   = "PENDING"
+	ReplicationStatusCompleted = "COMPLETED"
+	ReplicationStatusFailed    = "FAILED"
+)
+
+const replicationDestinationArnPrefix = "arn:aws:s3:"
+
+type ReplicationDestination struct {
+	// ARN of destination bucket, in form of "arn:aws:s3:<region>::<bucket>",
+	// where <region> names one of `replication_targets` in config
+	Bucket       string `xml:"Bucket"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+type ReplicationRule struct {
+	ID          string                 `xml:"ID,omitempty"`
+	Status      string                 `xml:"Status"`
+	Prefix      string                 `xml:"Prefix"`
+	Destination ReplicationDestination `xml:"Destination"`
+}
+
+type ReplicationConfiguration struct {
+	XMLName xml.Name          `xml:"ReplicationConfiguration"`
+	Role    string            `xml:"Role,omitempty"`
+	Rules   []ReplicationRule `xml:"Rule"`
+}
+
+// ParseReplicationDestination splits destination bucket ARN into the
+// target region and bucket name
+func ParseReplicationDestination(arn string) (region, bucket string, err error) {
+	if !strings.HasPrefix(arn, replicationDestinationArnPrefix) {
+		return "", "", ErrInvalidReplicationDestination
+	}
+	segments := strings.Split(arn, ":")
+	if len(segments) != 6 {
+		return "", "", ErrInvalidReplicationDestination
+	}
+	region, bucket = segments[3], segments[5]
+	if region == "" || bucket == "" {
+		return "", "", ErrInvalidReplicationDestination
+	}
+	return region, bucket, nil
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketReplication.html
+func (c *ReplicationConfiguration) Validate() error {
+	if len(c.Rules) == 0 || len(c.Rules) > MaxReplicationRulesCount {
+		return ErrInvalidReplicationRule
+	}
+	ids := make(map[string]bool)
+	for i, rule := range c.Rules {
+		if len(rule.ID) > MaxReplicationRuleIdLength {
+			return ErrInvalidReplicationRule
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrInvalidReplicationRule
+			}
+			ids[rule.ID] = true
+		}
+		if rule.Status != ReplicationRuleStatusEnabled && rule.Status != ReplicationRuleStatusDisabled {
+			return ErrInvalidReplicationRule
+		}
+		region, _, err := ParseReplicationDestination(rule.Destination.Bucket)
+		if err != nil {
+			return err
+		}
+		if _, ok := helper.CONFIG.ReplicationTargets[region]; !ok {
+			return ErrInvalidReplicationDestination
+		}
+		// prefixes of rules should not overlap, so that every object
+		// is replicated by one rule at most
+		for _, other := range c.Rules[i+1:] {
+			if strings.HasPrefix(rule.Prefix, other.Prefix) ||
+				strings.HasPrefix(other.Prefix, rule.Prefix) {
+				return ErrInvalidReplicationRule
+			}
+		}
+	}
+	return nil
+}
+
+// MatchRule returns the enabled rule which object `key` should be
+// replicated by, or nil if there is none
+func (c *ReplicationConfiguration) MatchRule(key string) *ReplicationRule {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Status != ReplicationRuleStatusEnabled {
+			continue
+		}
+		if strings.HasPrefix(key, rule.Prefix) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func ParseReplicationConfig(reader io.Reader) (*ReplicationConfiguration, error) {
+	replication := new(ReplicationConfiguration)
+	replicationBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxReplicationConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read replication config body:", err)
+		return nil, err
+	}
+	if len(replicationBuffer) > MaxReplicationConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(replicationBuffer, replication)
+	if err != nil {
+		helper.Logger.Error("Unable to parse replication config XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	err = replication.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return replication, nil
+}
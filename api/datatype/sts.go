@@ -0,0 +1,54 @@
+package datatype
+
+import "encoding/xml"
+
+type StsCredentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      string
+}
+
+type StsResponseMetadata struct {
+	RequestId string
+}
+
+type AssumedRoleUser struct {
+	Arn           string
+	AssumedRoleId string
+}
+
+type GetSessionTokenResponse struct {
+	XMLName          xml.Name              `xml:"https://sts.amazonaws.com/doc/2011-06-15/ GetSessionTokenResponse"`
+	Result           GetSessionTokenResult `xml:"GetSessionTokenResult"`
+	ResponseMetadata StsResponseMetadata
+}
+
+type GetSessionTokenResult struct {
+	Credentials StsCredentials
+}
+
+type AssumeRoleResponse struct {
+	XMLName          xml.Name         `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse"`
+	Result           AssumeRoleResult `xml:"AssumeRoleResult"`
+	ResponseMetadata StsResponseMetadata
+}
+
+type AssumeRoleResult struct {
+	Credentials      StsCredentials
+	AssumedRoleUser  AssumedRoleUser
+	PackedPolicySize int `xml:",omitempty"`
+}
+
+// StsErrorResponse is the error format of STS, different from S3
+type StsErrorResponse struct {
+	XMLName   xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ ErrorResponse"`
+	Error     StsError
+	RequestId string
+}
+
+type StsError struct {
+	Type    string
+	Code    string
+	Message string
+}
@@ -0,0 +1,175 @@
+package datatype
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/dustin/go-humanize"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+)
+
+const (
+	MaxObjectTagsCount          = 10
+	MaxBucketTagsCount          = 50
+	MaxTagKeyLength             = 128
+	MaxTagValueLength           = 256
+	MaxTaggingConfigurationSize = 16 * humanize.KiByte
+)
+
+// Tagging directives for CopyObject, see x-amz-tagging-directive
+const (
+	TaggingDirectiveCopy    = "COPY"
+	TaggingDirectiveReplace = "REPLACE"
+)
+
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type TagSet struct {
+	Tags []Tag `xml:"Tag"`
+}
+
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  TagSet   `xml:"TagSet"`
+}
+
+func validateTag(key, value string) error {
+	if key == "" || !utf8.ValidString(key) || utf8.RuneCountInString(key) > MaxTagKeyLength {
+		return ErrInvalidTagKey
+	}
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > MaxTagValueLength {
+		return ErrInvalidTagValue
+	}
+	return nil
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/dev/object-tagging.html
+func (t *Tagging) Validate() error {
+	if len(t.TagSet.Tags) > MaxObjectTagsCount {
+		return ErrTagsLimitExceeded
+	}
+	return t.validateTags()
+}
+
+// Reference:https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketTagging.html
+func (t *Tagging) ValidateBucketTagging() error {
+	if len(t.TagSet.Tags) > MaxBucketTagsCount {
+		return ErrBucketTagsLimitExceeded
+	}
+	return t.validateTags()
+}
+
+func (t *Tagging) validateTags() error {
+	keys := make(map[string]bool, len(t.TagSet.Tags))
+	for _, tag := range t.TagSet.Tags {
+		if err := validateTag(tag.Key, tag.Value); err != nil {
+			return err
+		}
+		if keys[tag.Key] {
+			return ErrDuplicateTagKey
+		}
+		keys[tag.Key] = true
+	}
+	return nil
+}
+
+// ToMap converts tag set into the form stored in object metadata
+func (t *Tagging) ToMap() map[string]string {
+	if len(t.TagSet.Tags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(t.TagSet.Tags))
+	for _, tag := range t.TagSet.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+func TaggingFromMap(tags map[string]string) Tagging {
+	tagging := Tagging{}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		tagging.TagSet.Tags = append(tagging.TagSet.Tags, Tag{Key: k, Value: tags[k]})
+	}
+	return tagging
+}
+
+func ParseTagging(reader io.Reader) (*Tagging, error) {
+	tagging, err := unmarshalTagging(reader)
+	if err != nil {
+		return nil, err
+	}
+	err = tagging.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return tagging, nil
+}
+
+func ParseBucketTagging(reader io.Reader) (*Tagging, error) {
+	tagging, err := unmarshalTagging(reader)
+	if err != nil {
+		return nil, err
+	}
+	err = tagging.ValidateBucketTagging()
+	if err != nil {
+		return nil, err
+	}
+	return tagging, nil
+}
+
+func unmarshalTagging(reader io.Reader) (*Tagging, error) {
+	tagging := new(Tagging)
+	taggingBuffer, err := ioutil.ReadAll(io.LimitReader(reader, MaxTaggingConfigurationSize+1))
+	if err != nil {
+		helper.Logger.Error("Unable to read tagging body:", err)
+		return nil, err
+	}
+	if len(taggingBuffer) > MaxTaggingConfigurationSize {
+		return nil, ErrEntityTooLarge
+	}
+	err = xml.Unmarshal(taggingBuffer, tagging)
+	if err != nil {
+		helper.Logger.Error("Unable to parse tagging XML body:", err)
+		return nil, ErrMalformedXML
+	}
+	return tagging, nil
+}
+
+// ParseTaggingHeader parses value of x-amz-tagging, which is URL query
+// encoded, e.g. "key1=value1&key2=value2"
+func ParseTaggingHeader(header string) (map[string]string, error) {
+	if header == "" {
+		return nil, nil
+	}
+	values, err := url.ParseQuery(header)
+	if err != nil {
+		return nil, ErrInvalidTag
+	}
+	if len(values) > MaxObjectTagsCount {
+		return nil, ErrTagsLimitExceeded
+	}
+	tags := make(map[string]string, len(values))
+	for k, v := range values {
+		if len(v) != 1 {
+			return nil, ErrDuplicateTagKey
+		}
+		if err = validateTag(k, v[0]); err != nil {
+			return nil, err
+		}
+		tags[k] = v[0]
+	}
+	return tags, nil
+}
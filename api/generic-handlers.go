@@ -170,16 +170,12 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 
 // List of not implemented bucket queries
 var notImplementedBucketResourceNames = map[string]bool{
-	"notification":   true,
-	"replication":    true,
-	"tagging":        true,
 	"requestPayment": true,
 }
 
 // List of not implemented object queries
 var notImplementedObjectResourceNames = map[string]bool{
 	"torrent": true,
-	"tagging": true,
 }
 
 func ContextLogger(r *http.Request) log.Logger {
@@ -173,6 +173,26 @@ func parseSseHeader(header http.Header) (request SseRequest, err error) {
 	return
 }
 
+// parseObjectLockHeader parses x-amz-object-lock-* headers of write requests,
+// retention mode and retain until date should be specified together
+func parseObjectLockHeader(header http.Header) (request ObjectLockRequest, err error) {
+	mode := header.Get("X-Amz-Object-Lock-Mode")
+	date := header.Get("X-Amz-Object-Lock-Retain-Until-Date")
+	if (mode == "") != (date == "") {
+		return request, ErrObjectLockInvalidHeaders
+	}
+	if mode != "" {
+		request.Mode, request.RetainUntilDate, err = ParseRetention(mode, date)
+		if err != nil {
+			return
+		}
+	}
+	if status := header.Get("X-Amz-Object-Lock-Legal-Hold"); status != "" {
+		request.LegalHold, err = ParseLegalHold(status)
+	}
+	return
+}
+
 // Suffix matcher string matches suffix in a platform specific way.
 // For example on windows since its case insensitive we are supposed
 // to do case insensitive checks.
@@ -35,6 +35,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // supportedGetReqParams - supported request parameters for GET presigned request.
@@ -101,7 +102,7 @@ func (api ObjectAPIHandlers) errAllowableObjectNotFound(w http.ResponseWriter, r
 	}) == policy.PolicyAllow {
 		err = ErrNoSuchKey
 	} else {
-		switch ctx.BucketInfo.ACL.CannedAcl {
+		switch ctx.BucketInfo.PublicAccessBlock.Effective().CannedAcl(ctx.BucketInfo.ACL) {
 		case "public-read", "public-read-write":
 			err = ErrNoSuchKey
 		case "authenticated-read":
@@ -366,11 +367,16 @@ func (api ObjectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 			logger.Error("Unable to get restore object status", object.BucketName, object.Name, version,
 				"error:", err)
 			WriteErrorResponse(w, r, err)
+			return
 		}
-		if freezer.Status == meta.ObjectHasRestored {
-			w.Header().Set("x-amz-restore", "ongoing-request='true'")
-		} else {
-			w.Header().Set("x-amz-restore", "ongoing-request='false'")
+		if err == nil {
+			if freezer.Status == meta.ObjectHasRestored {
+				expiryDate := freezer.LastModifiedTime.AddDate(0, 0, freezer.LifeTime)
+				w.Header().Set("x-amz-restore", "ongoing-request=\"false\", expiry-date=\""+
+					expiryDate.UTC().Format(http.TimeFormat)+"\"")
+			} else {
+				w.Header().Set("x-amz-restore", "ongoing-request=\"true\"")
+			}
 		}
 	}
 
@@ -505,6 +511,12 @@ func (api ObjectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	logger.Info("Copying object from", sourceBucketName, sourceObjectName,
 		sourceVersion, "to", targetBucketName, targetObjectName)
 
+	err = checkCredentialPolicies(credential, r, policy.GetObjectAction, sourceBucketName, sourceObjectName)
+	if err != nil {
+		WriteErrorResponseWithResource(w, r, err, copySource)
+		return
+	}
+
 	sourceObject, err := api.ObjectAPI.GetObjectInfo(sourceBucketName, sourceObjectName,
 		sourceVersion, credential)
 	if err != nil {
@@ -600,7 +612,7 @@ func (api ObjectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		pipeWriter.Close()
 	}()
 
-	targetACL, err := getAclFromHeader(r.Header)
+	targetACL, err := getAclFromHeader(r.Header, getRequestContext(r).BucketInfo)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -637,6 +649,26 @@ func (api ObjectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	taggingDirective := r.Header.Get("X-Amz-Tagging-Directive")
+	if taggingDirective == TaggingDirectiveCopy || taggingDirective == "" {
+		targetObject.Tagging = sourceObject.Tagging
+	} else if taggingDirective == TaggingDirectiveReplace {
+		targetObject.Tagging, err = ParseTaggingHeader(r.Header.Get("X-Amz-Tagging"))
+		if err != nil {
+			WriteErrorResponse(w, r, err)
+			return
+		}
+	} else {
+		WriteErrorResponse(w, r, ErrInvalidTaggingDirective)
+		return
+	}
+
+	lock, err := parseObjectLockHeader(r.Header)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
 	var isMetadataOnly bool
 	isMetadataOnly = false
 	if sourceBucketName == targetBucketName && sourceObjectName == targetObjectName {
@@ -648,7 +680,8 @@ func (api ObjectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	// Create the object.
-	result, err := api.ObjectAPI.CopyObject(targetObject, truelySourceObject, pipeReader, credential, sseRequest, isMetadataOnly)
+	result, err := api.ObjectAPI.CopyObject(targetObject, truelySourceObject, pipeReader, credential, sseRequest,
+		lock, isMetadataOnly)
 	if err != nil {
 		logger.Error("CopyObject failed:", err)
 		WriteErrorResponse(w, r, err)
@@ -746,6 +779,14 @@ func (api ObjectAPIHandlers) RenameObjectHandler(w http.ResponseWriter, r *http.
 	}
 	logger.Info("Bucket Multi-version is:", bucket.Versioning)
 
+	for _, action := range []policy.Action{policy.GetObjectAction, policy.DeleteObjectAction} {
+		err = checkCredentialPolicies(credential, r, action, ctx.BucketName, sourceObjectName)
+		if err != nil {
+			WriteErrorResponseWithResource(w, r, err, sourceObjectName)
+			return
+		}
+	}
+
 	var sourceVersion string
 	sourceObject, err := api.ObjectAPI.GetObjectInfo(ctx.BucketName, sourceObjectName,
 		sourceVersion, credential)
@@ -884,13 +925,25 @@ func (api ObjectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		//TODO:add kms
 	}
 
-	acl, err := getAclFromHeader(r.Header)
+	acl, err := getAclFromHeader(r.Header, getRequestContext(r).BucketInfo)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	tagging, err := ParseTaggingHeader(r.Header.Get("X-Amz-Tagging"))
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
 
-	credential, dataReadCloser, err := signature.VerifyUpload(r)
+	lock, err := parseObjectLockHeader(r.Header)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	credential, dataReadCloser, err := verifyUpload(r, policy.PutObjectAction)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -898,7 +951,7 @@ func (api ObjectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 
 	var result PutObjectResult
 	result, err = api.ObjectAPI.PutObject(bucketName, objectName, credential, size, dataReadCloser,
-		metadata, acl, sseRequest, storageClass)
+		metadata, acl, sseRequest, storageClass, tagging, lock)
 	if err != nil {
 		logger.Error("Unable to create object", objectName, "error:", err)
 		WriteErrorResponse(w, r, err)
@@ -1035,12 +1088,18 @@ func (api ObjectAPIHandlers) AppendObjectHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Verify auth
-	credential, dataReadCloser, err := signature.VerifyUpload(r)
+	credential, dataReadCloser, err := verifyUpload(r, policy.PutObjectAction)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
 	}
 
+	// appendable objects could not be protected by object lock
+	if ctx.BucketInfo != nil && ctx.BucketInfo.ObjectLock.IsEnabled() {
+		WriteErrorResponse(w, r, ErrInvalidBucketState)
+		return
+	}
+
 	// Check whether the object is exist or not
 	// Check whether the bucket is owned by the specified user
 	objInfo, err := api.ObjectAPI.GetObjectInfoByCtx(ctx, "", credential)
@@ -1063,7 +1122,7 @@ func (api ObjectAPIHandlers) AppendObjectHandler(w http.ResponseWriter, r *http.
 
 	if err == ErrNoSuchKey {
 		if isFirstAppend(position) {
-			acl, err = getAclFromHeader(r.Header)
+			acl, err = getAclFromHeader(r.Header, ctx.BucketInfo)
 			if err != nil {
 				WriteErrorResponse(w, r, err)
 				return
@@ -1188,7 +1247,7 @@ func (api ObjectAPIHandlers) PutObjectAclHandler(w http.ResponseWriter, r *http.
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutObjectAclAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1196,8 +1255,11 @@ func (api ObjectAPIHandlers) PutObjectAclHandler(w http.ResponseWriter, r *http.
 	var acl Acl
 	var policy AccessControlPolicy
 	if _, ok := r.Header["X-Amz-Acl"]; ok {
-		acl, err = getAclFromHeader(r.Header)
-		if err != nil {
+		acl, err = getAclFromHeader(r.Header, getRequestContext(r).BucketInfo)
+		if err == ErrPublicAccessBlocked {
+			WriteErrorResponse(w, r, err)
+			return
+		} else if err != nil {
 			WriteErrorResponse(w, r, ErrInvalidAcl)
 			return
 		}
@@ -1277,6 +1339,7 @@ func (api ObjectAPIHandlers) RestoreObjectHandler(w http.ResponseWriter, r *http
 	if err != nil {
 		logger.Error("Unable to get freezer info:", err)
 		WriteErrorResponse(w, r, ErrInvalidRestoreInfo)
+		return
 	}
 
 	freezer, err := api.ObjectAPI.GetFreezerStatus(object.BucketName, object.Name, object.VersionId)
@@ -1284,12 +1347,13 @@ func (api ObjectAPIHandlers) RestoreObjectHandler(w http.ResponseWriter, r *http
 		logger.Error("Unable to get restore object status", object.BucketName, object.Name,
 			"error:", err)
 		WriteErrorResponse(w, r, err)
+		return
 	}
 	if err == ErrNoSuchKey || freezer.Name == "" {
-		status, err := meta.MatchStatusIndex("READY")
+		tier, err := meta.MatchRestoreTier(info.GlacierJobParameters.Tier)
 		if err != nil {
-			logger.Error("Unable to get freezer status:", err)
-			WriteErrorResponse(w, r, ErrInvalidRestoreInfo)
+			WriteErrorResponse(w, r, err)
+			return
 		}
 
 		lifeTime := info.Days
@@ -1300,26 +1364,29 @@ func (api ObjectAPIHandlers) RestoreObjectHandler(w http.ResponseWriter, r *http
 		targetFreezer := &meta.Freezer{}
 		targetFreezer.BucketName = object.BucketName
 		targetFreezer.Name = object.Name
-		targetFreezer.Status = status
+		targetFreezer.Status = meta.ObjectNeedRestore
 		targetFreezer.LifeTime = lifeTime
+		targetFreezer.Tier = tier
+		targetFreezer.LastModifiedTime = time.Now().UTC()
 		err = api.ObjectAPI.CreateFreezer(targetFreezer)
 		if err != nil {
 			logger.Error("Unable to create freezer:", err)
 			WriteErrorResponse(w, r, ErrCreateRestoreObject)
+			return
 		}
 		logger.Info("Submit thaw request successfully")
 
 		// ResponseRecorder
-		w.WriteHeader(http.StatusAccepted)
 		w.(*ResponseRecorder).operationName = "RestoreObject"
-
 		WriteSuccessResponseWithStatus(w, nil, http.StatusAccepted)
+		return
 	}
 	if freezer.Status == meta.ObjectHasRestored {
 		err = api.ObjectAPI.UpdateFreezerDate(freezer, info.Days, true)
 		if err != nil {
 			logger.Error("Unable to Update freezer date:", err)
 			WriteErrorResponse(w, r, ErrInvalidRestoreInfo)
+			return
 		}
 
 		// ResponseRecorder
@@ -1331,6 +1398,7 @@ func (api ObjectAPIHandlers) RestoreObjectHandler(w http.ResponseWriter, r *http
 			if err != nil {
 				logger.Error("Unable to Update freezer date:", err)
 				WriteErrorResponse(w, r, ErrInvalidRestoreInfo)
+				return
 			}
 		}
 		// ResponseRecorder
@@ -1356,7 +1424,7 @@ func (api ObjectAPIHandlers) GetObjectAclHandler(w http.ResponseWriter, r *http.
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.GetObjectAclAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1389,6 +1457,115 @@ func (api ObjectAPIHandlers) GetObjectAclHandler(w http.ResponseWriter, r *http.
 	WriteSuccessResponse(w, aclBuffer)
 }
 
+// PutObjectTaggingHandler - replace the tag set of an existing object
+func (api ObjectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.PutObjectTaggingAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		WriteErrorResponse(w, r, ErrMissingContentLength)
+		return
+	}
+
+	tagging, err := ParseTagging(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	err = api.ObjectAPI.PutObjectTagging(ctx.BucketName, ctx.ObjectName, version, tagging.ToMap(), credential)
+	if err != nil {
+		logger.Error("Unable to set tagging for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "PutObjectTagging"
+	WriteSuccessResponse(w, nil)
+}
+
+// GetObjectTaggingHandler - return the tag set of an object
+func (api ObjectAPIHandlers) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.GetObjectTaggingAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	tagging, err := api.ObjectAPI.GetObjectTagging(ctx.BucketName, ctx.ObjectName, version, credential)
+	if err != nil {
+		logger.Error("Unable to fetch object tagging:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	taggingBuffer, err := xmlFormat(tagging)
+	if err != nil {
+		logger.Error("Failed to marshal tagging XML for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, ErrInternalError)
+		return
+	}
+
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	setXmlHeader(w)
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "GetObjectTagging"
+	WriteSuccessResponse(w, taggingBuffer)
+}
+
+// DeleteObjectTaggingHandler - remove all tags of an object
+func (api ObjectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.DeleteObjectTaggingAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	err = api.ObjectAPI.DeleteObjectTagging(ctx.BucketName, ctx.ObjectName, version, credential)
+	if err != nil {
+		logger.Error("Unable to delete tagging for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "DeleteObjectTagging"
+	WriteSuccessNoContent(w)
+}
+
 // Multipart objectAPIHandlers
 
 // NewMultipartUploadHandler - New multipart upload
@@ -1414,13 +1591,13 @@ func (api ObjectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutObjectAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
 	}
 
-	acl, err := getAclFromHeader(r.Header)
+	acl, err := getAclFromHeader(r.Header, getRequestContext(r).BucketInfo)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -1449,8 +1626,20 @@ func (api ObjectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	tagging, err := ParseTaggingHeader(r.Header.Get("X-Amz-Tagging"))
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	lock, err := parseObjectLockHeader(r.Header)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
 	uploadID, err := api.ObjectAPI.NewMultipartUpload(credential, bucketName, objectName,
-		metadata, acl, sseRequest, storageClass)
+		metadata, acl, sseRequest, storageClass, tagging, lock)
 	if err != nil {
 		logger.Error("Unable to initiate new multipart upload id:", err)
 		WriteErrorResponse(w, r, err)
@@ -1543,7 +1732,7 @@ func (api ObjectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	credential, dataReadCloser, err := signature.VerifyUpload(r)
+	credential, dataReadCloser, err := verifyUpload(r, policy.PutObjectAction)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -1607,7 +1796,7 @@ func (api ObjectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutObjectAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1671,6 +1860,12 @@ func (api ObjectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	err = checkCredentialPolicies(credential, r, policy.GetObjectAction, sourceBucketName, sourceObjectName)
+	if err != nil {
+		WriteErrorResponseWithResource(w, r, err, copySource)
+		return
+	}
+
 	sourceObject, err := api.ObjectAPI.GetObjectInfo(sourceBucketName, sourceObjectName,
 		sourceVersion, credential)
 	if err != nil {
@@ -1809,7 +2004,7 @@ func (api ObjectAPIHandlers) AbortMultipartUploadHandler(w http.ResponseWriter,
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.AbortMultipartUploadAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1848,7 +2043,7 @@ func (api ObjectAPIHandlers) ListObjectPartsHandler(w http.ResponseWriter, r *ht
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.ListMultipartUploadPartsAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1896,7 +2091,7 @@ func (api ObjectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		break
 	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
 		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, policy.PutObjectAction); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
@@ -1991,11 +2186,21 @@ func (api ObjectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 // Delete objectAPIHandlers
 
+// deleteObjectAction returns policy action of deleting object `version`,
+// removing a specific version is s3:DeleteObjectVersion as in S3
+func deleteObjectAction(version string) policy.Action {
+	if version != "" {
+		return policy.DeleteObjectVersionAction
+	}
+	return policy.DeleteObjectAction
+}
+
 // DeleteObjectHandler - delete an object
 func (api ObjectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bucketName := vars["bucket"]
 	objectName := vars["object"]
+	version := r.URL.Query().Get("versionId")
 
 	var credential common.Credential
 	var err error
@@ -2008,15 +2213,15 @@ func (api ObjectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		break
 	case signature.AuthTypeSignedV4, signature.AuthTypePresignedV4,
 		signature.AuthTypeSignedV2, signature.AuthTypePresignedV2:
-		if credential, err = signature.IsReqAuthenticated(r); err != nil {
+		if credential, err = isReqAuthenticated(r, deleteObjectAction(version)); err != nil {
 			WriteErrorResponse(w, r, err)
 			return
 		}
 	}
-	version := r.URL.Query().Get("versionId")
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	// Ignore delete object errors, since we are supposed to reply only 204.
-	result, err := api.ObjectAPI.DeleteObject(bucketName, objectName, version, credential)
+	result, err := api.ObjectAPI.DeleteObject(bucketName, objectName, version,
+		isGovernanceBypassed(r, credential, objectName), credential)
 	if err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -2084,7 +2289,8 @@ func (api ObjectAPIHandlers) PostObjectHandler(w http.ResponseWriter, r *http.Re
 	case signature.PostPolicyV4:
 		credential, err = signature.DoesPolicySignatureMatchV4(formValues)
 	case signature.PostPolicyAnonymous:
-		if bucket.ACL.CannedAcl != "public-read-write" {
+		if bucket.ACL.CannedAcl != "public-read-write" ||
+			bucket.PublicAccessBlock.Effective().IgnorePublicAcls {
 			WriteErrorResponse(w, r, ErrAccessDenied)
 			return
 		}
@@ -2097,6 +2303,12 @@ func (api ObjectAPIHandlers) PostObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	err = checkCredentialPolicies(credential, r, policy.PutObjectAction, bucketName, objectName)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
 	if err = signature.CheckPostPolicy(formValues, postPolicyType); err != nil {
 		WriteErrorResponse(w, r, err)
 		return
@@ -2121,6 +2333,11 @@ func (api ObjectAPIHandlers) PostObjectHandler(w http.ResponseWriter, r *http.Re
 		WriteErrorResponse(w, r, ErrInvalidCannedAcl)
 		return
 	}
+	err = checkPublicAcl(acl, bucket)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
 
 	sseRequest, err := parseSseHeader(headerfiedFormValues)
 	if err != nil {
@@ -2142,8 +2359,31 @@ func (api ObjectAPIHandlers) PostObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	result, err := api.ObjectAPI.PutObject(bucketName, objectName, credential, -1, fileBody,
-		metadata, acl, sseRequest, storageClass)
+	var tagging map[string]string
+	if taggingXml, ok := formValues["Tagging"]; ok {
+		t, err := ParseTagging(strings.NewReader(taggingXml))
+		if err != nil {
+			WriteErrorResponse(w, r, err)
+			return
+		}
+		tagging = t.ToMap()
+	}
+
+	lockHeader := make(http.Header)
+	for _, key := range []string{"X-Amz-Object-Lock-Mode", "X-Amz-Object-Lock-Retain-Until-Date",
+		"X-Amz-Object-Lock-Legal-Hold"} {
+		if value, ok := formValues[key]; ok {
+			lockHeader.Set(key, value)
+		}
+	}
+	lock, err := parseObjectLockHeader(lockHeader)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	result, err := api.ObjectAPI.PostObject(bucketName, objectName, credential, -1, fileBody,
+		metadata, acl, sseRequest, storageClass, tagging, lock)
 	if err != nil {
 		logger.Error("Unable to create object", objectName, "error:", err)
 		WriteErrorResponse(w, r, err)
@@ -18,6 +18,7 @@ package api
 
 import (
 	"io"
+	"time"
 
 	"github.com/journeymidnight/yig/api/datatype"
 	"github.com/journeymidnight/yig/api/datatype/policy"
@@ -28,7 +29,7 @@ import (
 // ObjectLayer implements primitives for object API layer.
 type ObjectLayer interface {
 	// Bucket operations.
-	MakeBucket(bucket string, acl datatype.Acl, credential common.Credential) error
+	MakeBucket(bucket string, acl datatype.Acl, credential common.Credential, objectLockEnabled bool) error
 	SetBucketLogging(bucket string, config datatype.BucketLoggingStatus) error
 	GetBucketLogging(bucket string) (datatype.BucketLoggingStatus, error)
 	SetBucketLifecycle(bucket string, config datatype.Lifecycle,
@@ -69,35 +70,80 @@ type ObjectLayer interface {
 	DeleteBucketEncryption(bucket *meta.Bucket) error
 	CheckBucketEncryption(bucket string) (*datatype.ApplyServerSideEncryptionByDefault, bool)
 
+	// Bucket tagging operations
+	SetBucketTagging(bucket *meta.Bucket, tagging map[string]string) error
+	GetBucketTagging(bucket string) (datatype.Tagging, error)
+	DeleteBucketTagging(bucket *meta.Bucket) error
+
+	// Bucket notification operations
+	SetBucketNotification(bucket *meta.Bucket, config datatype.NotificationConfiguration) error
+	GetBucketNotification(bucket string) (datatype.NotificationConfiguration, error)
+
+	// Bucket replication operations
+	SetBucketReplication(bucket *meta.Bucket, config datatype.ReplicationConfiguration) error
+	GetBucketReplication(bucket string) (datatype.ReplicationConfiguration, error)
+	DeleteBucketReplication(bucket *meta.Bucket) error
+
+	// Bucket object lock operations
+	SetBucketObjectLock(bucket *meta.Bucket, config datatype.ObjectLockConfiguration) error
+	GetBucketObjectLock(bucket string) (datatype.ObjectLockConfiguration, error)
+
+	// Bucket public access block operations
+	SetBucketPublicAccessBlock(bucket *meta.Bucket, config datatype.PublicAccessBlockConfiguration) error
+	GetBucketPublicAccessBlock(bucket string) (datatype.PublicAccessBlockConfiguration, error)
+	DeleteBucketPublicAccessBlock(bucket *meta.Bucket) error
+
 	// Object operations.
 	GetObject(object *meta.Object, startOffset int64, length int64, writer io.Writer,
 		sse datatype.SseRequest) (err error)
 	GetObjectInfo(bucket, object, version string, credential common.Credential) (objInfo *meta.Object, err error)
 	GetObjectInfoByCtx(ctx RequestContext, version string, credential common.Credential) (objInfo *meta.Object, err error)
 	PutObject(bucket, object string, credential common.Credential, size int64, data io.ReadCloser,
-		metadata map[string]string, acl datatype.Acl,
-		sse datatype.SseRequest, storageClass meta.StorageClass) (result datatype.PutObjectResult, err error)
+		metadata map[string]string, acl datatype.Acl, sse datatype.SseRequest,
+		storageClass meta.StorageClass, tagging map[string]string,
+		lock datatype.ObjectLockRequest) (result datatype.PutObjectResult, err error)
+	PostObject(bucket, object string, credential common.Credential, size int64, data io.ReadCloser,
+		metadata map[string]string, acl datatype.Acl, sse datatype.SseRequest,
+		storageClass meta.StorageClass, tagging map[string]string,
+		lock datatype.ObjectLockRequest) (result datatype.PutObjectResult, err error)
 	AppendObject(bucket, object string, credential common.Credential, offset uint64, size int64, data io.ReadCloser,
 		metadata map[string]string, acl datatype.Acl,
 		sse datatype.SseRequest, storageClass meta.StorageClass, objInfo *meta.Object) (result datatype.AppendObjectResult, err error)
 
 	CopyObject(targetObject *meta.Object, sourceObject *meta.Object, source io.Reader, credential common.Credential,
-		sseRequest datatype.SseRequest, isMetadataOnly bool) (result datatype.PutObjectResult, err error)
+		sseRequest datatype.SseRequest, lock datatype.ObjectLockRequest,
+		isMetadataOnly bool) (result datatype.PutObjectResult, err error)
 	RenameObject(targetObject *meta.Object, sourceObject string, credential common.Credential) (result datatype.RenameObjectResult, err error)
 	PutObjectMeta(bucket *meta.Bucket, targetObject *meta.Object, credential common.Credential) (err error)
 	SetObjectAcl(bucket string, object string, version string, policy datatype.AccessControlPolicy,
 		acl datatype.Acl, credential common.Credential) error
 	GetObjectAcl(bucket string, object string, version string, credential common.Credential) (
 		policy datatype.AccessControlPolicyResponse, err error)
-	DeleteObject(bucket, object, version string, credential common.Credential) (datatype.DeleteObjectResult,
-		error)
+	DeleteObject(bucket, object, version string, bypassGovernance bool,
+		credential common.Credential) (datatype.DeleteObjectResult, error)
+
+	// Object tagging operations
+	PutObjectTagging(bucket, object, version string, tagging map[string]string,
+		credential common.Credential) error
+	GetObjectTagging(bucket, object, version string, credential common.Credential) (datatype.Tagging, error)
+	DeleteObjectTagging(bucket, object, version string, credential common.Credential) error
+
+	// Object lock operations
+	PutObjectRetention(bucket, object, version string, mode string, retainUntilDate time.Time,
+		bypassGovernance bool, credential common.Credential) error
+	GetObjectRetention(bucket, object, version string, credential common.Credential) (
+		datatype.ObjectRetention, error)
+	PutObjectLegalHold(bucket, object, version string, legalHold bool, credential common.Credential) error
+	GetObjectLegalHold(bucket, object, version string, credential common.Credential) (
+		datatype.ObjectLegalHold, error)
 
 	// Multipart operations.
 	ListMultipartUploads(credential common.Credential, bucket string,
 		request datatype.ListUploadsRequest) (result datatype.ListMultipartUploadsResponse, err error)
 	NewMultipartUpload(credential common.Credential, bucket, object string,
-		metadata map[string]string, acl datatype.Acl,
-		sse datatype.SseRequest, storageClass meta.StorageClass) (uploadID string, err error)
+		metadata map[string]string, acl datatype.Acl, sse datatype.SseRequest,
+		storageClass meta.StorageClass, tagging map[string]string,
+		lock datatype.ObjectLockRequest) (uploadID string, err error)
 	PutObjectPart(bucket, object string, credential common.Credential, uploadID string, partID int,
 		size int64, data io.ReadCloser, md5Hex string,
 		sse datatype.SseRequest) (result datatype.PutObjectPartResult, err error)
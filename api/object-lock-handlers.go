@@ -0,0 +1,289 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/api/datatype/policy"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/iam/common"
+	"github.com/journeymidnight/yig/signature"
+)
+
+// isGovernanceBypassed checks x-amz-bypass-governance-retention of request,
+// bucket owner could always bypass governance retention, while other users
+// should be granted s3:BypassGovernanceRetention by bucket policy
+func isGovernanceBypassed(r *http.Request, credential common.Credential, objectName string) bool {
+	if !strings.EqualFold(r.Header.Get("X-Amz-Bypass-Governance-Retention"), "true") {
+		return false
+	}
+	ctx := getRequestContext(r)
+	if ctx.BucketInfo == nil {
+		return false
+	}
+	if ctx.BucketInfo.OwnerId == credential.UserId {
+		return true
+	}
+	allow, _ := IsBucketPolicyAllowed(credential, ctx.BucketInfo, r,
+		policy.BypassGovernanceRetentionAction, objectName)
+	return allow
+}
+
+func (api ObjectAPIHandlers) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	switch ctx.AuthType {
+	default:
+		// For all unknown auth types return error.
+		WriteErrorResponse(w, r, ErrAccessDenied)
+		return
+	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
+		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
+		if credential, err = isReqAuthenticated(r, policy.PutBucketObjectLockConfigurationAction); err != nil {
+			WriteErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	if ctx.BucketInfo == nil {
+		WriteErrorResponse(w, r, ErrNoSuchBucket)
+		return
+	}
+	if credential.UserId != ctx.BucketInfo.OwnerId {
+		WriteErrorResponse(w, r, ErrBucketAccessForbidden)
+		return
+	}
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		WriteErrorResponse(w, r, ErrMissingContentLength)
+		return
+	}
+
+	config, err := datatype.ParseObjectLockConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	err = api.ObjectAPI.SetBucketObjectLock(ctx.BucketInfo, *config)
+	if err != nil {
+		logger.Error("Unable to set object lock for bucket:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "PutBucketObjectLockConfiguration"
+	WriteSuccessResponse(w, nil)
+}
+
+func (api ObjectAPIHandlers) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	switch ctx.AuthType {
+	default:
+		// For all unknown auth types return error.
+		WriteErrorResponse(w, r, ErrAccessDenied)
+		return
+	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
+		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
+		if credential, err = isReqAuthenticated(r, policy.GetBucketObjectLockConfigurationAction); err != nil {
+			WriteErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	if ctx.BucketInfo == nil {
+		WriteErrorResponse(w, r, ErrNoSuchBucket)
+		return
+	}
+	if credential.UserId != ctx.BucketInfo.OwnerId {
+		WriteErrorResponse(w, r, ErrBucketAccessForbidden)
+		return
+	}
+
+	config, err := api.ObjectAPI.GetBucketObjectLock(ctx.BucketName)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	encodedSuccessResponse, err := xmlFormat(config)
+	if err != nil {
+		logger.Error("Failed to marshal object lock XML for bucket", ctx.BucketName,
+			"error:", err)
+		WriteErrorResponse(w, r, ErrInternalError)
+		return
+	}
+
+	setXmlHeader(w)
+	//ResponseRecorder
+	w.(*ResponseRecorder).operationName = "GetBucketObjectLockConfiguration"
+	// Write to client.
+	WriteSuccessResponse(w, encodedSuccessResponse)
+}
+
+func (api ObjectAPIHandlers) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.PutObjectRetentionAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		WriteErrorResponse(w, r, ErrMissingContentLength)
+		return
+	}
+
+	mode, retainUntilDate, err := datatype.ParseObjectRetention(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	err = api.ObjectAPI.PutObjectRetention(ctx.BucketName, ctx.ObjectName, version, mode, retainUntilDate,
+		isGovernanceBypassed(r, credential, ctx.ObjectName), credential)
+	if err != nil {
+		logger.Error("Unable to set retention for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "PutObjectRetention"
+	WriteSuccessResponse(w, nil)
+}
+
+func (api ObjectAPIHandlers) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.GetObjectRetentionAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	retention, err := api.ObjectAPI.GetObjectRetention(ctx.BucketName, ctx.ObjectName, version, credential)
+	if err != nil {
+		logger.Error("Unable to fetch object retention:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	retentionBuffer, err := xmlFormat(retention)
+	if err != nil {
+		logger.Error("Failed to marshal retention XML for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, ErrInternalError)
+		return
+	}
+
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	setXmlHeader(w)
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "GetObjectRetention"
+	WriteSuccessResponse(w, retentionBuffer)
+}
+
+func (api ObjectAPIHandlers) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.PutObjectLegalHoldAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		WriteErrorResponse(w, r, ErrMissingContentLength)
+		return
+	}
+
+	legalHold, err := datatype.ParseObjectLegalHold(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	err = api.ObjectAPI.PutObjectLegalHold(ctx.BucketName, ctx.ObjectName, version, legalHold, credential)
+	if err != nil {
+		logger.Error("Unable to set legal hold for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "PutObjectLegalHold"
+	WriteSuccessResponse(w, nil)
+}
+
+func (api ObjectAPIHandlers) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := getRequestContext(r)
+	logger := ctx.Logger
+
+	var credential common.Credential
+	var err error
+	if credential, err = checkRequestAuth(r, policy.GetObjectLegalHoldAction); err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	version := r.URL.Query().Get("versionId")
+	legalHold, err := api.ObjectAPI.GetObjectLegalHold(ctx.BucketName, ctx.ObjectName, version, credential)
+	if err != nil {
+		logger.Error("Unable to fetch object legal hold:", err)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	legalHoldBuffer, err := xmlFormat(legalHold)
+	if err != nil {
+		logger.Error("Failed to marshal legal hold XML for object", ctx.ObjectName,
+			"error:", err)
+		WriteErrorResponse(w, r, ErrInternalError)
+		return
+	}
+
+	if version != "" {
+		w.Header().Set("x-amz-version-id", version)
+	}
+
+	setXmlHeader(w)
+
+	// ResponseRecorder
+	w.(*ResponseRecorder).operationName = "GetObjectLegalHold"
+	WriteSuccessResponse(w, legalHoldBuffer)
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/api/datatype/policy"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam"
+	"github.com/journeymidnight/yig/iam/common"
+	"github.com/journeymidnight/yig/signature"
+)
+
+const (
+	stsMinDuration                 = 15 * time.Minute
+	stsMaxSessionTokenDuration     = 36 * time.Hour
+	stsDefaultSessionTokenDuration = 12 * time.Hour
+	stsMaxAssumeRoleDuration       = 12 * time.Hour
+	stsDefaultAssumeRoleDuration   = time.Hour
+	stsMaxSessionPolicySize        = 2048
+)
+
+// yig has no roles, a role ARN is only accepted in account of the caller
+// as arn:aws:iam::<account>:role/<role name>
+var (
+	roleArnRegexp     = regexp.MustCompile(`^arn:aws:iam::([^:]+):role/([\w+=,.@/-]{1,64})$`)
+	roleSessionRegexp = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+)
+
+// StsHandler - issues temporary credentials, supports actions
+// GetSessionToken and AssumeRole of AWS STS.
+// Temporary credentials act as the credential signing the request,
+// optionally restricted by a session policy for AssumeRole.
+func (api ObjectAPIHandlers) StsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := ContextLogger(r)
+
+	var credential common.Credential
+	var err error
+	switch signature.GetRequestAuthType(r) {
+	default:
+		// For all unknown auth types return error.
+		writeStsErrorResponse(w, r, ErrAccessDenied)
+		return
+	case signature.AuthTypePresignedV4, signature.AuthTypeSignedV4,
+		signature.AuthTypePresignedV2, signature.AuthTypeSignedV2:
+		if credential, err = signature.IsStsReqAuthenticated(r); err != nil {
+			writeStsErrorResponse(w, r, err)
+			return
+		}
+	}
+	// temporary credentials could not issue new ones
+	if credential.IsTemporary() {
+		writeStsErrorResponse(w, r, ErrAccessDenied)
+		return
+	}
+	if helper.CONFIG.StsKey == "" {
+		writeStsErrorResponse(w, r, ErrStsNotEnabled)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		writeStsErrorResponse(w, r, ErrInvalidStsParameter)
+		return
+	}
+
+	action := r.PostForm.Get("Action")
+	w.(*ResponseRecorder).operationName = "Sts" + action
+	switch action {
+	case "GetSessionToken":
+		duration, err := parseStsDuration(r, stsDefaultSessionTokenDuration, stsMaxSessionTokenDuration)
+		if err != nil {
+			writeStsErrorResponse(w, r, err)
+			return
+		}
+		temporary, err := iam.NewTemporaryCredential(credential, duration, "")
+		if err != nil {
+			logger.Error("Unable to issue temporary credential:", err)
+			writeStsErrorResponse(w, r, err)
+			return
+		}
+		response := GetSessionTokenResponse{
+			Result: GetSessionTokenResult{
+				Credentials: stsCredentials(temporary),
+			},
+			ResponseMetadata: StsResponseMetadata{
+				RequestId: getRequestContext(r).RequestID,
+			},
+		}
+		WriteSuccessResponse(w, EncodeResponse(response))
+	case "AssumeRole":
+		roleArn := r.PostForm.Get("RoleArn")
+		sessionName := r.PostForm.Get("RoleSessionName")
+		if roleArn == "" || sessionName == "" {
+			writeStsErrorResponse(w, r, ErrMissingStsParameter)
+			return
+		}
+		match := roleArnRegexp.FindStringSubmatch(roleArn)
+		if match == nil || !roleSessionRegexp.MatchString(sessionName) {
+			writeStsErrorResponse(w, r, ErrInvalidStsParameter)
+			return
+		}
+		if match[1] != credential.UserId {
+			writeStsErrorResponse(w, r, ErrAccessDenied)
+			return
+		}
+		duration, err := parseStsDuration(r, stsDefaultAssumeRoleDuration, stsMaxAssumeRoleDuration)
+		if err != nil {
+			writeStsErrorResponse(w, r, err)
+			return
+		}
+		sessionPolicy := r.PostForm.Get("Policy")
+		if len(sessionPolicy) > stsMaxSessionPolicySize {
+			writeStsErrorResponse(w, r, ErrPackedPolicyTooLarge)
+			return
+		}
+		if sessionPolicy != "" {
+			_, err = policy.ParseIdentityPolicy(strings.NewReader(sessionPolicy))
+			if err != nil {
+				logger.Info("Invalid session policy:", err)
+				writeStsErrorResponse(w, r, ErrMalformedPolicyDocument)
+				return
+			}
+		}
+		role := credential
+		role.RoleName = match[2]
+		temporary, err := iam.NewTemporaryCredential(role, duration, sessionPolicy)
+		if err != nil {
+			logger.Error("Unable to issue temporary credential:", err)
+			writeStsErrorResponse(w, r, err)
+			return
+		}
+		response := AssumeRoleResponse{
+			Result: AssumeRoleResult{
+				Credentials: stsCredentials(temporary),
+				AssumedRoleUser: AssumedRoleUser{
+					Arn: "arn:aws:sts::" + credential.UserId + ":assumed-role/" +
+						match[2] + "/" + sessionName,
+					AssumedRoleId: temporary.AccessKeyID + ":" + sessionName,
+				},
+			},
+			ResponseMetadata: StsResponseMetadata{
+				RequestId: getRequestContext(r).RequestID,
+			},
+		}
+		WriteSuccessResponse(w, EncodeResponse(response))
+	default:
+		writeStsErrorResponse(w, r, ErrInvalidStsAction)
+	}
+}
+
+func parseStsDuration(r *http.Request, defaultDuration, maxDuration time.Duration) (time.Duration, error) {
+	s := r.PostForm.Get("DurationSeconds")
+	if s == "" {
+		return defaultDuration, nil
+	}
+	seconds, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidStsParameter
+	}
+	duration := time.Duration(seconds) * time.Second
+	if duration < stsMinDuration || duration > maxDuration {
+		return 0, ErrInvalidStsParameter
+	}
+	return duration, nil
+}
+
+func stsCredentials(c common.Credential) StsCredentials {
+	return StsCredentials{
+		AccessKeyId:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Expiration:      c.Expiration.UTC().Format(time.RFC3339),
+	}
+}
+
+func writeStsErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	errorResponse := StsErrorResponse{
+		Error: StsError{
+			Type:    "Sender",
+			Code:    "InternalError",
+			Message: "We encountered an internal error, please try again.",
+		},
+		RequestId: getRequestContext(r).RequestID,
+	}
+	if apiErrorCode, ok := err.(ApiError); ok {
+		status = apiErrorCode.HttpStatusCode()
+		errorResponse.Error.Code = apiErrorCode.AwsErrorCode()
+		errorResponse.Error.Message = apiErrorCode.Description()
+	}
+	if status >= http.StatusInternalServerError {
+		errorResponse.Error.Type = "Receiver"
+	}
+	ContextLogger(r).Info("Response status code:", status, "err:", err)
+	WriteSuccessResponseWithStatus(w, EncodeResponse(errorResponse), status)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws/credentials"
+	v4 "github.com/journeymidnight/aws-sdk-go/aws/signer/v4"
+	. "github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/cache"
+	"github.com/journeymidnight/yig/iam/common"
+	"github.com/journeymidnight/yig/log"
+)
+
+var stsParent = common.Credential{
+	UserId:          "hehe",
+	DisplayName:     "hehe",
+	AccessKeyID:     "stshandlerkey",
+	SecretAccessKey: "stshandlersecret",
+}
+
+// callSts sends STS request with form `values` signed by `credential`
+func callSts(t *testing.T, credential common.Credential, values url.Values) *httptest.ResponseRecorder {
+	body := values.Encode()
+	r, err := http.NewRequest("POST", "http://s3.test.com:8080/", strings.NewReader(body))
+	if err != nil {
+		t.Fatal("NewRequest err:", err)
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	signer := v4.NewSigner(credentials.NewStaticCredentials(credential.AccessKeyID,
+		credential.SecretAccessKey, credential.SessionToken))
+	_, err = signer.Sign(r, strings.NewReader(body), "sts", "us-east-1", time.Now())
+	if err != nil {
+		t.Fatal("Sign err:", err)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), RequestContextKey,
+		RequestContext{RequestID: "sts-test", Logger: helper.Logger}))
+	recorder := httptest.NewRecorder()
+	ObjectAPIHandlers{}.StsHandler(NewResponseRecorder(recorder), r)
+	return recorder
+}
+
+func stsError(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	var response StsErrorResponse
+	err := xml.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal("Unmarshal error response err:", err, recorder.Body.String())
+	}
+	return response.Error.Code
+}
+
+func setupSts(t *testing.T) {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	helper.CONFIG.StsKey = "sts_secret"
+	cache.InitializeIamCache()
+	cache.IamCache.Set(stsParent.AccessKeyID, stsParent)
+}
+
+func TestGetSessionToken(t *testing.T) {
+	setupSts(t)
+	recorder := callSts(t, stsParent, url.Values{
+		"Action":          {"GetSessionToken"},
+		"DurationSeconds": {"900"},
+	})
+	if recorder.Code != http.StatusOK {
+		t.Fatal("GetSessionToken:", recorder.Code, recorder.Body.String())
+	}
+	var response GetSessionTokenResponse
+	err := xml.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal("Unmarshal GetSessionToken response err:", err)
+	}
+	c := response.Result.Credentials
+	expiration, err := time.Parse(time.RFC3339, c.Expiration)
+	if err != nil || c.AccessKeyId == "" || c.SecretAccessKey == "" || c.SessionToken == "" ||
+		expiration.Sub(time.Now()) > 15*time.Minute {
+		t.Fatal("GetSessionToken credentials:", c, err)
+	}
+
+	// temporary credentials could not issue new ones
+	temporary := common.Credential{
+		AccessKeyID:     c.AccessKeyId,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+	}
+	for _, action := range []string{"GetSessionToken", "AssumeRole"} {
+		recorder = callSts(t, temporary, url.Values{
+			"Action":          {action},
+			"RoleArn":         {"arn:aws:iam::hehe:role/reader"},
+			"RoleSessionName": {"session"},
+		})
+		if recorder.Code != http.StatusForbidden || stsError(t, recorder) != "AccessDenied" {
+			t.Fatal(action, "by temporary credentials:", recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestAssumeRole(t *testing.T) {
+	setupSts(t)
+	recorder := callSts(t, stsParent, url.Values{
+		"Action":          {"AssumeRole"},
+		"RoleArn":         {"arn:aws:iam::hehe:role/reader"},
+		"RoleSessionName": {"session"},
+		"Policy": {`{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+			`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"}]}`},
+	})
+	if recorder.Code != http.StatusOK {
+		t.Fatal("AssumeRole:", recorder.Code, recorder.Body.String())
+	}
+	var response AssumeRoleResponse
+	err := xml.Unmarshal(recorder.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal("Unmarshal AssumeRole response err:", err)
+	}
+	if response.Result.AssumedRoleUser.Arn != "arn:aws:sts::hehe:assumed-role/reader/session" ||
+		response.Result.Credentials.SessionToken == "" {
+		t.Fatal("AssumeRole response:", response)
+	}
+
+	for _, c := range []struct {
+		name   string
+		values url.Values
+		status int
+		code   string
+	}{
+		{"role of other account", url.Values{
+			"Action":          {"AssumeRole"},
+			"RoleArn":         {"arn:aws:iam::haha:role/reader"},
+			"RoleSessionName": {"session"},
+		}, http.StatusForbidden, "AccessDenied"},
+		{"missing session name", url.Values{
+			"Action":  {"AssumeRole"},
+			"RoleArn": {"arn:aws:iam::hehe:role/reader"},
+		}, http.StatusBadRequest, ""},
+		{"duration too long", url.Values{
+			"Action":          {"AssumeRole"},
+			"RoleArn":         {"arn:aws:iam::hehe:role/reader"},
+			"RoleSessionName": {"session"},
+			"DurationSeconds": {"86400"},
+		}, http.StatusBadRequest, ""},
+		{"malformed policy", url.Values{
+			"Action":          {"AssumeRole"},
+			"RoleArn":         {"arn:aws:iam::hehe:role/reader"},
+			"RoleSessionName": {"session"},
+			"Policy":          {`{"Statement":"hehe"}`},
+		}, http.StatusBadRequest, "MalformedPolicyDocument"},
+		{"unknown action", url.Values{
+			"Action": {"GetFederationToken"},
+		}, http.StatusBadRequest, ""},
+	} {
+		recorder = callSts(t, stsParent, c.values)
+		if recorder.Code != c.status || (c.code != "" && stsError(t, recorder) != c.code) {
+			t.Fatal(c.name, "AssumeRole:", recorder.Code, recorder.Body.String())
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package backend
 
 import (
+	"io"
+	"time"
+
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/log"
-	"io"
+	"github.com/journeymidnight/yig/mods"
 )
 
 const (
@@ -16,6 +19,11 @@ type Usage struct {
 	UsedSpacePercent int // range 0 ~ 100
 }
 
+type ObjectInfo struct {
+	Size         uint64
+	LastModified time.Time
+}
+
 type Cluster interface {
 	// get cluster ID
 	ID() string
@@ -32,6 +40,11 @@ type Cluster interface {
 		offset int64, length uint64) (io.ReadCloser, error)
 	// remove an object
 	Remove(poolName, objectName string) error
+	// list names of all objects in pool, listing stops if `fn` returns
+	// an error and the error is returned
+	List(poolName string, fn func(objectName string) error) error
+	// get size and last modified time of an object
+	Stat(poolName, objectName string) (ObjectInfo, error)
 }
 
 // Backend plugins should implement this interface
@@ -40,3 +53,49 @@ type Plugin interface {
 	// returns cluster ID -> Cluster, panic on errors
 	Initialize(logger *log.Logger, config helper.Config) map[string]Cluster
 }
+
+// backend name -> Plugin, built-in backends register themselves in init(),
+// external backends are loaded from mods plugins by LoadPlugins
+var plugins = make(map[string]Plugin)
+
+func RegisterPlugin(name string, plugin Plugin) {
+	plugins[name] = plugin
+}
+
+// LoadPlugins registers all backend plugins in `yigPlugins`, named as
+// their plugin names in config file
+func LoadPlugins(yigPlugins map[string]*mods.YigPlugin) {
+	for name, p := range yigPlugins {
+		if p.PluginType != mods.BACKEND_PLUGIN {
+			continue
+		}
+		c, err := p.Create(helper.CONFIG.Plugins[name].Args)
+		if err != nil {
+			helper.Logger.Error("failed to initial backend plugin:", name, "\nerr:", err)
+			continue
+		}
+		plugin, ok := c.(Plugin)
+		if !ok {
+			helper.Logger.Error("backend plugin", name, "does not implement backend.Plugin")
+			continue
+		}
+		helper.Logger.Println("Backend plugin is", name)
+		RegisterPlugin(name, plugin)
+	}
+}
+
+// Initialize returns clusters of all backends in `config.Backends`,
+// panic if any backend is not registered
+func Initialize(logger *log.Logger, config helper.Config) map[string]Cluster {
+	clusters := make(map[string]Cluster)
+	for _, name := range config.Backends {
+		plugin, ok := plugins[name]
+		if !ok {
+			panic("Backend not supported: " + name)
+		}
+		for id, cluster := range plugin.Initialize(logger, config) {
+			clusters[id] = cluster
+		}
+	}
+	return clusters
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/journeymidnight/radoshttpd/rados"
 	"github.com/journeymidnight/yig/backend"
 	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
 	"io"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -25,8 +27,22 @@ const (
 	MIN_CHUNK_SIZE             = 512 << 10       // 512K
 	BUFFER_SIZE                = 1 << 20         // 1M
 	MAX_CHUNK_SIZE             = 8 * BUFFER_SIZE // 8M
+	// libradosstriper stores striped object `oid` as pieces named
+	// `oid`.<16 hex digits of piece number>, the first piece always exists
+	STRIPER_FIRST_PIECE_SUFFIX = ".0000000000000000"
 )
 
+func init() {
+	backend.RegisterPlugin("ceph", Plugin{})
+}
+
+// Plugin stores objects in ceph clusters found by config.CephConfigPattern
+type Plugin struct{}
+
+func (Plugin) Initialize(logger *log.Logger, config helper.Config) map[string]backend.Cluster {
+	return Initialize(config)
+}
+
 func Initialize(config helper.Config) map[string]backend.Cluster {
 	cephConfigPattern := config.CephConfigPattern
 	if cephConfigPattern == "" {
@@ -349,7 +365,7 @@ func (cluster *CephCluster) Append(poolname string, existName string, data io.Re
 	if len(oid) == 0 {
 		oid = cluster.getUniqUploadName()
 	}
-	if poolname != backend.BIG_FILE_POOLNAME {
+	if poolname == backend.SMALL_FILE_POOLNAME {
 		return oid, 0,
 			errors.New("specified pool must be used for storing big file.")
 	}
@@ -560,6 +576,53 @@ func (cluster *CephCluster) Remove(poolname string, oid string) error {
 	return striper.Delete(oid)
 }
 
+func (cluster *CephCluster) List(poolname string, fn func(oid string) error) error {
+	pool, err := cluster.Conn.OpenPool(poolname)
+	if err != nil {
+		return errors.New("Bad poolname")
+	}
+	defer pool.Destroy()
+
+	if poolname == backend.SMALL_FILE_POOLNAME {
+		return pool.List(fn)
+	}
+	// objects in other pools are striped, list every object once by
+	// its first piece
+	return pool.List(func(piece string) error {
+		if !strings.HasSuffix(piece, STRIPER_FIRST_PIECE_SUFFIX) {
+			return nil
+		}
+		return fn(strings.TrimSuffix(piece, STRIPER_FIRST_PIECE_SUFFIX))
+	})
+}
+
+func (cluster *CephCluster) Stat(poolname string, oid string) (info backend.ObjectInfo, err error) {
+	pool, err := cluster.Conn.OpenPool(poolname)
+	if err != nil {
+		return info, errors.New("Bad poolname")
+	}
+	defer pool.Destroy()
+
+	if poolname == backend.SMALL_FILE_POOLNAME {
+		info.Size, info.LastModified, err = pool.Stat(oid)
+		return info, err
+	}
+
+	striper, err := pool.CreateStriper()
+	if err != nil {
+		return info, errors.New("Bad ioctx")
+	}
+	defer striper.Destroy()
+
+	size, mtime, err := striper.State(oid)
+	if err != nil {
+		return info, err
+	}
+	info.Size = size
+	info.LastModified = time.Unix(int64(mtime), 0)
+	return info, nil
+}
+
 func (cluster *CephCluster) ID() string {
 	return cluster.Name
 }
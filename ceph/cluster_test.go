@@ -92,3 +92,33 @@ func BenchmarkCephCluster_Put(b *testing.B) {
 		}
 	})
 }
+
+func TestCephCluster_List(t *testing.T) {
+	cluster := SetupMockCeph()
+	conn := cluster.Conn.(MockRadosConn)
+	conn.MockPool.Objects = []string{
+		"1:1",
+		"1:2.0000000000000000",
+		"1:2.0000000000000001",
+		"1:3.0000000000000000",
+	}
+	cluster.Conn = conn
+
+	var oids []string
+	err := cluster.List(backend.SMALL_FILE_POOLNAME, func(oid string) error {
+		oids = append(oids, oid)
+		return nil
+	})
+	if err != nil || len(oids) != 4 {
+		t.Fatal("List small pool:", oids, err)
+	}
+
+	oids = oids[:0]
+	err = cluster.List(backend.BIG_FILE_POOLNAME, func(oid string) error {
+		oids = append(oids, oid)
+		return nil
+	})
+	if err != nil || len(oids) != 2 || oids[0] != "1:2" || oids[1] != "1:3" {
+		t.Fatal("List big pool:", oids, err)
+	}
+}
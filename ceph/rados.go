@@ -1,6 +1,10 @@
 package ceph
 
-import "github.com/journeymidnight/radoshttpd/rados"
+import (
+	"time"
+
+	"github.com/journeymidnight/radoshttpd/rados"
+)
 
 // Interfaces for underlying rados lib, mainly to ease testing/mocking
 
@@ -18,6 +22,10 @@ type Pool interface {
 	Destroy()
 	CreateStriper() (StriperPool, error)
 	WriteSmallObject(oid string, data []byte) error
+	// List calls `fn` with names of all objects in pool, including
+	// pieces of striped objects
+	List(fn func(oid string) error) error
+	Stat(oid string) (size uint64, mtime time.Time, err error)
 }
 
 type StriperPool interface {
@@ -26,6 +34,8 @@ type StriperPool interface {
 	WriteAIO(oid string, data []byte, offset uint64) (AioCompletion, error)
 	Delete(oid string) error
 	Destroy()
+	// State returns size and last modified unix time of a striped object
+	State(oid string) (size uint64, mtime uint64, err error)
 	SetLayoutStripeUnit(uint uint) int
 	SetLayoutStripeCount(count uint) int
 	SetLayoutObjectSize(size uint) int
@@ -0,0 +1,55 @@
+package ceph
+
+// #cgo LDFLAGS: -lrados
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/journeymidnight/radoshttpd/rados"
+)
+
+// radoshttpd doesn't wrap object listing and stat of librados, call them
+// with the I/O context of rados.Pool, which is the only field of it
+func ioctxOf(p *rados.Pool) C.rados_ioctx_t {
+	return *(*C.rados_ioctx_t)(unsafe.Pointer(p))
+}
+
+func (p pool) List(fn func(oid string) error) error {
+	var ctx C.rados_list_ctx_t
+	ret := C.rados_nobjects_list_open(ioctxOf(p.Pool), &ctx)
+	if ret < 0 {
+		return rados.RadosError(int(ret))
+	}
+	defer C.rados_nobjects_list_close(ctx)
+	for {
+		var entry *C.char
+		ret = C.rados_nobjects_list_next(ctx, &entry, nil, nil)
+		if ret == -C.ENOENT {
+			return nil
+		}
+		if ret < 0 {
+			return rados.RadosError(int(ret))
+		}
+		err := fn(C.GoString(entry))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p pool) Stat(oid string) (size uint64, mtime time.Time, err error) {
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+	var c_psize C.uint64_t
+	var c_pmtime C.time_t
+	ret := C.rados_stat(ioctxOf(p.Pool), c_oid, &c_psize, &c_pmtime)
+	if ret < 0 {
+		return 0, mtime, rados.RadosError(int(ret))
+	}
+	return uint64(c_psize), time.Unix(int64(c_pmtime), 0), nil
+}
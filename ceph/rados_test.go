@@ -29,6 +29,7 @@ func (c MockRadosConn) Shutdown() {
 
 type MockPool struct {
 	MockStriper        MockStriperPool
+	Objects            []string // returned by List
 	FixedReadOverhead  time.Duration
 	FixedWriteOverhead time.Duration
 }
@@ -69,6 +70,21 @@ func (p MockPool) WriteSmallObject(oid string, data []byte) error {
 	return nil
 }
 
+func (p MockPool) List(fn func(oid string) error) error {
+	for _, oid := range p.Objects {
+		err := fn(oid)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p MockPool) Stat(oid string) (uint64, time.Time, error) {
+	time.Sleep(p.FixedReadOverhead)
+	return 0, time.Now(), nil
+}
+
 type MockStriperPool struct {
 	FixedReadOverhead  time.Duration
 	FixedWriteOverhead time.Duration
@@ -102,6 +118,11 @@ func (sp MockStriperPool) Destroy() {
 	return
 }
 
+func (sp MockStriperPool) State(oid string) (uint64, uint64, error) {
+	time.Sleep(sp.FixedReadOverhead)
+	return 0, uint64(time.Now().Unix()), nil
+}
+
 func (sp MockStriperPool) SetLayoutStripeUnit(uint uint) int {
 	return 0
 }
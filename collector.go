@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/journeymidnight/yig/api/datatype"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/redis"
 	"github.com/prometheus/client_golang/prometheus"
@@ -10,8 +11,8 @@ import (
 )
 
 const (
-	PidUsagePrefix    = "u_p_" // User usage redis key prefix ,eg. u_p_hehehehe
-	BucketUsagePrefix = "u_b_" // Bucket usage redis ket prefix ,eg u_b_test
+	PidUsagePrefix    = redis.UserUsagePrefix   // User usage redis key prefix ,eg. u_p_hehehehe
+	BucketUsagePrefix = redis.BucketUsagePrefix // Bucket usage redis ket prefix ,eg u_b_test
 )
 
 type Metrics struct {
@@ -23,6 +24,7 @@ type UsageDataWithBucket struct {
 	value        int64
 	owner        string
 	storageClass string
+	tagging      string
 }
 
 type UsageData struct {
@@ -37,7 +39,7 @@ func newGlobalMetric(namespace string, metricName string, docString string, labe
 func NewMetrics(namespace string) *Metrics {
 	return &Metrics{
 		metrics: map[string]*prometheus.Desc{
-			"bucket_usage_byte_metric": newGlobalMetric(namespace, "bucket_usage_byte_metric", "The description of bucket_usage_byte_metric", []string{"bucket_name", "owner", "storage_class"}),
+			"bucket_usage_byte_metric": newGlobalMetric(namespace, "bucket_usage_byte_metric", "The description of bucket_usage_byte_metric", []string{"bucket_name", "owner", "storage_class", "tagging"}),
 			"user_usage_byte_metric":   newGlobalMetric(namespace, "user_usage_byte_metric", "The description of User_usage_byte_metric", []string{"owner_id", "storage_class"}),
 		},
 	}
@@ -56,7 +58,7 @@ func (c *Metrics) Collect(ch chan<- prometheus.Metric) {
 	GaugeMetricDataForBucket := c.GenerateBucketUsageData()
 	for bucket, data := range GaugeMetricDataForBucket {
 		for _, v := range data {
-			ch <- prometheus.MustNewConstMetric(c.metrics["bucket_usage_byte_metric"], prometheus.GaugeValue, float64(v.value), bucket, v.owner, v.storageClass)
+			ch <- prometheus.MustNewConstMetric(c.metrics["bucket_usage_byte_metric"], prometheus.GaugeValue, float64(v.value), bucket, v.owner, v.storageClass, v.tagging)
 		}
 	}
 
@@ -91,8 +93,9 @@ func (c *Metrics) GenerateBucketUsageData() (GaugeMetricData map[string][]UsageD
 				err.Error())
 			return
 		}
+		tagging := formatTagging(bucket.Tagging)
 		for _, data := range datas {
-			GaugeMetricData[bucket.Name] = append(GaugeMetricData[bucket.Name], UsageDataWithBucket{data.value, bucket.OwnerId, data.storageClass})
+			GaugeMetricData[bucket.Name] = append(GaugeMetricData[bucket.Name], UsageDataWithBucket{data.value, bucket.OwnerId, data.storageClass, tagging})
 		}
 	}
 	return
@@ -130,6 +133,17 @@ func (c *Metrics) GenerateUserUsageData() (GaugeMetricData map[string][]UsageDat
 	return
 }
 
+// Bucket tags are flattened into one label like <key1>=<value1>,<key2>=<value2>
+// sorted by key, eg. team=storage,project=yig
+func formatTagging(tags map[string]string) string {
+	tagging := datatype.TaggingFromMap(tags)
+	pairs := make([]string, 0, len(tagging.TagSet.Tags))
+	for _, tag := range tagging.TagSet.Tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	return strings.Join(pairs, ",")
+}
+
 //  get usage from redis
 //  <Storage-Class1>:<usagenumber>,<Storage-Class2>:<usagenumber>
 //  eg. STANDARD:2222
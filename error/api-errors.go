@@ -177,6 +177,51 @@ const (
 	ErrInvalidRestoreInfo
 	ErrCreateRestoreObject
 	ErrInvalidGlacierObject
+	ErrInvalidTag
+	ErrInvalidTagKey
+	ErrInvalidTagValue
+	ErrTagsLimitExceeded
+	ErrDuplicateTagKey
+	ErrInvalidTaggingDirective
+	ErrBucketTagsLimitExceeded
+	ErrNoSuchTagSet
+	ErrInvalidNotificationEvent
+	ErrInvalidNotificationFilter
+	ErrInvalidNotificationDestination
+	ErrInvalidNotificationId
+	ErrExceededNotificationConfigurationsLimit
+	ErrInvalidReplicationRule
+	ErrInvalidReplicationDestination
+	ErrReplicationConfigurationNotFound
+	ErrInvalidObjectLockConfiguration
+	ErrObjectLockConfigurationNotFound
+	ErrObjectLockNotEnabled
+	ErrNoSuchObjectLockConfiguration
+	ErrInvalidObjectRetention
+	ErrPastObjectLockRetainDate
+	ErrObjectLockInvalidHeaders
+	ErrInvalidLegalHold
+	ErrObjectLocked
+	ErrInvalidBucketState
+	ErrInvalidObjectState
+	ErrLcRulesLimitExceeded
+	ErrInvalidLcRuleId
+	ErrInvalidLcFilter
+	ErrInvalidLcDate
+	ErrInvalidLcExpiration
+	ErrInvalidLcTransition
+	ErrInvalidLcNoncurrentVersion
+	ErrInvalidLcAbortMultipartUpload
+	ErrInvalidToken
+	ErrExpiredToken
+	ErrStsNotEnabled
+	ErrInvalidStsAction
+	ErrMissingStsParameter
+	ErrInvalidStsParameter
+	ErrMalformedPolicyDocument
+	ErrPackedPolicyTooLarge
+	ErrNoSuchPublicAccessBlockConfiguration
+	ErrPublicAccessBlocked
 )
 
 // error code to APIError structure, these fields carry respective
@@ -828,6 +873,231 @@ var ErrorCodeResponse = map[ApiErrorCode]ApiErrorStruct{
 		Description:    "Create object thaw operation failed",
 		HttpStatusCode: http.StatusInternalServerError,
 	},
+	ErrInvalidTag: {
+		AwsErrorCode:   "InvalidTag",
+		Description:    "The header 'x-amz-tagging' shall be encoded as UTF-8 then URLEncoded URL query parameters without tag name duplicates.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTagKey: {
+		AwsErrorCode:   "InvalidTag",
+		Description:    "The TagKey you have provided is invalid.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTagValue: {
+		AwsErrorCode:   "InvalidTag",
+		Description:    "The TagValue you have provided is invalid.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrTagsLimitExceeded: {
+		AwsErrorCode:   "BadRequest",
+		Description:    "Object tags cannot be greater than 10.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrDuplicateTagKey: {
+		AwsErrorCode:   "InvalidTag",
+		Description:    "Cannot provide multiple Tags with the same key.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTaggingDirective: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "Unknown tagging directive.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrBucketTagsLimitExceeded: {
+		AwsErrorCode:   "BadRequest",
+		Description:    "Bucket tags cannot be greater than 50.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchTagSet: {
+		AwsErrorCode:   "NoSuchTagSet",
+		Description:    "The TagSet does not exist.",
+		HttpStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidNotificationEvent: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The event is not supported for notifications.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidNotificationFilter: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The filter rule name must be either prefix or suffix, and each of them could be specified only once.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidNotificationDestination: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The notification destination ARN must be specified.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidNotificationId: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The notification configuration ID must be unique.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrExceededNotificationConfigurationsLimit: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The number of notification configurations cannot be greater than 100.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidReplicationRule: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The replication rule is invalid, rule IDs must be unique and prefixes must not overlap.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidReplicationDestination: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The replication destination must be in form of arn:aws:s3:<region>::<bucket> with a configured region.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrReplicationConfigurationNotFound: {
+		AwsErrorCode:   "ReplicationConfigurationNotFoundError",
+		Description:    "The replication configuration was not found.",
+		HttpStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidObjectLockConfiguration: {
+		AwsErrorCode:   "MalformedXML",
+		Description:    "The object lock configuration you provided was not well-formed or did not validate against our published schema.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectLockConfigurationNotFound: {
+		AwsErrorCode:   "ObjectLockConfigurationNotFoundError",
+		Description:    "Object Lock configuration does not exist for this bucket.",
+		HttpStatusCode: http.StatusNotFound,
+	},
+	ErrObjectLockNotEnabled: {
+		AwsErrorCode:   "InvalidRequest",
+		Description:    "Bucket is missing Object Lock Configuration.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchObjectLockConfiguration: {
+		AwsErrorCode:   "NoSuchObjectLockConfiguration",
+		Description:    "The specified object does not have a ObjectLock configuration.",
+		HttpStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidObjectRetention: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The retention mode must be GOVERNANCE or COMPLIANCE and the retain until date must be in ISO 8601 format.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrPastObjectLockRetainDate: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The retain until date must be in the future.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectLockInvalidHeaders: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "x-amz-object-lock-retain-until-date and x-amz-object-lock-mode must both be supplied.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLegalHold: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The legal hold status must be ON or OFF.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectLocked: {
+		AwsErrorCode:   "AccessDenied",
+		Description:    "Access Denied because object protected by object lock.",
+		HttpStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidBucketState: {
+		AwsErrorCode:   "InvalidBucketState",
+		Description:    "The request is not valid with the current state of the bucket.",
+		HttpStatusCode: http.StatusConflict,
+	},
+	ErrInvalidObjectState: {
+		AwsErrorCode:   "InvalidObjectState",
+		Description:    "The operation is not valid for the current state of the object.",
+		HttpStatusCode: http.StatusForbidden,
+	},
+	ErrLcRulesLimitExceeded: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "The number of lifecycle rules should not exceed 1000.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcRuleId: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "Lifecycle rule ID must be unique and no longer than 255 characters.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcFilter: {
+		AwsErrorCode:   "MalformedXML",
+		Description:    "Lifecycle rule should have either Prefix or Filter, and Filter should specify exactly one of Prefix, Tag, And or object size conditions.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcDate: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "Date must be at midnight GMT in ISO 8601 format.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcExpiration: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "Expiration should specify exactly one of Days, Date and ExpiredObjectDeleteMarker.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcTransition: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "Transition should specify Days or Date and a storage class, and objects should be transitioned before they expire.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcNoncurrentVersion: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "NoncurrentDays of noncurrent version actions should be a positive integer.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidLcAbortMultipartUpload: {
+		AwsErrorCode:   "InvalidArgument",
+		Description:    "DaysAfterInitiation should be a positive integer, and AbortIncompleteMultipartUpload cannot be specified with tags or object size conditions.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidToken: {
+		AwsErrorCode:   "InvalidToken",
+		Description:    "The provided token is malformed or otherwise invalid.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrExpiredToken: {
+		AwsErrorCode:   "ExpiredToken",
+		Description:    "The provided token has expired.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrStsNotEnabled: {
+		AwsErrorCode:   "NotImplemented",
+		Description:    "Temporary credentials are not enabled on this server.",
+		HttpStatusCode: http.StatusNotImplemented,
+	},
+	ErrInvalidStsAction: {
+		AwsErrorCode:   "InvalidAction",
+		Description:    "The action or operation requested is invalid.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingStsParameter: {
+		AwsErrorCode:   "MissingParameter",
+		Description:    "A required parameter for the specified action is not supplied.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidStsParameter: {
+		AwsErrorCode:   "InvalidParameterValue",
+		Description:    "An invalid or out-of-range value was supplied for the input parameter.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrMalformedPolicyDocument: {
+		AwsErrorCode:   "MalformedPolicyDocument",
+		Description:    "The policy document was malformed.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrPackedPolicyTooLarge: {
+		AwsErrorCode:   "PackedPolicyTooLarge",
+		Description:    "The session policy is larger than 2048 bytes.",
+		HttpStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchPublicAccessBlockConfiguration: {
+		AwsErrorCode:   "NoSuchPublicAccessBlockConfiguration",
+		Description:    "The public access block configuration was not found.",
+		HttpStatusCode: http.StatusNotFound,
+	},
+	ErrPublicAccessBlocked: {
+		AwsErrorCode:   "AccessDenied",
+		Description:    "Access Denied, public ACLs or policies are blocked by Block Public Access settings.",
+		HttpStatusCode: http.StatusForbidden,
+	},
 }
 
 func (e ApiErrorCode) AwsErrorCode() string {
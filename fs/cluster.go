@@ -0,0 +1,267 @@
+package fs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+)
+
+const (
+	OID_LENGTH = 16 // random bytes in object name
+	FILE_MODE  = 0644
+	DIR_MODE   = 0755
+)
+
+func init() {
+	backend.RegisterPlugin("fs", Plugin{})
+}
+
+// Plugin stores objects as files on local or mounted filesystems
+type Plugin struct{}
+
+func (Plugin) Initialize(logger *log.Logger, config helper.Config) map[string]backend.Cluster {
+	return Initialize(config)
+}
+
+func Initialize(config helper.Config) map[string]backend.Cluster {
+	if len(config.FsDataDirs) == 0 {
+		panic("No fs data directory configured")
+	}
+	clusters := make(map[string]backend.Cluster)
+	for _, dir := range config.FsDataDirs {
+		c, err := NewFsStorage(dir)
+		if err != nil {
+			panic("Failed to open fs data directory " + dir + ": " + err.Error())
+		}
+		clusters[c.ID()] = c
+	}
+	return clusters
+}
+
+// FsCluster keeps objects of pool `pool` in files
+// <Dir>/<pool>/<first 2 chars of oid>/<oid>, the directory works as
+// fsid of ceph clusters
+type FsCluster struct {
+	Dir string
+}
+
+func NewFsStorage(dir string) (*FsCluster, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, DIR_MODE)
+	if err != nil {
+		return nil, err
+	}
+	helper.Logger.Info("Loading fs data directory", dir)
+	return &FsCluster{Dir: dir}, nil
+}
+
+func newOid() (string, error) {
+	buf := make([]byte, OID_LENGTH)
+	_, err := io.ReadFull(rand.Reader, buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (cluster *FsCluster) path(poolName, oid string) (string, error) {
+	if poolName == "" || len(oid) < 2 || filepath.Base(poolName) != poolName ||
+		filepath.Base(oid) != oid {
+		return "", fmt.Errorf("Bad pool %s or oid %s", poolName, oid)
+	}
+	return filepath.Join(cluster.Dir, poolName, oid[:2], oid), nil
+}
+
+func (cluster *FsCluster) ID() string {
+	return cluster.Dir
+}
+
+func (cluster *FsCluster) GetUsage() (usage backend.Usage, err error) {
+	var stat syscall.Statfs_t
+	err = syscall.Statfs(cluster.Dir, &stat)
+	if err != nil {
+		return usage, err
+	}
+	if stat.Blocks == 0 {
+		return usage, errors.New("Bad filesystem stat of " + cluster.Dir)
+	}
+	usage.UsedSpacePercent = int((stat.Blocks - stat.Bfree) * 100 / stat.Blocks)
+	return
+}
+
+func (cluster *FsCluster) Put(poolName string, data io.Reader) (oid string,
+	size uint64, err error) {
+
+	oid, err = newOid()
+	if err != nil {
+		return "", 0, err
+	}
+	path, err := cluster.path(poolName, oid)
+	if err != nil {
+		return "", 0, err
+	}
+	err = os.MkdirAll(filepath.Dir(path), DIR_MODE)
+	if err != nil {
+		return "", 0, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, FILE_MODE)
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := io.Copy(f, data)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+	return oid, uint64(n), nil
+}
+
+func (cluster *FsCluster) Append(poolName, existName string, data io.Reader,
+	offset int64) (oid string, size uint64, err error) {
+
+	oid = existName
+	if len(oid) == 0 {
+		oid, err = newOid()
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	path, err := cluster.path(poolName, oid)
+	if err != nil {
+		return oid, 0, err
+	}
+	err = os.MkdirAll(filepath.Dir(path), DIR_MODE)
+	if err != nil {
+		return oid, 0, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, FILE_MODE)
+	if err != nil {
+		return oid, 0, err
+	}
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		f.Close()
+		return oid, 0, err
+	}
+	n, err := io.Copy(f, data)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		return oid, 0, err
+	}
+	return oid, uint64(n), nil
+}
+
+type fileReader struct {
+	io.Reader
+	file *os.File
+}
+
+func (r *fileReader) Close() error {
+	return r.file.Close()
+}
+
+func (cluster *FsCluster) GetReader(poolName, oid string, startOffset int64,
+	length uint64) (io.ReadCloser, error) {
+
+	path, err := cluster.path(poolName, oid)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Seek(startOffset, io.SeekStart)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if length == 0 {
+		return f, nil
+	}
+	return &fileReader{
+		Reader: io.LimitReader(f, int64(length)),
+		file:   f,
+	}, nil
+}
+
+func (cluster *FsCluster) Remove(poolName, oid string) error {
+	path, err := cluster.path(poolName, oid)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		// already removed, e.g. gc retries
+		return nil
+	}
+	return err
+}
+
+func (cluster *FsCluster) List(poolName string, fn func(oid string) error) error {
+	if poolName == "" || filepath.Base(poolName) != poolName {
+		return fmt.Errorf("Bad pool %s", poolName)
+	}
+	dirs, err := ioutil.ReadDir(filepath.Join(cluster.Dir, poolName))
+	if os.IsNotExist(err) {
+		// nothing is written to the pool yet
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(cluster.Dir, poolName, dir.Name()))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			err = fn(f.Name())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cluster *FsCluster) Stat(poolName, oid string) (info backend.ObjectInfo, err error) {
+	path, err := cluster.path(poolName, oid)
+	if err != nil {
+		return info, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return info, err
+	}
+	info.Size = uint64(fi.Size())
+	info.LastModified = fi.ModTime()
+	return info, nil
+}
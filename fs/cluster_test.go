@@ -0,0 +1,168 @@
+package fs_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/fs"
+)
+
+func setupFsCluster(t *testing.T) (*fs.FsCluster, func()) {
+	dir, err := ioutil.TempDir("", "yig-fs")
+	if err != nil {
+		t.Fatal("TempDir err:", err)
+	}
+	return &fs.FsCluster{Dir: dir}, func() { os.RemoveAll(dir) }
+}
+
+func TestFsCluster_PutAndGet(t *testing.T) {
+	cluster, cleanup := setupFsCluster(t)
+	defer cleanup()
+
+	data := []byte("hello, yig")
+	oid, size, err := cluster.Put(backend.SMALL_FILE_POOLNAME, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal("Put err:", err)
+	}
+	if size != uint64(len(data)) {
+		t.Fatal("Put size:", size, "should be:", len(data))
+	}
+
+	reader, err := cluster.GetReader(backend.SMALL_FILE_POOLNAME, oid, 0, 0)
+	if err != nil {
+		t.Fatal("GetReader err:", err)
+	}
+	got, err := ioutil.ReadAll(reader)
+	reader.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatal("GetReader whole object:", string(got), err)
+	}
+
+	reader, err = cluster.GetReader(backend.SMALL_FILE_POOLNAME, oid, 7, 3)
+	if err != nil {
+		t.Fatal("GetReader err:", err)
+	}
+	got, err = ioutil.ReadAll(reader)
+	reader.Close()
+	if err != nil || string(got) != "yig" {
+		t.Fatal("GetReader range:", string(got), err)
+	}
+
+	err = cluster.Remove(backend.SMALL_FILE_POOLNAME, oid)
+	if err != nil {
+		t.Fatal("Remove err:", err)
+	}
+	_, err = cluster.GetReader(backend.SMALL_FILE_POOLNAME, oid, 0, 0)
+	if err == nil {
+		t.Fatal("GetReader should fail after Remove")
+	}
+	// removing twice is fine
+	err = cluster.Remove(backend.SMALL_FILE_POOLNAME, oid)
+	if err != nil {
+		t.Fatal("Remove again err:", err)
+	}
+}
+
+func TestFsCluster_Append(t *testing.T) {
+	cluster, cleanup := setupFsCluster(t)
+	defer cleanup()
+
+	oid, size, err := cluster.Append(backend.BIG_FILE_POOLNAME, "",
+		bytes.NewReader([]byte("hello")), 0)
+	if err != nil || size != 5 {
+		t.Fatal("Append err:", err, size)
+	}
+	_, size, err = cluster.Append(backend.BIG_FILE_POOLNAME, oid,
+		bytes.NewReader([]byte(", yig")), 5)
+	if err != nil || size != 5 {
+		t.Fatal("Append err:", err, size)
+	}
+	reader, err := cluster.GetReader(backend.BIG_FILE_POOLNAME, oid, 0, 10)
+	if err != nil {
+		t.Fatal("GetReader err:", err)
+	}
+	defer reader.Close()
+	got, err := ioutil.ReadAll(reader)
+	if err != nil || string(got) != "hello, yig" {
+		t.Fatal("GetReader after Append:", string(got), err)
+	}
+}
+
+func TestFsCluster_BadName(t *testing.T) {
+	cluster, cleanup := setupFsCluster(t)
+	defer cleanup()
+
+	_, err := cluster.GetReader("../"+backend.SMALL_FILE_POOLNAME, "0123456789", 0, 0)
+	if err == nil {
+		t.Fatal("GetReader should fail with bad pool name")
+	}
+	err = cluster.Remove(backend.SMALL_FILE_POOLNAME, "../../etc")
+	if err == nil {
+		t.Fatal("Remove should fail with bad object name")
+	}
+}
+
+func TestFsCluster_GetUsage(t *testing.T) {
+	cluster, cleanup := setupFsCluster(t)
+	defer cleanup()
+
+	usage, err := cluster.GetUsage()
+	if err != nil {
+		t.Fatal("GetUsage err:", err)
+	}
+	if usage.UsedSpacePercent < 0 || usage.UsedSpacePercent > 100 {
+		t.Fatal("GetUsage bad percent:", usage.UsedSpacePercent)
+	}
+}
+
+func TestFsCluster_ListAndStat(t *testing.T) {
+	cluster, cleanup := setupFsCluster(t)
+	defer cleanup()
+
+	err := cluster.List(backend.BIG_FILE_POOLNAME, func(oid string) error {
+		t.Fatal("List empty pool returns", oid)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("List empty pool err:", err)
+	}
+
+	oids := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		oid, _, err := cluster.Put(backend.BIG_FILE_POOLNAME, bytes.NewReader([]byte("data")))
+		if err != nil {
+			t.Fatal("Put err:", err)
+		}
+		oids[oid] = true
+	}
+	listed := make(map[string]bool)
+	err = cluster.List(backend.BIG_FILE_POOLNAME, func(oid string) error {
+		listed[oid] = true
+		return nil
+	})
+	if err != nil || len(listed) != len(oids) {
+		t.Fatal("List:", listed, err)
+	}
+	for oid := range oids {
+		if !listed[oid] {
+			t.Fatal("List misses", oid)
+		}
+		info, err := cluster.Stat(backend.BIG_FILE_POOLNAME, oid)
+		if err != nil || info.Size != 4 || time.Since(info.LastModified) > time.Minute {
+			t.Fatal("Stat:", info, err)
+		}
+	}
+
+	stop := errors.New("stop")
+	err = cluster.List(backend.BIG_FILE_POOLNAME, func(oid string) error {
+		return stop
+	})
+	if err != stop {
+		t.Fatal("List should return error of fn:", err)
+	}
+}
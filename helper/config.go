@@ -2,6 +2,7 @@ package helper
 
 import (
 	"io/ioutil"
+	"net"
 
 	"github.com/BurntSushi/toml"
 )
@@ -26,6 +27,7 @@ type Config struct {
 	BindAdminAddress     string                  `toml:"admin_listener"`
 	SSLKeyPath           string                  `toml:"ssl_key_path"`
 	SSLCertPath          string                  `toml:"ssl_cert_path"`
+	TrustedProxies       []string                `toml:"trusted_proxies"` // IPs or CIDRs of proxies whose X-Forwarded-Proto is trusted
 	ZookeeperAddress     string                  `toml:"zk_address"`
 
 	InstanceId             string // if empty, generated one at server startup
@@ -34,16 +36,26 @@ type Config struct {
 	EnablePProf            bool   `toml:"enable_pprof"`
 	BindPProfAddress       string `toml:"pprof_listener"`
 	AdminKey               string `toml:"admin_key"` //used for tools/admin to communicate with yig
+	StsKey                 string `toml:"sts_key"`   // encrypts session tokens of temporary credentials, STS is disabled if empty
 	GcThread               int    `toml:"gc_thread"`
 	LcThread               int    //used for tools/lc only, set worker numbers to do lc
-	LogLevel               string `toml:"log_level"` // "info", "warn", "error"
+	ReplicationThread      int    `toml:"replication_thread"` // used for tools/replicate only
+	RestoreThread          int    `toml:"restore_thread"`     // used for tools/restore only
+	LogLevel               string `toml:"log_level"`          // "info", "warn", "error"
 	CephConfigPattern      string `toml:"ceph_config_pattern"`
 	ReservedOrigins        string `toml:"reserved_origins"` // www.ccc.com,www.bbb.com,127.0.0.1
-	MetaStore              string `toml:"meta_store"`
+	MetaStore              string `toml:"meta_store"`       // "tidb", "postgres" or "sqlite"
+	MetaStorePath          string `toml:"meta_store_path"`  // snapshot file of "sqlite" meta store
 	TidbInfo               string `toml:"tidb_info"`
+	PgInfo                 string `toml:"pg_info"` // connection string of "postgres" meta store
 	KeepAlive              bool   `toml:"keepalive"`
 	EnableCompression      bool   `toml:"enable_compression"`
 
+	// Data backends, "ceph", "fs" or name of a backend plugin
+	Backends []string `toml:"backends"`
+	// Directories used by "fs" backend, each directory is one cluster
+	FsDataDirs []string `toml:"fs_data_dirs"`
+
 	//About cache
 	EnableUsagePush       bool   `toml:"enable_usage_push"`
 	RedisAddress          string `toml:"redis_address"`           // redis connection string, e.g localhost:1234
@@ -78,6 +90,26 @@ type Config struct {
 	DownloadBufPoolSize int64 `toml:"download_buf_pool_size"`
 	UploadMinChunkSize  int64 `toml:"upload_min_chunk_size"`
 	UploadMaxChunkSize  int64 `toml:"upload_max_chunk_size"`
+
+	// Remote YIG regions that buckets could replicate to, keyed by region name
+	ReplicationTargets map[string]ReplicationTargetConfig `toml:"replication_targets"`
+
+	// Block public access settings enforced on all buckets, in addition to
+	// PublicAccessBlock configuration of each bucket
+	PublicAccessBlock PublicAccessBlockConfig `toml:"public_access_block"`
+}
+
+type ReplicationTargetConfig struct {
+	Endpoint  string `toml:"endpoint"` // e.g http://s3.cn-bj-2.example.com
+	AccessKey string `toml:"access_key"`
+	SecretKey string `toml:"secret_key"`
+}
+
+type PublicAccessBlockConfig struct {
+	BlockPublicAcls       bool `toml:"block_public_acls"`
+	IgnorePublicAcls      bool `toml:"ignore_public_acls"`
+	BlockPublicPolicy     bool `toml:"block_public_policy"`
+	RestrictPublicBuckets bool `toml:"restrict_public_buckets"`
 }
 
 type PluginConfig struct {
@@ -118,14 +150,25 @@ func MarshalTOMLConfig() error {
 	CONFIG.BindAdminAddress = c.BindAdminAddress
 	CONFIG.SSLKeyPath = c.SSLKeyPath
 	CONFIG.SSLCertPath = c.SSLCertPath
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			panic("load yig.toml error: invalid trusted_proxies " + proxy)
+		}
+	}
+	CONFIG.TrustedProxies = c.TrustedProxies
 	CONFIG.ZookeeperAddress = c.ZookeeperAddress
 	CONFIG.DebugMode = c.DebugMode
 	CONFIG.EnablePProf = c.EnablePProf
 	CONFIG.BindPProfAddress = c.BindPProfAddress
 	CONFIG.AdminKey = c.AdminKey
+	CONFIG.StsKey = c.StsKey
 	CONFIG.CephConfigPattern = c.CephConfigPattern
+	CONFIG.Backends = Ternary(len(c.Backends) == 0,
+		[]string{"ceph"}, c.Backends).([]string)
+	CONFIG.FsDataDirs = c.FsDataDirs
 	CONFIG.ReservedOrigins = c.ReservedOrigins
 	CONFIG.TidbInfo = c.TidbInfo
+	CONFIG.PgInfo = c.PgInfo
 	CONFIG.KeepAlive = c.KeepAlive
 	CONFIG.EnableCompression = c.EnableCompression
 	CONFIG.InstanceId = Ternary(c.InstanceId == "",
@@ -136,8 +179,15 @@ func MarshalTOMLConfig() error {
 		1, c.GcThread).(int)
 	CONFIG.LcThread = Ternary(c.LcThread == 0,
 		1, c.LcThread).(int)
+	CONFIG.ReplicationThread = Ternary(c.ReplicationThread == 0,
+		1, c.ReplicationThread).(int)
+	CONFIG.RestoreThread = Ternary(c.RestoreThread == 0,
+		1, c.RestoreThread).(int)
+	CONFIG.ReplicationTargets = c.ReplicationTargets
+	CONFIG.PublicAccessBlock = c.PublicAccessBlock
 	CONFIG.LogLevel = Ternary(len(c.LogLevel) == 0, "info", c.LogLevel).(string)
 	CONFIG.MetaStore = Ternary(c.MetaStore == "", "tidb", c.MetaStore).(string)
+	CONFIG.MetaStorePath = c.MetaStorePath
 
 	CONFIG.EnableUsagePush = c.EnableUsagePush
 	CONFIG.RedisAddress = c.RedisAddress
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"bytes"
+	"io"
 
 	"github.com/ugorji/go/codec"
 )
@@ -17,3 +18,14 @@ func MsgPackUnMarshal(data []byte, v interface{}) error {
 	dec := codec.NewDecoder(buf, new(codec.MsgpackHandle))
 	return dec.Decode(v)
 }
+
+// NewMsgPackEncoder returns an encoder writing values one after another to `w`
+func NewMsgPackEncoder(w io.Writer) *codec.Encoder {
+	return codec.NewEncoder(w, new(codec.MsgpackHandle))
+}
+
+// NewMsgPackDecoder returns a decoder reading values written by
+// NewMsgPackEncoder, it returns io.EOF after the last value
+func NewMsgPackDecoder(r io.Reader) *codec.Decoder {
+	return codec.NewDecoder(r, new(codec.MsgpackHandle))
+}
@@ -1,6 +1,11 @@
 package common
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype/policy"
+)
 
 // credential container for access and secret keys.
 type Credential struct {
@@ -9,6 +14,32 @@ type Credential struct {
 	AccessKeyID          string
 	SecretAccessKey      string
 	AllowOtherUserAccess bool
+	// Identity-based policies attached to the user and the access key,
+	// the access key has full permissions of its user if empty
+	Policies []policy.Policy
+
+	// Following are set only for temporary credentials issued by STS
+	SessionToken  string         `json:"-"`
+	Expiration    time.Time      `json:"-"`
+	SessionPolicy *policy.Policy `json:"-"` // nil if not restricted by a session policy
+	RoleName      string         `json:"-"` // set if issued by AssumeRole
+}
+
+// IsTemporary returns true for temporary credentials issued by STS
+func (a Credential) IsTemporary() bool {
+	return a.SessionToken != ""
+}
+
+// PrincipalArn returns ARN of the requester, which is the role for temporary
+// credentials issued by AssumeRole, or empty for anonymous requests
+func (a Credential) PrincipalArn() string {
+	if a.UserId == "" {
+		return ""
+	}
+	if a.RoleName != "" {
+		return "arn:aws:iam::" + a.UserId + ":role/" + a.RoleName
+	}
+	return "arn:aws:iam::" + a.UserId + ":root"
 }
 
 func (a Credential) String() string {
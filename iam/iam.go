@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/journeymidnight/yig/api/datatype/policy"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam/cache"
 	"github.com/journeymidnight/yig/iam/common"
@@ -21,6 +22,13 @@ type IamClient interface {
 	GetCredential(string) (common.Credential, error)
 }
 
+// IamPolicyClient is optionally implemented by IAM plugins supporting
+// identity-based policies, it returns policies attached to the user and
+// the access key of credential, built by policy.ParseIdentityPolicy
+type IamPolicyClient interface {
+	GetPolicies(credential common.Credential) ([]policy.Policy, error)
+}
+
 var iamClient IamClient
 
 func InitializeIamClient(plugins map[string]*mods.YigPlugin) {
@@ -55,6 +63,12 @@ func GetCredential(accessKey string) (credential common.Credential, err error) {
 	if err != nil {
 		return credential, err
 	}
+	if c, ok := iamClient.(IamPolicyClient); ok {
+		credential.Policies, err = c.GetPolicies(credential)
+		if err != nil {
+			return credential, err
+		}
+	}
 	cache.IamCache.Set(accessKey, credential)
 	return credential, nil
 
@@ -0,0 +1,155 @@
+package iam
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype/policy"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/common"
+)
+
+const (
+	// access keys of temporary credentials start with this prefix
+	TemporaryAccessKeyPrefix = "STS"
+	temporaryAccessKeyLength = 20
+	temporarySecretKeyLength = 40
+)
+
+// session of temporary credentials, it's encrypted into session token so
+// temporary credentials are verified without being stored
+type session struct {
+	AccessKey       string
+	SecretKey       string
+	ParentAccessKey string
+	Expiration      time.Time
+	Policy          string `json:",omitempty"`
+	Role            string `json:",omitempty"`
+}
+
+func sessionCipher() (cipher.AEAD, error) {
+	if helper.CONFIG.StsKey == "" {
+		return nil, ErrStsNotEnabled
+	}
+	key := sha256.Sum256([]byte(helper.CONFIG.StsKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+const (
+	accessKeyTable = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	secretKeyTable = accessKeyTable + "abcdefghijklmnopqrstuvwxyz"
+)
+
+func randomKey(table string, length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = table[int(b[i])%len(table)]
+	}
+	return string(b), nil
+}
+
+// NewTemporaryCredential issues temporary credentials acting as `parent`
+// until `duration` later, restricted by `sessionPolicy` if not empty.
+// They're credentials of role parent.RoleName if set.
+// Session policy should be validated by policy.ParseIdentityPolicy.
+func NewTemporaryCredential(parent common.Credential, duration time.Duration,
+	sessionPolicy string) (credential common.Credential, err error) {
+
+	aead, err := sessionCipher()
+	if err != nil {
+		return
+	}
+	accessKey, err := randomKey(accessKeyTable,
+		temporaryAccessKeyLength-len(TemporaryAccessKeyPrefix))
+	if err != nil {
+		return
+	}
+	secretKey, err := randomKey(secretKeyTable, temporarySecretKeyLength)
+	if err != nil {
+		return
+	}
+	s := session{
+		AccessKey:       TemporaryAccessKeyPrefix + accessKey,
+		SecretKey:       secretKey,
+		ParentAccessKey: parent.AccessKeyID,
+		Expiration:      time.Now().Add(duration).UTC().Truncate(time.Second),
+		Policy:          sessionPolicy,
+		Role:            parent.RoleName,
+	}
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return
+	}
+	// access key is authenticated along with the token, so a token works
+	// only with its own access key
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(s.AccessKey))
+
+	credential = parent
+	credential.AccessKeyID = s.AccessKey
+	credential.SecretAccessKey = s.SecretKey
+	credential.SessionToken = base64.RawURLEncoding.EncodeToString(sealed)
+	credential.Expiration = s.Expiration
+	return credential, nil
+}
+
+// GetTemporaryCredential verifies session token of temporary access key
+// `accessKey`, the returned credential acts as the credential it's issued
+// for, restricted by its session policy. Temporary credentials stop working
+// once the parent access key is removed.
+func GetTemporaryCredential(accessKey, sessionToken string) (credential common.Credential, err error) {
+	aead, err := sessionCipher()
+	if err != nil {
+		return
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(sessionToken)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return credential, ErrInvalidToken
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(accessKey))
+	if err != nil {
+		return credential, ErrInvalidToken
+	}
+	var s session
+	if err = json.Unmarshal(plaintext, &s); err != nil || s.AccessKey != accessKey {
+		return credential, ErrInvalidToken
+	}
+	if time.Now().After(s.Expiration) {
+		return credential, ErrExpiredToken
+	}
+
+	credential, err = GetCredential(s.ParentAccessKey)
+	if err != nil {
+		return credential, ErrInvalidAccessKeyID
+	}
+	if s.Policy != "" {
+		credential.SessionPolicy, err = policy.ParseIdentityPolicy(strings.NewReader(s.Policy))
+		if err != nil {
+			return credential, ErrInvalidToken
+		}
+	}
+	credential.AccessKeyID = s.AccessKey
+	credential.SecretAccessKey = s.SecretKey
+	credential.SessionToken = sessionToken
+	credential.Expiration = s.Expiration
+	credential.RoleName = s.Role
+	return credential, nil
+}
@@ -0,0 +1,160 @@
+package iam
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/common"
+)
+
+// fakeIam knows credentials by access key
+type fakeIam map[string]common.Credential
+
+func (f fakeIam) GetKeysByUid(uid string) ([]common.Credential, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f fakeIam) GetCredential(accessKey string) (common.Credential, error) {
+	c, ok := f[accessKey]
+	if !ok {
+		return c, errors.New("no such access key")
+	}
+	return c, nil
+}
+
+var parent = common.Credential{
+	UserId:          "hehe",
+	DisplayName:     "hehe",
+	AccessKeyID:     "stsparentkey",
+	SecretAccessKey: "stsparentsecret",
+}
+
+func setupSts(t *testing.T) {
+	helper.CONFIG.StsKey = "sts_secret"
+	iamClient = fakeIam{parent.AccessKeyID: parent}
+}
+
+func TestTemporaryCredential(t *testing.T) {
+	setupSts(t)
+	sessionPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+		`"Action":"s3:GetObject","Resource":"arn:aws:s3:::hehe/*"}]}`
+	temporary, err := NewTemporaryCredential(parent, time.Hour, sessionPolicy)
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	if !temporary.IsTemporary() || temporary.AccessKeyID == parent.AccessKeyID ||
+		temporary.SecretAccessKey == parent.SecretAccessKey || temporary.SessionToken == "" {
+		t.Fatal("Temporary credential:", temporary)
+	}
+
+	got, err := GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != nil {
+		t.Fatal("GetTemporaryCredential err:", err)
+	}
+	if got.UserId != parent.UserId || got.AccessKeyID != temporary.AccessKeyID ||
+		got.SecretAccessKey != temporary.SecretAccessKey ||
+		!got.Expiration.Equal(temporary.Expiration) ||
+		got.SessionPolicy == nil || len(got.SessionPolicy.Statements) != 1 {
+		t.Fatal("GetTemporaryCredential:", got)
+	}
+
+	temporary, err = NewTemporaryCredential(parent, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential without policy err:", err)
+	}
+	got, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != nil || got.SessionPolicy != nil || got.PrincipalArn() != "arn:aws:iam::hehe:root" {
+		t.Fatal("GetTemporaryCredential without policy:", got, err)
+	}
+
+	role := parent
+	role.RoleName = "reader"
+	temporary, err = NewTemporaryCredential(role, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential of role err:", err)
+	}
+	got, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != nil || got.RoleName != "reader" || got.PrincipalArn() != "arn:aws:iam::hehe:role/reader" {
+		t.Fatal("GetTemporaryCredential of role:", got, err)
+	}
+}
+
+func TestTemporaryCredentialInvalid(t *testing.T) {
+	setupSts(t)
+	temporary, err := NewTemporaryCredential(parent, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	other, err := NewTemporaryCredential(parent, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	sealed, _ := base64.RawURLEncoding.DecodeString(temporary.SessionToken)
+	sealed[len(sealed)-1] ^= 1
+	tampered := base64.RawURLEncoding.EncodeToString(sealed)
+
+	for _, c := range []struct {
+		name         string
+		accessKey    string
+		sessionToken string
+	}{
+		{"tampered", temporary.AccessKeyID, tampered},
+		{"not base64", temporary.AccessKeyID, "!" + temporary.SessionToken},
+		{"truncated", temporary.AccessKeyID, temporary.SessionToken[:8]},
+		{"empty", temporary.AccessKeyID, ""},
+		{"token of other access key", temporary.AccessKeyID, other.SessionToken},
+		{"permanent access key", parent.AccessKeyID, temporary.SessionToken},
+	} {
+		_, err = GetTemporaryCredential(c.accessKey, c.sessionToken)
+		if err != ErrInvalidToken {
+			t.Fatal(c.name, "GetTemporaryCredential:", err)
+		}
+	}
+
+	// sealed by another key
+	helper.CONFIG.StsKey = "another_secret"
+	_, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != ErrInvalidToken {
+		t.Fatal("GetTemporaryCredential with another key:", err)
+	}
+	helper.CONFIG.StsKey = ""
+	_, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != ErrStsNotEnabled {
+		t.Fatal("GetTemporaryCredential without key:", err)
+	}
+	_, err = NewTemporaryCredential(parent, time.Hour, "")
+	if err != ErrStsNotEnabled {
+		t.Fatal("NewTemporaryCredential without key:", err)
+	}
+}
+
+func TestTemporaryCredentialExpired(t *testing.T) {
+	setupSts(t)
+	temporary, err := NewTemporaryCredential(parent, -time.Minute, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	_, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != ErrExpiredToken {
+		t.Fatal("GetTemporaryCredential expired:", err)
+	}
+}
+
+// temporary credentials stop working once the parent access key is removed
+func TestTemporaryCredentialParentRemoved(t *testing.T) {
+	setupSts(t)
+	removed := parent
+	removed.AccessKeyID = "stsremovedkey"
+	temporary, err := NewTemporaryCredential(removed, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	_, err = GetTemporaryCredential(temporary.AccessKeyID, temporary.SessionToken)
+	if err != ErrInvalidAccessKeyID {
+		t.Fatal("GetTemporaryCredential of removed parent:", err)
+	}
+}
@@ -0,0 +1,14 @@
+BASEDIR=$(dirname $(pwd))
+echo ${BASEDIR}
+WORKDIR=$1
+sudo docker rm --force replicate
+if [ -x "$BASEDIR/replicate" ]; then
+    sudo docker run -d --name replicate \
+			 -v ${BASEDIR}/integrate/cephconf:/etc/ceph/ \
+			 -v ${BASEDIR}/integrate/yigconf:/etc/yig/ \
+			 -v ${BASEDIR}:/var/log/yig \
+			 -v ${BASEDIR}:${WORKDIR} \
+                         --net=integrate_vpcbr \
+			 journeymidnight/yig /work/replicate
+    echo "started replicate from local dir"
+fi
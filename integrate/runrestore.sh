@@ -0,0 +1,14 @@
+BASEDIR=$(dirname $(pwd))
+echo ${BASEDIR}
+WORKDIR=$1
+sudo docker rm --force restore
+if [ -x "$BASEDIR/restore" ]; then
+    sudo docker run -d --name restore \
+			 -v ${BASEDIR}/integrate/cephconf:/etc/ceph/ \
+			 -v ${BASEDIR}/integrate/yigconf:/etc/yig/ \
+			 -v ${BASEDIR}:/var/log/yig \
+			 -v ${BASEDIR}:${WORKDIR} \
+                         --net=integrate_vpcbr \
+			 journeymidnight/yig /work/restore
+    echo "started restore from local dir"
+fi
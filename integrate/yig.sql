@@ -32,6 +32,12 @@ CREATE TABLE `buckets` (
   `policy` JSON DEFAULT NULL,
   `website` JSON DEFAULT NULL,
   `encryption` JSON DEFAULT NULL,
+  `tagging` JSON DEFAULT NULL,
+  `notification` JSON DEFAULT NULL,
+  `replication` JSON DEFAULT NULL,
+  `objectlock` JSON DEFAULT NULL,
+  `placement` JSON DEFAULT NULL,
+  `publicaccessblock` JSON DEFAULT NULL,
   `createtime` datetime DEFAULT NULL,
   `usages` bigint(20) DEFAULT NULL,
   `versioning` varchar(255) DEFAULT NULL,
@@ -50,6 +56,7 @@ CREATE TABLE `cluster` (
   `fsid` varchar(255) DEFAULT NULL,
   `pool` varchar(255) DEFAULT NULL,
   `weight` int(11) DEFAULT NULL,
+  `status` tinyint(1) NOT NULL DEFAULT 0,
    UNIQUE KEY `rowkey` (`fsid`,`pool`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -142,6 +149,7 @@ CREATE TABLE `multiparts` (
   `cipher` blob DEFAULT NULL,
   `attrs` JSON DEFAULT NULL,
   `storageclass` tinyint(1) DEFAULT 0,
+  `tagging` JSON DEFAULT NULL,
   UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`uploadtime`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -197,10 +205,37 @@ CREATE TABLE `objects` (
   `initializationvector` blob DEFAULT NULL,
   `type` tinyint(1) DEFAULT 0,
   `storageclass` tinyint(1) DEFAULT 0,
+  `tagging` JSON DEFAULT NULL,
+  `replicationstatus` varchar(255) DEFAULT NULL,
+  `objectlockmode` varchar(255) DEFAULT NULL,
+  `retainuntildate` datetime DEFAULT NULL,
+  `legalhold` tinyint(1) DEFAULT 0,
    UNIQUE KEY `rowkey` (`bucketname`,`name`,`version`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `replication`
+--
+
+DROP TABLE IF EXISTS `replication`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `replication` (
+  `bucketname` varchar(255) DEFAULT NULL,
+  `objectname` varchar(255) DEFAULT NULL,
+  `version` bigint(20) UNSIGNED DEFAULT NULL,
+  `operation` tinyint(1) DEFAULT 0,
+  `targetregion` varchar(255) DEFAULT NULL,
+  `targetbucket` varchar(255) DEFAULT NULL,
+  `storageclass` varchar(255) DEFAULT NULL,
+  `status` varchar(255) DEFAULT NULL,
+  `mtime` datetime DEFAULT NULL,
+  `triedtimes` int(11) DEFAULT NULL,
+   UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `restoreobjectpart`
 --
@@ -243,6 +278,7 @@ CREATE TABLE `restoreobjects` (
   `size` bigint(20) DEFAULT NULL,
   `objectid` varchar(255) DEFAULT NULL,
   `etag` varchar(255) DEFAULT NULL,
+  `tier` tinyint(1) DEFAULT '1',
   UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`version`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -291,4 +327,17 @@ DROP TABLE IF EXISTS `lifecycle`;
 CREATE TABLE `lifecycle` (
                        `bucketname` varchar(255) DEFAULT NULL,
                        `status` varchar(255) DEFAULT NULL
-) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
\ No newline at end of file
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+
+--
+-- Table structure for table `schema_version`, see `yig migrate`
+--
+
+DROP TABLE IF EXISTS `schema_version`;
+CREATE TABLE `schema_version` (
+  `version` int(11) NOT NULL,
+  `name` varchar(255) DEFAULT NULL,
+  `appliedtime` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+INSERT INTO `schema_version` (`version`,`name`) VALUES (1,'initial schema'),(2,'object tagging'),(3,'bucket tagging'),(4,'bucket notification'),(5,'bucket replication'),(6,'object lock'),(7,'restore tier'),(8,'cluster status'),(9,'bucket placement'),(10,'bucket public access block');
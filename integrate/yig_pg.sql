@@ -0,0 +1,231 @@
+--
+-- PostgreSQL schema of yig, used with meta_store = "postgres".
+-- Tables and columns are the same as yig.sql, except that:
+--   versions and upload times are stored in numeric(20) since they are
+--   unsigned 64-bit integers, which don't fit into bigint;
+--   JSON documents are stored in text columns;
+--   "offset" is quoted since it's a keyword of PostgreSQL.
+-- Create the database with LC_COLLATE 'C', so names are compared byte by
+-- byte like utf8_bin of yig.sql, e.g.
+--   CREATE DATABASE yig LC_COLLATE 'C' LC_CTYPE 'C' TEMPLATE template0;
+--
+
+DROP TABLE IF EXISTS buckets;
+CREATE TABLE buckets (
+  bucketname varchar(255) NOT NULL DEFAULT '',
+  acl text DEFAULT NULL,
+  cors text DEFAULT NULL,
+  logging text NOT NULL DEFAULT '',
+  lc text DEFAULT NULL,
+  uid varchar(255) DEFAULT NULL,
+  policy text DEFAULT NULL,
+  website text DEFAULT NULL,
+  encryption text DEFAULT NULL,
+  tagging text DEFAULT NULL,
+  notification text DEFAULT NULL,
+  replication text DEFAULT NULL,
+  objectlock text DEFAULT NULL,
+  placement text DEFAULT NULL,
+  publicaccessblock text DEFAULT NULL,
+  createtime timestamp DEFAULT NULL,
+  usages bigint DEFAULT NULL,
+  versioning varchar(255) DEFAULT NULL,
+  PRIMARY KEY (bucketname)
+);
+
+DROP TABLE IF EXISTS cluster;
+CREATE TABLE cluster (
+  fsid varchar(255) DEFAULT NULL,
+  pool varchar(255) DEFAULT NULL,
+  weight integer DEFAULT NULL,
+  status smallint NOT NULL DEFAULT 0,
+  UNIQUE (fsid, pool)
+);
+
+DROP TABLE IF EXISTS gc;
+CREATE TABLE gc (
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL,
+  location varchar(255) DEFAULT NULL,
+  pool varchar(255) DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  status varchar(255) DEFAULT NULL,
+  mtime timestamp DEFAULT NULL,
+  part boolean DEFAULT NULL,
+  triedtimes integer DEFAULT NULL,
+  UNIQUE (bucketname, objectname, version)
+);
+
+DROP TABLE IF EXISTS gcpart;
+CREATE TABLE gcpart (
+  partnumber integer DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  "offset" bigint DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  lastmodified timestamp DEFAULT NULL,
+  initializationvector bytea DEFAULT NULL,
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL
+);
+CREATE INDEX gcpart_rowkey ON gcpart (bucketname, objectname, version);
+
+DROP TABLE IF EXISTS multipartpart;
+CREATE TABLE multipartpart (
+  partnumber integer DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  "offset" bigint DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  lastmodified timestamp DEFAULT NULL,
+  initializationvector bytea DEFAULT NULL,
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  uploadtime numeric(20) DEFAULT NULL
+);
+CREATE INDEX multipartpart_rowkey ON multipartpart (bucketname, objectname, uploadtime);
+
+DROP TABLE IF EXISTS multiparts;
+CREATE TABLE multiparts (
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  uploadtime numeric(20) DEFAULT NULL,
+  initiatorid varchar(255) DEFAULT NULL,
+  ownerid varchar(255) DEFAULT NULL,
+  contenttype varchar(255) DEFAULT NULL,
+  location varchar(255) DEFAULT NULL,
+  pool varchar(255) DEFAULT NULL,
+  acl text DEFAULT NULL,
+  sserequest text DEFAULT NULL,
+  encryption bytea DEFAULT NULL,
+  cipher bytea DEFAULT NULL,
+  attrs text DEFAULT NULL,
+  storageclass smallint DEFAULT 0,
+  tagging text DEFAULT NULL,
+  UNIQUE (bucketname, objectname, uploadtime)
+);
+
+DROP TABLE IF EXISTS objectpart;
+CREATE TABLE objectpart (
+  partnumber integer DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  "offset" bigint DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  lastmodified timestamp DEFAULT NULL,
+  initializationvector bytea DEFAULT NULL,
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL
+);
+CREATE INDEX objectpart_rowkey ON objectpart (bucketname, objectname, version);
+
+DROP TABLE IF EXISTS objects;
+CREATE TABLE objects (
+  bucketname varchar(255) DEFAULT NULL,
+  name varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL,
+  location varchar(255) DEFAULT NULL,
+  pool varchar(255) DEFAULT NULL,
+  ownerid varchar(255) DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  lastmodifiedtime timestamp DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  contenttype varchar(255) DEFAULT NULL,
+  customattributes text DEFAULT NULL,
+  acl text DEFAULT NULL,
+  nullversion boolean DEFAULT NULL,
+  deletemarker boolean DEFAULT NULL,
+  ssetype varchar(255) DEFAULT NULL,
+  encryptionkey bytea DEFAULT NULL,
+  initializationvector bytea DEFAULT NULL,
+  type smallint DEFAULT 0,
+  storageclass smallint DEFAULT 0,
+  tagging text DEFAULT NULL,
+  replicationstatus varchar(255) DEFAULT NULL,
+  objectlockmode varchar(255) DEFAULT NULL,
+  retainuntildate timestamp DEFAULT NULL,
+  legalhold boolean DEFAULT false,
+  UNIQUE (bucketname, name, version)
+);
+
+DROP TABLE IF EXISTS replication;
+CREATE TABLE replication (
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL,
+  operation smallint DEFAULT 0,
+  targetregion varchar(255) DEFAULT NULL,
+  targetbucket varchar(255) DEFAULT NULL,
+  storageclass varchar(255) DEFAULT NULL,
+  status varchar(255) DEFAULT NULL,
+  mtime timestamp DEFAULT NULL,
+  triedtimes integer DEFAULT NULL,
+  UNIQUE (bucketname, objectname, version)
+);
+
+DROP TABLE IF EXISTS restoreobjectpart;
+CREATE TABLE restoreobjectpart (
+  partnumber integer DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  "offset" bigint DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  lastmodified timestamp DEFAULT NULL,
+  initializationvector bytea DEFAULT NULL,
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL
+);
+CREATE INDEX restoreobjectpart_rowkey ON restoreobjectpart (bucketname, objectname, version);
+
+DROP TABLE IF EXISTS restoreobjects;
+CREATE TABLE restoreobjects (
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  version numeric(20) DEFAULT NULL,
+  status smallint DEFAULT 0,
+  lifetime smallint DEFAULT 1,
+  lastmodifiedtime timestamp DEFAULT NULL,
+  location varchar(255) DEFAULT NULL,
+  pool varchar(255) DEFAULT NULL,
+  ownerid varchar(255) DEFAULT NULL,
+  size bigint DEFAULT NULL,
+  objectid varchar(255) DEFAULT NULL,
+  etag varchar(255) DEFAULT NULL,
+  tier smallint DEFAULT 1,
+  UNIQUE (bucketname, objectname, version)
+);
+
+DROP TABLE IF EXISTS objmap;
+CREATE TABLE objmap (
+  bucketname varchar(255) DEFAULT NULL,
+  objectname varchar(255) DEFAULT NULL,
+  nullvernum numeric(20) DEFAULT NULL,
+  UNIQUE (bucketname, objectname)
+);
+
+DROP TABLE IF EXISTS users;
+CREATE TABLE users (
+  userid varchar(255) DEFAULT NULL,
+  bucketname varchar(255) DEFAULT NULL
+);
+
+DROP TABLE IF EXISTS lifecycle;
+CREATE TABLE lifecycle (
+  bucketname varchar(255) DEFAULT NULL,
+  status varchar(255) DEFAULT NULL
+);
+
+-- see `yig migrate`
+DROP TABLE IF EXISTS schema_version;
+CREATE TABLE schema_version (
+  version integer NOT NULL,
+  name varchar(255) DEFAULT NULL,
+  appliedtime timestamp DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (version)
+);
+INSERT INTO schema_version (version, name) VALUES (1,'initial schema'),(2,'object tagging'),(3,'bucket tagging'),(4,'bucket notification'),(5,'bucket replication'),(6,'object lock'),(7,'restore tier'),(8,'cluster status'),(9,'bucket placement'),(10,'bucket public access block');
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/journeymidnight/yig/backend"
 	"github.com/journeymidnight/yig/compression"
 	"github.com/journeymidnight/yig/crypto"
 	"math/rand"
@@ -36,6 +37,10 @@ func main() {
 
 	helper.SetupConfig()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	// yig log
 	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
 	helper.Logger = log.NewFileLogger(helper.CONFIG.LogPath, logLevel)
@@ -53,10 +58,12 @@ func main() {
 
 	// Read all *.so from plugins directory, and fill the variable allPlugins
 	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
 
 	kms := crypto.NewKMS(allPluginMap)
 
 	yig := storage.New(helper.CONFIG.MetaCacheType, helper.CONFIG.EnableDataCache, kms)
+	checkMetaSchema(yig.MetaStorage.Client)
 	adminServerConfig := &adminServerConfig{
 		Address: helper.CONFIG.BindAdminAddress,
 		Logger:  helper.Logger,
@@ -0,0 +1,98 @@
+// Package archive exports metadata of a bucket to a portable archive and
+// imports it back, data of objects stays in backend clusters.
+//
+// An archive is a stream of records: a header, the bucket, objects with
+// their parts, object maps, multipart uploads with their parts, and a
+// trailer counting the records to detect truncated archives. Records are
+// encoded in newline-delimited JSON or msgpack
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+const FormatVersion = 1
+
+const (
+	FormatJSON    = "json"
+	FormatMsgPack = "msgpack"
+)
+
+const (
+	RecordHeader    = "header"
+	RecordBucket    = "bucket"
+	RecordObject    = "object"
+	RecordObjectMap = "objmap"
+	RecordMultipart = "multipart"
+	RecordTrailer   = "trailer"
+)
+
+var ErrTruncated = errors.New("archive is truncated")
+
+type Header struct {
+	FormatVersion int
+	Bucket        string
+	ExportTime    time.Time
+}
+
+type Trailer struct {
+	Objects    int
+	ObjectMaps int
+	Multiparts int
+}
+
+// Record holds one of its fields according to Type
+type Record struct {
+	Type      string
+	Header    *Header          `json:",omitempty"`
+	Bucket    *types.Bucket    `json:",omitempty"`
+	Object    *types.Object    `json:",omitempty"`
+	ObjectMap *types.ObjMap    `json:",omitempty"`
+	Multipart *types.Multipart `json:",omitempty"`
+	Trailer   *Trailer         `json:",omitempty"`
+}
+
+// both json and msgpack encoders and decoders work on a stream of values
+
+type encoder interface {
+	Encode(v interface{}) error
+}
+
+type decoder interface {
+	// Decode returns io.EOF after the last value
+	Decode(v interface{}) error
+}
+
+func newEncoder(w io.Writer, format string) (encoder, error) {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w), nil
+	case FormatMsgPack:
+		return helper.NewMsgPackEncoder(w), nil
+	}
+	return nil, fmt.Errorf("unknown archive format %s", format)
+}
+
+// newDecoder detects format of archive by its first byte, JSON records
+// always start with '{'
+func newDecoder(r io.Reader) (decoder, error) {
+	reader := bufio.NewReader(r)
+	first, err := reader.Peek(1)
+	if err == io.EOF {
+		return nil, ErrTruncated
+	} else if err != nil {
+		return nil, err
+	}
+	if first[0] == '{' {
+		return json.NewDecoder(reader), nil
+	}
+	return helper.NewMsgPackDecoder(reader), nil
+}
@@ -0,0 +1,291 @@
+package archive_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/api/datatype/policy"
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/meta/archive"
+	"github.com/journeymidnight/yig/meta/client/memclient"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// cluster holds ids of objects it has
+type cluster map[string]bool
+
+func (c cluster) ID() string                       { return "fsid" }
+func (c cluster) GetUsage() (backend.Usage, error) { return backend.Usage{}, nil }
+func (c cluster) Put(poolname string, data io.Reader) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c cluster) Append(poolName, existName string, objectChunk io.Reader,
+	offset int64) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c cluster) GetReader(poolName, objectName string,
+	offset int64, length uint64) (io.ReadCloser, error) {
+	if !c[objectName] {
+		return nil, errors.New("no such object")
+	}
+	return ioutil.NopCloser(strings.NewReader("data")), nil
+}
+func (c cluster) Remove(poolName, objectName string) error { return nil }
+func (c cluster) List(poolName string, fn func(objectName string) error) error {
+	return errors.New("not implemented")
+}
+func (c cluster) Stat(poolName, objectName string) (backend.ObjectInfo, error) {
+	return backend.ObjectInfo{}, errors.New("not implemented")
+}
+
+func newClient(t *testing.T) *memclient.MemClient {
+	client, err := memclient.NewMemClientWithPath("")
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	return client
+}
+
+func prepareBucket(t *testing.T, client *memclient.MemClient) {
+	bucket := Bucket{
+		Name:       "hehe",
+		OwnerId:    "haha",
+		CreateTime: time.Now(),
+		Versioning: VersionEnabled,
+		Tagging:    map[string]string{"k": "v"},
+		Policy: policy.Policy{
+			Version: policy.DefaultVersion,
+			Statements: []policy.Statement{policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource("hehe", "*")),
+				nil,
+			)},
+		},
+		Lifecycle: datatype.Lifecycle{Rule: []datatype.LifecycleRule{{ID: "rule"}}},
+	}
+	_, err := client.CheckAndPutBucket(bucket)
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	now := time.Now()
+	objects := []*Object{
+		{Name: "a", LastModifiedTime: now.Add(-time.Second), Size: 4, ObjectId: "oid-a-old"},
+		{Name: "a", LastModifiedTime: now, Size: 4, ObjectId: "oid-a"},
+		{Name: "b", LastModifiedTime: now, Size: 8, Parts: map[int]*Part{
+			1: {PartNumber: 1, Size: 8, ObjectId: "oid-b1", LastModified: "2019-01-01 00:00:00"},
+		}},
+		{Name: "c", LastModifiedTime: now, DeleteMarker: true},
+	}
+	for _, o := range objects {
+		o.BucketName = "hehe"
+		o.Location = "fsid"
+		o.CustomAttributes = map[string]string{}
+		err = client.PutObject(o, nil)
+		if err != nil {
+			t.Fatal("PutObject err:", err)
+		}
+	}
+	err = client.PutObjectMap(&ObjMap{BucketName: "hehe", Name: "a", NullVerNum: 1}, nil)
+	if err != nil {
+		t.Fatal("PutObjectMap err:", err)
+	}
+	multipart := Multipart{
+		BucketName:  "hehe",
+		ObjectName:  "d",
+		InitialTime: now,
+		Metadata:    MultipartMetadata{Location: "fsid", OwnerId: "haha"},
+	}
+	err = client.CreateMultipart(multipart)
+	if err != nil {
+		t.Fatal("CreateMultipart err:", err)
+	}
+	err = client.PutObjectPart(&multipart, &Part{
+		PartNumber:   1,
+		Size:         5,
+		ObjectId:     "oid-d1",
+		LastModified: now.UTC().Format(CREATE_TIME_LAYOUT),
+	}, nil)
+	if err != nil {
+		t.Fatal("PutObjectPart err:", err)
+	}
+}
+
+var allData = cluster{"oid-a-old": true, "oid-a": true, "oid-b1": true, "oid-d1": true}
+
+func exportBucket(t *testing.T, format string) *bytes.Buffer {
+	client := newClient(t)
+	prepareBucket(t, client)
+	exporter := archive.Exporter{
+		Client:  client,
+		Checker: &archive.DataChecker{Clusters: map[string]backend.Cluster{"fsid": allData}},
+	}
+	var buf bytes.Buffer
+	stats, err := exporter.Export("hehe", &buf, format)
+	if err != nil {
+		t.Fatal("Export err:", err)
+	}
+	expected := archive.Trailer{Objects: 4, ObjectMaps: 1, Multiparts: 1}
+	if stats.Trailer != expected || len(stats.Missing) != 0 {
+		t.Fatal("Export stats:", stats)
+	}
+	return &buf
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []string{archive.FormatJSON, archive.FormatMsgPack} {
+		buf := exportBucket(t, format)
+		data := buf.Bytes()
+
+		client := newClient(t)
+		importer := archive.Importer{
+			Client:  client,
+			Checker: &archive.DataChecker{Clusters: map[string]backend.Cluster{"fsid": allData}},
+		}
+		stats, err := importer.Import(bytes.NewReader(data))
+		if err != nil || stats.Skipped != 0 || len(stats.Missing) != 0 {
+			t.Fatal(format, "Import:", stats, err)
+		}
+		bucket, err := client.GetBucket("hehe")
+		if err != nil || bucket.OwnerId != "haha" || bucket.Tagging["k"] != "v" ||
+			len(bucket.Policy.Statements) != 1 || bucket.Versioning != VersionEnabled ||
+			!bucket.Policy.Statements[0].Resources.Match("hehe/x", nil) {
+			t.Fatal(format, "GetBucket:", bucket, err)
+		}
+		buckets, err := client.GetUserBuckets("haha")
+		if err != nil || len(buckets) != 1 {
+			t.Fatal(format, "GetUserBuckets:", buckets, err)
+		}
+		lcs, err := client.ScanLifeCycle(10, "")
+		if err != nil || len(lcs.Lcs) != 1 {
+			t.Fatal(format, "ScanLifeCycle:", lcs, err)
+		}
+		objects, err := client.GetAllObject("hehe", "a", "")
+		if err != nil || len(objects) != 2 {
+			t.Fatal(format, "GetAllObject:", objects, err)
+		}
+		object, err := client.GetObject("hehe", "b", "")
+		if err != nil || len(object.Parts) != 1 || object.Parts[1].ObjectId != "oid-b1" {
+			t.Fatal(format, "GetObject:", object, err)
+		}
+		object, err = client.GetObject("hehe", "c", "")
+		if err != nil || !object.DeleteMarker {
+			t.Fatal(format, "GetObject delete marker:", object, err)
+		}
+		objMap, err := client.GetObjectMap("hehe", "a")
+		if err != nil || objMap.NullVerNum != 1 {
+			t.Fatal(format, "GetObjectMap:", objMap, err)
+		}
+		uploads, err := client.ListStaleMultipartUploads(time.Now().Add(time.Minute), 10)
+		if err != nil || len(uploads) != 1 {
+			t.Fatal(format, "ListStaleMultipartUploads:", uploads, err)
+		}
+		multipart, err := client.GetMultipart("hehe", "d", uploads[0].UploadId)
+		if err != nil || len(multipart.Parts) != 1 || multipart.Parts[1].ObjectId != "oid-d1" {
+			t.Fatal(format, "GetMultipart:", multipart, err)
+		}
+
+		// importing again changes nothing
+		stats, err = importer.Import(bytes.NewReader(data))
+		if err != nil || stats.Skipped != 6 {
+			t.Fatal(format, "Import again:", stats, err)
+		}
+	}
+}
+
+func TestImportMissingData(t *testing.T) {
+	buf := exportBucket(t, archive.FormatJSON)
+	client := newClient(t)
+	importer := archive.Importer{
+		Client: client,
+		Checker: &archive.DataChecker{Clusters: map[string]backend.Cluster{
+			"fsid": cluster{"oid-a-old": true, "oid-a": true, "oid-d1": true},
+		}},
+	}
+	stats, err := importer.Import(buf)
+	if err != nil || len(stats.Missing) != 1 || stats.Missing[0].ObjectId != "oid-b1" {
+		t.Fatal("Import:", stats, err)
+	}
+	_, err = client.GetObject("hehe", "b", "")
+	if err == nil {
+		t.Fatal("Object with missing data is imported")
+	}
+}
+
+// gc rows referring to imported data are removed, others are kept
+func TestImportGarbage(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		buf := exportBucket(t, archive.FormatJSON)
+		client := newClient(t)
+		now := time.Now()
+		for _, o := range []*Object{
+			{Name: "a", LastModifiedTime: now, ObjectId: "oid-a"},
+			{Name: "a", LastModifiedTime: now.Add(time.Second), ObjectId: "oid-x"},
+			{Name: "b", LastModifiedTime: now, Parts: map[int]*Part{
+				1: {PartNumber: 1, ObjectId: "oid-b1"},
+			}},
+			{Name: "d", LastModifiedTime: now, Parts: map[int]*Part{
+				1: {PartNumber: 1, ObjectId: "oid-d1"},
+			}},
+			{Name: "e", LastModifiedTime: now, ObjectId: "oid-a"},
+		} {
+			o.BucketName = "hehe"
+			o.Location = "fsid"
+			err := client.PutObjectToGarbageCollection(o, nil)
+			if err != nil {
+				t.Fatal("PutObjectToGarbageCollection err:", err)
+			}
+		}
+		importer := archive.Importer{
+			Client:  client,
+			Checker: &archive.DataChecker{Clusters: map[string]backend.Cluster{"fsid": allData}},
+			DryRun:  dryRun,
+		}
+		stats, err := importer.Import(buf)
+		if err != nil || stats.Garbages != 3 {
+			t.Fatal(dryRun, "Import:", stats, err)
+		}
+		gcs, err := client.ScanGarbageCollection(10, "")
+		if err != nil {
+			t.Fatal(dryRun, "ScanGarbageCollection err:", err)
+		}
+		if dryRun && len(gcs) != 5 {
+			t.Fatal("Garbage is removed in dry run:", gcs)
+		}
+		if !dryRun && (len(gcs) != 2 || gcs[0].ObjectId != "oid-x" || gcs[1].ObjectName != "e") {
+			t.Fatal("ScanGarbageCollection after import:", gcs)
+		}
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	buf := exportBucket(t, archive.FormatMsgPack)
+	client := newClient(t)
+	importer := archive.Importer{Client: client, DryRun: true}
+	stats, err := importer.Import(buf)
+	if err != nil || stats.Objects != 4 {
+		t.Fatal("Import:", stats, err)
+	}
+	_, err = client.GetBucket("hehe")
+	if err == nil {
+		t.Fatal("Bucket is created in dry run")
+	}
+}
+
+func TestImportTruncated(t *testing.T) {
+	for _, format := range []string{archive.FormatJSON, archive.FormatMsgPack} {
+		data := exportBucket(t, format).Bytes()
+		importer := archive.Importer{Client: newClient(t), DryRun: true}
+		_, err := importer.Import(bytes.NewReader(data[:len(data)-10]))
+		if err == nil {
+			t.Fatal(format, "Import truncated archive succeeded")
+		}
+	}
+}
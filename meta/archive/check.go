@@ -0,0 +1,92 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+// DataChecker checks that data referenced by metadata exists in clusters
+type DataChecker struct {
+	Clusters map[string]backend.Cluster // fsid -> cluster
+}
+
+// MissingData describes an object id which can't be read from its cluster
+type MissingData struct {
+	Object   string
+	Version  string // version in meta store, or upload id of multipart uploads
+	Location string
+	Pool     string
+	ObjectId string
+	Err      error
+}
+
+func (m MissingData) String() string {
+	return fmt.Sprintf("%s(%s) %s/%s/%s: %v", m.Object, m.Version,
+		m.Location, m.Pool, m.ObjectId, m.Err)
+}
+
+// versionOf returns version of `o` in meta store
+func versionOf(o *types.Object) string {
+	return strconv.FormatUint(math.MaxUint64-uint64(o.LastModifiedTime.UnixNano()), 10)
+}
+
+// checkData reads the first byte of object `oid`
+func (c *DataChecker) checkData(location, pool, oid string) error {
+	cluster, ok := c.Clusters[location]
+	if !ok {
+		return fmt.Errorf("unknown cluster %s", location)
+	}
+	reader, err := cluster.GetReader(pool, oid, 0, 1)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	var b [1]byte
+	_, err = io.ReadFull(reader, b[:])
+	return err
+}
+
+func (c *DataChecker) checkParts(missing []MissingData, name, version, location, pool string,
+	parts map[int]*types.Part) []MissingData {
+
+	for _, p := range parts {
+		if p.Size == 0 {
+			continue
+		}
+		err := c.checkData(location, pool, p.ObjectId)
+		if err != nil {
+			missing = append(missing, MissingData{name, version, location, pool, p.ObjectId, err})
+		}
+	}
+	return missing
+}
+
+// CheckObject returns data of `o` which can't be read
+func (c *DataChecker) CheckObject(o *types.Object) (missing []MissingData) {
+	if o.DeleteMarker {
+		return nil
+	}
+	version := versionOf(o)
+	if len(o.Parts) != 0 {
+		return c.checkParts(missing, o.Name, version, o.Location, o.Pool, o.Parts)
+	}
+	if o.Size == 0 {
+		return nil
+	}
+	err := c.checkData(o.Location, o.Pool, o.ObjectId)
+	if err != nil {
+		missing = append(missing, MissingData{o.Name, version, o.Location, o.Pool, o.ObjectId, err})
+	}
+	return
+}
+
+// CheckMultipart returns uploaded parts of `m` which can't be read
+func (c *DataChecker) CheckMultipart(m *types.Multipart) (missing []MissingData) {
+	uploadId, _ := m.GetUploadId()
+	return c.checkParts(missing, m.ObjectName, uploadId, m.Metadata.Location, m.Metadata.Pool, m.Parts)
+}
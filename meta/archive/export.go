@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"database/sql"
+	"io"
+	"time"
+
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+const scanLimit = 1000
+
+type Exporter struct {
+	Client client.Client
+	// optional, objects with missing data are still exported and
+	// reported in Stats
+	Checker *DataChecker
+}
+
+type Stats struct {
+	Trailer
+	Skipped  int // records already in meta store when importing
+	Garbages int // gc rows removed when importing, as their data is imported
+	Missing  []MissingData
+}
+
+// Export writes metadata of bucket `bucketName` to `w` in `format`
+func (e *Exporter) Export(bucketName string, w io.Writer, format string) (stats Stats, err error) {
+	enc, err := newEncoder(w, format)
+	if err != nil {
+		return
+	}
+	bucket, err := e.Client.GetBucket(bucketName)
+	if err != nil {
+		return
+	}
+	err = enc.Encode(&Record{Type: RecordHeader, Header: &Header{
+		FormatVersion: FormatVersion,
+		Bucket:        bucketName,
+		ExportTime:    time.Now().UTC(),
+	}})
+	if err != nil {
+		return
+	}
+	err = enc.Encode(&Record{Type: RecordBucket, Bucket: bucket})
+	if err != nil {
+		return
+	}
+	err = e.exportObjects(bucketName, enc, &stats)
+	if err != nil {
+		return
+	}
+	err = e.exportMultiparts(bucketName, enc, &stats)
+	if err != nil {
+		return
+	}
+	err = enc.Encode(&Record{Type: RecordTrailer, Trailer: &stats.Trailer})
+	return
+}
+
+func (e *Exporter) exportObjects(bucketName string, enc encoder, stats *Stats) error {
+	var marker string
+	for {
+		names, err := e.Client.ScanObjectNames(bucketName, marker, scanLimit)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			objects, err := e.Client.GetAllObject(bucketName, name, "")
+			if err != nil {
+				return err
+			}
+			for _, object := range objects {
+				if e.Checker != nil {
+					stats.Missing = append(stats.Missing, e.Checker.CheckObject(object)...)
+				}
+				err = enc.Encode(&Record{Type: RecordObject, Object: object})
+				if err != nil {
+					return err
+				}
+				stats.Objects += 1
+			}
+			objMap, err := e.Client.GetObjectMap(bucketName, name)
+			if err == sql.ErrNoRows {
+				continue
+			} else if err != nil {
+				return err
+			}
+			err = enc.Encode(&Record{Type: RecordObjectMap, ObjectMap: objMap})
+			if err != nil {
+				return err
+			}
+			stats.ObjectMaps += 1
+		}
+		if len(names) < scanLimit {
+			return nil
+		}
+		marker = names[len(names)-1]
+	}
+}
+
+func (e *Exporter) exportMultiparts(bucketName string, enc encoder, stats *Stats) error {
+	var keyMarker, uploadIdMarker string
+	for {
+		uploads, _, truncated, nextKeyMarker, nextUploadIdMarker, err :=
+			e.Client.ListMultipartUploads(bucketName, keyMarker, uploadIdMarker, "", "", "", scanLimit)
+		if err != nil {
+			return err
+		}
+		for _, upload := range uploads {
+			var multipart types.Multipart
+			multipart, err = e.Client.GetMultipart(bucketName, upload.Key, upload.UploadId)
+			if err != nil {
+				return err
+			}
+			if e.Checker != nil {
+				stats.Missing = append(stats.Missing, e.Checker.CheckMultipart(&multipart)...)
+			}
+			err = enc.Encode(&Record{Type: RecordMultipart, Multipart: &multipart})
+			if err != nil {
+				return err
+			}
+			stats.Multiparts += 1
+		}
+		if !truncated {
+			return nil
+		}
+		keyMarker, uploadIdMarker = nextKeyMarker, nextUploadIdMarker
+	}
+}
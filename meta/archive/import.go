@@ -0,0 +1,312 @@
+package archive
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/meta"
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+const garbageScanLimit = 100
+
+type Importer struct {
+	Client client.Client
+	// optional, objects and uploads whose data can't be read are not
+	// imported and reported in Stats
+	Checker *DataChecker
+	// check archive and data without writing to meta store
+	DryRun bool
+}
+
+// Import restores metadata in archive `r` into meta store. The bucket is
+// created if not exists, otherwise its config is kept. Objects, object maps
+// and multipart uploads which already exist are skipped, so an interrupted
+// import could be started again. Pending gc rows referring to data of
+// imported objects and uploads are removed, so gc won't delete the data
+// after it's referenced again
+func (i *Importer) Import(r io.Reader) (stats Stats, err error) {
+	dec, err := newDecoder(r)
+	if err != nil {
+		return
+	}
+	record, err := readRecord(dec, RecordHeader)
+	if err != nil {
+		return
+	}
+	if record.Header.FormatVersion > FormatVersion {
+		err = fmt.Errorf("archive format version %d is not supported", record.Header.FormatVersion)
+		return
+	}
+	record, err = readRecord(dec, RecordBucket)
+	if err != nil {
+		return
+	}
+	bucket := record.Bucket
+	// usage is added up by imported objects
+	var created bool
+	if !i.DryRun {
+		created, err = i.importBucket(*bucket)
+		if err != nil {
+			return
+		}
+	}
+	for {
+		record, err = readRecord(dec, "")
+		if err != nil {
+			return
+		}
+		if record.Type == RecordTrailer {
+			break
+		}
+		if bucketOf(record) != bucket.Name {
+			err = fmt.Errorf("%s record of bucket %s in archive of %s", record.Type, bucketOf(record), bucket.Name)
+			return
+		}
+		switch record.Type {
+		case RecordObject:
+			stats.Objects += 1
+			err = i.importObject(record.Object, &stats)
+		case RecordObjectMap:
+			stats.ObjectMaps += 1
+			err = i.importObjectMap(record.ObjectMap, &stats)
+		case RecordMultipart:
+			stats.Multiparts += 1
+			err = i.importMultipart(record.Multipart, &stats)
+		default:
+			err = fmt.Errorf("unexpected %s record", record.Type)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if *record.Trailer != stats.Trailer {
+		err = fmt.Errorf("archive has %+v records, but %+v are read", *record.Trailer, stats.Trailer)
+		return
+	}
+	if created && len(bucket.Lifecycle.Rule) != 0 {
+		err = i.Client.PutBucketToLifeCycle(meta.LifeCycleFromBucket(*bucket))
+	}
+	return
+}
+
+// readRecord reads next record, which should be of type `expected` if
+// it's not empty
+func readRecord(dec decoder, expected string) (*Record, error) {
+	record := new(Record)
+	err := dec.Decode(record)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, ErrTruncated
+	} else if err != nil {
+		return nil, err
+	}
+	if expected != "" && record.Type != expected {
+		return nil, fmt.Errorf("expect %s record but got %s", expected, record.Type)
+	}
+	var ok bool
+	switch record.Type {
+	case RecordHeader:
+		ok = record.Header != nil
+	case RecordBucket:
+		ok = record.Bucket != nil
+	case RecordObject:
+		ok = record.Object != nil
+	case RecordObjectMap:
+		ok = record.ObjectMap != nil
+	case RecordMultipart:
+		ok = record.Multipart != nil
+	case RecordTrailer:
+		ok = record.Trailer != nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("bad %s record", record.Type)
+	}
+	return record, nil
+}
+
+func bucketOf(record *Record) string {
+	switch record.Type {
+	case RecordObject:
+		return record.Object.BucketName
+	case RecordObjectMap:
+		return record.ObjectMap.BucketName
+	case RecordMultipart:
+		return record.Multipart.BucketName
+	}
+	return ""
+}
+
+func (i *Importer) importBucket(bucket types.Bucket) (created bool, err error) {
+	_, err = i.Client.GetBucket(bucket.Name)
+	if err == nil {
+		return false, nil
+	} else if err != ErrNoSuchBucket {
+		return false, err
+	}
+	bucket.Usage = 0
+	created, err = i.Client.CheckAndPutBucket(bucket)
+	if err != nil || !created {
+		return
+	}
+	err = i.Client.AddBucketForUser(bucket.Name, bucket.OwnerId)
+	return
+}
+
+func (i *Importer) importObject(object *types.Object, stats *Stats) error {
+	version := versionOf(object)
+	_, err := i.Client.GetObject(object.BucketName, object.Name, version)
+	if err == nil {
+		stats.Skipped += 1
+		return nil
+	} else if err != ErrNoSuchKey {
+		return err
+	}
+	if i.Checker != nil {
+		missing := i.Checker.CheckObject(object)
+		if len(missing) != 0 {
+			stats.Missing = append(stats.Missing, missing...)
+			return nil
+		}
+	}
+	err = i.removeGarbage(object.BucketName, object.Name, object.Location, object.Pool,
+		dataOf(object.ObjectId, object.Parts), stats)
+	if err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+	err = i.Client.PutObject(object, nil)
+	if err != nil {
+		return err
+	}
+	return i.Client.UpdateUsage(object.BucketName, object.Size, nil)
+}
+
+func (i *Importer) importObjectMap(objMap *types.ObjMap, stats *Stats) error {
+	_, err := i.Client.GetObjectMap(objMap.BucketName, objMap.Name)
+	if err == nil {
+		stats.Skipped += 1
+		return nil
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+	return i.Client.PutObjectMap(objMap, nil)
+}
+
+func (i *Importer) importMultipart(multipart *types.Multipart, stats *Stats) error {
+	uploadId, err := multipart.GetUploadId()
+	if err != nil {
+		return err
+	}
+	_, err = i.Client.GetMultipart(multipart.BucketName, multipart.ObjectName, uploadId)
+	if err == nil {
+		stats.Skipped += 1
+		return nil
+	} else if err != ErrNoSuchUpload {
+		return err
+	}
+	if i.Checker != nil {
+		missing := i.Checker.CheckMultipart(multipart)
+		if len(missing) != 0 {
+			stats.Missing = append(stats.Missing, missing...)
+			return nil
+		}
+	}
+	err = i.removeGarbage(multipart.BucketName, multipart.ObjectName, multipart.Metadata.Location,
+		multipart.Metadata.Pool, dataOf("", multipart.Parts), stats)
+	if err != nil {
+		return err
+	}
+	if i.DryRun {
+		return nil
+	}
+	err = i.Client.CreateMultipart(*multipart)
+	if err != nil {
+		return err
+	}
+	for _, part := range multipart.Parts {
+		err = i.Client.PutObjectPart(multipart, part, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataOf returns ids of objects in Ceph which hold data of an object or
+// multipart upload
+func dataOf(objectId string, parts map[int]*types.Part) map[string]bool {
+	ids := make(map[string]bool)
+	if objectId != "" {
+		ids[objectId] = true
+	}
+	for _, p := range parts {
+		if p.ObjectId != "" {
+			ids[p.ObjectId] = true
+		}
+	}
+	return ids
+}
+
+// removeGarbage removes gc rows of object `name` which refer to any object
+// in `ids`. Rows are only counted in dry run
+func (i *Importer) removeGarbage(bucketName, name, location, pool string,
+	ids map[string]bool, stats *Stats) error {
+
+	if len(ids) == 0 {
+		return nil
+	}
+	prefix := bucketName + types.ObjectNameSeparator + name + types.ObjectNameSeparator
+	startRowKey := prefix
+	for {
+		gcs, err := i.Client.ScanGarbageCollection(garbageScanLimit, startRowKey)
+		if err != nil {
+			return err
+		}
+		for _, gc := range gcs {
+			// start row key is inclusive, and the last row of previous
+			// batch is scanned again
+			if gc.Rowkey == startRowKey {
+				continue
+			}
+			if !strings.HasPrefix(gc.Rowkey, prefix) {
+				return nil
+			}
+			if gc.Location != location || gc.Pool != pool {
+				continue
+			}
+			if !ids[gc.ObjectId] && !anyPart(gc.Parts, ids) {
+				continue
+			}
+			stats.Garbages += 1
+			if i.DryRun {
+				continue
+			}
+			err = i.Client.RemoveGarbageCollection(gc)
+			if err != nil {
+				return err
+			}
+		}
+		if len(gcs) < garbageScanLimit {
+			return nil
+		}
+		startRowKey = gcs[len(gcs)-1].Rowkey
+	}
+}
+
+func anyPart(parts map[int]*types.Part, ids map[string]bool) bool {
+	for _, p := range parts {
+		if ids[p.ObjectId] {
+			return true
+		}
+	}
+	return false
+}
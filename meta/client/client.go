@@ -2,6 +2,8 @@ package client
 
 import (
 	"database/sql"
+	"time"
+
 	"github.com/journeymidnight/yig/api/datatype"
 	. "github.com/journeymidnight/yig/meta/types"
 )
@@ -22,7 +24,11 @@ type Client interface {
 	ReplaceObjectMetas(object *Object, tx DB) (err error)
 	DeleteObject(object *Object, tx DB) error
 	UpdateObject(object *Object, tx DB) (err error)
+	UpdateObjectLocation(object, sourceObject *Object, tx DB) (updated bool, err error)
 	UpdateObjectAcl(object *Object) error
+	UpdateObjectTagging(object *Object) error
+	UpdateObjectReplicationStatus(object *Object) error
+	UpdateObjectLock(object *Object) error
 	UpdateObjectAttrs(object *Object) error
 	//bucket
 	GetBucket(bucketName string) (bucket *Bucket, err error)
@@ -39,16 +45,20 @@ type Client interface {
 	PutObjectPart(multipart *Multipart, part *Part, tx DB) (err error)
 	DeleteMultipart(multipart *Multipart, tx DB) (err error)
 	ListMultipartUploads(bucketName, keyMarker, uploadIdMarker, prefix, delimiter, encodingType string, maxUploads int) (uploads []datatype.Upload, prefixs []string, isTruncated bool, nextKeyMarker, nextUploadIdMarker string, err error)
+	ListStaleMultipartUploads(initiatedBefore time.Time, limit int) (uploads []Multipart, err error)
 	//objmap
 	GetObjectMap(bucketName, objectName string) (objMap *ObjMap, err error)
 	PutObjectMap(objMap *ObjMap, tx DB) error
 	DeleteObjectMap(objMap *ObjMap, tx DB) error
 	//cluster
 	GetClusters() (cluster []Cluster, err error)
+	SetClusterStatus(fsid string, status ClusterStatus) error
+	PutCluster(cluster Cluster) error
 	//lc
 	PutBucketToLifeCycle(lifeCycle LifeCycle) error
 	RemoveBucketFromLifeCycle(bucket Bucket) error
 	ScanLifeCycle(limit int, marker string) (result ScanLifeCycleResult, err error)
+	ScanObjectNames(bucketName, marker string, limit int) (names []string, err error)
 	//user
 	GetUserBuckets(userId string) (buckets []string, err error)
 	AddBucketForUser(bucketName, userId string) (err error)
@@ -58,10 +68,19 @@ type Client interface {
 	PutFreezerToGarbageCollection(object *Freezer, tx DB) (err error)
 	ScanGarbageCollection(limit int, startRowKey string) ([]GarbageCollection, error)
 	RemoveGarbageCollection(garbage GarbageCollection) error
+	//replication
+	PutObjectToReplication(replication *Replication) error
+	ScanReplication(limit int, startRowKey string, status string) ([]Replication, error)
+	UpdateReplication(replication *Replication) error
+	RemoveReplication(replication *Replication) error
 	//freezer
 	CreateFreezer(freezer *Freezer) (err error)
 	GetFreezer(bucketName, objectName, version string) (freezer *Freezer, err error)
 	GetFreezerStatus(bucketName, objectName, version string) (freezer *Freezer, err error)
 	UploadFreezerDate(bucketName, objectName string, lifetime int) (err error)
 	DeleteFreezer(bucketName, objectName string, tx DB) (err error)
+	ListFreezers(status Status, limit int) (freezers []Freezer, err error)
+	ListExpiredFreezers(now time.Time, limit int) (freezers []Freezer, err error)
+	UpdateFreezerStatus(freezer *Freezer, status Status) (updated bool, err error)
+	UpdateFreezer(freezer *Freezer, status Status, tx DB) (err error)
 }
@@ -0,0 +1,433 @@
+// Package clienttest checks implementations of client.Client against the
+// behavior yig relies on, every meta store runs the same cases
+package clienttest
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/meta/client"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// RunClientTests runs all cases against `c`. Cases use their own bucket
+// names so `c` could be a shared database, but it should not be used by
+// yig at the same time
+func RunClientTests(t *testing.T, c client.Client) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, c client.Client, bucket string)
+	}{
+		{"Bucket", testBucket},
+		{"ObjectVersions", testObjectVersions},
+		{"ListObjects", testListObjects},
+		{"Multipart", testMultipart},
+		{"GarbageCollection", testGarbageCollection},
+		{"Freezer", testFreezer},
+		{"ObjectLocation", testObjectLocation},
+		{"AppendObject", testAppendObject},
+	}
+	for _, tc := range cases {
+		bucket := "clienttest-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, c, bucket)
+		})
+	}
+}
+
+func putObject(t *testing.T, c client.Client, bucket, name string,
+	modified time.Time) *Object {
+
+	object := &Object{
+		BucketName:       bucket,
+		Name:             name,
+		LastModifiedTime: modified,
+		Etag:             name,
+		CustomAttributes: map[string]string{"name": name},
+	}
+	err := c.PutObject(object, nil)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	return object
+}
+
+func testBucket(t *testing.T, c client.Client, bucketName string) {
+	bucket := Bucket{Name: bucketName, OwnerId: "haha", CreateTime: time.Now()}
+	created, err := c.CheckAndPutBucket(bucket)
+	if err != nil || !created {
+		t.Fatal("CheckAndPutBucket:", created, err)
+	}
+	defer c.DeleteBucket(bucket)
+	created, err = c.CheckAndPutBucket(bucket)
+	if err != nil || created {
+		t.Fatal("CheckAndPutBucket existing bucket:", created, err)
+	}
+	bucket.Versioning = VersionEnabled
+	err = c.PutBucket(bucket)
+	if err != nil {
+		t.Fatal("PutBucket err:", err)
+	}
+	got, err := c.GetBucket(bucketName)
+	if err != nil || got.OwnerId != "haha" || got.Versioning != VersionEnabled {
+		t.Fatal("GetBucket:", got, err)
+	}
+	err = c.DeleteBucket(bucket)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+	_, err = c.GetBucket(bucketName)
+	if err != ErrNoSuchBucket {
+		t.Fatal("GetBucket after delete:", err)
+	}
+}
+
+func testObjectVersions(t *testing.T, c client.Client, bucket string) {
+	now := time.Now()
+	older := putObject(t, c, bucket, "a", now.Add(-time.Second))
+	newer := putObject(t, c, bucket, "a", now)
+	defer c.DeleteObject(older, nil)
+
+	latest, err := c.GetObject(bucket, "a", "")
+	if err != nil || !latest.LastModifiedTime.Equal(newer.LastModifiedTime) {
+		t.Fatal("GetObject latest:", latest, err)
+	}
+	// callers must not change objects stored in client
+	latest.CustomAttributes["name"] = "changed"
+	latest, _ = c.GetObject(bucket, "a", "")
+	if latest.CustomAttributes["name"] != "a" {
+		t.Fatal("GetObject returns shared object")
+	}
+	all, err := c.GetAllObject(bucket, "a", "")
+	if err != nil || len(all) != 2 {
+		t.Fatal("GetAllObject:", all, err)
+	}
+	err = c.DeleteObject(newer, nil)
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+	latest, err = c.GetObject(bucket, "a", "")
+	if err != nil || !latest.LastModifiedTime.Equal(older.LastModifiedTime) {
+		t.Fatal("GetObject after delete:", latest, err)
+	}
+	_, err = c.GetObject(bucket, "b", "")
+	if err != ErrNoSuchKey {
+		t.Fatal("GetObject non-existing:", err)
+	}
+}
+
+func testListObjects(t *testing.T, c client.Client, bucket string) {
+	now := time.Now()
+	var objects []*Object
+	for _, name := range []string{"a", "b/1", "b/2", "c", "d"} {
+		objects = append(objects, putObject(t, c, bucket, name, now))
+	}
+	deleteMarker := &Object{
+		BucketName:       bucket,
+		Name:             "c",
+		LastModifiedTime: now.Add(time.Second),
+		DeleteMarker:     true,
+	}
+	err := c.PutObject(deleteMarker, nil)
+	if err != nil {
+		t.Fatal("PutObject delete marker err:", err)
+	}
+	objects = append(objects, deleteMarker)
+	defer func() {
+		for _, o := range objects {
+			c.DeleteObject(o, nil)
+		}
+	}()
+
+	listed, prefixes, truncated, nextMarker, _, err :=
+		c.ListObjects(bucket, "", "", "", "/", false, 2)
+	if err != nil || len(listed) != 1 || listed[0].Name != "a" ||
+		len(prefixes) != 1 || prefixes[0] != "b/" || !truncated || nextMarker != "b/" {
+		t.Fatal("ListObjects first page:", listed, prefixes, truncated, nextMarker, err)
+	}
+	listed, prefixes, truncated, _, _, err =
+		c.ListObjects(bucket, nextMarker, "", "", "/", false, 2)
+	if err != nil || len(listed) != 1 || listed[0].Name != "d" ||
+		len(prefixes) != 0 || truncated {
+		t.Fatal("ListObjects second page:", listed, prefixes, truncated, err)
+	}
+}
+
+func testMultipart(t *testing.T, c client.Client, bucket string) {
+	multipart := Multipart{
+		BucketName:  bucket,
+		ObjectName:  "a",
+		InitialTime: time.Now().Add(-time.Hour),
+	}
+	err := c.CreateMultipart(multipart)
+	if err != nil {
+		t.Fatal("CreateMultipart err:", err)
+	}
+	defer c.DeleteMultipart(&multipart, nil)
+	uploadId, _ := multipart.GetUploadId()
+	part := &Part{
+		PartNumber:   1,
+		Size:         5,
+		ObjectId:     "oid",
+		LastModified: time.Now().UTC().Format(CREATE_TIME_LAYOUT),
+	}
+	err = c.PutObjectPart(&multipart, part, nil)
+	if err != nil {
+		t.Fatal("PutObjectPart err:", err)
+	}
+	got, err := c.GetMultipart(bucket, "a", uploadId)
+	if err != nil || len(got.Parts) != 1 || got.Parts[1].ObjectId != "oid" {
+		t.Fatal("GetMultipart:", got, err)
+	}
+	stale, err := c.ListStaleMultipartUploads(time.Now().Add(-time.Minute), 1000)
+	if err != nil {
+		t.Fatal("ListStaleMultipartUploads err:", err)
+	}
+	var found bool
+	for _, upload := range stale {
+		if upload.BucketName == bucket && upload.UploadId == uploadId {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("ListStaleMultipartUploads:", stale)
+	}
+	err = c.DeleteMultipart(&multipart, nil)
+	if err != nil {
+		t.Fatal("DeleteMultipart err:", err)
+	}
+	_, err = c.GetMultipart(bucket, "a", uploadId)
+	if err != ErrNoSuchUpload {
+		t.Fatal("GetMultipart after delete:", err)
+	}
+}
+
+// scanGarbage returns garbages of `bucket`, the order of rows is not checked
+// since not every store sorts them when scanning from a row key
+func scanGarbage(t *testing.T, c client.Client, bucket string) map[string]GarbageCollection {
+	startRowKey := bucket + ObjectNameSeparator + ObjectNameSeparator + "0"
+	gcs, err := c.ScanGarbageCollection(1000, startRowKey)
+	if err != nil {
+		t.Fatal("ScanGarbageCollection err:", err)
+	}
+	garbages := make(map[string]GarbageCollection)
+	for _, gc := range gcs {
+		if gc.BucketName == bucket {
+			garbages[gc.ObjectName] = gc
+		}
+	}
+	return garbages
+}
+
+func testGarbageCollection(t *testing.T, c client.Client, bucket string) {
+	now := time.Now()
+	for _, name := range []string{"b", "a", "c"} {
+		err := c.PutObjectToGarbageCollection(&Object{
+			BucketName:       bucket,
+			Name:             name,
+			LastModifiedTime: now,
+			ObjectId:         "oid-" + name,
+		}, nil)
+		if err != nil {
+			t.Fatal("PutObjectToGarbageCollection err:", err)
+		}
+	}
+	garbages := scanGarbage(t, c, bucket)
+	if len(garbages) != 3 || garbages["a"].ObjectId != "oid-a" {
+		t.Fatal("ScanGarbageCollection:", garbages)
+	}
+	for _, gc := range garbages {
+		if gc.ObjectName == "c" {
+			continue
+		}
+		err := c.RemoveGarbageCollection(gc)
+		if err != nil {
+			t.Fatal("RemoveGarbageCollection err:", err)
+		}
+	}
+	garbages = scanGarbage(t, c, bucket)
+	if len(garbages) != 1 {
+		t.Fatal("ScanGarbageCollection after remove:", garbages)
+	}
+	c.RemoveGarbageCollection(garbages["c"])
+}
+
+func testFreezer(t *testing.T, c client.Client, bucket string) {
+	freezer := &Freezer{
+		BucketName:       bucket,
+		Name:             "a",
+		Status:           ObjectNeedRestore,
+		LifeTime:         1,
+		LastModifiedTime: time.Now(),
+	}
+	err := c.CreateFreezer(freezer)
+	if err != nil {
+		t.Fatal("CreateFreezer err:", err)
+	}
+	defer c.DeleteFreezer(bucket, "a", nil)
+	updated, err := c.UpdateFreezerStatus(freezer, ObjectRestoring)
+	if err != nil || !updated {
+		t.Fatal("UpdateFreezerStatus:", updated, err)
+	}
+	// status has been changed
+	updated, err = c.UpdateFreezerStatus(freezer, ObjectRestoring)
+	if err != nil || updated {
+		t.Fatal("UpdateFreezerStatus again:", updated, err)
+	}
+	freezer.Status = ObjectRestoring
+	freezer.ObjectId = "oid"
+	freezer.LastModifiedTime = time.Now().Add(-48 * time.Hour)
+	err = c.UpdateFreezer(freezer, ObjectHasRestored, nil)
+	if err != nil {
+		t.Fatal("UpdateFreezer err:", err)
+	}
+	got, err := c.GetFreezer(bucket, "a", "")
+	if err != nil || got.Status != ObjectHasRestored || got.ObjectId != "oid" {
+		t.Fatal("GetFreezer:", got, err)
+	}
+	expired, err := c.ListExpiredFreezers(time.Now(), 1000)
+	if err != nil {
+		t.Fatal("ListExpiredFreezers err:", err)
+	}
+	var found bool
+	for _, f := range expired {
+		if f.BucketName == bucket {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("ListExpiredFreezers:", expired)
+	}
+}
+
+func testObjectLocation(t *testing.T, c client.Client, bucket string) {
+	now := time.Now()
+	source := &Object{
+		BucketName:       bucket,
+		Name:             "a",
+		Location:         "fsid-a",
+		Pool:             "tiger",
+		ObjectId:         "oid-a",
+		Size:             4,
+		LastModifiedTime: now,
+	}
+	multipartSource := &Object{
+		BucketName:       bucket,
+		Name:             "b",
+		Location:         "fsid-a",
+		Pool:             "tiger",
+		Size:             8,
+		LastModifiedTime: now,
+		Parts: map[int]*Part{
+			1: {PartNumber: 1, Size: 4, ObjectId: "oid-b1"},
+			2: {PartNumber: 2, Size: 4, ObjectId: "oid-b2", Offset: 4},
+		},
+	}
+	for _, o := range []*Object{source, multipartSource} {
+		err := c.PutObject(o, nil)
+		if err != nil {
+			t.Fatal("PutObject err:", err)
+		}
+		defer c.DeleteObject(o, nil)
+	}
+
+	target := *source
+	target.Location, target.ObjectId = "fsid-b", "oid-a-moved"
+	target.StorageClass = ObjectStorageClassStandardIa
+	updated, err := c.UpdateObjectLocation(&target, source, nil)
+	if err != nil || !updated {
+		t.Fatal("UpdateObjectLocation:", updated, err)
+	}
+	got, err := c.GetObject(bucket, "a", "")
+	if err != nil || got.Location != "fsid-b" || got.ObjectId != "oid-a-moved" ||
+		got.StorageClass != ObjectStorageClassStandardIa {
+		t.Fatal("GetObject after moved:", got, err)
+	}
+	// object has been moved away from source
+	updated, err = c.UpdateObjectLocation(&target, source, nil)
+	if err != nil || updated {
+		t.Fatal("UpdateObjectLocation again:", updated, err)
+	}
+
+	multipartTarget := *multipartSource
+	multipartTarget.Location = "fsid-b"
+	multipartTarget.Parts = map[int]*Part{
+		1: {PartNumber: 1, Size: 4, ObjectId: "oid-b1-moved"},
+		2: {PartNumber: 2, Size: 4, ObjectId: "oid-b2-moved", Offset: 4},
+	}
+	// part 2 has changed since copied
+	changedSource := *multipartSource
+	changedSource.Parts = map[int]*Part{
+		1: multipartSource.Parts[1],
+		2: {PartNumber: 2, Size: 4, ObjectId: "oid-b2-old", Offset: 4},
+	}
+	updated, err = c.UpdateObjectLocation(&multipartTarget, &changedSource, nil)
+	if err != nil || updated {
+		t.Fatal("UpdateObjectLocation with changed part:", updated, err)
+	}
+	got, err = c.GetObject(bucket, "b", "")
+	if err != nil || got.Location != "fsid-a" || got.Parts[1].ObjectId != "oid-b1" {
+		t.Fatal("GetObject after failed move:", got, err)
+	}
+	updated, err = c.UpdateObjectLocation(&multipartTarget, multipartSource, nil)
+	if err != nil || !updated {
+		t.Fatal("UpdateObjectLocation multipart:", updated, err)
+	}
+	got, err = c.GetObject(bucket, "b", "")
+	if err != nil || got.Location != "fsid-b" ||
+		got.Parts[1].ObjectId != "oid-b1-moved" || got.Parts[2].ObjectId != "oid-b2-moved" {
+		t.Fatal("GetObject after multipart moved:", got, err)
+	}
+}
+
+func testAppendObject(t *testing.T, c client.Client, bucket string) {
+	object := &Object{
+		BucketName:       bucket,
+		Name:             "a",
+		Location:         "fsid-a",
+		Pool:             "tiger",
+		ObjectId:         "oid-a",
+		Size:             4,
+		LastModifiedTime: time.Now().Add(-time.Minute),
+		NullVersion:      true,
+		Type:             ObjectTypeAppendable,
+	}
+	err := c.PutObject(object, nil)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+
+	appended := *object
+	appended.Size = 8
+	appended.LastModifiedTime = time.Now().Add(-time.Second)
+	err = c.UpdateAppendObject(&appended, nil)
+	if err != nil {
+		t.Fatal("UpdateAppendObject err:", err)
+	}
+	got, err := c.GetObject(bucket, "a", "")
+	if err != nil || got.Size != 8 {
+		t.Fatal("GetObject after appended:", got, err)
+	}
+	defer c.DeleteObject(got, nil)
+
+	// appended to the data before it's moved
+	moved := appended
+	moved.ObjectId = "oid-a-moved"
+	updated, err := c.UpdateObjectLocation(&moved, &appended, nil)
+	if err != nil || !updated {
+		t.Fatal("UpdateObjectLocation:", updated, err)
+	}
+	stale := appended
+	stale.Size = 12
+	stale.LastModifiedTime = time.Now()
+	err = c.UpdateAppendObject(&stale, nil)
+	if err != ErrPositionNotEqualToLength {
+		t.Fatal("UpdateAppendObject to moved data:", err)
+	}
+	got, err = c.GetObject(bucket, "a", "")
+	if err != nil || got.Size != 8 || got.ObjectId != "oid-a-moved" {
+		t.Fatal("GetObject after stale append:", got, err)
+	}
+}
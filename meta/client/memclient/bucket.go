@@ -0,0 +1,176 @@
+package memclient
+
+import (
+	"sort"
+	"strings"
+
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (m *MemClient) GetBucket(bucketName string) (bucket *Bucket, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	b, ok := m.data.Buckets[bucketName]
+	if !ok {
+		return nil, ErrNoSuchBucket
+	}
+	bucket = new(Bucket)
+	clone(b, bucket)
+	return bucket, nil
+}
+
+func (m *MemClient) GetBuckets() (buckets []Bucket, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, name := range sortedKeys(m.data.Buckets) {
+		var bucket Bucket
+		clone(m.data.Buckets[name], &bucket)
+		buckets = append(buckets, bucket)
+	}
+	return
+}
+
+// Actually this method is used to update bucket
+func (m *MemClient) PutBucket(bucket Bucket) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	b, ok := m.data.Buckets[bucket.Name]
+	if !ok {
+		return nil
+	}
+	// create time and usage are not updated, same as tidb
+	updated := new(Bucket)
+	clone(bucket, updated)
+	updated.CreateTime = b.CreateTime
+	updated.Usage = b.Usage
+	m.data.Buckets[bucket.Name] = updated
+	return m.save(nil)
+}
+
+func (m *MemClient) CheckAndPutBucket(bucket Bucket) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.data.Buckets[bucket.Name]; ok {
+		return false, nil
+	}
+	created := new(Bucket)
+	clone(bucket, created)
+	m.data.Buckets[bucket.Name] = created
+	return true, m.save(nil)
+}
+
+// ListObjects lists the latest version of objects in bucket,
+// versioned listing is not supported yet, same as tidb
+func (m *MemClient) ListObjects(bucketName, marker, verIdMarker, prefix, delimiter string, versioned bool, maxKeys int) (retObjects []*Object, prefixes []string, truncated bool, nextMarker, nextVerIdMarker string, err error) {
+	if versioned {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var count int
+	commonPrefixes := make(map[string]struct{})
+	objects := m.data.Objects[bucketName]
+	for _, name := range sortedKeys(objects) {
+		if name <= marker {
+			continue
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		versions := objects[name]
+		latest := versions[sortedVersions(versions)[0]]
+		if latest.DeleteMarker {
+			continue
+		}
+		if len(delimiter) != 0 {
+			subStr := strings.TrimPrefix(name, prefix)
+			n := strings.Index(subStr, delimiter)
+			if n != -1 {
+				prefixKey := prefix + subStr[:n+len(delimiter)]
+				if prefixKey == marker {
+					continue
+				}
+				if _, ok := commonPrefixes[prefixKey]; ok {
+					continue
+				}
+				if count == maxKeys {
+					truncated = true
+					break
+				}
+				commonPrefixes[prefixKey] = struct{}{}
+				nextMarker = prefixKey
+				count += 1
+				continue
+			}
+		}
+		if count == maxKeys {
+			truncated = true
+			break
+		}
+		o := copyObject(latest)
+		retObjects = append(retObjects, o)
+		nextMarker = name
+		count += 1
+	}
+	prefixes = helper.Keys(commonPrefixes)
+	return
+}
+
+func (m *MemClient) DeleteBucket(bucket Bucket) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.data.Buckets, bucket.Name)
+	return m.save(nil)
+}
+
+func (m *MemClient) UpdateUsage(bucketName string, size int64, tx DB) (err error) {
+	if !helper.CONFIG.PiggybackUpdateUsage {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if b, ok := m.data.Buckets[bucketName]; ok {
+		m.keepUsage(tx, bucketName, size)
+		m.updateUsage(bucketName, b, size)
+		return m.save(tx)
+	}
+	return nil
+}
+
+// updateUsage replaces bucket `b` with a copy whose usage is changed by
+// `size`. Must be called with lock held
+func (m *MemClient) updateUsage(bucketName string, b *Bucket, size int64) {
+	updated := *b
+	updated.Usage += size
+	m.data.Buckets[bucketName] = &updated
+}
+
+// util function
+func sortedKeys(m interface{}) (keys []string) {
+	switch m := m.(type) {
+	case map[string]*Bucket:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]map[uint64]*Object:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]map[uint64]*Multipart:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]LifeCycle:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]struct{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
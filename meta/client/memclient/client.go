@@ -0,0 +1,458 @@
+package memclient
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/journeymidnight/yig/helper"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// MemClient keeps all metadata in process memory, it's the embedded meta
+// store used by single node deployments and tests which have no TiDB cluster.
+// If `path` is set, a JSON snapshot of all tables is written to it once
+// changes are made and loaded at startup, concurrent changes are written
+// together. Since every snapshot holds all metadata, it's meant for up to
+// about 100,000 objects, use TiDB for more.
+// Records in tables are never modified once stored, changes replace them
+// with modified copies, so snapshots are written without holding lock.
+// Transactions are serialized, changes made in a transaction are undone by
+// AbortTrans and written only by CommitTrans.
+type MemClient struct {
+	lock sync.Mutex
+	path string
+	data *tables
+
+	// held from NewTrans to CommitTrans/AbortTrans
+	txLock sync.Mutex
+	tx     *transaction
+
+	// changes up to generation `saved` have been written to `path`
+	saveLock sync.Mutex
+	changed  uint64
+	saved    uint64
+}
+
+// transaction collects actions which undo changes made in it, the *sql.Tx is
+// only a token identifying the transaction and never used to run queries
+type transaction struct {
+	token *sql.Tx
+	undo  []func()
+}
+
+// tables are the in-memory counterparts of tables in integrate/yig.sql,
+// versions and upload times are stored as in tidb, i.e.
+// math.MaxUint64 - time.UnixNano()
+type tables struct {
+	Buckets      map[string]*Bucket
+	Objects      map[string]map[string]map[uint64]*Object    // bucket -> name -> version
+	ObjMaps      map[string]map[string]*ObjMap               // bucket -> name
+	Multiparts   map[string]map[string]map[uint64]*Multipart // bucket -> name -> uploadtime
+	Clusters     []Cluster
+	LifeCycles   map[string]LifeCycle           // bucket
+	Users        map[string]map[string]struct{} // userid -> buckets
+	Garbages     map[string]*GarbageCollection  // rowkey
+	Replications map[string]*Replication        // rowkey
+	Freezers     map[string]map[string]*Freezer // bucket -> name
+}
+
+func newTables() *tables {
+	return &tables{
+		Buckets:      make(map[string]*Bucket),
+		Objects:      make(map[string]map[string]map[uint64]*Object),
+		ObjMaps:      make(map[string]map[string]*ObjMap),
+		Multiparts:   make(map[string]map[string]map[uint64]*Multipart),
+		LifeCycles:   make(map[string]LifeCycle),
+		Users:        make(map[string]map[string]struct{}),
+		Garbages:     make(map[string]*GarbageCollection),
+		Replications: make(map[string]*Replication),
+		Freezers:     make(map[string]map[string]*Freezer),
+	}
+}
+
+// snapshot copies maps of all tables, records are shared since they're never
+// modified once stored. Must be called with lock held
+func (t *tables) snapshot() *tables {
+	s := newTables()
+	for name, bucket := range t.Buckets {
+		s.Buckets[name] = bucket
+	}
+	for bucket, byName := range t.Objects {
+		s.Objects[bucket] = make(map[string]map[uint64]*Object, len(byName))
+		for name, versions := range byName {
+			copied := make(map[uint64]*Object, len(versions))
+			for version, object := range versions {
+				copied[version] = object
+			}
+			s.Objects[bucket][name] = copied
+		}
+	}
+	for bucket, byName := range t.ObjMaps {
+		s.ObjMaps[bucket] = make(map[string]*ObjMap, len(byName))
+		for name, objMap := range byName {
+			s.ObjMaps[bucket][name] = objMap
+		}
+	}
+	for bucket, byName := range t.Multiparts {
+		s.Multiparts[bucket] = make(map[string]map[uint64]*Multipart, len(byName))
+		for name, uploads := range byName {
+			copied := make(map[uint64]*Multipart, len(uploads))
+			for uploadTime, upload := range uploads {
+				copied[uploadTime] = upload
+			}
+			s.Multiparts[bucket][name] = copied
+		}
+	}
+	s.Clusters = append(s.Clusters, t.Clusters...)
+	for bucket, lifeCycle := range t.LifeCycles {
+		s.LifeCycles[bucket] = lifeCycle
+	}
+	for userId, buckets := range t.Users {
+		s.Users[userId] = make(map[string]struct{}, len(buckets))
+		for bucket := range buckets {
+			s.Users[userId][bucket] = struct{}{}
+		}
+	}
+	for rowkey, garbage := range t.Garbages {
+		s.Garbages[rowkey] = garbage
+	}
+	for rowkey, replication := range t.Replications {
+		s.Replications[rowkey] = replication
+	}
+	for bucket, byName := range t.Freezers {
+		s.Freezers[bucket] = make(map[string]*Freezer, len(byName))
+		for name, freezer := range byName {
+			s.Freezers[bucket][name] = freezer
+		}
+	}
+	return s
+}
+
+func NewMemClient() *MemClient {
+	cli, err := NewMemClientWithPath(helper.CONFIG.MetaStorePath)
+	if err != nil {
+		panic("Failed to load meta store " + helper.CONFIG.MetaStorePath + ": " + err.Error())
+	}
+	return cli
+}
+
+// NewMemClientWithPath creates a client persisted to `path`, or a pure
+// in-memory one if `path` is empty
+func NewMemClientWithPath(path string) (*MemClient, error) {
+	cli := &MemClient{
+		path: path,
+		data: newTables(),
+	}
+	if path == "" {
+		return cli, nil
+	}
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cli, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(buf, cli.data)
+	if err != nil {
+		return nil, err
+	}
+	// tables added in later versions are missing in old snapshots
+	empty := newTables()
+	if cli.data.Buckets == nil {
+		cli.data.Buckets = empty.Buckets
+	}
+	if cli.data.Objects == nil {
+		cli.data.Objects = empty.Objects
+	}
+	if cli.data.ObjMaps == nil {
+		cli.data.ObjMaps = empty.ObjMaps
+	}
+	if cli.data.Multiparts == nil {
+		cli.data.Multiparts = empty.Multiparts
+	}
+	if cli.data.LifeCycles == nil {
+		cli.data.LifeCycles = empty.LifeCycles
+	}
+	if cli.data.Users == nil {
+		cli.data.Users = empty.Users
+	}
+	if cli.data.Garbages == nil {
+		cli.data.Garbages = empty.Garbages
+	}
+	if cli.data.Replications == nil {
+		cli.data.Replications = empty.Replications
+	}
+	if cli.data.Freezers == nil {
+		cli.data.Freezers = empty.Freezers
+	}
+	return cli, nil
+}
+
+// save makes changes made out of transactions durable, changes made in a
+// transaction are saved when it's committed.
+// Must be called with lock held as the last step of a change, the lock is
+// released while the snapshot is written, so changes made by others in the
+// meantime are written together in the next snapshot.
+func (m *MemClient) save(tx DB) error {
+	if m.path == "" || m.inTrans(tx) != nil {
+		return nil
+	}
+	m.changed++
+	generation := m.changed
+	m.lock.Unlock()
+	defer m.lock.Lock()
+	return m.flush(generation)
+}
+
+// flush writes snapshot of all tables into a temporary file and renames it
+// to `path`, so a crash never leaves a half written snapshot. Nothing is
+// written if changes up to `generation` have been written by others
+func (m *MemClient) flush(generation uint64) error {
+	m.saveLock.Lock()
+	defer m.saveLock.Unlock()
+	if m.saved >= generation {
+		return nil
+	}
+	m.lock.Lock()
+	current := m.changed
+	snapshot := m.data.snapshot()
+	m.lock.Unlock()
+	buf, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(m.path), filepath.Base(m.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	err = os.Rename(f.Name(), m.path)
+	if err != nil {
+		return err
+	}
+	m.saved = current
+	return nil
+}
+
+func (m *MemClient) NewTrans() (tx *sql.Tx, err error) {
+	m.txLock.Lock()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.tx = &transaction{token: new(sql.Tx)}
+	return m.tx.token, nil
+}
+
+func (m *MemClient) AbortTrans(tx *sql.Tx) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t := m.inTrans(tx)
+	if t == nil {
+		return nil
+	}
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	m.tx = nil
+	m.txLock.Unlock()
+	if len(t.undo) == 0 {
+		return nil
+	}
+	// snapshots written by others may contain the undone changes
+	return m.save(nil)
+}
+
+func (m *MemClient) CommitTrans(tx *sql.Tx) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t := m.inTrans(tx)
+	if t == nil {
+		return nil
+	}
+	m.tx = nil
+	m.txLock.Unlock()
+	if len(t.undo) == 0 {
+		return nil
+	}
+	return m.save(nil)
+}
+
+// inTrans returns the open transaction which `tx` refers to, or nil if
+// `tx` is not in a transaction.
+// Must be called with lock held
+func (m *MemClient) inTrans(tx DB) *transaction {
+	if m.tx == nil || tx == nil {
+		return nil
+	}
+	if token, ok := tx.(*sql.Tx); ok && token == m.tx.token {
+		return m.tx
+	}
+	return nil
+}
+
+// keep records `undo` which reverts a change about to be made in `tx`.
+// Must be called with lock held
+func (m *MemClient) keep(tx DB, undo func()) {
+	if t := m.inTrans(tx); t != nil {
+		t.undo = append(t.undo, undo)
+	}
+}
+
+// keepObject keeps version `version` of an object, so it could be restored
+// if `tx` is aborted. Only the version is restored, so changes made to other
+// versions out of `tx` meanwhile are kept. Must be called with lock held
+func (m *MemClient) keepObject(tx DB, bucketName, objectName string, version uint64) {
+	if m.inTrans(tx) == nil {
+		return
+	}
+	object, ok := m.data.Objects[bucketName][objectName][version]
+	var saved *Object
+	clone(object, &saved)
+	m.keep(tx, func() {
+		if !ok {
+			versions := m.data.Objects[bucketName][objectName]
+			delete(versions, version)
+			if versions != nil && len(versions) == 0 {
+				delete(m.data.Objects[bucketName], objectName)
+			}
+			return
+		}
+		m.objectVersions(bucketName, objectName)[version] = saved
+	})
+}
+
+// Must be called with lock held
+func (m *MemClient) keepObjectMap(tx DB, bucketName, objectName string) {
+	if m.inTrans(tx) == nil {
+		return
+	}
+	objMap, ok := m.data.ObjMaps[bucketName][objectName]
+	var saved ObjMap
+	if ok {
+		saved = *objMap
+	}
+	m.keep(tx, func() {
+		if !ok {
+			delete(m.data.ObjMaps[bucketName], objectName)
+			return
+		}
+		if _, ok := m.data.ObjMaps[bucketName]; !ok {
+			m.data.ObjMaps[bucketName] = make(map[string]*ObjMap)
+		}
+		m.data.ObjMaps[bucketName][objectName] = &saved
+	})
+}
+
+// keepMultipart keeps upload of an object started at `uploadTime`, so it
+// could be restored if `tx` is aborted. Must be called with lock held
+func (m *MemClient) keepMultipart(tx DB, bucketName, objectName string, uploadTime uint64) {
+	if m.inTrans(tx) == nil {
+		return
+	}
+	upload, ok := m.data.Multiparts[bucketName][objectName][uploadTime]
+	var saved *Multipart
+	clone(upload, &saved)
+	m.keep(tx, func() {
+		if !ok {
+			uploads := m.data.Multiparts[bucketName][objectName]
+			delete(uploads, uploadTime)
+			if uploads != nil && len(uploads) == 0 {
+				delete(m.data.Multiparts[bucketName], objectName)
+			}
+			return
+		}
+		if _, ok := m.data.Multiparts[bucketName]; !ok {
+			m.data.Multiparts[bucketName] = make(map[string]map[uint64]*Multipart)
+		}
+		if _, ok := m.data.Multiparts[bucketName][objectName]; !ok {
+			m.data.Multiparts[bucketName][objectName] = make(map[uint64]*Multipart)
+		}
+		m.data.Multiparts[bucketName][objectName][uploadTime] = saved
+	})
+}
+
+// keepPart keeps part `partNumber` of an upload, so it could be restored if
+// `tx` is aborted, other parts uploaded meanwhile are kept.
+// Must be called with lock held
+func (m *MemClient) keepPart(tx DB, bucketName, objectName string, uploadTime uint64, partNumber int) {
+	if m.inTrans(tx) == nil {
+		return
+	}
+	part, ok := m.data.Multiparts[bucketName][objectName][uploadTime].Parts[partNumber]
+	var saved *Part
+	clone(part, &saved)
+	m.keep(tx, func() {
+		m.updateParts(bucketName, objectName, uploadTime, func(parts map[int]*Part) {
+			if !ok {
+				delete(parts, partNumber)
+				return
+			}
+			parts[partNumber] = saved
+		})
+	})
+}
+
+// Must be called with lock held
+func (m *MemClient) keepFreezer(tx DB, bucketName, objectName string) {
+	if m.inTrans(tx) == nil {
+		return
+	}
+	freezer, ok := m.data.Freezers[bucketName][objectName]
+	var saved *Freezer
+	clone(freezer, &saved)
+	m.keep(tx, func() {
+		if !ok {
+			delete(m.data.Freezers[bucketName], objectName)
+			return
+		}
+		if _, ok := m.data.Freezers[bucketName]; !ok {
+			m.data.Freezers[bucketName] = make(map[string]*Freezer)
+		}
+		m.data.Freezers[bucketName][objectName] = saved
+	})
+}
+
+// Must be called with lock held, before garbage `rowkey` is added
+func (m *MemClient) keepGarbage(tx DB, rowkey string) {
+	m.keep(tx, func() {
+		delete(m.data.Garbages, rowkey)
+	})
+}
+
+// keepUsage records reverting usage of a bucket changed by `size` in `tx`,
+// so usage changed by others meanwhile is kept. Must be called with lock held
+func (m *MemClient) keepUsage(tx DB, bucketName string, size int64) {
+	m.keep(tx, func() {
+		if b, ok := m.data.Buckets[bucketName]; ok {
+			m.updateUsage(bucketName, b, -size)
+		}
+	})
+}
+
+// clone deep copies `from` into `to` so callers never share records
+// with the tables
+func clone(from, to interface{}) {
+	buf, err := json.Marshal(from)
+	if err != nil {
+		panic("Failed to copy meta record: " + err.Error())
+	}
+	err = json.Unmarshal(buf, to)
+	if err != nil {
+		panic("Failed to copy meta record: " + err.Error())
+	}
+}
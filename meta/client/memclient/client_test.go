@@ -0,0 +1,358 @@
+package memclient_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/meta/client/clienttest"
+	"github.com/journeymidnight/yig/meta/client/memclient"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func newClient(t *testing.T) *memclient.MemClient {
+	client, err := memclient.NewMemClientWithPath("")
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	return client
+}
+
+func putObject(t *testing.T, client *memclient.MemClient, bucket, name string,
+	modified time.Time) *Object {
+
+	object := &Object{
+		BucketName:       bucket,
+		Name:             name,
+		LastModifiedTime: modified,
+		Etag:             name,
+		CustomAttributes: map[string]string{"name": name},
+	}
+	err := client.PutObject(object, nil)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	return object
+}
+
+func TestMemClient(t *testing.T) {
+	clienttest.RunClientTests(t, newClient(t))
+}
+
+// garbages are scanned in the order of row key
+func TestMemClient_GarbageCollection(t *testing.T) {
+	client := newClient(t)
+	now := time.Now()
+	for _, name := range []string{"b", "a", "c"} {
+		err := client.PutObjectToGarbageCollection(&Object{
+			BucketName:       "hehe",
+			Name:             name,
+			LastModifiedTime: now,
+		}, nil)
+		if err != nil {
+			t.Fatal("PutObjectToGarbageCollection err:", err)
+		}
+	}
+	gcs, err := client.ScanGarbageCollection(2, "")
+	if err != nil || len(gcs) != 2 || gcs[0].ObjectName != "a" || gcs[1].ObjectName != "b" {
+		t.Fatal("ScanGarbageCollection:", gcs, err)
+	}
+	// start row key is inclusive
+	gcs, err = client.ScanGarbageCollection(2, gcs[1].Rowkey)
+	if err != nil || len(gcs) != 2 || gcs[0].ObjectName != "b" || gcs[1].ObjectName != "c" {
+		t.Fatal("ScanGarbageCollection from row key:", gcs, err)
+	}
+	err = client.RemoveGarbageCollection(gcs[0])
+	if err != nil {
+		t.Fatal("RemoveGarbageCollection err:", err)
+	}
+	gcs, _ = client.ScanGarbageCollection(10, "")
+	if len(gcs) != 2 {
+		t.Fatal("ScanGarbageCollection after remove:", gcs)
+	}
+}
+
+func TestMemClient_Snapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yig-meta")
+	if err != nil {
+		t.Fatal("TempDir err:", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "meta.json")
+
+	client, err := memclient.NewMemClientWithPath(path)
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	_, err = client.CheckAndPutBucket(Bucket{Name: "hehe", CreateTime: time.Now()})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	object := putObject(t, client, "hehe", "a", time.Now())
+	err = client.AddBucketForUser("hehe", "haha")
+	if err != nil {
+		t.Fatal("AddBucketForUser err:", err)
+	}
+
+	client, err = memclient.NewMemClientWithPath(path)
+	if err != nil {
+		t.Fatal("Reload snapshot err:", err)
+	}
+	_, err = client.GetBucket("hehe")
+	if err != nil {
+		t.Fatal("GetBucket after reload:", err)
+	}
+	got, err := client.GetObject("hehe", "a", "")
+	if err != nil || !got.LastModifiedTime.Equal(object.LastModifiedTime) {
+		t.Fatal("GetObject after reload:", got, err)
+	}
+	buckets, err := client.GetUserBuckets("haha")
+	if err != nil || len(buckets) != 1 || buckets[0] != "hehe" {
+		t.Fatal("GetUserBuckets after reload:", buckets, err)
+	}
+}
+
+// changes made in an aborted transaction are reverted, committed ones are kept
+func TestMemClient_Transaction(t *testing.T) {
+	helper.CONFIG.PiggybackUpdateUsage = true
+	defer func() { helper.CONFIG.PiggybackUpdateUsage = false }()
+	client := newClient(t)
+	_, err := client.CheckAndPutBucket(Bucket{Name: "hehe", CreateTime: time.Now()})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	old := putObject(t, client, "hehe", "a", time.Now())
+
+	change := func(name string, nullVerNum uint64) {
+		tx, err := client.NewTrans()
+		if err != nil {
+			t.Fatal("NewTrans err:", err)
+		}
+		err = client.PutObjectToGarbageCollection(old, tx)
+		if err != nil {
+			t.Fatal("PutObjectToGarbageCollection err:", err)
+		}
+		object := &Object{
+			BucketName:       "hehe",
+			Name:             "a",
+			LastModifiedTime: time.Now(),
+			Etag:             name,
+		}
+		err = client.PutObject(object, tx)
+		if err != nil {
+			t.Fatal("PutObject err:", err)
+		}
+		err = client.PutObjectMap(&ObjMap{BucketName: "hehe", Name: "a", NullVerNum: nullVerNum}, tx)
+		if err != nil {
+			t.Fatal("PutObjectMap err:", err)
+		}
+		err = client.UpdateUsage("hehe", 10, tx)
+		if err != nil {
+			t.Fatal("UpdateUsage err:", err)
+		}
+		if name == "aborted" {
+			err = client.AbortTrans(tx)
+		} else {
+			err = client.CommitTrans(tx)
+		}
+		if err != nil {
+			t.Fatal("End transaction err:", err)
+		}
+	}
+
+	change("aborted", 1)
+	got, err := client.GetObject("hehe", "a", "")
+	if err != nil || got.Etag != "a" {
+		t.Fatal("GetObject after abort:", got, err)
+	}
+	if _, err = client.GetObjectMap("hehe", "a"); err == nil {
+		t.Fatal("GetObjectMap after abort should fail")
+	}
+	gcs, _ := client.ScanGarbageCollection(10, "")
+	if len(gcs) != 0 {
+		t.Fatal("ScanGarbageCollection after abort:", gcs)
+	}
+	bucket, _ := client.GetBucket("hehe")
+	if bucket.Usage != 0 {
+		t.Fatal("Usage after abort:", bucket.Usage)
+	}
+
+	change("committed", 2)
+	got, err = client.GetObject("hehe", "a", "")
+	if err != nil || got.Etag != "committed" {
+		t.Fatal("GetObject after commit:", got, err)
+	}
+	objMap, err := client.GetObjectMap("hehe", "a")
+	if err != nil || objMap.NullVerNum != 2 {
+		t.Fatal("GetObjectMap after commit:", objMap, err)
+	}
+	gcs, _ = client.ScanGarbageCollection(10, "")
+	if len(gcs) != 1 {
+		t.Fatal("ScanGarbageCollection after commit:", gcs)
+	}
+	bucket, _ = client.GetBucket("hehe")
+	if bucket.Usage != 10 {
+		t.Fatal("Usage after commit:", bucket.Usage)
+	}
+}
+
+// aborting a transaction undoes only its own changes, changes made out of it
+// meanwhile to other versions, parts and usage are kept
+func TestMemClient_AbortKeepsOtherChanges(t *testing.T) {
+	helper.CONFIG.PiggybackUpdateUsage = true
+	defer func() { helper.CONFIG.PiggybackUpdateUsage = false }()
+	client := newClient(t)
+	_, err := client.CheckAndPutBucket(Bucket{Name: "hehe", CreateTime: time.Now()})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	now := time.Now()
+	old := putObject(t, client, "hehe", "a", now.Add(-time.Hour))
+	multipart := Multipart{BucketName: "hehe", ObjectName: "b", InitialTime: now}
+	err = client.CreateMultipart(multipart)
+	if err != nil {
+		t.Fatal("CreateMultipart err:", err)
+	}
+	putPart := func(number int, tx DB) {
+		err := client.PutObjectPart(&multipart, &Part{
+			PartNumber:   number,
+			LastModified: now.UTC().Format(CREATE_TIME_LAYOUT),
+		}, tx)
+		if err != nil {
+			t.Fatal("PutObjectPart err:", err)
+		}
+	}
+
+	tx, err := client.NewTrans()
+	if err != nil {
+		t.Fatal("NewTrans err:", err)
+	}
+	err = client.PutObject(&Object{BucketName: "hehe", Name: "a", LastModifiedTime: now, Etag: "aborted"}, tx)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	err = client.UpdateUsage("hehe", 10, tx)
+	if err != nil {
+		t.Fatal("UpdateUsage err:", err)
+	}
+	putPart(1, tx)
+
+	// out of transaction
+	concurrent := putObject(t, client, "hehe", "a", now.Add(time.Hour))
+	old.Tagging = map[string]string{"hehe": "haha"}
+	err = client.UpdateObjectTagging(old)
+	if err != nil {
+		t.Fatal("UpdateObjectTagging err:", err)
+	}
+	err = client.UpdateUsage("hehe", 5, nil)
+	if err != nil {
+		t.Fatal("UpdateUsage err:", err)
+	}
+	putPart(2, nil)
+
+	err = client.AbortTrans(tx)
+	if err != nil {
+		t.Fatal("AbortTrans err:", err)
+	}
+	versions, err := client.GetAllObject("hehe", "a", "")
+	if err != nil || len(versions) != 2 || versions[0].Etag != concurrent.Etag ||
+		versions[1].Tagging["hehe"] != "haha" {
+		t.Fatal("GetAllObject after abort:", versions, err)
+	}
+	bucket, _ := client.GetBucket("hehe")
+	if bucket.Usage != 5 {
+		t.Fatal("Usage after abort:", bucket.Usage)
+	}
+	uploadId, _ := multipart.GetUploadId()
+	got, err := client.GetMultipart("hehe", "b", uploadId)
+	if err != nil || len(got.Parts) != 1 || got.Parts[2] == nil {
+		t.Fatal("GetMultipart after abort:", got.Parts, err)
+	}
+}
+
+// concurrent changes are written together, and none of them is lost
+func TestMemClient_ConcurrentSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yig-meta")
+	if err != nil {
+		t.Fatal("TempDir err:", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "meta.json")
+
+	client, err := memclient.NewMemClientWithPath(path)
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	_, err = client.CheckAndPutBucket(Bucket{Name: "hehe", CreateTime: time.Now()})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			putObject(t, client, "hehe", strconv.Itoa(i), time.Now())
+		}(i)
+	}
+	wg.Wait()
+
+	client, err = memclient.NewMemClientWithPath(path)
+	if err != nil {
+		t.Fatal("Reload snapshot err:", err)
+	}
+	for i := 0; i < 20; i++ {
+		_, err = client.GetObject("hehe", strconv.Itoa(i), "")
+		if err != nil {
+			t.Fatal("GetObject after reload:", i, err)
+		}
+	}
+}
+
+func TestMemClient_Clusters(t *testing.T) {
+	client := newClient(t)
+	for _, c := range []Cluster{
+		{Fsid: "a", Pool: "rabbit", Weight: 1},
+		{Fsid: "a", Pool: "tiger", Weight: 1},
+		{Fsid: "b", Pool: "tiger", Weight: 1},
+	} {
+		err := client.PutCluster(c)
+		if err != nil {
+			t.Fatal("PutCluster err:", err)
+		}
+	}
+	err := client.SetClusterStatus("a", ClusterStatusDraining)
+	if err != nil {
+		t.Fatal("SetClusterStatus err:", err)
+	}
+	// status is kept when weight changes
+	err = client.PutCluster(Cluster{Fsid: "a", Pool: "tiger", Weight: 0})
+	if err != nil {
+		t.Fatal("PutCluster existing err:", err)
+	}
+	clusters, err := client.GetClusters()
+	if err != nil || len(clusters) != 3 {
+		t.Fatal("GetClusters:", clusters, err)
+	}
+	for _, c := range clusters {
+		switch {
+		case c.Fsid == "a" && c.Pool == "tiger":
+			if c.Weight != 0 || c.Status != ClusterStatusDraining {
+				t.Fatal("Unexpected cluster:", c)
+			}
+		case c.Fsid == "a":
+			if c.Weight != 1 || c.Status != ClusterStatusDraining {
+				t.Fatal("Unexpected cluster:", c)
+			}
+		default:
+			if c.Weight != 1 || c.Status != ClusterStatusActive {
+				t.Fatal("Unexpected cluster:", c)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package memclient
+
+import (
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// GetClusters returns clusters loaded from snapshot, yig writes into the
+// first data cluster if there's none
+func (m *MemClient) GetClusters() (cluster []Cluster, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append(cluster, m.data.Clusters...), nil
+}
+
+func (m *MemClient) SetClusterStatus(fsid string, status ClusterStatus) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i := range m.data.Clusters {
+		if m.data.Clusters[i].Fsid == fsid {
+			m.data.Clusters[i].Status = status
+		}
+	}
+	return m.save(nil)
+}
+
+func (m *MemClient) PutCluster(cluster Cluster) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i := range m.data.Clusters {
+		if m.data.Clusters[i].Fsid == cluster.Fsid && m.data.Clusters[i].Pool == cluster.Pool {
+			m.data.Clusters[i].Weight = cluster.Weight
+			return m.save(nil)
+		}
+	}
+	m.data.Clusters = append(m.data.Clusters, cluster)
+	return m.save(nil)
+}
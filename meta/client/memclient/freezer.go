@@ -0,0 +1,195 @@
+package memclient
+
+import (
+	"sort"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (m *MemClient) CreateFreezer(freezer *Freezer) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored := &Freezer{
+		BucketName:       freezer.BucketName,
+		Name:             freezer.Name,
+		Status:           freezer.Status,
+		LifeTime:         freezer.LifeTime,
+		LastModifiedTime: freezer.LastModifiedTime,
+		Tier:             freezer.Tier,
+	}
+	freezers, ok := m.data.Freezers[freezer.BucketName]
+	if !ok {
+		freezers = make(map[string]*Freezer)
+		m.data.Freezers[freezer.BucketName] = freezers
+	}
+	freezers[freezer.Name] = stored
+	return m.save(nil)
+}
+
+func (m *MemClient) GetFreezer(bucketName, objectName, version string) (freezer *Freezer, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.Freezers[bucketName][objectName]
+	if !ok {
+		return nil, ErrNoSuchKey
+	}
+	freezer = new(Freezer)
+	clone(stored, freezer)
+	if freezer.Parts == nil {
+		freezer.Parts = make(map[int]*Part)
+	}
+	//build simple index for multipart
+	freezer.PartsIndex = nil
+	if len(freezer.Parts) != 0 {
+		var sortedPartNum = make([]int64, len(freezer.Parts))
+		for k, v := range freezer.Parts {
+			sortedPartNum[k-1] = v.Offset
+		}
+		freezer.PartsIndex = &SimpleIndex{Index: sortedPartNum}
+	}
+	return
+}
+
+func (m *MemClient) GetFreezerStatus(bucketName, objectName, version string) (freezer *Freezer, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.Freezers[bucketName][objectName]
+	if !ok {
+		return nil, ErrNoSuchKey
+	}
+	return &Freezer{
+		BucketName:       stored.BucketName,
+		Name:             stored.Name,
+		Status:           stored.Status,
+		LifeTime:         stored.LifeTime,
+		LastModifiedTime: stored.LastModifiedTime,
+	}, nil
+}
+
+func (m *MemClient) UploadFreezerDate(bucketName, objectName string, lifetime int) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if stored, ok := m.data.Freezers[bucketName][objectName]; ok {
+		updated := *stored
+		updated.LifeTime = lifetime
+		m.data.Freezers[bucketName][objectName] = &updated
+	}
+	return m.save(nil)
+}
+
+func (m *MemClient) DeleteFreezer(bucketName, objectName string, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepFreezer(tx, bucketName, objectName)
+	freezers := m.data.Freezers[bucketName]
+	delete(freezers, objectName)
+	if freezers != nil && len(freezers) == 0 {
+		delete(m.data.Freezers, bucketName)
+	}
+	return m.save(tx)
+}
+
+// ListFreezers returns at most `limit` freezers in `status`, in the order of
+// tier and request time
+func (m *MemClient) ListFreezers(status Status, limit int) (freezers []Freezer, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, byName := range m.data.Freezers {
+		for _, stored := range byName {
+			if stored.Status != status {
+				continue
+			}
+			freezers = append(freezers, Freezer{
+				BucketName:       stored.BucketName,
+				Name:             stored.Name,
+				Status:           stored.Status,
+				LifeTime:         stored.LifeTime,
+				LastModifiedTime: stored.LastModifiedTime,
+				Tier:             stored.Tier,
+			})
+		}
+	}
+	sort.Slice(freezers, func(i, j int) bool {
+		if freezers[i].Tier != freezers[j].Tier {
+			return freezers[i].Tier < freezers[j].Tier
+		}
+		return freezers[i].LastModifiedTime.Before(freezers[j].LastModifiedTime)
+	})
+	if len(freezers) > limit {
+		freezers = freezers[:limit]
+	}
+	return
+}
+
+// ListExpiredFreezers returns at most `limit` restored freezers whose
+// lifetime has passed at `now`
+func (m *MemClient) ListExpiredFreezers(now time.Time, limit int) (freezers []Freezer, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, byName := range m.data.Freezers {
+		for _, stored := range byName {
+			if len(freezers) >= limit {
+				return
+			}
+			if stored.Status != ObjectHasRestored ||
+				!stored.LastModifiedTime.AddDate(0, 0, stored.LifeTime).Before(now) {
+				continue
+			}
+			freezers = append(freezers, Freezer{
+				BucketName: stored.BucketName,
+				Name:       stored.Name,
+				Status:     ObjectHasRestored,
+			})
+		}
+	}
+	return
+}
+
+// UpdateFreezerStatus changes status of freezer only if it's still in
+// `freezer.Status`, returns false if the freezer is changed by others
+func (m *MemClient) UpdateFreezerStatus(freezer *Freezer, status Status) (updated bool, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.Freezers[freezer.BucketName][freezer.Name]
+	if !ok || stored.Status != freezer.Status {
+		return false, nil
+	}
+	changed := *stored
+	changed.Status = status
+	m.data.Freezers[freezer.BucketName][freezer.Name] = &changed
+	return true, m.save(nil)
+}
+
+// UpdateFreezer saves location of restored data into freezer and
+// changes its status
+func (m *MemClient) UpdateFreezer(freezer *Freezer, status Status, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.Freezers[freezer.BucketName][freezer.Name]
+	if !ok || stored.Status != freezer.Status {
+		return ErrNoSuchKey
+	}
+	m.keepFreezer(tx, freezer.BucketName, freezer.Name)
+	updated := *stored
+	updated.Status = status
+	updated.LastModifiedTime = freezer.LastModifiedTime
+	updated.Location = freezer.Location
+	updated.Pool = freezer.Pool
+	updated.OwnerId = freezer.OwnerId
+	updated.Size = freezer.Size
+	updated.ObjectId = freezer.ObjectId
+	updated.Etag = freezer.Etag
+	updated.Parts = make(map[int]*Part, len(stored.Parts)+len(freezer.Parts))
+	for n, p := range stored.Parts {
+		updated.Parts[n] = p
+	}
+	for n, p := range freezer.Parts {
+		part := new(Part)
+		clone(p, part)
+		updated.Parts[n] = part
+	}
+	m.data.Freezers[freezer.BucketName][freezer.Name] = &updated
+	return m.save(tx)
+}
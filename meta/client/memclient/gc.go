@@ -0,0 +1,104 @@
+package memclient
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// gc
+func (m *MemClient) PutObjectToGarbageCollection(object *Object, tx DB) (err error) {
+	gc := GarbageCollection{
+		BucketName: object.BucketName,
+		ObjectName: object.Name,
+		Location:   object.Location,
+		Pool:       object.Pool,
+		ObjectId:   object.ObjectId,
+		Parts:      object.Parts,
+	}
+	return m.putGarbageCollection(gc, object.LastModifiedTime, tx)
+}
+
+func (m *MemClient) PutFreezerToGarbageCollection(object *Freezer, tx DB) (err error) {
+	gc := GarbageCollection{
+		BucketName: object.BucketName,
+		ObjectName: object.Name,
+		Location:   object.Location,
+		Pool:       object.Pool,
+		ObjectId:   object.ObjectId,
+		Parts:      object.Parts,
+	}
+	return m.putGarbageCollection(gc, object.LastModifiedTime, tx)
+}
+
+func (m *MemClient) putGarbageCollection(gc GarbageCollection, lastModifiedTime time.Time, tx DB) error {
+	version := math.MaxUint64 - uint64(lastModifiedTime.UnixNano())
+	gc.Rowkey = gc.BucketName + ObjectNameSeparator + gc.ObjectName +
+		ObjectNameSeparator + strconv.FormatUint(version, 10)
+	gc.Status = "Pending"
+	gc.MTime = time.Now().UTC()
+	gc.TriedTimes = 0
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	// same as "insert ignore"
+	if _, ok := m.data.Garbages[gc.Rowkey]; ok {
+		return nil
+	}
+	m.keepGarbage(tx, gc.Rowkey)
+	stored := new(GarbageCollection)
+	clone(gc, stored)
+	m.data.Garbages[gc.Rowkey] = stored
+	return m.save(tx)
+}
+
+func (m *MemClient) ScanGarbageCollection(limit int, startRowKey string) (gcs []GarbageCollection, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var rowkeys []string
+	for rowkey := range m.data.Garbages {
+		if startRowKey == "" || !rowkeyLess(rowkey, startRowKey) {
+			rowkeys = append(rowkeys, rowkey)
+		}
+	}
+	sort.Slice(rowkeys, func(i, j int) bool { return rowkeyLess(rowkeys[i], rowkeys[j]) })
+	for _, rowkey := range rowkeys {
+		if len(gcs) >= limit {
+			break
+		}
+		var gc GarbageCollection
+		clone(m.data.Garbages[rowkey], &gc)
+		gcs = append(gcs, gc)
+	}
+	return
+}
+
+func (m *MemClient) RemoveGarbageCollection(garbage GarbageCollection) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.data.Garbages, garbage.Rowkey)
+	return m.save(nil)
+}
+
+// util function
+// rowkeyLess compares rowkeys in the form of bucket+sep+object+sep+version
+// in the order of bucket, object and numeric version
+func rowkeyLess(a, b string) bool {
+	as := strings.Split(a, ObjectNameSeparator)
+	bs := strings.Split(b, ObjectNameSeparator)
+	if len(as) != 3 || len(bs) != 3 {
+		return a < b
+	}
+	if as[0] != bs[0] {
+		return as[0] < bs[0]
+	}
+	if as[1] != bs[1] {
+		return as[1] < bs[1]
+	}
+	av, _ := strconv.ParseUint(as[2], 10, 64)
+	bv, _ := strconv.ParseUint(bs[2], 10, 64)
+	return av < bv
+}
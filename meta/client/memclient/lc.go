@@ -0,0 +1,58 @@
+package memclient
+
+import (
+	"sort"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (m *MemClient) PutBucketToLifeCycle(lifeCycle LifeCycle) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.data.LifeCycles[lifeCycle.BucketName] = lifeCycle
+	return m.save(nil)
+}
+
+func (m *MemClient) RemoveBucketFromLifeCycle(bucket Bucket) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.data.LifeCycles, bucket.Name)
+	return m.save(nil)
+}
+
+func (m *MemClient) ScanLifeCycle(limit int, marker string) (result ScanLifeCycleResult, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result.Lcs = make([]LifeCycle, 0, limit)
+	for _, name := range sortedKeys(m.data.LifeCycles) {
+		if name <= marker {
+			continue
+		}
+		if len(result.Lcs) == limit {
+			break
+		}
+		result.Lcs = append(result.Lcs, m.data.LifeCycles[name])
+		result.NextMarker = name
+	}
+	if len(result.Lcs) == limit {
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// ScanObjectNames lists distinct object names of bucket after marker,
+// lifecycle worker then checks all versions of each name
+func (m *MemClient) ScanObjectNames(bucketName, marker string, limit int) (names []string, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for name := range m.data.Objects[bucketName] {
+		if name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	return names, nil
+}
@@ -0,0 +1,231 @@
+package memclient
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam"
+	"github.com/journeymidnight/yig/iam/common"
+	. "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/meta/util"
+)
+
+func (m *MemClient) GetMultipart(bucketName, objectName, uploadId string) (multipart Multipart, err error) {
+	timestampString, err := util.Decrypt(uploadId)
+	if err != nil {
+		return
+	}
+	uploadTime, err := strconv.ParseUint(timestampString, 10, 64)
+	if err != nil {
+		return
+	}
+	uploadTime = math.MaxUint64 - uploadTime
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.Multiparts[bucketName][objectName][uploadTime]
+	if !ok {
+		err = ErrNoSuchUpload
+		return
+	}
+	clone(stored, &multipart)
+	if multipart.Parts == nil {
+		multipart.Parts = make(map[int]*Part)
+	}
+	return
+}
+
+func (m *MemClient) CreateMultipart(multipart Multipart) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored := new(Multipart)
+	clone(multipart, stored)
+	stored.UploadId = ""
+	stored.Parts = make(map[int]*Part)
+	uploads, ok := m.data.Multiparts[multipart.BucketName]
+	if !ok {
+		uploads = make(map[string]map[uint64]*Multipart)
+		m.data.Multiparts[multipart.BucketName] = uploads
+	}
+	if _, ok := uploads[multipart.ObjectName]; !ok {
+		uploads[multipart.ObjectName] = make(map[uint64]*Multipart)
+	}
+	uploads[multipart.ObjectName][uploadTimeOf(multipart.InitialTime)] = stored
+	return m.save(nil)
+}
+
+func (m *MemClient) PutObjectPart(multipart *Multipart, part *Part, tx DB) (err error) {
+	_, err = time.Parse(CREATE_TIME_LAYOUT, part.LastModified)
+	if err != nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.data.Multiparts[multipart.BucketName][multipart.ObjectName][uploadTimeOf(multipart.InitialTime)]
+	if !ok {
+		return nil
+	}
+	m.keepPart(tx, multipart.BucketName, multipart.ObjectName,
+		uploadTimeOf(multipart.InitialTime), part.PartNumber)
+	p := new(Part)
+	clone(part, p)
+	m.updateParts(multipart.BucketName, multipart.ObjectName, uploadTimeOf(multipart.InitialTime),
+		func(parts map[int]*Part) {
+			parts[p.PartNumber] = p
+		})
+	return m.save(tx)
+}
+
+// updateParts replaces an upload with a copy whose parts are changed by
+// `update`. Must be called with lock held
+func (m *MemClient) updateParts(bucketName, objectName string, uploadTime uint64,
+	update func(parts map[int]*Part)) {
+
+	uploads := m.data.Multiparts[bucketName][objectName]
+	stored, ok := uploads[uploadTime]
+	if !ok {
+		return
+	}
+	updated := *stored
+	updated.Parts = make(map[int]*Part, len(stored.Parts)+1)
+	for n, p := range stored.Parts {
+		updated.Parts[n] = p
+	}
+	update(updated.Parts)
+	uploads[uploadTime] = &updated
+}
+
+func (m *MemClient) DeleteMultipart(multipart *Multipart, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	uploadTime := uploadTimeOf(multipart.InitialTime)
+	m.keepMultipart(tx, multipart.BucketName, multipart.ObjectName, uploadTime)
+	uploads := m.data.Multiparts[multipart.BucketName][multipart.ObjectName]
+	delete(uploads, uploadTime)
+	if uploads != nil && len(uploads) == 0 {
+		delete(m.data.Multiparts[multipart.BucketName], multipart.ObjectName)
+	}
+	return m.save(tx)
+}
+
+func (m *MemClient) ListMultipartUploads(bucketName, keyMarker, uploadIdMarker, prefix, delimiter, encodingType string, maxUploads int) (uploads []datatype.Upload, prefixs []string, isTruncated bool, nextKeyMarker, nextUploadIdMarker string, err error) {
+	var uploadNum uint64
+	if uploadIdMarker != "" {
+		var timestampString string
+		timestampString, err = util.Decrypt(uploadIdMarker)
+		if err != nil {
+			return
+		}
+		uploadNum, err = strconv.ParseUint(timestampString, 10, 64)
+		if err != nil {
+			return
+		}
+		uploadNum = math.MaxUint64 - uploadNum
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var count int
+	commonPrefixes := make(map[string]struct{})
+	multiparts := m.data.Multiparts[bucketName]
+	for _, name := range sortedKeys(multiparts) {
+		if name < keyMarker {
+			continue
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if len(delimiter) != 0 {
+			subStr := strings.TrimPrefix(name, prefix)
+			n := strings.Index(subStr, delimiter)
+			if n != -1 {
+				commonPrefixes[prefix+subStr[:n+len(delimiter)]] = struct{}{}
+				continue
+			}
+		}
+		for _, uploadtime := range sortedUploadTimes(multiparts[name]) {
+			//filte by uploadtime and key
+			if uploadNum != 0 && name == keyMarker && uploadtime < uploadNum {
+				continue
+			}
+			if count >= maxUploads {
+				isTruncated = true
+				nextKeyMarker = name
+				nextUploadIdMarker = GetMultipartUploadIdForTidb(uploadtime)
+				prefixs = helper.Keys(commonPrefixes)
+				return
+			}
+			multipart := multiparts[name][uploadtime]
+			upload := datatype.Upload{StorageClass: multipart.Metadata.StorageClass.ToString()}
+			upload.UploadId = GetMultipartUploadIdForTidb(uploadtime)
+			upload.Key = name
+			if encodingType != "" {
+				upload.Key = url.QueryEscape(upload.Key)
+			}
+			var user common.Credential
+			user, err = iam.GetCredentialByUserId(multipart.Metadata.OwnerId)
+			if err != nil {
+				return
+			}
+			upload.Owner.ID = user.UserId
+			upload.Owner.DisplayName = user.DisplayName
+			user, err = iam.GetCredentialByUserId(multipart.Metadata.InitiatorId)
+			if err != nil {
+				return
+			}
+			upload.Initiator.ID = user.UserId
+			upload.Initiator.DisplayName = user.DisplayName
+			upload.Initiated = multipart.InitialTime.UTC().Format(CREATE_TIME_LAYOUT)
+			uploads = append(uploads, upload)
+			count += 1
+		}
+	}
+	prefixs = helper.Keys(commonPrefixes)
+	return
+}
+
+func (m *MemClient) ListStaleMultipartUploads(initiatedBefore time.Time, limit int) (uploads []Multipart, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, multiparts := range m.data.Multiparts {
+		for _, byTime := range multiparts {
+			for _, multipart := range byTime {
+				if !multipart.InitialTime.Before(initiatedBefore) {
+					continue
+				}
+				var upload Multipart
+				clone(multipart, &upload)
+				upload.Parts = nil
+				upload.GetUploadId()
+				uploads = append(uploads, upload)
+			}
+		}
+	}
+	// oldest first
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].InitialTime.Before(uploads[j].InitialTime)
+	})
+	if len(uploads) > limit {
+		uploads = uploads[:limit]
+	}
+	return
+}
+
+// util function
+func uploadTimeOf(initialTime time.Time) uint64 {
+	return math.MaxUint64 - uint64(initialTime.UnixNano())
+}
+
+func sortedUploadTimes(multiparts map[uint64]*Multipart) []uint64 {
+	sorted := make([]uint64, 0, len(multiparts))
+	for t := range multiparts {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,311 @@
+package memclient
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (m *MemClient) GetObject(bucketName, objectName, version string) (object *Object, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.data.Objects[bucketName][objectName]
+	if len(versions) == 0 {
+		return nil, ErrNoSuchKey
+	}
+	var o *Object
+	if version == "" {
+		o = versions[sortedVersions(versions)[0]]
+	} else {
+		v, err := strconv.ParseUint(version, 10, 64)
+		if err != nil {
+			return nil, ErrNoSuchKey
+		}
+		o = versions[v]
+	}
+	if o == nil {
+		return nil, ErrNoSuchKey
+	}
+	return copyObject(o), nil
+}
+
+func (m *MemClient) GetAllObject(bucketName, objectName, version string) (object []*Object, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.data.Objects[bucketName][objectName]
+	for _, v := range sortedVersions(versions) {
+		object = append(object, copyObject(versions[v]))
+	}
+	return
+}
+
+func (m *MemClient) UpdateObjectAttrs(object *Object) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.data.Objects[object.BucketName][object.Name]
+	for version, o := range versions {
+		updated := *o
+		updated.CustomAttributes = nil
+		clone(object.CustomAttributes, &updated.CustomAttributes)
+		versions[version] = &updated
+	}
+	return m.save(nil)
+}
+
+func (m *MemClient) UpdateObjectAcl(object *Object) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.updateObject(object, func(o *Object) {
+		o.ACL = datatype.Acl{}
+		clone(object.ACL, &o.ACL)
+	})
+	return m.save(nil)
+}
+
+func (m *MemClient) UpdateObjectTagging(object *Object) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.updateObject(object, func(o *Object) {
+		o.Tagging = nil
+		clone(object.Tagging, &o.Tagging)
+	})
+	return m.save(nil)
+}
+
+func (m *MemClient) UpdateObjectReplicationStatus(object *Object) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.updateObject(object, func(o *Object) {
+		o.ReplicationStatus = object.ReplicationStatus
+	})
+	return m.save(nil)
+}
+
+func (m *MemClient) UpdateObjectLock(object *Object) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.updateObject(object, func(o *Object) {
+		o.ObjectLockMode = object.ObjectLockMode
+		o.RetainUntilDate = object.RetainUntilDate
+		o.LegalHold = object.LegalHold
+	})
+	return m.save(nil)
+}
+
+func (m *MemClient) RenameObject(object *Object, sourceObject string, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	version := versionOf(object)
+	m.keepObject(tx, object.BucketName, sourceObject, version)
+	m.keepObject(tx, object.BucketName, object.Name, version)
+	versions := m.data.Objects[object.BucketName][sourceObject]
+	o, ok := versions[version]
+	if !ok {
+		return nil
+	}
+	delete(versions, version)
+	if len(versions) == 0 {
+		delete(m.data.Objects[object.BucketName], sourceObject)
+	}
+	renamed := *o
+	renamed.Name = object.Name
+	m.objectVersions(object.BucketName, object.Name)[version] = &renamed
+	return m.save(tx)
+}
+
+// RenameObjectPart does nothing since parts are kept in their object and
+// renamed by RenameObject
+func (m *MemClient) RenameObjectPart(object *Object, sourceObject string, tx DB) (err error) {
+	return nil
+}
+
+func (m *MemClient) ReplaceObjectMetas(object *Object, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.data.Objects[object.BucketName][object.Name]
+	for version, o := range versions {
+		m.keepObject(tx, object.BucketName, object.Name, version)
+		updated := *o
+		updated.ContentType = object.ContentType
+		updated.CustomAttributes = nil
+		clone(object.CustomAttributes, &updated.CustomAttributes)
+		updated.StorageClass = object.StorageClass
+		updated.Tagging = nil
+		clone(object.Tagging, &updated.Tagging)
+		versions[version] = &updated
+	}
+	return m.save(tx)
+}
+
+// UpdateAppendObject moves appendable object to the version of its new
+// modification time, if its data is still where `object` has been appended to
+func (m *MemClient) UpdateAppendObject(object *Object, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.data.Objects[object.BucketName][object.Name]
+	var appended bool
+	for version, o := range versions {
+		if o.Location == object.Location && o.Pool == object.Pool && o.ObjectId == object.ObjectId {
+			m.keepObject(tx, object.BucketName, object.Name, version)
+			m.keepObject(tx, object.BucketName, object.Name, versionOf(object))
+			updated := *o
+			updated.LastModifiedTime = object.LastModifiedTime
+			updated.Size = object.Size
+			delete(versions, version)
+			versions[versionOf(&updated)] = &updated
+			appended = true
+			break
+		}
+	}
+	if !appended {
+		// same as tidb
+		return ErrPositionNotEqualToLength
+	}
+	return m.save(tx)
+}
+
+func (m *MemClient) PutObject(object *Object, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObject(tx, object.BucketName, object.Name, versionOf(object))
+	o := copyObject(object)
+	o.VersionId = ""
+	m.objectVersions(object.BucketName, object.Name)[versionOf(object)] = o
+	return m.save(tx)
+}
+
+func (m *MemClient) UpdateObject(object *Object, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObject(tx, object.BucketName, object.Name, versionOf(object))
+	updated := m.updateObject(object, func(o *Object) {
+		o.Location = object.Location
+		o.Pool = object.Pool
+		o.Size = object.Size
+		o.ObjectId = object.ObjectId
+		o.Etag = object.Etag
+		o.InitializationVector = append([]byte(nil), object.InitializationVector...)
+		o.StorageClass = object.StorageClass
+		o.Parts = nil
+		clone(object.Parts, &o.Parts)
+	})
+	if updated == nil {
+		return nil
+	}
+	return m.save(tx)
+}
+
+func (m *MemClient) UpdateObjectLocation(object, sourceObject *Object, tx DB) (updated bool, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObject(tx, object.BucketName, object.Name, versionOf(object))
+	o := m.findObject(object)
+	if o == nil || o.Location != sourceObject.Location || o.Pool != sourceObject.Pool ||
+		o.ObjectId != sourceObject.ObjectId || o.Size != sourceObject.Size ||
+		o.StorageClass != sourceObject.StorageClass || len(o.Parts) != len(object.Parts) {
+		return false, nil
+	}
+	for number, p := range o.Parts {
+		sourcePart, ok := sourceObject.Parts[number]
+		if !ok || object.Parts[number] == nil || p.ObjectId != sourcePart.ObjectId {
+			return false, nil
+		}
+	}
+	m.updateObject(object, func(o *Object) {
+		o.Location = object.Location
+		o.Pool = object.Pool
+		o.ObjectId = object.ObjectId
+		o.StorageClass = object.StorageClass
+		parts := make(map[int]*Part, len(o.Parts))
+		for number, p := range o.Parts {
+			part := *p
+			part.ObjectId = object.Parts[number].ObjectId
+			parts[number] = &part
+		}
+		o.Parts = parts
+	})
+	return true, m.save(tx)
+}
+
+func (m *MemClient) DeleteObject(object *Object, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObject(tx, object.BucketName, object.Name, versionOf(object))
+	versions := m.data.Objects[object.BucketName][object.Name]
+	delete(versions, versionOf(object))
+	if versions != nil && len(versions) == 0 {
+		delete(m.data.Objects[object.BucketName], object.Name)
+	}
+	return m.save(tx)
+}
+
+// util function
+func versionOf(object *Object) uint64 {
+	return math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+}
+
+func sortedVersions(versions map[uint64]*Object) []uint64 {
+	sorted := make([]uint64, 0, len(versions))
+	for v := range versions {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// must be called with lock held
+func (m *MemClient) findObject(object *Object) *Object {
+	return m.data.Objects[object.BucketName][object.Name][versionOf(object)]
+}
+
+// updateObject replaces version of `object` with a copy changed by `update`,
+// and returns the copy, or nil if there's no such version.
+// Must be called with lock held
+func (m *MemClient) updateObject(object *Object, update func(o *Object)) *Object {
+	versions := m.data.Objects[object.BucketName][object.Name]
+	o, ok := versions[versionOf(object)]
+	if !ok {
+		return nil
+	}
+	updated := *o
+	update(&updated)
+	versions[versionOf(object)] = &updated
+	return &updated
+}
+
+// must be called with lock held
+func (m *MemClient) objectVersions(bucketName, objectName string) map[uint64]*Object {
+	objects, ok := m.data.Objects[bucketName]
+	if !ok {
+		objects = make(map[string]map[uint64]*Object)
+		m.data.Objects[bucketName] = objects
+	}
+	versions, ok := objects[objectName]
+	if !ok {
+		versions = make(map[uint64]*Object)
+		objects[objectName] = versions
+	}
+	return versions
+}
+
+func copyObject(from *Object) *Object {
+	object := new(Object)
+	clone(from, object)
+	if object.Parts == nil {
+		object.Parts = make(map[int]*Part)
+	}
+	//build simple index for multipart
+	object.PartsIndex = nil
+	if len(object.Parts) != 0 {
+		var sortedPartNum = make([]int64, len(object.Parts))
+		for k, v := range object.Parts {
+			sortedPartNum[k-1] = v.Offset
+		}
+		object.PartsIndex = &SimpleIndex{Index: sortedPartNum}
+	}
+	return object
+}
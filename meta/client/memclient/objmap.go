@@ -0,0 +1,51 @@
+package memclient
+
+import (
+	"database/sql"
+	"strconv"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// objmap
+func (m *MemClient) GetObjectMap(bucketName, objectName string) (objMap *ObjMap, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stored, ok := m.data.ObjMaps[bucketName][objectName]
+	if !ok {
+		// callers check sql.ErrNoRows, same as tidb
+		return nil, sql.ErrNoRows
+	}
+	objMap = &ObjMap{
+		BucketName: stored.BucketName,
+		Name:       stored.Name,
+		NullVerNum: stored.NullVerNum,
+	}
+	objMap.NullVerId = strconv.FormatUint(objMap.NullVerNum, 10)
+	return
+}
+
+func (m *MemClient) PutObjectMap(objMap *ObjMap, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObjectMap(tx, objMap.BucketName, objMap.Name)
+	objMaps, ok := m.data.ObjMaps[objMap.BucketName]
+	if !ok {
+		objMaps = make(map[string]*ObjMap)
+		m.data.ObjMaps[objMap.BucketName] = objMaps
+	}
+	objMaps[objMap.Name] = &ObjMap{
+		BucketName: objMap.BucketName,
+		Name:       objMap.Name,
+		NullVerNum: objMap.NullVerNum,
+	}
+	return m.save(tx)
+}
+
+func (m *MemClient) DeleteObjectMap(objMap *ObjMap, tx DB) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keepObjectMap(tx, objMap.BucketName, objMap.Name)
+	delete(m.data.ObjMaps[objMap.BucketName], objMap.Name)
+	return m.save(tx)
+}
@@ -0,0 +1,69 @@
+package memclient
+
+import (
+	"sort"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (m *MemClient) PutObjectToReplication(replication *Replication) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	rowkey := replication.GetRowkey()
+	// same as "insert ignore"
+	if _, ok := m.data.Replications[rowkey]; ok {
+		return nil
+	}
+	stored := new(Replication)
+	clone(replication, stored)
+	m.data.Replications[rowkey] = stored
+	return m.save(nil)
+}
+
+// ScanReplication lists entries of replication queue with given status,
+// starting from `startRowKey`(inclusive) in the order of rowkey
+func (m *MemClient) ScanReplication(limit int, startRowKey string, status string) (
+	replications []Replication, err error) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var rowkeys []string
+	for rowkey, r := range m.data.Replications {
+		if r.Status != status {
+			continue
+		}
+		if startRowKey == "" || !rowkeyLess(rowkey, startRowKey) {
+			rowkeys = append(rowkeys, rowkey)
+		}
+	}
+	sort.Slice(rowkeys, func(i, j int) bool { return rowkeyLess(rowkeys[i], rowkeys[j]) })
+	for _, rowkey := range rowkeys {
+		if len(replications) >= limit {
+			break
+		}
+		var r Replication
+		clone(m.data.Replications[rowkey], &r)
+		replications = append(replications, r)
+	}
+	return
+}
+
+func (m *MemClient) UpdateReplication(replication *Replication) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if r, ok := m.data.Replications[replication.GetRowkey()]; ok {
+		updated := *r
+		updated.Status = replication.Status
+		updated.MTime = replication.MTime
+		updated.TriedTimes = replication.TriedTimes
+		m.data.Replications[replication.GetRowkey()] = &updated
+	}
+	return m.save(nil)
+}
+
+func (m *MemClient) RemoveReplication(replication *Replication) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.data.Replications, replication.GetRowkey())
+	return m.save(nil)
+}
@@ -0,0 +1,30 @@
+package memclient
+
+func (m *MemClient) GetUserBuckets(userId string) (buckets []string, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return sortedKeys(m.data.Users[userId]), nil
+}
+
+func (m *MemClient) AddBucketForUser(bucketName, userId string) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	buckets, ok := m.data.Users[userId]
+	if !ok {
+		buckets = make(map[string]struct{})
+		m.data.Users[userId] = buckets
+	}
+	buckets[bucketName] = struct{}{}
+	return m.save(nil)
+}
+
+func (m *MemClient) RemoveBucketForUser(bucketName string, userId string) (err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	buckets := m.data.Users[userId]
+	delete(buckets, bucketName)
+	if buckets != nil && len(buckets) == 0 {
+		delete(m.data.Users, userId)
+	}
+	return m.save(nil)
+}
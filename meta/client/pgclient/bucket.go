@@ -0,0 +1,255 @@
+package pgclient
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+const bucketColumns = "bucketname,COALESCE(acl,''),COALESCE(cors,''),COALESCE(logging,''),COALESCE(lc,''),uid," +
+	"COALESCE(policy,''),COALESCE(website,''),COALESCE(encryption,''),COALESCE(tagging,''),COALESCE(notification,'')," +
+	"COALESCE(replication,''),COALESCE(objectlock,''),COALESCE(placement,''),COALESCE(publicaccessblock,''),createtime,usages,versioning"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBucket(row scanner) (bucket *Bucket, err error) {
+	var acl, cors, logging, lc, policy, website, encryption, tagging, notification, replication, objectLock, placement, publicAccessBlock string
+	var createTime time.Time
+	bucket = new(Bucket)
+	err = row.Scan(
+		&bucket.Name,
+		&acl,
+		&cors,
+		&logging,
+		&lc,
+		&bucket.OwnerId,
+		&policy,
+		&website,
+		&encryption,
+		&tagging,
+		&notification,
+		&replication,
+		&objectLock,
+		&placement,
+		&publicAccessBlock,
+		&createTime,
+		&bucket.Usage,
+		&bucket.Versioning,
+	)
+	if err != nil {
+		return
+	}
+	// same as tidb which parses createtime in UTC
+	bucket.CreateTime = inLocation(createTime, time.UTC)
+	documents := []struct {
+		value  string
+		target interface{}
+	}{
+		{acl, &bucket.ACL},
+		{cors, &bucket.CORS},
+		{logging, &bucket.BucketLogging},
+		{lc, &bucket.Lifecycle},
+		{policy, &bucket.Policy},
+		{website, &bucket.Website},
+		{encryption, &bucket.Encryption},
+		{tagging, &bucket.Tagging},
+		{notification, &bucket.Notification},
+		{replication, &bucket.Replication},
+		{objectLock, &bucket.ObjectLock},
+		{placement, &bucket.Placement},
+		{publicAccessBlock, &bucket.PublicAccessBlock},
+	}
+	for _, d := range documents {
+		if d.value == "" {
+			continue
+		}
+		err = json.Unmarshal([]byte(d.value), d.target)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (t *PgClient) GetBucket(bucketName string) (bucket *Bucket, err error) {
+	sqltext := "select " + bucketColumns + " from buckets where bucketname=?;"
+	bucket, err = scanBucket(queryRow(t.Client, sqltext, bucketName))
+	if err == sql.ErrNoRows {
+		err = ErrNoSuchBucket
+	}
+	return
+}
+
+func (t *PgClient) GetBuckets() (buckets []Bucket, err error) {
+	sqltext := "select " + bucketColumns + " from buckets order by bucketname;"
+	rows, err := query(t.Client, sqltext)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var bucket *Bucket
+		bucket, err = scanBucket(rows)
+		if err != nil {
+			return
+		}
+		buckets = append(buckets, *bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// Actually this method is used to update bucket
+func (t *PgClient) PutBucket(bucket Bucket) error {
+	sql, args := bucket.GetUpdateSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) CheckAndPutBucket(bucket Bucket) (bool, error) {
+	sql, args := bucket.GetCreateSql(PostgresDialect)
+	sql = PostgresDialect.InsertIgnore(sql)
+	result, err := t.Client.Exec(sql, args...)
+	if err != nil {
+		return false, err
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return created == 1, nil
+}
+
+func (t *PgClient) ListObjects(bucketName, marker, verIdMarker, prefix, delimiter string, versioned bool, maxKeys int) (retObjects []*Object, prefixes []string, truncated bool, nextMarker, nextVerIdMarker string, err error) {
+	if versioned {
+		return
+	}
+	var count int
+	var exit bool
+	objectMap := make(map[string]struct{})
+	objectNum := make(map[string]int)
+	commonPrefixes := make(map[string]struct{})
+	omarker := marker
+	for {
+		var loopcount int
+		var rows *sql.Rows
+		if marker == "" {
+			sqltext := "select bucketname,name,version,nullversion,deletemarker from objects where bucketname=? order by bucketname,name,version limit ?;"
+			rows, err = query(t.Client, sqltext, bucketName, maxKeys)
+		} else {
+			sqltext := "select bucketname,name,version,nullversion,deletemarker from objects where bucketname=? and name>=? order by bucketname,name,version limit ? offset ?;"
+			rows, err = query(t.Client, sqltext, bucketName, marker, maxKeys, objectNum[marker])
+		}
+		if err != nil {
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			loopcount += 1
+			var bucketname, name string
+			var version uint64
+			var nullversion, deletemarker bool
+			err = rows.Scan(
+				&bucketname,
+				&name,
+				&version,
+				&nullversion,
+				&deletemarker,
+			)
+			if err != nil {
+				return
+			}
+			//prepare next marker
+			objectNum[name] += 1
+			marker = name
+			//filte by prefix
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			//filte by objectname
+			if _, ok := objectMap[name]; ok {
+				continue
+			}
+			objectMap[name] = struct{}{}
+			//filte by deletemarker
+			if deletemarker {
+				continue
+			}
+			if name == omarker {
+				continue
+			}
+			//filte by delemiter
+			if len(delimiter) != 0 {
+				subStr := strings.TrimPrefix(name, prefix)
+				n := strings.Index(subStr, delimiter)
+				if n != -1 {
+					prefixKey := prefix + subStr[:n+len(delimiter)]
+					if prefixKey == omarker {
+						continue
+					}
+					if _, ok := commonPrefixes[prefixKey]; !ok {
+						if count == maxKeys {
+							truncated = true
+							exit = true
+							break
+						}
+						commonPrefixes[prefixKey] = struct{}{}
+						nextMarker = prefixKey
+						count += 1
+					}
+					continue
+				}
+			}
+			if count == maxKeys {
+				truncated = true
+				exit = true
+				break
+			}
+			var o *Object
+			o, err = t.GetObject(bucketname, name, strconv.FormatUint(version, 10))
+			if err != nil {
+				return
+			}
+			count += 1
+			nextMarker = name
+			retObjects = append(retObjects, o)
+		}
+		err = rows.Err()
+		if err != nil {
+			return
+		}
+		if loopcount == 0 {
+			exit = true
+		}
+		if exit {
+			break
+		}
+	}
+	prefixes = helper.Keys(commonPrefixes)
+	return
+}
+
+func (t *PgClient) DeleteBucket(bucket Bucket) error {
+	sqltext := "delete from buckets where bucketname=?;"
+	_, err := exec(t.Client, sqltext, bucket.Name)
+	return err
+}
+
+func (t *PgClient) UpdateUsage(bucketName string, size int64, tx DB) (err error) {
+	if !helper.CONFIG.PiggybackUpdateUsage {
+		return nil
+	}
+	if tx == nil {
+		tx = t.Client
+	}
+	sqltext := "update buckets set usages=usages+? where bucketname=?;"
+	_, err = exec(tx, sqltext, size, bucketName)
+	return
+}
@@ -0,0 +1,55 @@
+package pgclient
+
+import (
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/journeymidnight/yig/helper"
+	. "github.com/journeymidnight/yig/meta/types"
+	_ "github.com/lib/pq"
+)
+
+// PgClient stores metadata in PostgreSQL, see integrate/yig_pg.sql for
+// the schema
+type PgClient struct {
+	Client *sql.DB
+}
+
+func NewPgClient() *PgClient {
+	cli := &PgClient{}
+	conn, err := sql.Open("postgres", helper.CONFIG.PgInfo)
+	if err != nil {
+		os.Exit(1)
+	}
+	conn.SetMaxIdleConns(helper.CONFIG.DbMaxIdleConns)
+	conn.SetMaxOpenConns(helper.CONFIG.DbMaxOpenConns)
+	conn.SetConnMaxLifetime(time.Duration(helper.CONFIG.DbConnMaxLifeSeconds) * time.Second)
+	cli.Client = conn
+	return cli
+}
+
+// SQL in this package is written with `?` placeholders like tidbclient,
+// and bound by PostgresDialect before sent to database
+
+func exec(tx DB, sqltext string, args ...interface{}) (sql.Result, error) {
+	sqltext, args = PostgresDialect.Bind(sqltext, args)
+	return tx.Exec(sqltext, args...)
+}
+
+func query(tx DB, sqltext string, args ...interface{}) (*sql.Rows, error) {
+	sqltext, args = PostgresDialect.Bind(sqltext, args)
+	return tx.Query(sqltext, args...)
+}
+
+func queryRow(tx DB, sqltext string, args ...interface{}) *sql.Row {
+	sqltext, args = PostgresDialect.Bind(sqltext, args)
+	return tx.QueryRow(sqltext, args...)
+}
+
+// timestamps are stored without time zone like datetime of tidb,
+// inLocation reads the wall clock of `t` in `loc`
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond(), loc)
+}
@@ -0,0 +1,48 @@
+package pgclient
+
+import (
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (t *PgClient) GetClusters() (cluster []Cluster, err error) {
+	sqltext := "select fsid,pool,weight,status from cluster"
+	rows, err := query(t.Client, sqltext)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		c := Cluster{}
+		err = rows.Scan(&c.Fsid, &c.Pool, &c.Weight, &c.Status)
+		if err != nil {
+			return nil, err
+		}
+		cluster = append(cluster, c)
+	}
+	return cluster, rows.Err()
+}
+
+// SetClusterStatus changes status of all pools in cluster `fsid`
+func (t *PgClient) SetClusterStatus(fsid string, status ClusterStatus) error {
+	sqltext := "update cluster set status=? where fsid=?"
+	_, err := exec(t.Client, sqltext, status, fsid)
+	return err
+}
+
+// PutCluster adds pool `cluster.Pool` of cluster `cluster.Fsid`, or changes
+// its weight if it exists, status of existing pools is kept
+func (t *PgClient) PutCluster(cluster Cluster) error {
+	sqltext := "update cluster set weight=? where fsid=? and pool=?"
+	result, err := exec(t.Client, sqltext, cluster.Weight, cluster.Fsid, cluster.Pool)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected != 0 {
+		return err
+	}
+	// the pool doesn't exist, or its weight is not changed
+	sqltext = PostgresDialect.InsertIgnore("insert into cluster(fsid,pool,weight,status) values(?,?,?,?)")
+	_, err = exec(t.Client, sqltext, cluster.Fsid, cluster.Pool, cluster.Weight, cluster.Status)
+	return err
+}
@@ -0,0 +1,240 @@
+package pgclient
+
+import (
+	"database/sql"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (t *PgClient) CreateFreezer(freezer *Freezer) (err error) {
+	sql, args := freezer.GetCreateSql(PostgresDialect)
+	_, err = t.Client.Exec(sql, args...)
+	return
+}
+
+func (t *PgClient) GetFreezer(bucketName, objectName, version string) (freezer *Freezer, err error) {
+	var lastmodifiedtime time.Time
+	sqltext := "select bucketname,objectname,COALESCE(version::text,''),status,lifetime,lastmodifiedtime,COALESCE(location,''),COALESCE(pool,'')," +
+		"COALESCE(ownerid,''),COALESCE(size,0),COALESCE(objectid,''),COALESCE(etag,'') from restoreobjects where bucketname=? and objectname=?;"
+	freezer = &Freezer{}
+	err = queryRow(t.Client, sqltext, bucketName, objectName).Scan(
+		&freezer.BucketName,
+		&freezer.Name,
+		&freezer.VersionId,
+		&freezer.Status,
+		&freezer.LifeTime,
+		&lastmodifiedtime,
+		&freezer.Location,
+		&freezer.Pool,
+		&freezer.OwnerId,
+		&freezer.Size,
+		&freezer.ObjectId,
+		&freezer.Etag,
+	)
+	if err == sql.ErrNoRows {
+		err = ErrNoSuchKey
+		return
+	} else if err != nil {
+		return
+	}
+	freezer.LastModifiedTime = inLocation(lastmodifiedtime, time.Local)
+	freezer.Parts, err = getFreezerParts(freezer.BucketName, freezer.Name, t.Client)
+	if err != nil {
+		return
+	}
+	//build simple index for multipart
+	if len(freezer.Parts) != 0 {
+		var sortedPartNum = make([]int64, len(freezer.Parts))
+		for k, v := range freezer.Parts {
+			sortedPartNum[k-1] = v.Offset
+		}
+		freezer.PartsIndex = &SimpleIndex{Index: sortedPartNum}
+	}
+	return
+}
+
+func (t *PgClient) GetFreezerStatus(bucketName, objectName, version string) (freezer *Freezer, err error) {
+	var lastmodifiedtime time.Time
+	sqltext := "select bucketname,objectname,COALESCE(version::text,''),status,lifetime,lastmodifiedtime from restoreobjects where bucketname=? and objectname=?;"
+	freezer = &Freezer{}
+	err = queryRow(t.Client, sqltext, bucketName, objectName).Scan(
+		&freezer.BucketName,
+		&freezer.Name,
+		&freezer.VersionId,
+		&freezer.Status,
+		&freezer.LifeTime,
+		&lastmodifiedtime,
+	)
+	if err == sql.ErrNoRows {
+		err = ErrNoSuchKey
+		return
+	} else if err != nil {
+		return
+	}
+	freezer.LastModifiedTime = inLocation(lastmodifiedtime, time.Local)
+	return
+}
+
+func (t *PgClient) UploadFreezerDate(bucketName, objectName string, lifetime int) (err error) {
+	sqltext := "update restoreobjects set lifetime=? where bucketname=? and objectname=?;"
+	_, err = exec(t.Client, sqltext, lifetime, bucketName, objectName)
+	return
+}
+
+func (t *PgClient) DeleteFreezer(bucketName, objectName string, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	sqltext := "delete from restoreobjects where bucketname=? and objectname=?;"
+	_, err = exec(tx, sqltext, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	sqltext = "delete from restoreobjectpart where bucketname=? and objectname=?;"
+	_, err = exec(tx, sqltext, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListFreezers returns at most `limit` freezers in `status`, in the order of
+// tier and request time
+func (t *PgClient) ListFreezers(status Status, limit int) (freezers []Freezer, err error) {
+	sqltext := "select bucketname,objectname,status,lifetime,lastmodifiedtime,COALESCE(tier,1) from restoreobjects " +
+		"where status=? order by tier,lastmodifiedtime limit ?;"
+	rows, err := query(t.Client, sqltext, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var freezer Freezer
+		var lastmodifiedtime time.Time
+		err = rows.Scan(
+			&freezer.BucketName,
+			&freezer.Name,
+			&freezer.Status,
+			&freezer.LifeTime,
+			&lastmodifiedtime,
+			&freezer.Tier,
+		)
+		if err != nil {
+			return nil, err
+		}
+		freezer.LastModifiedTime = inLocation(lastmodifiedtime, time.Local)
+		freezers = append(freezers, freezer)
+	}
+	return freezers, rows.Err()
+}
+
+// ListExpiredFreezers returns at most `limit` restored freezers whose
+// lifetime has passed at `now`
+func (t *PgClient) ListExpiredFreezers(now time.Time, limit int) (freezers []Freezer, err error) {
+	sqltext := "select bucketname,objectname from restoreobjects " +
+		"where status=? and lastmodifiedtime+lifetime*interval '1 day'<? limit ?;"
+	rows, err := query(t.Client, sqltext, ObjectHasRestored, now.Format(TIME_LAYOUT_TIDB), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		freezer := Freezer{Status: ObjectHasRestored}
+		err = rows.Scan(
+			&freezer.BucketName,
+			&freezer.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		freezers = append(freezers, freezer)
+	}
+	return freezers, rows.Err()
+}
+
+// UpdateFreezerStatus changes status of freezer only if it's still in
+// `freezer.Status`, returns false if the freezer is changed by others
+func (t *PgClient) UpdateFreezerStatus(freezer *Freezer, status Status) (updated bool, err error) {
+	sqltext := "update restoreobjects set status=? where bucketname=? and objectname=? and status=?;"
+	result, err := exec(t.Client, sqltext, status, freezer.BucketName, freezer.Name, freezer.Status)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UpdateFreezer saves location of restored data into freezer and
+// changes its status
+func (t *PgClient) UpdateFreezer(freezer *Freezer, status Status, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	sqltext, args := freezer.GetUpdateSql(status, PostgresDialect)
+	result, err := tx.Exec(sqltext, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNoSuchKey
+	}
+	for _, p := range freezer.Parts {
+		psql, args := p.GetCreateFreezerSql(freezer.BucketName, freezer.Name, PostgresDialect)
+		_, err = tx.Exec(psql, args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// util function
+func getFreezerParts(bucketName, objectName string, cli *sql.DB) (parts map[int]*Part, err error) {
+	parts = make(map[int]*Part)
+	sqltext := "select partnumber,size,objectid,\"offset\",etag,lastmodified,initializationvector from restoreobjectpart where bucketname=? and objectname=?;"
+	rows, err := query(cli, sqltext, bucketName, objectName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p *Part
+		p, err = scanPart(rows, TIME_LAYOUT_TIDB)
+		if err != nil {
+			return
+		}
+		parts[p.PartNumber] = p
+	}
+	return parts, rows.Err()
+}
@@ -0,0 +1,195 @@
+package pgclient
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// gc
+func (t *PgClient) PutObjectToGarbageCollection(object *Object, tx DB) (err error) {
+	gc := GarbageCollection{
+		BucketName: object.BucketName,
+		ObjectName: object.Name,
+		Location:   object.Location,
+		Pool:       object.Pool,
+		ObjectId:   object.ObjectId,
+		Parts:      object.Parts,
+	}
+	return t.putGarbageCollection(gc, object.LastModifiedTime, tx)
+}
+
+func (t *PgClient) PutFreezerToGarbageCollection(object *Freezer, tx DB) (err error) {
+	gc := GarbageCollection{
+		BucketName: object.BucketName,
+		ObjectName: object.Name,
+		Location:   object.Location,
+		Pool:       object.Pool,
+		ObjectId:   object.ObjectId,
+		Parts:      object.Parts,
+	}
+	return t.putGarbageCollection(gc, object.LastModifiedTime, tx)
+}
+
+func (t *PgClient) putGarbageCollection(gc GarbageCollection, lastModifiedTime time.Time, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	mtime := time.Now().UTC().Format(TIME_LAYOUT_TIDB)
+	version := math.MaxUint64 - uint64(lastModifiedTime.UnixNano())
+	sqltext := PostgresDialect.InsertIgnore(
+		"insert into gc(bucketname,objectname,version,location,pool,objectid,status,mtime,part,triedtimes) values(?,?,?,?,?,?,?,?,?,?);")
+	_, err = exec(tx, sqltext, gc.BucketName, gc.ObjectName, version, gc.Location, gc.Pool, gc.ObjectId,
+		"Pending", mtime, len(gc.Parts) > 0, 0)
+	if err != nil {
+		return err
+	}
+	for _, p := range gc.Parts {
+		psql, args := p.GetCreateGcSql(gc.BucketName, gc.ObjectName, version, PostgresDialect)
+		_, err = tx.Exec(psql, args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *PgClient) ScanGarbageCollection(limit int, startRowKey string) (gcs []GarbageCollection, err error) {
+	var rows *sql.Rows
+	if startRowKey == "" {
+		sqltext := "select bucketname,objectname,version from gc order by bucketname,objectname,version limit ?;"
+		rows, err = query(t.Client, sqltext, limit)
+	} else {
+		s := strings.Split(startRowKey, ObjectNameSeparator)
+		bucketname := s[0]
+		objectname := s[1]
+		version := s[2]
+		sqltext := "select bucketname,objectname,version from gc where bucketname>? or (bucketname=? and objectname>?) or " +
+			"(bucketname=? and objectname=? and version>=?) order by bucketname,objectname,version limit ?;"
+		rows, err = query(t.Client, sqltext, bucketname, bucketname, objectname, bucketname, objectname, version, limit)
+	}
+	if err != nil {
+		return
+	}
+	// read all keys before querying details, since rows holds a connection
+	var keys [][3]string
+	for rows.Next() {
+		var key [3]string
+		err = rows.Scan(
+			&key[0],
+			&key[1],
+			&key[2],
+		)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		var gc GarbageCollection
+		gc, err = t.GetGarbageCollection(key[0], key[1], key[2])
+		if err != nil {
+			return
+		}
+		gcs = append(gcs, gc)
+	}
+	return
+}
+
+func (t *PgClient) RemoveGarbageCollection(garbage GarbageCollection) (err error) {
+	var tx *sql.Tx
+	tx, err = t.Client.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	version := strings.Split(garbage.Rowkey, ObjectNameSeparator)[2]
+	sqltext := "delete from gc where bucketname=? and objectname=? and version=?;"
+	_, err = exec(tx, sqltext, garbage.BucketName, garbage.ObjectName, version)
+	if err != nil {
+		return err
+	}
+	if len(garbage.Parts) > 0 {
+		sqltext := "delete from gcpart where bucketname=? and objectname=? and version=?;"
+		_, err = exec(tx, sqltext, garbage.BucketName, garbage.ObjectName, version)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// util func
+func (t *PgClient) GetGarbageCollection(bucketName, objectName, version string) (gc GarbageCollection, err error) {
+	sqltext := "select bucketname,objectname,version,location,pool,objectid,status,mtime,part,triedtimes from gc where bucketname=? and objectname=? and version=?;"
+	var hasPart bool
+	var v string
+	err = queryRow(t.Client, sqltext, bucketName, objectName, version).Scan(
+		&gc.BucketName,
+		&gc.ObjectName,
+		&v,
+		&gc.Location,
+		&gc.Pool,
+		&gc.ObjectId,
+		&gc.Status,
+		&gc.MTime,
+		&hasPart,
+		&gc.TriedTimes,
+	)
+	if err != nil {
+		return
+	}
+	gc.MTime = inLocation(gc.MTime, time.UTC)
+	gc.Rowkey = gc.BucketName + ObjectNameSeparator + gc.ObjectName + ObjectNameSeparator + v
+	if hasPart {
+		gc.Parts, err = getGcParts(bucketName, objectName, version, t.Client)
+	}
+	return
+}
+
+func getGcParts(bucketname, objectname, version string, cli *sql.DB) (parts map[int]*Part, err error) {
+	parts = make(map[int]*Part)
+	sqltext := "select partnumber,size,objectid,\"offset\",etag,lastmodified,initializationvector from gcpart where bucketname=? and objectname=? and version=?;"
+	rows, err := query(cli, sqltext, bucketname, objectname, version)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p *Part
+		p, err = scanPart(rows, TIME_LAYOUT_TIDB)
+		if err != nil {
+			return
+		}
+		parts[p.PartNumber] = p
+	}
+	return parts, rows.Err()
+}
@@ -0,0 +1,73 @@
+package pgclient
+
+import (
+	"github.com/journeymidnight/yig/helper"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (t *PgClient) PutBucketToLifeCycle(lifeCycle LifeCycle) error {
+	sqltext := "insert into lifecycle(bucketname,status) values (?,?);"
+	_, err := exec(t.Client, sqltext, lifeCycle.BucketName, lifeCycle.Status)
+	if err != nil {
+		helper.Logger.Error("Failed to execute:", sqltext, "err:", err)
+		return nil
+	}
+	return nil
+}
+
+func (t *PgClient) RemoveBucketFromLifeCycle(bucket Bucket) error {
+	sqltext := "delete from lifecycle where bucketname=?;"
+	_, err := exec(t.Client, sqltext, bucket.Name)
+	if err != nil {
+		helper.Logger.Error("Failed to execute:", sqltext, "err:", err)
+		return nil
+	}
+	return nil
+}
+
+func (t *PgClient) ScanLifeCycle(limit int, marker string) (result ScanLifeCycleResult, err error) {
+	result.Truncated = false
+	sqltext := "select bucketname,status from lifecycle where bucketname > ? order by bucketname limit ?;"
+	rows, err := query(t.Client, sqltext, marker, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	result.Lcs = make([]LifeCycle, 0, limit)
+	var lc LifeCycle
+	for rows.Next() {
+		err = rows.Scan(
+			&lc.BucketName,
+			&lc.Status)
+		if err != nil {
+			helper.Logger.Error("Failed in scan LifeCycle:", err)
+			return
+		}
+		result.Lcs = append(result.Lcs, lc)
+	}
+	result.NextMarker = lc.BucketName
+	if len(result.Lcs) == limit {
+		result.Truncated = true
+	}
+	return result, rows.Err()
+}
+
+// ScanObjectNames lists distinct object names of bucket after marker,
+// lifecycle worker then checks all versions of each name
+func (t *PgClient) ScanObjectNames(bucketName, marker string, limit int) (names []string, err error) {
+	sqltext := "select distinct name from objects where bucketname=? and name>? order by name limit ?;"
+	rows, err := query(t.Client, sqltext, bucketName, marker, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,315 @@
+package pgclient
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam"
+	"github.com/journeymidnight/yig/iam/common"
+	. "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/meta/util"
+)
+
+func (t *PgClient) GetMultipart(bucketName, objectName, uploadId string) (multipart Multipart, err error) {
+	multipart.Parts = make(map[int]*Part)
+	timestampString, err := util.Decrypt(uploadId)
+	if err != nil {
+		return
+	}
+	uploadTime, err := strconv.ParseUint(timestampString, 10, 64)
+	if err != nil {
+		return
+	}
+	uploadTime = math.MaxUint64 - uploadTime
+	sqltext := "select bucketname,objectname,uploadtime,initiatorid,ownerid,contenttype,location,pool,acl,sserequest," +
+		"encryption,COALESCE(cipher,''),attrs,storageclass,COALESCE(tagging,'') from multiparts where bucketname=? and objectname=? and uploadtime=?;"
+	var initialTime uint64
+	var acl, sseRequest, attrs, tagging string
+	err = queryRow(t.Client, sqltext, bucketName, objectName, uploadTime).Scan(
+		&multipart.BucketName,
+		&multipart.ObjectName,
+		&initialTime,
+		&multipart.Metadata.InitiatorId,
+		&multipart.Metadata.OwnerId,
+		&multipart.Metadata.ContentType,
+		&multipart.Metadata.Location,
+		&multipart.Metadata.Pool,
+		&acl,
+		&sseRequest,
+		&multipart.Metadata.EncryptionKey,
+		&multipart.Metadata.CipherKey,
+		&attrs,
+		&multipart.Metadata.StorageClass,
+		&tagging,
+	)
+	if err == sql.ErrNoRows {
+		err = ErrNoSuchUpload
+		return
+	} else if err != nil {
+		return
+	}
+	rinitial := int64(math.MaxUint64 - initialTime)
+	s := rinitial / 1e9
+	ns := rinitial % 1e9
+	multipart.InitialTime = time.Unix(s, ns)
+	err = json.Unmarshal([]byte(acl), &multipart.Metadata.Acl)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(sseRequest), &multipart.Metadata.SseRequest)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(attrs), &multipart.Metadata.Attrs)
+	if err != nil {
+		return
+	}
+	if tagging != "" {
+		err = json.Unmarshal([]byte(tagging), &multipart.Metadata.Tagging)
+		if err != nil {
+			return
+		}
+	}
+
+	sqltext = "select partnumber,size,objectid,\"offset\",etag,lastmodified,initializationvector from multipartpart where bucketname=? and objectname=? and uploadtime=?;"
+	rows, err := query(t.Client, sqltext, bucketName, objectName, uploadTime)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p *Part
+		p, err = scanPart(rows, CREATE_TIME_LAYOUT)
+		if err != nil {
+			return
+		}
+		multipart.Parts[p.PartNumber] = p
+	}
+	err = rows.Err()
+	return
+}
+
+func (t *PgClient) CreateMultipart(multipart Multipart) (err error) {
+	m := multipart.Metadata
+	uploadtime := math.MaxUint64 - uint64(multipart.InitialTime.UnixNano())
+	acl, _ := json.Marshal(m.Acl)
+	sseRequest, _ := json.Marshal(m.SseRequest)
+	attrs, _ := json.Marshal(m.Attrs)
+	tagging, _ := json.Marshal(m.Tagging)
+	sqltext := "insert into multiparts(bucketname,objectname,uploadtime,initiatorid,ownerid,contenttype,location,pool,acl,sserequest,encryption,cipher,attrs,storageclass,tagging) " +
+		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	_, err = exec(t.Client, sqltext, multipart.BucketName, multipart.ObjectName, uploadtime, m.InitiatorId, m.OwnerId, m.ContentType, m.Location, m.Pool,
+		string(acl), string(sseRequest), m.EncryptionKey, m.CipherKey, string(attrs), m.StorageClass, string(tagging))
+	return
+}
+
+func (t *PgClient) PutObjectPart(multipart *Multipart, part *Part, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+
+	uploadtime := math.MaxUint64 - uint64(multipart.InitialTime.UnixNano())
+	lastt, err := time.Parse(CREATE_TIME_LAYOUT, part.LastModified)
+	if err != nil {
+		return
+	}
+	lastModified := lastt.Format(TIME_LAYOUT_TIDB)
+	sqltext := "insert into multipartpart(partnumber,size,objectid,\"offset\",etag,lastmodified,initializationvector,bucketname,objectname,uploadtime) " +
+		"values(?,?,?,?,?,?,?,?,?,?)"
+	_, err = exec(tx, sqltext, part.PartNumber, part.Size, part.ObjectId, part.Offset, part.Etag, lastModified, part.InitializationVector, multipart.BucketName, multipart.ObjectName, uploadtime)
+	return
+}
+
+func (t *PgClient) DeleteMultipart(multipart *Multipart, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	uploadtime := math.MaxUint64 - uint64(multipart.InitialTime.UnixNano())
+	sqltext := "delete from multiparts where bucketname=? and objectname=? and uploadtime=?;"
+	_, err = exec(tx, sqltext, multipart.BucketName, multipart.ObjectName, uploadtime)
+	if err != nil {
+		return
+	}
+	sqltext = "delete from multipartpart where bucketname=? and objectname=? and uploadtime=?;"
+	_, err = exec(tx, sqltext, multipart.BucketName, multipart.ObjectName, uploadtime)
+	return err
+}
+
+func (t *PgClient) ListMultipartUploads(bucketName, keyMarker, uploadIdMarker, prefix, delimiter, encodingType string, maxUploads int) (uploads []datatype.Upload, prefixs []string, isTruncated bool, nextKeyMarker, nextUploadIdMarker string, err error) {
+	var count int
+	var exit bool
+	commonPrefixes := make(map[string]struct{})
+	var uploadNum uint64
+	if uploadIdMarker != "" {
+		var timestampString string
+		timestampString, err = util.Decrypt(uploadIdMarker)
+		if err != nil {
+			return
+		}
+		uploadNum, err = strconv.ParseUint(timestampString, 10, 64)
+		if err != nil {
+			return
+		}
+		uploadNum = math.MaxUint64 - uploadNum
+	}
+	objnum := make(map[string]int)
+	currentMarker := keyMarker
+	first := true
+
+	for {
+		var loopnum int
+		var rows *sql.Rows
+		if currentMarker == "" {
+			sqltext := "select objectname,uploadtime,initiatorid,ownerid,storageclass from multiparts where bucketname=? order by bucketname,objectname,uploadtime limit ? offset ?;"
+			rows, err = query(t.Client, sqltext, bucketName, maxUploads, objnum[currentMarker])
+		} else {
+			sqltext := "select objectname,uploadtime,initiatorid,ownerid,storageclass from multiparts where bucketname=? and objectname>=? order by bucketname,objectname,uploadtime limit ? offset ?;"
+			rows, err = query(t.Client, sqltext, bucketName, currentMarker, maxUploads, objnum[currentMarker])
+		}
+		if err != nil {
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			loopnum += 1
+			var name, initiatorid, ownerid string
+			var uploadtime uint64
+			var storageClass StorageClass
+			err = rows.Scan(
+				&name,
+				&uploadtime,
+				&initiatorid,
+				&ownerid,
+				&storageClass,
+			)
+			if err != nil {
+				return
+			}
+			objnum[name] += 1
+			currentMarker = name
+			upload := datatype.Upload{StorageClass: storageClass.ToString()}
+			//filte by uploadtime and key
+			if first && uploadNum != 0 && name == keyMarker && uploadtime < uploadNum {
+				continue
+			}
+			//filte by prefix
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			//filte by delimiter
+			if len(delimiter) != 0 {
+				subStr := strings.TrimPrefix(name, prefix)
+				n := strings.Index(subStr, delimiter)
+				if n != -1 {
+					commonPrefixes[prefix+subStr[:n+len(delimiter)]] = struct{}{}
+					continue
+				}
+			}
+			if count >= maxUploads {
+				isTruncated = true
+				exit = true
+				nextKeyMarker = name
+				nextUploadIdMarker = GetMultipartUploadIdForTidb(uploadtime)
+				break
+			}
+			upload.UploadId = GetMultipartUploadIdForTidb(uploadtime)
+			upload.Key = name
+			if encodingType != "" {
+				upload.Key = url.QueryEscape(upload.Key)
+			}
+			var user common.Credential
+			user, err = iam.GetCredentialByUserId(ownerid)
+			if err != nil {
+				return
+			}
+			upload.Owner.ID = user.UserId
+			upload.Owner.DisplayName = user.DisplayName
+			user, err = iam.GetCredentialByUserId(initiatorid)
+			if err != nil {
+				return
+			}
+			upload.Initiator.ID = user.UserId
+			upload.Initiator.DisplayName = user.DisplayName
+			timestamp := int64(math.MaxUint64 - uploadtime)
+			s := timestamp / 1e9
+			ns := timestamp % 1e9
+			upload.Initiated = time.Unix(s, ns).UTC().Format(CREATE_TIME_LAYOUT)
+			uploads = append(uploads, upload)
+			count += 1
+		}
+		err = rows.Err()
+		if err != nil {
+			return
+		}
+		if loopnum == 0 {
+			exit = true
+		}
+		first = false
+		if exit {
+			break
+		}
+	}
+	prefixs = helper.Keys(commonPrefixes)
+	return
+}
+
+func (t *PgClient) RenameObjectPart(object *Object, sourceObject string, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sql, args := object.GetUpdateObjectPartNameSql(sourceObject, PostgresDialect)
+	_, err = tx.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) ListStaleMultipartUploads(initiatedBefore time.Time, limit int) (uploads []Multipart, err error) {
+	// uploadtime is stored as math.MaxUint64 - initiated time, older uploads
+	// have larger uploadtime
+	uploadTime := math.MaxUint64 - uint64(initiatedBefore.UnixNano())
+	sqltext := "select bucketname,objectname,uploadtime,initiatorid,ownerid,storageclass from multiparts " +
+		"where uploadtime>? order by uploadtime desc limit ?;"
+	rows, err := query(t.Client, sqltext, uploadTime, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var multipart Multipart
+		var initialTime uint64
+		err = rows.Scan(
+			&multipart.BucketName,
+			&multipart.ObjectName,
+			&initialTime,
+			&multipart.Metadata.InitiatorId,
+			&multipart.Metadata.OwnerId,
+			&multipart.Metadata.StorageClass,
+		)
+		if err != nil {
+			return
+		}
+		rinitial := int64(math.MaxUint64 - initialTime)
+		multipart.InitialTime = time.Unix(rinitial/1e9, rinitial%1e9)
+		multipart.UploadId = GetMultipartUploadIdForTidb(initialTime)
+		uploads = append(uploads, multipart)
+	}
+	return uploads, rows.Err()
+}
@@ -0,0 +1,398 @@
+package pgclient
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+	. "github.com/journeymidnight/yig/meta/types"
+	"github.com/lib/pq"
+)
+
+func (t *PgClient) GetObject(bucketName, objectName, version string) (object *Object, err error) {
+	var customattributes, acl, tagging string
+	var iversion uint64
+	var retainUntilDate pq.NullTime
+
+	var row *sql.Row
+	sqltext := "select version,location,pool,ownerid,size,objectid,etag,contenttype," +
+		"customattributes,acl,nullversion,deletemarker,ssetype,encryptionkey,initializationvector,type,storageclass,COALESCE(tagging,''),COALESCE(replicationstatus,'')," +
+		"COALESCE(objectlockmode,''),retainuntildate,COALESCE(legalhold,false) from objects where bucketname=? and name=? "
+	if version == "" {
+		sqltext += "order by bucketname,name,version limit 1;"
+		row = queryRow(t.Client, sqltext, bucketName, objectName)
+	} else {
+		sqltext += "and version=?;"
+		row = queryRow(t.Client, sqltext, bucketName, objectName, version)
+	}
+	object = &Object{}
+	err = row.Scan(
+		&iversion,
+		&object.Location,
+		&object.Pool,
+		&object.OwnerId,
+		&object.Size,
+		&object.ObjectId,
+		&object.Etag,
+		&object.ContentType,
+		&customattributes,
+		&acl,
+		&object.NullVersion,
+		&object.DeleteMarker,
+		&object.SseType,
+		&object.EncryptionKey,
+		&object.InitializationVector,
+		&object.Type,
+		&object.StorageClass,
+		&tagging,
+		&object.ReplicationStatus,
+		&object.ObjectLockMode,
+		&retainUntilDate,
+		&object.LegalHold,
+	)
+	if err == sql.ErrNoRows {
+		err = ErrNoSuchKey
+		return
+	} else if err != nil {
+		return
+	}
+	rversion := math.MaxUint64 - iversion
+	s := int64(rversion) / 1e9
+	ns := int64(rversion) % 1e9
+	object.LastModifiedTime = time.Unix(s, ns)
+	object.Name = objectName
+	object.BucketName = bucketName
+	err = json.Unmarshal([]byte(acl), &object.ACL)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(customattributes), &object.CustomAttributes)
+	if err != nil {
+		return
+	}
+	if tagging != "" {
+		err = json.Unmarshal([]byte(tagging), &object.Tagging)
+		if err != nil {
+			return
+		}
+	}
+	if retainUntilDate.Valid {
+		object.RetainUntilDate = inLocation(retainUntilDate.Time, time.UTC)
+	}
+	object.Parts, err = getParts(object.BucketName, object.Name, iversion, t.Client)
+	if err != nil {
+		return
+	}
+	//build simple index for multipart
+	if len(object.Parts) != 0 {
+		var sortedPartNum = make([]int64, len(object.Parts))
+		for k, v := range object.Parts {
+			sortedPartNum[k-1] = v.Offset
+		}
+		object.PartsIndex = &SimpleIndex{Index: sortedPartNum}
+	}
+	object.VersionId = object.GetVersionId()
+	return
+}
+
+func (t *PgClient) GetAllObject(bucketName, objectName, version string) (object []*Object, err error) {
+	sqltext := "select version from objects where bucketname=? and name=? order by version;"
+	var versions []string
+	rows, err := query(t.Client, sqltext, bucketName, objectName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sversion string
+		err = rows.Scan(&sversion)
+		if err != nil {
+			return
+		}
+		versions = append(versions, sversion)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	for _, v := range versions {
+		var obj *Object
+		obj, err = t.GetObject(bucketName, objectName, v)
+		if err != nil {
+			return
+		}
+		object = append(object, obj)
+	}
+	return
+}
+
+func (t *PgClient) UpdateObjectAttrs(object *Object) error {
+	sql, args := object.GetUpdateAttrsSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) UpdateObjectAcl(object *Object) error {
+	sql, args := object.GetUpdateAclSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) UpdateObjectTagging(object *Object) error {
+	sql, args := object.GetUpdateTaggingSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) UpdateObjectReplicationStatus(object *Object) error {
+	sql, args := object.GetUpdateReplicationStatusSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) UpdateObjectLock(object *Object) error {
+	sql, args := object.GetUpdateObjectLockSql(PostgresDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *PgClient) RenameObject(object *Object, sourceObject string, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sql, args := object.GetUpdateNameSql(sourceObject, PostgresDialect)
+	_, err = tx.Exec(sql, args...)
+	return
+}
+
+func (t *PgClient) ReplaceObjectMetas(object *Object, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sql, args := object.GetReplaceObjectMetasSql(PostgresDialect)
+	_, err = tx.Exec(sql, args...)
+	return
+}
+
+func (t *PgClient) UpdateAppendObject(object *Object, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sql, args := object.GetAppendSql(PostgresDialect)
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// moved or deleted since appended, the appended data is lost
+		// with the old data, so client has to check length and retry
+		return ErrPositionNotEqualToLength
+	}
+	return nil
+}
+
+func (t *PgClient) PutObject(object *Object, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	sql, args := object.GetCreateSql(PostgresDialect)
+	_, err = tx.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	if object.Parts != nil {
+		v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+		version := strconv.FormatUint(v, 10)
+		for _, p := range object.Parts {
+			psql, args := p.GetCreateSql(object.BucketName, object.Name, version, PostgresDialect)
+			_, err = tx.Exec(psql, args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *PgClient) UpdateObject(object *Object, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+
+	v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+	version := strconv.FormatUint(v, 10)
+	sqltext := "delete from objectpart where objectname=? and bucketname=? and version=?;"
+	_, err = exec(tx, sqltext, object.Name, object.BucketName, version)
+	if err != nil {
+		return err
+	}
+
+	sql, args := object.GetUpdateSql(PostgresDialect)
+	_, err = tx.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	if object.Parts != nil {
+		for _, p := range object.Parts {
+			psql, args := p.GetCreateSql(object.BucketName, object.Name, version, PostgresDialect)
+			_, err = tx.Exec(psql, args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateObjectLocation moves data location of `object` (and object ids of
+// its parts) from `sourceObject`, nothing is updated if the object row or
+// any part doesn't refer to data of `sourceObject` any more
+func (t *PgClient) UpdateObjectLocation(object, sourceObject *Object, tx DB) (updated bool, err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if err == nil && updated {
+				err = tx.(*sql.Tx).Commit()
+			} else {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+
+	sql, args := object.GetUpdateLocationSql(sourceObject, PostgresDialect)
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected != 1 {
+		return false, err
+	}
+	v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+	version := strconv.FormatUint(v, 10)
+	for number, p := range object.Parts {
+		sourcePart, ok := sourceObject.Parts[number]
+		if !ok {
+			return false, nil
+		}
+		if p.ObjectId == sourcePart.ObjectId {
+			// data of the part stays where it is
+			continue
+		}
+		psql, args := p.GetUpdateObjectIdSql(object.BucketName, object.Name, version,
+			sourcePart.ObjectId, PostgresDialect)
+		result, err = tx.Exec(psql, args...)
+		if err != nil {
+			return false, err
+		}
+		affected, err = result.RowsAffected()
+		if err != nil || affected != 1 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (t *PgClient) DeleteObject(object *Object, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+
+	v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+	version := strconv.FormatUint(v, 10)
+	sqltext := "delete from objects where name=? and bucketname=? and version=?;"
+	_, err = exec(tx, sqltext, object.Name, object.BucketName, version)
+	if err != nil {
+		return err
+	}
+	sqltext = "delete from objectpart where objectname=? and bucketname=? and version=?;"
+	_, err = exec(tx, sqltext, object.Name, object.BucketName, version)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// util function
+func getParts(bucketName, objectName string, version uint64, cli *sql.DB) (parts map[int]*Part, err error) {
+	parts = make(map[int]*Part)
+	sqltext := "select partnumber,size,objectid,\"offset\",etag,lastmodified,initializationvector from objectpart where bucketname=? and objectname=? and version=?;"
+	rows, err := query(cli, sqltext, bucketName, objectName, version)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p *Part
+		p, err = scanPart(rows, TIME_LAYOUT_TIDB)
+		if err != nil {
+			return
+		}
+		parts[p.PartNumber] = p
+	}
+	return parts, rows.Err()
+}
+
+// scanPart reads a row of part tables, lastmodified is formatted in `layout`
+// which is the same as what tidbclient returns for the table
+func scanPart(row scanner, layout string) (p *Part, err error) {
+	var lastModified time.Time
+	p = &Part{}
+	err = row.Scan(
+		&p.PartNumber,
+		&p.Size,
+		&p.ObjectId,
+		&p.Offset,
+		&p.Etag,
+		&lastModified,
+		&p.InitializationVector,
+	)
+	if err != nil {
+		return
+	}
+	p.LastModified = lastModified.Format(layout)
+	return
+}
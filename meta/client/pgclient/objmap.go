@@ -0,0 +1,41 @@
+package pgclient
+
+import (
+	"strconv"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+// objmap
+func (t *PgClient) GetObjectMap(bucketName, objectName string) (objMap *ObjMap, err error) {
+	objMap = &ObjMap{}
+	sqltext := "select bucketname,objectname,nullvernum from objmap where bucketname=? and objectname=?;"
+	err = queryRow(t.Client, sqltext, bucketName, objectName).Scan(
+		&objMap.BucketName,
+		&objMap.Name,
+		&objMap.NullVerNum,
+	)
+	if err != nil {
+		return
+	}
+	objMap.NullVerId = strconv.FormatUint(objMap.NullVerNum, 10)
+	return
+}
+
+func (t *PgClient) PutObjectMap(objMap *ObjMap, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sqltext := "insert into objmap(bucketname,objectname,nullvernum) values(?,?,?);"
+	_, err = exec(tx, sqltext, objMap.BucketName, objMap.Name, objMap.NullVerNum)
+	return err
+}
+
+func (t *PgClient) DeleteObjectMap(objMap *ObjMap, tx DB) (err error) {
+	if tx == nil {
+		tx = t.Client
+	}
+	sqltext := "delete from objmap where bucketname=? and objectname=?;"
+	_, err = exec(tx, sqltext, objMap.BucketName, objMap.Name)
+	return err
+}
@@ -0,0 +1,18 @@
+package pgclient
+
+import "database/sql"
+
+func (t *PgClient) NewTrans() (tx *sql.Tx, err error) {
+	tx, err = t.Client.Begin()
+	return
+}
+
+func (t *PgClient) AbortTrans(tx *sql.Tx) (err error) {
+	err = tx.Rollback()
+	return
+}
+
+func (t *PgClient) CommitTrans(tx *sql.Tx) (err error) {
+	err = tx.Commit()
+	return
+}
@@ -0,0 +1,31 @@
+package pgclient
+
+func (t *PgClient) GetUserBuckets(userId string) (buckets []string, err error) {
+	sqltext := "select bucketname from users where userid=? order by bucketname;"
+	rows, err := query(t.Client, sqltext, userId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tmp string
+		err = rows.Scan(&tmp)
+		if err != nil {
+			return
+		}
+		buckets = append(buckets, tmp)
+	}
+	return buckets, rows.Err()
+}
+
+func (t *PgClient) AddBucketForUser(bucketName, userId string) (err error) {
+	sqltext := "insert into users(userid,bucketname) values(?,?)"
+	_, err = exec(t.Client, sqltext, userId, bucketName)
+	return
+}
+
+func (t *PgClient) RemoveBucketForUser(bucketName string, userId string) (err error) {
+	sqltext := "delete from users where userid=? and bucketname=?;"
+	_, err = exec(t.Client, sqltext, userId, bucketName)
+	return
+}
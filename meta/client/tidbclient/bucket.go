@@ -14,8 +14,8 @@ import (
 )
 
 func (t *TidbClient) GetBucket(bucketName string) (bucket *Bucket, err error) {
-	var acl, cors, logging, lc, policy, website, encryption, createTime string
-	sqltext := "select bucketname,acl,cors,COALESCE(logging,\"\"),lc,uid,policy,website,COALESCE(encryption,\"\"),createtime,usages,versioning from buckets where bucketname=?;"
+	var acl, cors, logging, lc, policy, website, encryption, tagging, notification, replication, objectLock, placement, publicAccessBlock, createTime string
+	sqltext := "select bucketname,acl,cors,COALESCE(logging,\"\"),lc,uid,policy,website,COALESCE(encryption,\"\"),COALESCE(tagging,\"\"),COALESCE(notification,\"\"),COALESCE(replication,\"\"),COALESCE(objectlock,\"\"),COALESCE(placement,\"\"),COALESCE(publicaccessblock,\"\"),createtime,usages,versioning from buckets where bucketname=?;"
 	bucket = new(Bucket)
 	err = t.Client.QueryRow(sqltext, bucketName).Scan(
 		&bucket.Name,
@@ -27,6 +27,12 @@ func (t *TidbClient) GetBucket(bucketName string) (bucket *Bucket, err error) {
 		&policy,
 		&website,
 		&encryption,
+		&tagging,
+		&notification,
+		&replication,
+		&objectLock,
+		&placement,
+		&publicAccessBlock,
 		&createTime,
 		&bucket.Usage,
 		&bucket.Versioning,
@@ -69,11 +75,47 @@ func (t *TidbClient) GetBucket(bucketName string) (bucket *Bucket, err error) {
 	if err != nil {
 		return
 	}
+	if tagging != "" {
+		err = json.Unmarshal([]byte(tagging), &bucket.Tagging)
+		if err != nil {
+			return
+		}
+	}
+	if notification != "" {
+		err = json.Unmarshal([]byte(notification), &bucket.Notification)
+		if err != nil {
+			return
+		}
+	}
+	if replication != "" {
+		err = json.Unmarshal([]byte(replication), &bucket.Replication)
+		if err != nil {
+			return
+		}
+	}
+	if objectLock != "" {
+		err = json.Unmarshal([]byte(objectLock), &bucket.ObjectLock)
+		if err != nil {
+			return
+		}
+	}
+	if placement != "" {
+		err = json.Unmarshal([]byte(placement), &bucket.Placement)
+		if err != nil {
+			return
+		}
+	}
+	if publicAccessBlock != "" {
+		err = json.Unmarshal([]byte(publicAccessBlock), &bucket.PublicAccessBlock)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
 func (t *TidbClient) GetBuckets() (buckets []Bucket, err error) {
-	sqltext := "select bucketname,acl,cors,COALESCE(logging,\"\"),lc,uid,policy,website,COALESCE(encryption,\"\"),createtime,usages,versioning from buckets;"
+	sqltext := "select bucketname,acl,cors,COALESCE(logging,\"\"),lc,uid,policy,website,COALESCE(encryption,\"\"),COALESCE(tagging,\"\"),COALESCE(notification,\"\"),COALESCE(replication,\"\"),COALESCE(objectlock,\"\"),COALESCE(placement,\"\"),COALESCE(publicaccessblock,\"\"),createtime,usages,versioning from buckets;"
 	rows, err := t.Client.Query(sqltext)
 	if err == sql.ErrNoRows {
 		err = nil
@@ -85,7 +127,7 @@ func (t *TidbClient) GetBuckets() (buckets []Bucket, err error) {
 
 	for rows.Next() {
 		var tmp Bucket
-		var acl, cors, logging, lc, policy, website, encryption, createTime string
+		var acl, cors, logging, lc, policy, website, encryption, tagging, notification, replication, objectLock, placement, publicAccessBlock, createTime string
 		err = rows.Scan(
 			&tmp.Name,
 			&acl,
@@ -96,6 +138,13 @@ func (t *TidbClient) GetBuckets() (buckets []Bucket, err error) {
 			&policy,
 			&website,
 			&encryption,
+			&tagging,
+			&notification,
+			&replication,
+			&objectLock,
+			&placement,
+			&publicAccessBlock,
+		&publicAccessBlock,
 			&createTime,
 			&tmp.Usage,
 			&tmp.Versioning)
@@ -134,6 +183,42 @@ func (t *TidbClient) GetBuckets() (buckets []Bucket, err error) {
 		if err != nil {
 			return
 		}
+		if tagging != "" {
+			err = json.Unmarshal([]byte(tagging), &tmp.Tagging)
+			if err != nil {
+				return
+			}
+		}
+		if notification != "" {
+			err = json.Unmarshal([]byte(notification), &tmp.Notification)
+			if err != nil {
+				return
+			}
+		}
+		if replication != "" {
+			err = json.Unmarshal([]byte(replication), &tmp.Replication)
+			if err != nil {
+				return
+			}
+		}
+		if objectLock != "" {
+			err = json.Unmarshal([]byte(objectLock), &tmp.ObjectLock)
+			if err != nil {
+				return
+			}
+		}
+		if placement != "" {
+			err = json.Unmarshal([]byte(placement), &tmp.Placement)
+			if err != nil {
+				return
+			}
+		}
+		if publicAccessBlock != "" {
+			err = json.Unmarshal([]byte(publicAccessBlock), &tmp.PublicAccessBlock)
+			if err != nil {
+				return
+			}
+		}
 		buckets = append(buckets, tmp)
 	}
 	return
@@ -141,7 +226,7 @@ func (t *TidbClient) GetBuckets() (buckets []Bucket, err error) {
 
 //Actually this method is used to update bucket
 func (t *TidbClient) PutBucket(bucket Bucket) error {
-	sql, args := bucket.GetUpdateSql()
+	sql, args := bucket.GetUpdateSql(MySQLDialect)
 	_, err := t.Client.Exec(sql, args...)
 	if err != nil {
 		return err
@@ -161,7 +246,7 @@ func (t *TidbClient) CheckAndPutBucket(bucket Bucket) (bool, error) {
 	} else {
 		processed = true
 	}
-	sql, args := bucket.GetCreateSql()
+	sql, args := bucket.GetCreateSql(MySQLDialect)
 	_, err = t.Client.Exec(sql, args...)
 	return processed, err
 }
@@ -0,0 +1,21 @@
+package tidbclient_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/meta/client/clienttest"
+	"github.com/journeymidnight/yig/meta/client/tidbclient"
+)
+
+// set YIG_TEST_TIDB_INFO to a database created by integrate/yig.sql, e.g.
+// "root:@tcp(127.0.0.1:4000)/yig"
+func TestTidbClient(t *testing.T) {
+	tidbInfo := os.Getenv("YIG_TEST_TIDB_INFO")
+	if tidbInfo == "" {
+		t.Skip("YIG_TEST_TIDB_INFO is not set")
+	}
+	helper.CONFIG.TidbInfo = tidbInfo
+	clienttest.RunClientTests(t, tidbclient.NewTidbClient())
+}
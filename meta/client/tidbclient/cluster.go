@@ -5,7 +5,7 @@ import (
 )
 
 func (t *TidbClient) GetClusters() (cluster []Cluster, err error) {
-	sqltext := "select fsid,pool,weight from cluster"
+	sqltext := "select fsid,pool,weight,status from cluster"
 	rows, err := t.Client.Query(sqltext)
 	if err != nil {
 		return nil, err
@@ -13,7 +13,7 @@ func (t *TidbClient) GetClusters() (cluster []Cluster, err error) {
 	defer rows.Close()
 	for rows.Next() {
 		c := Cluster{}
-		err = rows.Scan(&c.Fsid, &c.Pool, &c.Weight)
+		err = rows.Scan(&c.Fsid, &c.Pool, &c.Weight, &c.Status)
 		cluster = append(cluster, c)
 		if err != nil {
 			return nil, err
@@ -21,3 +21,28 @@ func (t *TidbClient) GetClusters() (cluster []Cluster, err error) {
 	}
 	return cluster, nil
 }
+
+// SetClusterStatus changes status of all pools in cluster `fsid`
+func (t *TidbClient) SetClusterStatus(fsid string, status ClusterStatus) error {
+	sqltext := "update cluster set status=? where fsid=?"
+	_, err := t.Client.Exec(sqltext, status, fsid)
+	return err
+}
+
+// PutCluster adds pool `cluster.Pool` of cluster `cluster.Fsid`, or changes
+// its weight if it exists, status of existing pools is kept
+func (t *TidbClient) PutCluster(cluster Cluster) error {
+	sqltext := "update cluster set weight=? where fsid=? and pool=?"
+	result, err := t.Client.Exec(sqltext, cluster.Weight, cluster.Fsid, cluster.Pool)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected != 0 {
+		return err
+	}
+	// the pool doesn't exist, or its weight is not changed
+	sqltext = MySQLDialect.InsertIgnore("insert into cluster(fsid,pool,weight,status) values(?,?,?,?)")
+	_, err = t.Client.Exec(sqltext, cluster.Fsid, cluster.Pool, cluster.Weight, cluster.Status)
+	return err
+}
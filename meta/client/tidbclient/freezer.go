@@ -8,7 +8,7 @@ import (
 )
 
 func (t *TidbClient) CreateFreezer(freezer *Freezer) (err error) {
-	sql, args := freezer.GetCreateSql()
+	sql, args := freezer.GetCreateSql(MySQLDialect)
 	_, err = t.Client.Exec(sql, args...)
 	return
 }
@@ -53,7 +53,8 @@ func (t *TidbClient) GetFreezer(bucketName, objectName, version string) (freezer
 }
 
 func (t *TidbClient) GetFreezerStatus(bucketName, objectName, version string) (freezer *Freezer, err error) {
-	sqltext := "select bucketname,objectname,IFNULL(version,''),status from restoreobjects where bucketname=? and objectname=?;"
+	var lastmodifiedtime string
+	sqltext := "select bucketname,objectname,IFNULL(version,''),status,lifetime,lastmodifiedtime from restoreobjects where bucketname=? and objectname=?;"
 	row := t.Client.QueryRow(sqltext, bucketName, objectName)
 	freezer = &Freezer{}
 	err = row.Scan(
@@ -61,11 +62,15 @@ func (t *TidbClient) GetFreezerStatus(bucketName, objectName, version string) (f
 		&freezer.Name,
 		&freezer.VersionId,
 		&freezer.Status,
+		&freezer.LifeTime,
+		&lastmodifiedtime,
 	)
 	if err == sql.ErrNoRows || freezer.Name != objectName {
 		err = ErrNoSuchKey
 		return
 	}
+	local, _ := time.LoadLocation("Local")
+	freezer.LastModifiedTime, _ = time.ParseInLocation(TIME_LAYOUT_TIDB, lastmodifiedtime, local)
 	return
 }
 
@@ -98,7 +103,7 @@ func (t *TidbClient) DeleteFreezer(bucketName, objectName string, tx DB) (err er
 	if err != nil {
 		return err
 	}
-	sqltext = "delete from restoreobjectpart where objectname=? and bucketname=?;"
+	sqltext = "delete from restoreobjectpart where bucketname=? and objectname=?;"
 	_, err = tx.Exec(sqltext, bucketName, objectName)
 	if err != nil {
 		return err
@@ -106,6 +111,115 @@ func (t *TidbClient) DeleteFreezer(bucketName, objectName string, tx DB) (err er
 	return nil
 }
 
+// ListFreezers returns at most `limit` freezers in `status`, in the order of
+// tier and request time
+func (t *TidbClient) ListFreezers(status Status, limit int) (freezers []Freezer, err error) {
+	sqltext := "select bucketname,objectname,status,lifetime,lastmodifiedtime,IFNULL(tier,1) from restoreobjects " +
+		"where status=? order by tier,lastmodifiedtime limit ?;"
+	rows, err := t.Client.Query(sqltext, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	local, _ := time.LoadLocation("Local")
+	for rows.Next() {
+		var freezer Freezer
+		var lastmodifiedtime string
+		err = rows.Scan(
+			&freezer.BucketName,
+			&freezer.Name,
+			&freezer.Status,
+			&freezer.LifeTime,
+			&lastmodifiedtime,
+			&freezer.Tier,
+		)
+		if err != nil {
+			return nil, err
+		}
+		freezer.LastModifiedTime, _ = time.ParseInLocation(TIME_LAYOUT_TIDB, lastmodifiedtime, local)
+		freezers = append(freezers, freezer)
+	}
+	return freezers, rows.Err()
+}
+
+// ListExpiredFreezers returns at most `limit` restored freezers whose
+// lifetime has passed at `now`
+func (t *TidbClient) ListExpiredFreezers(now time.Time, limit int) (freezers []Freezer, err error) {
+	sqltext := "select bucketname,objectname from restoreobjects " +
+		"where status=? and date_add(lastmodifiedtime,interval lifetime day)<? limit ?;"
+	rows, err := t.Client.Query(sqltext, ObjectHasRestored, now.Format(TIME_LAYOUT_TIDB), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		freezer := Freezer{Status: ObjectHasRestored}
+		err = rows.Scan(
+			&freezer.BucketName,
+			&freezer.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		freezers = append(freezers, freezer)
+	}
+	return freezers, rows.Err()
+}
+
+// UpdateFreezerStatus changes status of freezer only if it's still in
+// `freezer.Status`, returns false if the freezer is changed by others
+func (t *TidbClient) UpdateFreezerStatus(freezer *Freezer, status Status) (updated bool, err error) {
+	sqltext := "update restoreobjects set status=? where bucketname=? and objectname=? and status=?;"
+	result, err := t.Client.Exec(sqltext, status, freezer.BucketName, freezer.Name, freezer.Status)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UpdateFreezer saves location of restored data into freezer and
+// changes its status
+func (t *TidbClient) UpdateFreezer(freezer *Freezer, status Status, tx DB) (err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = tx.(*sql.Tx).Commit()
+			}
+			if err != nil {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+	sqltext, args := freezer.GetUpdateSql(status, MySQLDialect)
+	result, err := tx.Exec(sqltext, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNoSuchKey
+	}
+	for _, p := range freezer.Parts {
+		psql, args := p.GetCreateFreezerSql(freezer.BucketName, freezer.Name, MySQLDialect)
+		_, err = tx.Exec(psql, args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //util function
 func getFreezerParts(bucketName, objectName string, cli *sql.DB) (parts map[int]*Part, err error) {
 	parts = make(map[int]*Part)
@@ -38,7 +38,7 @@ func (t *TidbClient) PutObjectToGarbageCollection(object *Object, tx DB) (err er
 		return err
 	}
 	for _, p := range object.Parts {
-		psql, args := p.GetCreateGcSql(o.BucketName, o.ObjectName, version)
+		psql, args := p.GetCreateGcSql(o.BucketName, o.ObjectName, version, MySQLDialect)
 		_, err = tx.Exec(psql, args...)
 		if err != nil {
 			return err
@@ -147,7 +147,7 @@ func (t *TidbClient) PutFreezerToGarbageCollection(object *Freezer, tx DB) (err
 		return err
 	}
 	for _, p := range object.Parts {
-		psql, args := p.GetCreateGcSql(o.BucketName, o.ObjectName, version)
+		psql, args := p.GetCreateGcSql(o.BucketName, o.ObjectName, version, MySQLDialect)
 		_, err = tx.Exec(psql, args...)
 		if err != nil {
 			return err
@@ -57,3 +57,23 @@ func (t *TidbClient) ScanLifeCycle(limit int, marker string) (result ScanLifeCyc
 	}
 	return result, nil
 }
+
+// ScanObjectNames lists distinct object names of bucket after marker,
+// lifecycle worker then checks all versions of each name
+func (t *TidbClient) ScanObjectNames(bucketName, marker string, limit int) (names []string, err error) {
+	sqltext := "select distinct name from objects where bucketname=? and name>? order by name limit ?;"
+	rows, err := t.Client.Query(sqltext, bucketName, marker, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
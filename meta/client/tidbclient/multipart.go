@@ -30,9 +30,9 @@ func (t *TidbClient) GetMultipart(bucketName, objectName, uploadId string) (mult
 	}
 	uploadTime = math.MaxUint64 - uploadTime
 	sqltext := "select bucketname,objectname,uploadtime,initiatorid,ownerid,contenttype,location,pool,acl,sserequest," +
-		"encryption,COALESCE(cipher,\"\"),attrs,storageclass from multiparts where bucketname=? and objectname=? and uploadtime=?;"
+		"encryption,COALESCE(cipher,\"\"),attrs,storageclass,COALESCE(tagging,\"\") from multiparts where bucketname=? and objectname=? and uploadtime=?;"
 	var initialTime uint64
-	var acl, sseRequest, attrs string
+	var acl, sseRequest, attrs, tagging string
 	err = t.Client.QueryRow(sqltext, bucketName, objectName, uploadTime).Scan(
 		&multipart.BucketName,
 		&multipart.ObjectName,
@@ -48,6 +48,7 @@ func (t *TidbClient) GetMultipart(bucketName, objectName, uploadId string) (mult
 		&multipart.Metadata.CipherKey,
 		&attrs,
 		&multipart.Metadata.StorageClass,
+		&tagging,
 	)
 	if err != nil && err == sql.ErrNoRows {
 		err = ErrNoSuchUpload
@@ -71,6 +72,12 @@ func (t *TidbClient) GetMultipart(bucketName, objectName, uploadId string) (mult
 	if err != nil {
 		return
 	}
+	if tagging != "" {
+		err = json.Unmarshal([]byte(tagging), &multipart.Metadata.Tagging)
+		if err != nil {
+			return
+		}
+	}
 
 	sqltext = "select partnumber,size,objectid,offset,etag,lastmodified,initializationvector from multipartpart where bucketname=? and objectname=? and uploadtime=?;"
 	rows, err := t.Client.Query(sqltext, bucketName, objectName, uploadTime)
@@ -108,9 +115,10 @@ func (t *TidbClient) CreateMultipart(multipart Multipart) (err error) {
 	acl, _ := json.Marshal(m.Acl)
 	sseRequest, _ := json.Marshal(m.SseRequest)
 	attrs, _ := json.Marshal(m.Attrs)
-	sqltext := "insert into multiparts(bucketname,objectname,uploadtime,initiatorid,ownerid,contenttype,location,pool,acl,sserequest,encryption,cipher,attrs,storageclass) " +
-		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
-	_, err = t.Client.Exec(sqltext, multipart.BucketName, multipart.ObjectName, uploadtime, m.InitiatorId, m.OwnerId, m.ContentType, m.Location, m.Pool, acl, sseRequest, m.EncryptionKey, m.CipherKey, attrs, m.StorageClass)
+	tagging, _ := json.Marshal(m.Tagging)
+	sqltext := "insert into multiparts(bucketname,objectname,uploadtime,initiatorid,ownerid,contenttype,location,pool,acl,sserequest,encryption,cipher,attrs,storageclass,tagging) " +
+		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	_, err = t.Client.Exec(sqltext, multipart.BucketName, multipart.ObjectName, uploadtime, m.InitiatorId, m.OwnerId, m.ContentType, m.Location, m.Pool, acl, sseRequest, m.EncryptionKey, m.CipherKey, attrs, m.StorageClass, tagging)
 	return
 }
 
@@ -163,10 +171,16 @@ func (t *TidbClient) ListMultipartUploads(bucketName, keyMarker, uploadIdMarker,
 	commonPrefixes := make(map[string]struct{})
 	var uploadNum uint64
 	if uploadIdMarker != "" {
-		uploadNum, err = strconv.ParseUint(uploadIdMarker, 10, 64)
-	}
-	if err != nil {
-		return
+		var timestampString string
+		timestampString, err = util.Decrypt(uploadIdMarker)
+		if err != nil {
+			return
+		}
+		uploadNum, err = strconv.ParseUint(timestampString, 10, 64)
+		if err != nil {
+			return
+		}
+		uploadNum = math.MaxUint64 - uploadNum
 	}
 	var objnum map[string]int = make(map[string]int)
 	var currentMarker string = keyMarker
@@ -214,7 +228,7 @@ func (t *TidbClient) ListMultipartUploads(bucketName, keyMarker, uploadIdMarker,
 			//filte by uploadtime and key
 			if first {
 				if uploadNum != 0 {
-					if name == currentMarker && uploadtime < uploadNum {
+					if name == keyMarker && uploadtime < uploadNum {
 						continue
 					}
 				}
@@ -285,7 +299,40 @@ func (t *TidbClient) RenameObjectPart(object *Object, sourceObject string, tx DB
 	if tx == nil {
 		tx = t.Client
 	}
-	sql, args := object.GetUpdateObjectPartNameSql(sourceObject)
+	sql, args := object.GetUpdateObjectPartNameSql(sourceObject, MySQLDialect)
 	_, err = tx.Exec(sql, args...)
 	return err
 }
+
+func (t *TidbClient) ListStaleMultipartUploads(initiatedBefore time.Time, limit int) (uploads []Multipart, err error) {
+	// uploadtime is stored as math.MaxUint64 - initiated time, older uploads
+	// have larger uploadtime
+	uploadTime := math.MaxUint64 - uint64(initiatedBefore.UnixNano())
+	sqltext := "select bucketname,objectname,uploadtime,initiatorid,ownerid,storageclass from multiparts " +
+		"where uploadtime>? order by uploadtime desc limit ?;"
+	rows, err := t.Client.Query(sqltext, uploadTime, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var multipart Multipart
+		var initialTime uint64
+		err = rows.Scan(
+			&multipart.BucketName,
+			&multipart.ObjectName,
+			&initialTime,
+			&multipart.Metadata.InitiatorId,
+			&multipart.Metadata.OwnerId,
+			&multipart.Metadata.StorageClass,
+		)
+		if err != nil {
+			return
+		}
+		rinitial := int64(math.MaxUint64 - initialTime)
+		multipart.InitialTime = time.Unix(rinitial/1e9, rinitial%1e9)
+		multipart.UploadId = GetMultipartUploadIdForTidb(initialTime)
+		uploads = append(uploads, multipart)
+	}
+	return uploads, rows.Err()
+}
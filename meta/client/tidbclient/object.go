@@ -14,12 +14,13 @@ import (
 )
 
 func (t *TidbClient) GetObject(bucketName, objectName, version string) (object *Object, err error) {
-	var ibucketname, iname, customattributes, acl, lastModifiedTime string
+	var ibucketname, iname, customattributes, acl, lastModifiedTime, tagging, retainUntilDate string
 	var iversion uint64
 
 	var row *sql.Row
 	sqltext := "select bucketname,name,version,location,pool,ownerid,size,objectid,lastmodifiedtime,etag,contenttype," +
-		"customattributes,acl,nullversion,deletemarker,ssetype,encryptionkey,initializationvector,type,storageclass from objects where bucketname=? and name=? "
+		"customattributes,acl,nullversion,deletemarker,ssetype,encryptionkey,initializationvector,type,storageclass,COALESCE(tagging,\"\"),COALESCE(replicationstatus,\"\")," +
+		"COALESCE(objectlockmode,\"\"),COALESCE(retainuntildate,\"\"),COALESCE(legalhold,0) from objects where bucketname=? and name=? "
 	if version == "" {
 		sqltext += "order by bucketname,name,version limit 1;"
 		row = t.Client.QueryRow(sqltext, bucketName, objectName)
@@ -49,6 +50,11 @@ func (t *TidbClient) GetObject(bucketName, objectName, version string) (object *
 		&object.InitializationVector,
 		&object.Type,
 		&object.StorageClass,
+		&tagging,
+		&object.ReplicationStatus,
+		&object.ObjectLockMode,
+		&retainUntilDate,
+		&object.LegalHold,
 	)
 	if err == sql.ErrNoRows {
 		err = ErrNoSuchKey
@@ -70,6 +76,18 @@ func (t *TidbClient) GetObject(bucketName, objectName, version string) (object *
 	if err != nil {
 		return
 	}
+	if tagging != "" {
+		err = json.Unmarshal([]byte(tagging), &object.Tagging)
+		if err != nil {
+			return
+		}
+	}
+	if retainUntilDate != "" {
+		object.RetainUntilDate, err = time.Parse(TIME_LAYOUT_TIDB, retainUntilDate)
+		if err != nil {
+			return
+		}
+	}
 	object.Parts, err = getParts(object.BucketName, object.Name, iversion, t.Client)
 	//build simple index for multipart
 	if len(object.Parts) != 0 {
@@ -114,13 +132,31 @@ func (t *TidbClient) GetAllObject(bucketName, objectName, version string) (objec
 }
 
 func (t *TidbClient) UpdateObjectAttrs(object *Object) error {
-	sql, args := object.GetUpdateAttrsSql()
+	sql, args := object.GetUpdateAttrsSql(MySQLDialect)
 	_, err := t.Client.Exec(sql, args...)
 	return err
 }
 
 func (t *TidbClient) UpdateObjectAcl(object *Object) error {
-	sql, args := object.GetUpdateAclSql()
+	sql, args := object.GetUpdateAclSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *TidbClient) UpdateObjectTagging(object *Object) error {
+	sql, args := object.GetUpdateTaggingSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *TidbClient) UpdateObjectReplicationStatus(object *Object) error {
+	sql, args := object.GetUpdateReplicationStatusSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *TidbClient) UpdateObjectLock(object *Object) error {
+	sql, args := object.GetUpdateObjectLockSql(MySQLDialect)
 	_, err := t.Client.Exec(sql, args...)
 	return err
 }
@@ -129,7 +165,7 @@ func (t *TidbClient) RenameObject(object *Object, sourceObject string, tx DB) (e
 	if tx == nil {
 		tx = t.Client
 	}
-	sql, args := object.GetUpdateNameSql(sourceObject)
+	sql, args := object.GetUpdateNameSql(sourceObject, MySQLDialect)
 	_, err = tx.Exec(sql, args...)
 	return
 }
@@ -138,7 +174,7 @@ func (t *TidbClient) ReplaceObjectMetas(object *Object, tx DB) (err error) {
 	if tx == nil {
 		tx = t.Client
 	}
-	sql, args := object.GetReplaceObjectMetasSql()
+	sql, args := object.GetReplaceObjectMetasSql(MySQLDialect)
 	_, err = tx.Exec(sql, args...)
 	return
 }
@@ -147,9 +183,21 @@ func (t *TidbClient) UpdateAppendObject(object *Object, tx DB) (err error) {
 	if tx == nil {
 		tx = t.Client
 	}
-	sql, args := object.GetAppendSql()
-	_, err = tx.Exec(sql, args...)
-	return err
+	sql, args := object.GetAppendSql(MySQLDialect)
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// moved or deleted since appended, the appended data is lost
+		// with the old data, so client has to check length and retry
+		return ErrPositionNotEqualToLength
+	}
+	return nil
 }
 
 func (t *TidbClient) PutObject(object *Object, tx DB) (err error) {
@@ -167,13 +215,13 @@ func (t *TidbClient) PutObject(object *Object, tx DB) (err error) {
 			}
 		}()
 	}
-	sql, args := object.GetCreateSql()
+	sql, args := object.GetCreateSql(MySQLDialect)
 	_, err = tx.Exec(sql, args...)
 	if object.Parts != nil {
 		v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
 		version := strconv.FormatUint(v, 10)
 		for _, p := range object.Parts {
-			psql, args := p.GetCreateSql(object.BucketName, object.Name, version)
+			psql, args := p.GetCreateSql(object.BucketName, object.Name, version, MySQLDialect)
 			_, err = tx.Exec(psql, args...)
 			if err != nil {
 				return err
@@ -207,11 +255,14 @@ func (t *TidbClient) UpdateObject(object *Object, tx DB) (err error) {
 		return err
 	}
 
-	sql, args := object.GetUpdateSql()
+	sql, args := object.GetUpdateSql(MySQLDialect)
 	_, err = tx.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
 	if object.Parts != nil {
 		for _, p := range object.Parts {
-			psql, args := p.GetCreateSql(object.BucketName, object.Name, version)
+			psql, args := p.GetCreateSql(object.BucketName, object.Name, version, MySQLDialect)
 			_, err = tx.Exec(psql, args...)
 			if err != nil {
 				return err
@@ -221,6 +272,58 @@ func (t *TidbClient) UpdateObject(object *Object, tx DB) (err error) {
 	return nil
 }
 
+// UpdateObjectLocation moves data location and storage class of `object` (and
+// object ids of its parts) from `sourceObject`, nothing is updated if the object
+// row or any part doesn't refer to data of `sourceObject` any more
+func (t *TidbClient) UpdateObjectLocation(object, sourceObject *Object, tx DB) (updated bool, err error) {
+	if tx == nil {
+		tx, err = t.Client.Begin()
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if err == nil && updated {
+				err = tx.(*sql.Tx).Commit()
+			} else {
+				tx.(*sql.Tx).Rollback()
+			}
+		}()
+	}
+
+	sql, args := object.GetUpdateLocationSql(sourceObject, MySQLDialect)
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected != 1 {
+		return false, err
+	}
+	v := math.MaxUint64 - uint64(object.LastModifiedTime.UnixNano())
+	version := strconv.FormatUint(v, 10)
+	for number, p := range object.Parts {
+		sourcePart, ok := sourceObject.Parts[number]
+		if !ok {
+			return false, nil
+		}
+		if p.ObjectId == sourcePart.ObjectId {
+			// data of the part stays where it is
+			continue
+		}
+		psql, args := p.GetUpdateObjectIdSql(object.BucketName, object.Name, version,
+			sourcePart.ObjectId, MySQLDialect)
+		result, err = tx.Exec(psql, args...)
+		if err != nil {
+			return false, err
+		}
+		affected, err = result.RowsAffected()
+		if err != nil || affected != 1 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 func (t *TidbClient) DeleteObject(object *Object, tx DB) (err error) {
 	if tx == nil {
 		tx, err = t.Client.Begin()
@@ -0,0 +1,82 @@
+package tidbclient
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+func (t *TidbClient) PutObjectToReplication(replication *Replication) error {
+	sql, args := replication.GetCreateSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+// ScanReplication lists entries of replication queue with given status,
+// starting from `startRowKey`(inclusive) in the order of rowkey
+func (t *TidbClient) ScanReplication(limit int, startRowKey string, status string) (
+	replications []Replication, err error) {
+
+	sqltext := "select bucketname,objectname,version,operation,targetregion,targetbucket," +
+		"storageclass,status,mtime,triedtimes from replication where status=? "
+	args := []interface{}{status}
+	if startRowKey != "" {
+		s := strings.Split(startRowKey, ObjectNameSeparator)
+		bucketName := s[0]
+		objectName := s[1]
+		version, err := strconv.ParseUint(s[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		sqltext += "and (bucketname>? or (bucketname=? and objectname>?) or " +
+			"(bucketname=? and objectname=? and version>=?)) "
+		args = append(args, bucketName, bucketName, objectName, bucketName, objectName, version)
+	}
+	sqltext += "order by bucketname,objectname,version limit ?;"
+	args = append(args, limit)
+	rows, err := t.Client.Query(sqltext, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r Replication
+		var mtime string
+		err = rows.Scan(
+			&r.BucketName,
+			&r.ObjectName,
+			&r.Version,
+			&r.Operation,
+			&r.TargetRegion,
+			&r.TargetBucket,
+			&r.StorageClass,
+			&r.Status,
+			&mtime,
+			&r.TriedTimes,
+		)
+		if err != nil {
+			return
+		}
+		r.MTime, err = time.Parse(TIME_LAYOUT_TIDB, mtime)
+		if err != nil {
+			return
+		}
+		r.GetRowkey()
+		replications = append(replications, r)
+	}
+	return replications, rows.Err()
+}
+
+func (t *TidbClient) UpdateReplication(replication *Replication) error {
+	sql, args := replication.GetUpdateSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
+
+func (t *TidbClient) RemoveReplication(replication *Replication) error {
+	sql, args := replication.GetRemoveSql(MySQLDialect)
+	_, err := t.Client.Exec(sql, args...)
+	return err
+}
@@ -7,14 +7,16 @@ import (
 	"github.com/journeymidnight/yig/redis"
 )
 
+const clustersRowKey = "cephClusters"
+
 func (m *Meta) GetClusters() (cluster []Cluster, err error) {
-	rowKey := "cephClusters"
+	rowKey := clustersRowKey
 	getCluster := func() (c interface{}, err error) {
 		helper.Logger.Info("GetClusters CacheMiss")
 		return m.Client.GetClusters()
 	}
 	unmarshaller := func(in []byte) (interface{}, error) {
-		var cluster Cluster
+		var cluster []Cluster
 		err := helper.MsgPackUnMarshal(in, &cluster)
 		return cluster, err
 	}
@@ -29,3 +31,32 @@ func (m *Meta) GetClusters() (cluster []Cluster, err error) {
 	}
 	return cluster, nil
 }
+
+// SetClusterStatus changes status of cluster `fsid`, and invalidates cached
+// clusters so every yig instance picks clusters with the new status at its
+// next topology refresh
+func (m *Meta) SetClusterStatus(fsid string, status ClusterStatus) error {
+	err := m.Client.SetClusterStatus(fsid, status)
+	if err != nil {
+		return err
+	}
+	m.RemoveClustersCache()
+	return nil
+}
+
+// PutCluster adds a pool of cluster or changes its weight, a pool with
+// weight 0 is never picked for new data
+func (m *Meta) PutCluster(cluster Cluster) error {
+	err := m.Client.PutCluster(cluster)
+	if err != nil {
+		return err
+	}
+	m.RemoveClustersCache()
+	return nil
+}
+
+// RemoveClustersCache invalidates cached clusters, cache is shared by all
+// yig instances so they all read clusters from meta store next time
+func (m *Meta) RemoveClustersCache() {
+	m.Cache.Remove(redis.ClusterTable, clustersRowKey)
+}
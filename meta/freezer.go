@@ -2,6 +2,8 @@ package meta
 
 import (
 	"database/sql"
+	"time"
+
 	"github.com/journeymidnight/yig/meta/types"
 )
 
@@ -21,6 +23,22 @@ func (m *Meta) UpdateFreezerDate(freezer *types.Freezer) error {
 	return m.Client.UploadFreezerDate(freezer.BucketName, freezer.Name, freezer.LifeTime)
 }
 
+func (m *Meta) ListFreezers(status types.Status, limit int) ([]types.Freezer, error) {
+	return m.Client.ListFreezers(status, limit)
+}
+
+func (m *Meta) ListExpiredFreezers(now time.Time, limit int) ([]types.Freezer, error) {
+	return m.Client.ListExpiredFreezers(now, limit)
+}
+
+func (m *Meta) UpdateFreezerStatus(freezer *types.Freezer, status types.Status) (bool, error) {
+	return m.Client.UpdateFreezerStatus(freezer, status)
+}
+
+func (m *Meta) UpdateFreezer(freezer *types.Freezer, status types.Status) error {
+	return m.Client.UpdateFreezer(freezer, status, nil)
+}
+
 func (m *Meta) DeleteFreezer(freezer *types.Freezer) (err error) {
 	var tx *sql.Tx
 	tx, err = m.Client.NewTrans()
@@ -41,6 +59,10 @@ func (m *Meta) DeleteFreezer(freezer *types.Freezer) (err error) {
 		return err
 	}
 
+	// freezer not restored yet has no data to recycle
+	if freezer.ObjectId == "" && len(freezer.Parts) == 0 {
+		return nil
+	}
 	err = m.Client.PutFreezerToGarbageCollection(freezer, tx)
 	if err != nil {
 		return err
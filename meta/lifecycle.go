@@ -20,3 +20,7 @@ func (m *Meta) RemoveBucketFromLifeCycle(bucket Bucket) error {
 func (m *Meta) ScanLifeCycle(limit int, marker string) (result ScanLifeCycleResult, err error) {
 	return m.Client.ScanLifeCycle(limit, marker)
 }
+
+func (m *Meta) ScanObjectNames(bucketName, marker string, limit int) (names []string, err error) {
+	return m.Client.ScanObjectNames(bucketName, marker, limit)
+}
@@ -3,6 +3,8 @@ package meta
 import (
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/client/memclient"
+	"github.com/journeymidnight/yig/meta/client/pgclient"
 	"github.com/journeymidnight/yig/meta/client/tidbclient"
 )
 
@@ -17,12 +19,22 @@ type Meta struct {
 
 func New(myCacheType CacheType) *Meta {
 	meta := Meta{
-		Cache: newMetaCache(myCacheType),
+		Cache:  newMetaCache(myCacheType),
+		Client: NewClient(),
 	}
-	if helper.CONFIG.MetaStore == "tidb" {
-		meta.Client = tidbclient.NewTidbClient()
-	} else {
+	return &meta
+}
+
+// NewClient connects to the meta store in config
+func NewClient() client.Client {
+	switch helper.CONFIG.MetaStore {
+	case "tidb":
+		return tidbclient.NewTidbClient()
+	case "postgres":
+		return pgclient.NewPgClient()
+	case "sqlite", "memory":
+		return memclient.NewMemClient()
+	default:
 		panic("unsupport metastore")
 	}
-	return &meta
 }
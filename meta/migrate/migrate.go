@@ -0,0 +1,191 @@
+// Package migrate upgrades and downgrades the schema of SQL meta stores.
+// Applied migrations are recorded in table `schema_version`
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/client/pgclient"
+	"github.com/journeymidnight/yig/meta/client/tidbclient"
+	. "github.com/journeymidnight/yig/meta/types"
+)
+
+var ErrUnsupportedStore = errors.New("meta store has no schema to migrate")
+
+type Migrator struct {
+	db      *sql.DB
+	store   string // key of statements in Migration
+	dialect Dialect
+}
+
+// New returns a Migrator for the database of `c`, or ErrUnsupportedStore
+// if `c` is not backed by a SQL database
+func New(c client.Client) (*Migrator, error) {
+	switch c := c.(type) {
+	case *tidbclient.TidbClient:
+		return &Migrator{db: c.Client, store: "tidb", dialect: MySQLDialect}, nil
+	case *pgclient.PgClient:
+		return &Migrator{db: c.Client, store: "postgres", dialect: PostgresDialect}, nil
+	}
+	return nil, ErrUnsupportedStore
+}
+
+// Applied returns versions of applied migrations in ascending order
+func (m *Migrator) Applied() (versions []int, err error) {
+	var currentSchema string
+	switch m.store {
+	case "tidb":
+		currentSchema = "database()"
+	case "postgres":
+		currentSchema = "current_schema()"
+	}
+	var n int
+	sqltext := "select count(*) from information_schema.tables where table_name='schema_version' and table_schema=" +
+		currentSchema + ";"
+	err = m.db.QueryRow(sqltext).Scan(&n)
+	if err != nil || n == 0 {
+		return
+	}
+	rows, err := m.db.Query("select version from schema_version order by version;")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		err = rows.Scan(&version)
+		if err != nil {
+			return
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// Version returns the latest applied migration, 0 if none is applied
+func (m *Migrator) Version() (int, error) {
+	versions, err := m.Applied()
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Check returns an error if the schema is older than LatestVersion. A newer
+// schema is accepted, since migrations only add tables and columns
+func (m *Migrator) Check() (version int, err error) {
+	version, err = m.Version()
+	if err != nil {
+		return
+	}
+	if version < LatestVersion() {
+		err = fmt.Errorf("meta schema version is %d, but %d is required, run `yig migrate up` first",
+			version, LatestVersion())
+	}
+	return
+}
+
+// Up applies migrations until schema reaches version `to`
+func (m *Migrator) Up(to int, progress func(Migration)) error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	err = m.createVersionTable()
+	if err != nil {
+		return err
+	}
+	for _, migration := range Migrations {
+		if migration.Version <= version || migration.Version > to {
+			continue
+		}
+		if progress != nil {
+			progress(migration)
+		}
+		sqltext, args := m.dialect.Bind("insert into schema_version(version,name) values(?,?);",
+			[]interface{}{migration.Version, migration.Name})
+		err = m.apply(migration.Up[m.store], sqltext, args)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts migrations until schema goes back to version `to`
+func (m *Migrator) Down(to int, progress func(Migration)) error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	for i := len(Migrations) - 1; i >= 0; i-- {
+		migration := Migrations[i]
+		if migration.Version > version || migration.Version <= to {
+			continue
+		}
+		statements, ok := migration.Down[m.store]
+		if !ok {
+			return fmt.Errorf("migration %d (%s) can't be reverted", migration.Version, migration.Name)
+		}
+		if progress != nil {
+			progress(migration)
+		}
+		sqltext, args := m.dialect.Bind("delete from schema_version where version=?;",
+			[]interface{}{migration.Version})
+		err = m.apply(statements, sqltext, args)
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) createVersionTable() error {
+	var sqltext string
+	switch m.store {
+	case "tidb":
+		sqltext = "CREATE TABLE IF NOT EXISTS `schema_version` (" +
+			"`version` int(11) NOT NULL," +
+			"`name` varchar(255) DEFAULT NULL," +
+			"`appliedtime` datetime DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`version`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin"
+	case "postgres":
+		sqltext = "CREATE TABLE IF NOT EXISTS schema_version (" +
+			"version integer NOT NULL," +
+			"name varchar(255) DEFAULT NULL," +
+			"appliedtime timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (version))"
+	}
+	_, err := m.db.Exec(sqltext)
+	return err
+}
+
+// apply runs `statements` and records the change of version in a
+// transaction. DDL commits implicitly in TiDB, so statements of migrations
+// are written to be safe when retried
+func (m *Migrator) apply(statements []string, versionSql string, versionArgs []interface{}) (err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(versionSql, versionArgs...)
+	return err
+}
@@ -0,0 +1,42 @@
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+var stores = []string{"tidb", "postgres"}
+
+func TestMigrations(t *testing.T) {
+	for i, m := range Migrations {
+		if m.Version != i+1 {
+			t.Fatal("Migration versions should start from 1 and increase by 1:", m.Version, m.Name)
+		}
+		for _, store := range stores {
+			if len(m.Up[store]) == 0 {
+				t.Fatal("Migration", m.Version, "has no statement for", store)
+			}
+			if m.Version > 1 && len(m.Down[store]) == 0 {
+				t.Fatal("Migration", m.Version, "can't be reverted for", store)
+			}
+		}
+	}
+}
+
+// schema files for fresh install should be at the latest version
+func TestSchemaFiles(t *testing.T) {
+	for _, file := range []string{"../../integrate/yig.sql", "../../integrate/yig_pg.sql"} {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal("ReadFile err:", err)
+		}
+		for _, m := range Migrations {
+			record := fmt.Sprintf("(%d,'%s')", m.Version, m.Name)
+			if !strings.Contains(string(content), record) {
+				t.Fatal(file, "doesn't record migration", record)
+			}
+		}
+	}
+}
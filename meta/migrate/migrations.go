@@ -0,0 +1,459 @@
+package migrate
+
+// Migration changes the schema of meta stores from Version-1 to Version.
+// Statements are keyed by meta store, and should be safe to run again on
+// a database which already has the change, since DDL can't be rolled back
+// in TiDB
+type Migration struct {
+	Version int
+	Name    string
+	Up      map[string][]string
+	Down    map[string][]string
+}
+
+// Migrations are applied in order, append new migrations at the end and
+// update integrate/yig.sql and integrate/yig_pg.sql as well
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		Up: map[string][]string{
+			"tidb":     tidbInitialSchema,
+			"postgres": pgInitialSchema,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "object tagging",
+		Up: map[string][]string{
+			"tidb": {
+				"ALTER TABLE `objects` ADD COLUMN IF NOT EXISTS `tagging` JSON DEFAULT NULL",
+				"ALTER TABLE `multiparts` ADD COLUMN IF NOT EXISTS `tagging` JSON DEFAULT NULL",
+			},
+			"postgres": {
+				"ALTER TABLE objects ADD COLUMN IF NOT EXISTS tagging text DEFAULT NULL",
+				"ALTER TABLE multiparts ADD COLUMN IF NOT EXISTS tagging text DEFAULT NULL",
+			},
+		},
+		Down: map[string][]string{
+			"tidb": {
+				"ALTER TABLE `objects` DROP COLUMN IF EXISTS `tagging`",
+				"ALTER TABLE `multiparts` DROP COLUMN IF EXISTS `tagging`",
+			},
+			"postgres": {
+				"ALTER TABLE objects DROP COLUMN IF EXISTS tagging",
+				"ALTER TABLE multiparts DROP COLUMN IF EXISTS tagging",
+			},
+		},
+	},
+	{
+		Version: 3,
+		Name:    "bucket tagging",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `tagging` JSON DEFAULT NULL"},
+			"postgres": {"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS tagging text DEFAULT NULL"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `tagging`"},
+			"postgres": {"ALTER TABLE buckets DROP COLUMN IF EXISTS tagging"},
+		},
+	},
+	{
+		Version: 4,
+		Name:    "bucket notification",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `notification` JSON DEFAULT NULL"},
+			"postgres": {"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS notification text DEFAULT NULL"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `notification`"},
+			"postgres": {"ALTER TABLE buckets DROP COLUMN IF EXISTS notification"},
+		},
+	},
+	{
+		Version: 5,
+		Name:    "bucket replication",
+		Up: map[string][]string{
+			"tidb": {
+				"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `replication` JSON DEFAULT NULL",
+				"ALTER TABLE `objects` ADD COLUMN IF NOT EXISTS `replicationstatus` varchar(255) DEFAULT NULL",
+				"CREATE TABLE IF NOT EXISTS `replication` (" +
+					"`bucketname` varchar(255) DEFAULT NULL," +
+					"`objectname` varchar(255) DEFAULT NULL," +
+					"`version` bigint(20) UNSIGNED DEFAULT NULL," +
+					"`operation` tinyint(1) DEFAULT 0," +
+					"`targetregion` varchar(255) DEFAULT NULL," +
+					"`targetbucket` varchar(255) DEFAULT NULL," +
+					"`storageclass` varchar(255) DEFAULT NULL," +
+					"`status` varchar(255) DEFAULT NULL," +
+					"`mtime` datetime DEFAULT NULL," +
+					"`triedtimes` int(11) DEFAULT NULL," +
+					"UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+			},
+			"postgres": {
+				"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS replication text DEFAULT NULL",
+				"ALTER TABLE objects ADD COLUMN IF NOT EXISTS replicationstatus varchar(255) DEFAULT NULL",
+				"CREATE TABLE IF NOT EXISTS replication (" +
+					"bucketname varchar(255) DEFAULT NULL," +
+					"objectname varchar(255) DEFAULT NULL," +
+					"version numeric(20) DEFAULT NULL," +
+					"operation smallint DEFAULT 0," +
+					"targetregion varchar(255) DEFAULT NULL," +
+					"targetbucket varchar(255) DEFAULT NULL," +
+					"storageclass varchar(255) DEFAULT NULL," +
+					"status varchar(255) DEFAULT NULL," +
+					"mtime timestamp DEFAULT NULL," +
+					"triedtimes integer DEFAULT NULL," +
+					"UNIQUE (bucketname, objectname, version))",
+			},
+		},
+		Down: map[string][]string{
+			"tidb": {
+				"DROP TABLE IF EXISTS `replication`",
+				"ALTER TABLE `objects` DROP COLUMN IF EXISTS `replicationstatus`",
+				"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `replication`",
+			},
+			"postgres": {
+				"DROP TABLE IF EXISTS replication",
+				"ALTER TABLE objects DROP COLUMN IF EXISTS replicationstatus",
+				"ALTER TABLE buckets DROP COLUMN IF EXISTS replication",
+			},
+		},
+	},
+	{
+		Version: 6,
+		Name:    "object lock",
+		Up: map[string][]string{
+			"tidb": {
+				"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `objectlock` JSON DEFAULT NULL",
+				"ALTER TABLE `objects` ADD COLUMN IF NOT EXISTS `objectlockmode` varchar(255) DEFAULT NULL",
+				"ALTER TABLE `objects` ADD COLUMN IF NOT EXISTS `retainuntildate` datetime DEFAULT NULL",
+				"ALTER TABLE `objects` ADD COLUMN IF NOT EXISTS `legalhold` tinyint(1) DEFAULT 0",
+			},
+			"postgres": {
+				"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS objectlock text DEFAULT NULL",
+				"ALTER TABLE objects ADD COLUMN IF NOT EXISTS objectlockmode varchar(255) DEFAULT NULL",
+				"ALTER TABLE objects ADD COLUMN IF NOT EXISTS retainuntildate timestamp DEFAULT NULL",
+				"ALTER TABLE objects ADD COLUMN IF NOT EXISTS legalhold boolean DEFAULT false",
+			},
+		},
+		Down: map[string][]string{
+			"tidb": {
+				"ALTER TABLE `objects` DROP COLUMN IF EXISTS `legalhold`",
+				"ALTER TABLE `objects` DROP COLUMN IF EXISTS `retainuntildate`",
+				"ALTER TABLE `objects` DROP COLUMN IF EXISTS `objectlockmode`",
+				"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `objectlock`",
+			},
+			"postgres": {
+				"ALTER TABLE objects DROP COLUMN IF EXISTS legalhold",
+				"ALTER TABLE objects DROP COLUMN IF EXISTS retainuntildate",
+				"ALTER TABLE objects DROP COLUMN IF EXISTS objectlockmode",
+				"ALTER TABLE buckets DROP COLUMN IF EXISTS objectlock",
+			},
+		},
+	},
+	{
+		Version: 7,
+		Name:    "restore tier",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `restoreobjects` ADD COLUMN IF NOT EXISTS `tier` tinyint(1) DEFAULT '1'"},
+			"postgres": {"ALTER TABLE restoreobjects ADD COLUMN IF NOT EXISTS tier smallint DEFAULT 1"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `restoreobjects` DROP COLUMN IF EXISTS `tier`"},
+			"postgres": {"ALTER TABLE restoreobjects DROP COLUMN IF EXISTS tier"},
+		},
+	},
+	{
+		Version: 8,
+		Name:    "cluster status",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `cluster` ADD COLUMN IF NOT EXISTS `status` tinyint(1) NOT NULL DEFAULT 0"},
+			"postgres": {"ALTER TABLE cluster ADD COLUMN IF NOT EXISTS status smallint NOT NULL DEFAULT 0"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `cluster` DROP COLUMN IF EXISTS `status`"},
+			"postgres": {"ALTER TABLE cluster DROP COLUMN IF EXISTS status"},
+		},
+	},
+	{
+		Version: 9,
+		Name:    "bucket placement",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `placement` JSON DEFAULT NULL"},
+			"postgres": {"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS placement text DEFAULT NULL"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `placement`"},
+			"postgres": {"ALTER TABLE buckets DROP COLUMN IF EXISTS placement"},
+		},
+	},
+	{
+		Version: 10,
+		Name:    "bucket public access block",
+		Up: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` ADD COLUMN IF NOT EXISTS `publicaccessblock` JSON DEFAULT NULL"},
+			"postgres": {"ALTER TABLE buckets ADD COLUMN IF NOT EXISTS publicaccessblock text DEFAULT NULL"},
+		},
+		Down: map[string][]string{
+			"tidb":     {"ALTER TABLE `buckets` DROP COLUMN IF EXISTS `publicaccessblock`"},
+			"postgres": {"ALTER TABLE buckets DROP COLUMN IF EXISTS publicaccessblock"},
+		},
+	},
+}
+
+// LatestVersion is the schema version this code expects
+func LatestVersion() int {
+	return Migrations[len(Migrations)-1].Version
+}
+
+// tables of the initial schema, the same as integrate/yig.sql before
+// schema versions were introduced
+var tidbInitialSchema = []string{
+	"CREATE TABLE IF NOT EXISTS `buckets` (" +
+		"`bucketname` varchar(255) NOT NULL DEFAULT ''," +
+		"`acl` JSON DEFAULT NULL," +
+		"`cors` JSON DEFAULT NULL," +
+		"`logging` JSON NOT NULL DEFAULT ''," +
+		"`lc` JSON DEFAULT NULL," +
+		"`uid` varchar(255) DEFAULT NULL," +
+		"`policy` JSON DEFAULT NULL," +
+		"`website` JSON DEFAULT NULL," +
+		"`encryption` JSON DEFAULT NULL," +
+		"`createtime` datetime DEFAULT NULL," +
+		"`usages` bigint(20) DEFAULT NULL," +
+		"`versioning` varchar(255) DEFAULT NULL," +
+		"PRIMARY KEY (`bucketname`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `cluster` (" +
+		"`fsid` varchar(255) DEFAULT NULL," +
+		"`pool` varchar(255) DEFAULT NULL," +
+		"`weight` int(11) DEFAULT NULL," +
+		"UNIQUE KEY `rowkey` (`fsid`,`pool`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `gc` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`objectname` varchar(255) DEFAULT NULL," +
+		"`version` bigint(20) UNSIGNED DEFAULT NULL," +
+		"`location` varchar(255) DEFAULT NULL," +
+		"`pool` varchar(255) DEFAULT NULL," +
+		"`objectid` varchar(255) DEFAULT NULL," +
+		"`status` varchar(255) DEFAULT NULL," +
+		"`mtime` datetime DEFAULT NULL," +
+		"`part` tinyint(1) DEFAULT NULL," +
+		"`triedtimes` int(11) DEFAULT NULL," +
+		"UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `gcpart` (" + tidbPartColumns +
+		"`version` bigint(20) UNSIGNED DEFAULT NULL," +
+		"KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `multipartpart` (" + tidbPartColumns +
+		"`uploadtime` bigint(20) UNSIGNED DEFAULT NULL," +
+		"KEY `rowkey` (`bucketname`,`objectname`,`uploadtime`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `multiparts` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`objectname` varchar(255) DEFAULT NULL," +
+		"`uploadtime` bigint(20) UNSIGNED DEFAULT NULL," +
+		"`initiatorid` varchar(255) DEFAULT NULL," +
+		"`ownerid` varchar(255) DEFAULT NULL," +
+		"`contenttype` varchar(255) DEFAULT NULL," +
+		"`location` varchar(255) DEFAULT NULL," +
+		"`pool` varchar(255) DEFAULT NULL," +
+		"`acl` JSON DEFAULT NULL," +
+		"`sserequest` JSON DEFAULT NULL," +
+		"`encryption` blob DEFAULT NULL," +
+		"`cipher` blob DEFAULT NULL," +
+		"`attrs` JSON DEFAULT NULL," +
+		"`storageclass` tinyint(1) DEFAULT 0," +
+		"UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`uploadtime`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `objectpart` (" + tidbPartColumns +
+		"`version` varchar(255) DEFAULT NULL," +
+		"KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `objects` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`name` varchar(255) DEFAULT NULL," +
+		"`version` bigint(20) UNSIGNED DEFAULT NULL," +
+		"`location` varchar(255) DEFAULT NULL," +
+		"`pool` varchar(255) DEFAULT NULL," +
+		"`ownerid` varchar(255) DEFAULT NULL," +
+		"`size` bigint(20) DEFAULT NULL," +
+		"`objectid` varchar(255) DEFAULT NULL," +
+		"`lastmodifiedtime` datetime DEFAULT NULL," +
+		"`etag` varchar(255) DEFAULT NULL," +
+		"`contenttype` varchar(255) DEFAULT NULL," +
+		"`customattributes` JSON DEFAULT NULL," +
+		"`acl` JSON DEFAULT NULL," +
+		"`nullversion` tinyint(1) DEFAULT NULL," +
+		"`deletemarker` tinyint(1) DEFAULT NULL," +
+		"`ssetype` varchar(255) DEFAULT NULL," +
+		"`encryptionkey` blob DEFAULT NULL," +
+		"`initializationvector` blob DEFAULT NULL," +
+		"`type` tinyint(1) DEFAULT 0," +
+		"`storageclass` tinyint(1) DEFAULT 0," +
+		"UNIQUE KEY `rowkey` (`bucketname`,`name`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `restoreobjectpart` (" + tidbPartColumns +
+		"`version` bigint(20) unsigned DEFAULT NULL," +
+		"KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `restoreobjects` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`objectname` varchar(255) DEFAULT NULL," +
+		"`version` bigint(20) unsigned DEFAULT NULL," +
+		"`status` tinyint(1) DEFAULT '0'," +
+		"`lifetime` tinyint(2) DEFAULT '1'," +
+		"`lastmodifiedtime` datetime DEFAULT NULL," +
+		"`location` varchar(255) DEFAULT NULL," +
+		"`pool` varchar(255) DEFAULT NULL," +
+		"`ownerid` varchar(255) DEFAULT NULL," +
+		"`size` bigint(20) DEFAULT NULL," +
+		"`objectid` varchar(255) DEFAULT NULL," +
+		"`etag` varchar(255) DEFAULT NULL," +
+		"UNIQUE KEY `rowkey` (`bucketname`,`objectname`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `objmap` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`objectname` varchar(255) DEFAULT NULL," +
+		"`nullvernum` bigint(20) DEFAULT NULL," +
+		"UNIQUE KEY `objmap` (`bucketname`,`objectname`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `users` (" +
+		"`userid` varchar(255) DEFAULT NULL," +
+		"`bucketname` varchar(255) DEFAULT NULL" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	"CREATE TABLE IF NOT EXISTS `lifecycle` (" +
+		"`bucketname` varchar(255) DEFAULT NULL," +
+		"`status` varchar(255) DEFAULT NULL" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+}
+
+const tidbPartColumns = "`partnumber` int(11) DEFAULT NULL," +
+	"`size` bigint(20) DEFAULT NULL," +
+	"`objectid` varchar(255) DEFAULT NULL," +
+	"`offset` bigint(20) DEFAULT NULL," +
+	"`etag` varchar(255) DEFAULT NULL," +
+	"`lastmodified` datetime DEFAULT NULL," +
+	"`initializationvector` blob DEFAULT NULL," +
+	"`bucketname` varchar(255) DEFAULT NULL," +
+	"`objectname` varchar(255) DEFAULT NULL,"
+
+// see integrate/yig_pg.sql for differences from tidb
+var pgInitialSchema = []string{
+	"CREATE TABLE IF NOT EXISTS buckets (" +
+		"bucketname varchar(255) NOT NULL DEFAULT ''," +
+		"acl text DEFAULT NULL," +
+		"cors text DEFAULT NULL," +
+		"logging text NOT NULL DEFAULT ''," +
+		"lc text DEFAULT NULL," +
+		"uid varchar(255) DEFAULT NULL," +
+		"policy text DEFAULT NULL," +
+		"website text DEFAULT NULL," +
+		"encryption text DEFAULT NULL," +
+		"createtime timestamp DEFAULT NULL," +
+		"usages bigint DEFAULT NULL," +
+		"versioning varchar(255) DEFAULT NULL," +
+		"PRIMARY KEY (bucketname))",
+	"CREATE TABLE IF NOT EXISTS cluster (" +
+		"fsid varchar(255) DEFAULT NULL," +
+		"pool varchar(255) DEFAULT NULL," +
+		"weight integer DEFAULT NULL," +
+		"UNIQUE (fsid, pool))",
+	"CREATE TABLE IF NOT EXISTS gc (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"objectname varchar(255) DEFAULT NULL," +
+		"version numeric(20) DEFAULT NULL," +
+		"location varchar(255) DEFAULT NULL," +
+		"pool varchar(255) DEFAULT NULL," +
+		"objectid varchar(255) DEFAULT NULL," +
+		"status varchar(255) DEFAULT NULL," +
+		"mtime timestamp DEFAULT NULL," +
+		"part boolean DEFAULT NULL," +
+		"triedtimes integer DEFAULT NULL," +
+		"UNIQUE (bucketname, objectname, version))",
+	"CREATE TABLE IF NOT EXISTS gcpart (" + pgPartColumns + "version numeric(20) DEFAULT NULL)",
+	"CREATE INDEX IF NOT EXISTS gcpart_rowkey ON gcpart (bucketname, objectname, version)",
+	"CREATE TABLE IF NOT EXISTS multipartpart (" + pgPartColumns + "uploadtime numeric(20) DEFAULT NULL)",
+	"CREATE INDEX IF NOT EXISTS multipartpart_rowkey ON multipartpart (bucketname, objectname, uploadtime)",
+	"CREATE TABLE IF NOT EXISTS multiparts (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"objectname varchar(255) DEFAULT NULL," +
+		"uploadtime numeric(20) DEFAULT NULL," +
+		"initiatorid varchar(255) DEFAULT NULL," +
+		"ownerid varchar(255) DEFAULT NULL," +
+		"contenttype varchar(255) DEFAULT NULL," +
+		"location varchar(255) DEFAULT NULL," +
+		"pool varchar(255) DEFAULT NULL," +
+		"acl text DEFAULT NULL," +
+		"sserequest text DEFAULT NULL," +
+		"encryption bytea DEFAULT NULL," +
+		"cipher bytea DEFAULT NULL," +
+		"attrs text DEFAULT NULL," +
+		"storageclass smallint DEFAULT 0," +
+		"UNIQUE (bucketname, objectname, uploadtime))",
+	"CREATE TABLE IF NOT EXISTS objectpart (" + pgPartColumns + "version numeric(20) DEFAULT NULL)",
+	"CREATE INDEX IF NOT EXISTS objectpart_rowkey ON objectpart (bucketname, objectname, version)",
+	"CREATE TABLE IF NOT EXISTS objects (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"name varchar(255) DEFAULT NULL," +
+		"version numeric(20) DEFAULT NULL," +
+		"location varchar(255) DEFAULT NULL," +
+		"pool varchar(255) DEFAULT NULL," +
+		"ownerid varchar(255) DEFAULT NULL," +
+		"size bigint DEFAULT NULL," +
+		"objectid varchar(255) DEFAULT NULL," +
+		"lastmodifiedtime timestamp DEFAULT NULL," +
+		"etag varchar(255) DEFAULT NULL," +
+		"contenttype varchar(255) DEFAULT NULL," +
+		"customattributes text DEFAULT NULL," +
+		"acl text DEFAULT NULL," +
+		"nullversion boolean DEFAULT NULL," +
+		"deletemarker boolean DEFAULT NULL," +
+		"ssetype varchar(255) DEFAULT NULL," +
+		"encryptionkey bytea DEFAULT NULL," +
+		"initializationvector bytea DEFAULT NULL," +
+		"type smallint DEFAULT 0," +
+		"storageclass smallint DEFAULT 0," +
+		"UNIQUE (bucketname, name, version))",
+	"CREATE TABLE IF NOT EXISTS restoreobjectpart (" + pgPartColumns + "version numeric(20) DEFAULT NULL)",
+	"CREATE INDEX IF NOT EXISTS restoreobjectpart_rowkey ON restoreobjectpart (bucketname, objectname, version)",
+	"CREATE TABLE IF NOT EXISTS restoreobjects (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"objectname varchar(255) DEFAULT NULL," +
+		"version numeric(20) DEFAULT NULL," +
+		"status smallint DEFAULT 0," +
+		"lifetime smallint DEFAULT 1," +
+		"lastmodifiedtime timestamp DEFAULT NULL," +
+		"location varchar(255) DEFAULT NULL," +
+		"pool varchar(255) DEFAULT NULL," +
+		"ownerid varchar(255) DEFAULT NULL," +
+		"size bigint DEFAULT NULL," +
+		"objectid varchar(255) DEFAULT NULL," +
+		"etag varchar(255) DEFAULT NULL," +
+		"UNIQUE (bucketname, objectname, version))",
+	"CREATE TABLE IF NOT EXISTS objmap (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"objectname varchar(255) DEFAULT NULL," +
+		"nullvernum numeric(20) DEFAULT NULL," +
+		"UNIQUE (bucketname, objectname))",
+	"CREATE TABLE IF NOT EXISTS users (" +
+		"userid varchar(255) DEFAULT NULL," +
+		"bucketname varchar(255) DEFAULT NULL)",
+	"CREATE TABLE IF NOT EXISTS lifecycle (" +
+		"bucketname varchar(255) DEFAULT NULL," +
+		"status varchar(255) DEFAULT NULL)",
+}
+
+const pgPartColumns = "partnumber integer DEFAULT NULL," +
+	"size bigint DEFAULT NULL," +
+	"objectid varchar(255) DEFAULT NULL," +
+	"\"offset\" bigint DEFAULT NULL," +
+	"etag varchar(255) DEFAULT NULL," +
+	"lastmodified timestamp DEFAULT NULL," +
+	"initializationvector bytea DEFAULT NULL," +
+	"bucketname varchar(255) DEFAULT NULL," +
+	"objectname varchar(255) DEFAULT NULL,"
@@ -2,6 +2,8 @@ package meta
 
 import (
 	"database/sql"
+	"time"
+
 	. "github.com/journeymidnight/yig/meta/types"
 )
 
@@ -9,6 +11,17 @@ func (m *Meta) GetMultipart(bucketName, objectName, uploadId string) (multipart
 	return m.Client.GetMultipart(bucketName, objectName, uploadId)
 }
 
+func garbageObjectFromMultipart(multipart Multipart) *Object {
+	return &Object{
+		BucketName:       multipart.BucketName,
+		Name:             multipart.ObjectName,
+		LastModifiedTime: multipart.InitialTime,
+		Location:         multipart.Metadata.Location,
+		Pool:             multipart.Metadata.Pool,
+		Parts:            multipart.Parts,
+	}
+}
+
 func (m *Meta) DeleteMultipart(multipart Multipart) (err error) {
 	tx, err := m.Client.NewTrans()
 	if err != nil {
@@ -23,6 +36,11 @@ func (m *Meta) DeleteMultipart(multipart Multipart) (err error) {
 	if err != nil {
 		return
 	}
+	// uploaded parts are removed from Ceph by gc
+	err = m.Client.PutObjectToGarbageCollection(garbageObjectFromMultipart(multipart), tx)
+	if err != nil {
+		return
+	}
 	var removedSize int64 = 0
 	for _, p := range multipart.Parts {
 		removedSize += p.Size
@@ -83,3 +101,9 @@ func (m *Meta) RenameObjectPart(object *Object, sourceObject string) (err error)
 	err = m.Client.CommitTrans(tx)
 	return err
 }
+
+// ListStaleMultipartUploads lists at most `limit` multipart uploads across
+// all buckets initiated before `initiatedBefore`, oldest first
+func (m *Meta) ListStaleMultipartUploads(initiatedBefore time.Time, limit int) (uploads []Multipart, err error) {
+	return m.Client.ListStaleMultipartUploads(initiatedBefore, limit)
+}
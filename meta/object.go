@@ -130,6 +130,21 @@ func (m *Meta) UpdateObjectAcl(object *Object) error {
 	return err
 }
 
+func (m *Meta) UpdateObjectTagging(object *Object) error {
+	err := m.Client.UpdateObjectTagging(object)
+	return err
+}
+
+func (m *Meta) UpdateObjectReplicationStatus(object *Object) error {
+	err := m.Client.UpdateObjectReplicationStatus(object)
+	return err
+}
+
+func (m *Meta) UpdateObjectLock(object *Object) error {
+	err := m.Client.UpdateObjectLock(object)
+	return err
+}
+
 func (m *Meta) UpdateObjectAttrs(object *Object) error {
 	err := m.Client.UpdateObjectAttrs(object)
 	return err
@@ -253,3 +268,33 @@ func (m *Meta) AppendObject(object *Object, isExist bool) error {
 	}
 	return m.Client.CommitTrans(tx)
 }
+
+// MoveObject replaces data location and storage class of `object` if it still
+// refers to data of `sourceObject`, the data of `sourceObject` is moved into gc
+// in the same transaction, unless it stays in use by `object`.
+// Returns false if the object has been changed or deleted
+func (m *Meta) MoveObject(object, sourceObject *Object) (moved bool, err error) {
+	var tx *sql.Tx
+	tx, err = m.Client.NewTrans()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err == nil && moved {
+			err = m.Client.CommitTrans(tx)
+		}
+		if err != nil || !moved {
+			m.Client.AbortTrans(tx)
+		}
+	}()
+
+	moved, err = m.Client.UpdateObjectLocation(object, sourceObject, tx)
+	if err != nil || !moved {
+		return false, err
+	}
+	if object.Location == sourceObject.Location && object.Pool == sourceObject.Pool &&
+		object.ObjectId == sourceObject.ObjectId {
+		return true, nil
+	}
+	return true, m.Client.PutObjectToGarbageCollection(sourceObject, tx)
+}
@@ -0,0 +1,20 @@
+package meta
+
+import . "github.com/journeymidnight/yig/meta/types"
+
+// Insert an entry to `replication` table
+func (m *Meta) PutObjectToReplication(replication *Replication) error {
+	return m.Client.PutObjectToReplication(replication)
+}
+
+func (m *Meta) ScanReplication(limit int, startRowKey string, status string) ([]Replication, error) {
+	return m.Client.ScanReplication(limit, startRowKey, status)
+}
+
+func (m *Meta) UpdateReplication(replication *Replication) error {
+	return m.Client.UpdateReplication(replication)
+}
+
+func (m *Meta) RemoveReplication(replication *Replication) error {
+	return m.Client.RemoveReplication(replication)
+}
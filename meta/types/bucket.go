@@ -19,17 +19,23 @@ type Bucket struct {
 	Name string
 	// Date and time when the bucket was created,
 	// should be serialized into format "2006-01-02T15:04:05.000Z"
-	CreateTime    time.Time
-	OwnerId       string
-	CORS          datatype.Cors
-	ACL           datatype.Acl
-	BucketLogging datatype.BucketLoggingStatus
-	Lifecycle     datatype.Lifecycle
-	Policy        policy.Policy
-	Website       datatype.WebsiteConfiguration
-	Encryption    datatype.EncryptionConfiguration
-	Versioning    string // actually enum: Disabled/Enabled/Suspended
-	Usage         int64
+	CreateTime        time.Time
+	OwnerId           string
+	CORS              datatype.Cors
+	ACL               datatype.Acl
+	BucketLogging     datatype.BucketLoggingStatus
+	Lifecycle         datatype.Lifecycle
+	Policy            policy.Policy
+	Website           datatype.WebsiteConfiguration
+	Encryption        datatype.EncryptionConfiguration
+	Tagging           map[string]string
+	Notification      datatype.NotificationConfiguration
+	Replication       datatype.ReplicationConfiguration
+	ObjectLock        datatype.ObjectLockConfiguration
+	Placement         Placement
+	PublicAccessBlock datatype.PublicAccessBlockConfiguration
+	Versioning        string // actually enum: Disabled/Enabled/Suspended
+	Usage             int64
 }
 
 func (b *Bucket) String() (s string) {
@@ -43,13 +49,19 @@ func (b *Bucket) String() (s string) {
 	s += "Policy: " + fmt.Sprintf("%+v", b.Policy) + "\t"
 	s += "Website: " + fmt.Sprintf("%+v", b.Website) + "\t"
 	s += "Encryption" + fmt.Sprintf("%+v", b.Encryption) + "\t"
+	s += "Tagging: " + fmt.Sprintf("%+v", b.Tagging) + "\t"
+	s += "Notification: " + fmt.Sprintf("%+v", b.Notification) + "\t"
+	s += "Replication: " + fmt.Sprintf("%+v", b.Replication) + "\t"
+	s += "ObjectLock: " + fmt.Sprintf("%+v", b.ObjectLock) + "\t"
+	s += "Placement: " + fmt.Sprintf("%+v", b.Placement) + "\t"
+	s += "PublicAccessBlock: " + fmt.Sprintf("%+v", b.PublicAccessBlock) + "\t"
 	s += "Version: " + b.Versioning + "\t"
 	s += "Usage: " + humanize.Bytes(uint64(b.Usage)) + "\t"
 	return
 }
 
-//Tidb related function
-func (b Bucket) GetUpdateSql() (string, []interface{}) {
+//SQL related function, see Dialect
+func (b Bucket) GetUpdateSql(d Dialect) (string, []interface{}) {
 	acl, _ := json.Marshal(b.ACL)
 	cors, _ := json.Marshal(b.CORS)
 	logging, _ := json.Marshal(b.BucketLogging)
@@ -57,12 +69,18 @@ func (b Bucket) GetUpdateSql() (string, []interface{}) {
 	bucket_policy, _ := json.Marshal(b.Policy)
 	website, _ := json.Marshal(b.Website)
 	encryption, _ := json.Marshal(b.Encryption)
-	sql := "update buckets set bucketname=?,acl=?,policy=?,cors=?,logging=?,lc=?,website=?,encryption=?,uid=?,versioning=? where bucketname=?"
-	args := []interface{}{b.Name, acl, bucket_policy, cors, logging, lc, website, encryption, b.OwnerId, b.Versioning, b.Name}
-	return sql, args
+	tagging, _ := json.Marshal(b.Tagging)
+	notification, _ := json.Marshal(b.Notification)
+	replication, _ := json.Marshal(b.Replication)
+	objectLock, _ := json.Marshal(b.ObjectLock)
+	placement, _ := json.Marshal(b.Placement)
+	publicAccessBlock, _ := json.Marshal(b.PublicAccessBlock)
+	sql := "update buckets set bucketname=?,acl=?,policy=?,cors=?,logging=?,lc=?,website=?,encryption=?,tagging=?,notification=?,replication=?,objectlock=?,placement=?,publicaccessblock=?,uid=?,versioning=? where bucketname=?"
+	args := []interface{}{b.Name, string(acl), string(bucket_policy), string(cors), string(logging), string(lc), string(website), string(encryption), string(tagging), string(notification), string(replication), string(objectLock), string(placement), string(publicAccessBlock), b.OwnerId, b.Versioning, b.Name}
+	return d.Bind(sql, args)
 }
 
-func (b Bucket) GetCreateSql() (string, []interface{}) {
+func (b Bucket) GetCreateSql(d Dialect) (string, []interface{}) {
 	acl, _ := json.Marshal(b.ACL)
 	cors, _ := json.Marshal(b.CORS)
 	logging, _ := json.Marshal(b.BucketLogging)
@@ -70,9 +88,15 @@ func (b Bucket) GetCreateSql() (string, []interface{}) {
 	bucket_policy, _ := json.Marshal(b.Policy)
 	website, _ := json.Marshal(b.Website)
 	encryption, _ := json.Marshal(b.Encryption)
+	tagging, _ := json.Marshal(b.Tagging)
+	notification, _ := json.Marshal(b.Notification)
+	replication, _ := json.Marshal(b.Replication)
+	objectLock, _ := json.Marshal(b.ObjectLock)
+	placement, _ := json.Marshal(b.Placement)
+	publicAccessBlock, _ := json.Marshal(b.PublicAccessBlock)
 	createTime := b.CreateTime.Format(TIME_LAYOUT_TIDB)
-	sql := "insert into buckets(bucketname,acl,cors,logging,lc,uid,policy,website,encryption,createtime,usages,versioning) " +
-		"values(?,?,?,?,?,?,?,?,?,?,?,?);"
-	args := []interface{}{b.Name, acl, cors, logging, lc, b.OwnerId, bucket_policy, website, encryption, createTime, b.Usage, b.Versioning}
-	return sql, args
+	sql := "insert into buckets(bucketname,acl,cors,logging,lc,uid,policy,website,encryption,tagging,notification,replication,objectlock,placement,publicaccessblock,createtime,usages,versioning) " +
+		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	args := []interface{}{b.Name, string(acl), string(cors), string(logging), string(lc), b.OwnerId, string(bucket_policy), string(website), string(encryption), string(tagging), string(notification), string(replication), string(objectLock), string(placement), string(publicAccessBlock), createTime, b.Usage, b.Versioning}
+	return d.Bind(sql, args)
 }
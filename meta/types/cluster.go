@@ -1,7 +1,28 @@
 package types
 
+type ClusterStatus uint8
+
+const (
+	// new objects could be written into active clusters
+	ClusterStatusActive ClusterStatus = iota
+	// no new objects are written into draining clusters, and existing
+	// objects are moved out by yig_drain
+	ClusterStatusDraining
+)
+
+func (s ClusterStatus) String() string {
+	switch s {
+	case ClusterStatusActive:
+		return "active"
+	case ClusterStatusDraining:
+		return "draining"
+	}
+	return "unknown"
+}
+
 type Cluster struct {
 	Fsid   string
 	Pool   string
 	Weight int
+	Status ClusterStatus
 }
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect adapts SQL built by types to the flavor of a database.
+// SQL is written in MySQL syntax with `?` placeholders, which works with
+// TiDB as is
+type Dialect interface {
+	// Bind rewrites placeholders in `sql` and converts arguments the
+	// database doesn't accept
+	Bind(sql string, args []interface{}) (string, []interface{})
+	// InsertIgnore turns an "insert into" statement into one which skips
+	// rows with duplicated keys
+	InsertIgnore(sql string) string
+	// Quote quotes column names which are keywords of the database
+	Quote(column string) string
+}
+
+var (
+	MySQLDialect    Dialect = mysqlDialect{}
+	PostgresDialect Dialect = postgresDialect{}
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Bind(sql string, args []interface{}) (string, []interface{}) {
+	return sql, args
+}
+
+func (mysqlDialect) InsertIgnore(sql string) string {
+	return strings.Replace(sql, "insert into", "insert ignore into", 1)
+}
+
+func (mysqlDialect) Quote(column string) string {
+	return "`" + column + "`"
+}
+
+type postgresDialect struct{}
+
+// Bind replaces `?` with `$1`, `$2`..., and passes uint64 arguments as
+// strings since versions are larger than bigint and stored in numeric columns
+func (postgresDialect) Bind(sql string, args []interface{}) (string, []interface{}) {
+	var b strings.Builder
+	var n int
+	var quoted bool
+	for _, c := range sql {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n += 1
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	bound := make([]interface{}, len(args))
+	for i, arg := range args {
+		if v, ok := arg.(uint64); ok {
+			bound[i] = strconv.FormatUint(v, 10)
+		} else {
+			bound[i] = arg
+		}
+	}
+	return b.String(), bound
+}
+
+func (postgresDialect) InsertIgnore(sql string) string {
+	return strings.TrimSuffix(sql, ";") + " on conflict do nothing"
+}
+
+func (postgresDialect) Quote(column string) string {
+	return `"` + column + `"`
+}
@@ -0,0 +1,25 @@
+package types
+
+import "testing"
+
+func TestPostgresBind(t *testing.T) {
+	sql, args := PostgresDialect.Bind(
+		"select * from objects where name=? and etag<>'?' and version=?",
+		[]interface{}{"a", uint64(18446744073709551615)})
+	if sql != "select * from objects where name=$1 and etag<>'?' and version=$2" {
+		t.Fatal("Bind sql:", sql)
+	}
+	if args[0] != "a" || args[1] != "18446744073709551615" {
+		t.Fatal("Bind args:", args)
+	}
+}
+
+func TestInsertIgnore(t *testing.T) {
+	sql := "insert into gc(bucketname) values(?);"
+	if s := MySQLDialect.InsertIgnore(sql); s != "insert ignore into gc(bucketname) values(?);" {
+		t.Fatal("MySQL InsertIgnore:", s)
+	}
+	if s := PostgresDialect.InsertIgnore(sql); s != "insert into gc(bucketname) values(?) on conflict do nothing" {
+		t.Fatal("Postgres InsertIgnore:", s)
+	}
+}
@@ -1,6 +1,49 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	. "github.com/journeymidnight/yig/error"
+)
+
+// RestoreTier decides the order in which restore requests are processed,
+// requests with lower tier value are restored first
+type RestoreTier uint8
+
+const (
+	RestoreTierExpedited RestoreTier = iota
+	RestoreTierStandard
+	RestoreTierBulk
+)
+
+var (
+	RestoreTierIndexMap = map[RestoreTier]string{
+		RestoreTierExpedited: "Expedited",
+		RestoreTierStandard:  "Standard",
+		RestoreTierBulk:      "Bulk",
+	}
+
+	RestoreTierStringMap = map[string]RestoreTier{
+		"Expedited": RestoreTierExpedited,
+		"Standard":  RestoreTierStandard,
+		"Bulk":      RestoreTierBulk,
+	}
+)
+
+func (t RestoreTier) ToString() string {
+	return RestoreTierIndexMap[t]
+}
+
+// MatchRestoreTier returns Standard tier if `tier` is not specified
+func MatchRestoreTier(tier string) (RestoreTier, error) {
+	if tier == "" {
+		return RestoreTierStandard, nil
+	}
+	if index, ok := RestoreTierStringMap[tier]; ok {
+		return index, nil
+	}
+	return 0, ErrInvalidRestoreInfo
+}
 
 type Freezer struct {
 	Rowkey           []byte // Rowkey cache
@@ -18,23 +61,25 @@ type Freezer struct {
 	VersionId        string // version cache
 	Status           Status
 	LifeTime         int
+	Tier             RestoreTier
 }
 
-func (o *Freezer) GetCreateSql() (string, []interface{}) {
+func (o *Freezer) GetCreateSql(d Dialect) (string, []interface{}) {
 	// TODO Multi-version control
 	lastModifiedTime := o.LastModifiedTime.Format(TIME_LAYOUT_TIDB)
-	sql := "insert into restoreobjects(bucketname,objectname,status,lifetime,lastmodifiedtime) values(?,?,?,?,?)"
-	args := []interface{}{o.BucketName, o.Name, o.Status, o.LifeTime, lastModifiedTime}
-	return sql, args
+	sql := "insert into restoreobjects(bucketname,objectname,status,lifetime,lastmodifiedtime,tier) values(?,?,?,?,?,?)"
+	args := []interface{}{o.BucketName, o.Name, o.Status, o.LifeTime, lastModifiedTime, o.Tier}
+	return d.Bind(sql, args)
 }
 
-func (o *Freezer) GetUpdateSql(status Status) (string, []interface{}) {
+func (o *Freezer) GetUpdateSql(status Status, d Dialect) (string, []interface{}) {
 	// TODO Multi-version control
 	// version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	lastModifiedTime := o.LastModifiedTime.Format(TIME_LAYOUT_TIDB)
 	sql := "update restoreobjects set status=?,lastmodifiedtime=?,location=?,pool=?," +
-		"ownerid=?,size=?,etag=? where bucketname=? and objectname=? and status=?"
-	args := []interface{}{status, lastModifiedTime, o.Location, o.Pool, o.OwnerId, o.Size, o.Etag, o.BucketName, o.Name, o.Status}
+		"ownerid=?,size=?,objectid=?,etag=? where bucketname=? and objectname=? and status=?"
+	args := []interface{}{status, lastModifiedTime, o.Location, o.Pool, o.OwnerId, o.Size, o.ObjectId,
+		o.Etag, o.BucketName, o.Name, o.Status}
 
-	return sql, args
+	return d.Bind(sql, args)
 }
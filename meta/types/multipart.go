@@ -37,6 +37,8 @@ type MultipartMetadata struct {
 	CipherKey     []byte
 	Attrs         map[string]string
 	StorageClass  StorageClass
+	Tagging       map[string]string
+	ObjectLock    datatype.ObjectLockRequest
 }
 
 type Multipart struct {
@@ -84,23 +86,41 @@ func valuesForParts(parts map[int]*Part) (values map[string][]byte, err error) {
 	return
 }
 
-func (p *Part) GetCreateSql(bucketname, objectname, version string) (string, []interface{}) {
-	sql := "insert into objectpart(partnumber,size,objectid,offset,etag,lastmodified,initializationvector,bucketname,objectname,version) " +
+func (p *Part) GetCreateSql(bucketname, objectname, version string, d Dialect) (string, []interface{}) {
+	sql := "insert into objectpart(partnumber,size,objectid," + d.Quote("offset") + ",etag,lastmodified,initializationvector,bucketname,objectname,version) " +
 		"values(?,?,?,?,?,?,?,?,?,?)"
 	args := []interface{}{p.PartNumber, p.Size, p.ObjectId, p.Offset, p.Etag, p.LastModified, p.InitializationVector, bucketname, objectname, version}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
-func (p *Part) GetCreateGcSql(bucketname, objectname string, version uint64) (string, []interface{}) {
-	sql := "insert into gcpart(partnumber,size,objectid,offset,etag,lastmodified,initializationvector,bucketname,objectname,version) " +
+func (p *Part) GetCreateGcSql(bucketname, objectname string, version uint64, d Dialect) (string, []interface{}) {
+	sql := "insert into gcpart(partnumber,size,objectid," + d.Quote("offset") + ",etag,lastmodified,initializationvector,bucketname,objectname,version) " +
 		"values(?,?,?,?,?,?,?,?,?,?)"
 	args := []interface{}{p.PartNumber, p.Size, p.ObjectId, p.Offset, p.Etag, p.LastModified, p.InitializationVector, bucketname, objectname, version}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetUpdateObjectPartNameSql(sourceObject string) (string, []interface{}) {
+func (p *Part) GetCreateFreezerSql(bucketname, objectname string, d Dialect) (string, []interface{}) {
+	sql := "insert into restoreobjectpart(partnumber,size,objectid," + d.Quote("offset") + ",etag,lastmodified,initializationvector,bucketname,objectname) " +
+		"values(?,?,?,?,?,?,?,?,?)"
+	args := []interface{}{p.PartNumber, p.Size, p.ObjectId, p.Offset, p.Etag, p.LastModified, p.InitializationVector, bucketname, objectname}
+	return d.Bind(sql, args)
+}
+
+func (o *Object) GetUpdateObjectPartNameSql(sourceObject string, d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	sql := "update objectpart set objectname=? where bucketname=? and objectname=? and version=?"
 	args := []interface{}{o.Name, o.BucketName, sourceObject, version}
-	return sql, args
+	return d.Bind(sql, args)
+}
+
+// GetUpdateObjectIdSql replaces object id of the part, only if it's still
+// `sourceObjectId`
+func (p *Part) GetUpdateObjectIdSql(bucketname, objectname, version, sourceObjectId string,
+	d Dialect) (string, []interface{}) {
+
+	sql := "update objectpart set objectid=? where bucketname=? and objectname=? and version=? " +
+		"and partnumber=? and objectid=?"
+	args := []interface{}{p.ObjectId, bucketname, objectname, version, p.PartNumber, sourceObjectId}
+	return d.Bind(sql, args)
 }
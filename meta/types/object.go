@@ -46,6 +46,16 @@ type Object struct {
 	// ObjectType include `Normal`, `Appendable`, 'Multipart'
 	Type         ObjectType
 	StorageClass StorageClass
+	// user defined tag set, see `?tagging` sub-resource
+	Tagging map[string]string
+	// PENDING/COMPLETED/FAILED if object matches a replication rule of its bucket
+	ReplicationStatus string
+	// GOVERNANCE/COMPLIANCE if object is under retention until RetainUntilDate,
+	// see `?retention` sub-resource
+	ObjectLockMode  string
+	RetainUntilDate time.Time
+	// see `?legal-hold` sub-resource
+	LegalHold bool
 }
 
 type ObjectType int
@@ -138,76 +148,143 @@ func (o *Object) GetVersionId() string {
 	return o.VersionId
 }
 
-//Tidb related function
+// IsRetained reports whether object is under an unexpired retention
+func (o *Object) IsRetained() bool {
+	return o.ObjectLockMode != "" && time.Now().Before(o.RetainUntilDate)
+}
+
+// IsLocked reports whether object is protected from being deleted or
+// overwritten by object lock, objects in GOVERNANCE mode could be
+// removed when `bypassGovernance` is set
+func (o *Object) IsLocked(bypassGovernance bool) bool {
+	if o.LegalHold {
+		return true
+	}
+	if !o.IsRetained() {
+		return false
+	}
+	return o.ObjectLockMode == datatype.ObjectLockModeCompliance || !bypassGovernance
+}
+
+//SQL related function, see Dialect
 
-func (o *Object) GetCreateSql() (string, []interface{}) {
+func (o *Object) GetCreateSql(d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	customAttributes, _ := json.Marshal(o.CustomAttributes)
 	acl, _ := json.Marshal(o.ACL)
+	tagging, _ := json.Marshal(o.Tagging)
 	lastModifiedTime := o.LastModifiedTime.Format(TIME_LAYOUT_TIDB)
 	sql := "insert into objects(bucketname,name,version,location,pool,ownerid,size,objectid,lastmodifiedtime,etag," +
-		"contenttype,customattributes,acl,nullversion,deletemarker,ssetype,encryptionkey,initializationvector,type,storageclass) " +
-		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+		"contenttype,customattributes,acl,nullversion,deletemarker,ssetype,encryptionkey,initializationvector,type,storageclass,tagging,replicationstatus," +
+		"objectlockmode,retainuntildate,legalhold) " +
+		"values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
 	args := []interface{}{o.BucketName, o.Name, version, o.Location, o.Pool, o.OwnerId, o.Size, o.ObjectId,
-		lastModifiedTime, o.Etag, o.ContentType, customAttributes, acl, o.NullVersion, o.DeleteMarker,
-		o.SseType, o.EncryptionKey, o.InitializationVector, o.Type, o.StorageClass}
-	return sql, args
+		lastModifiedTime, o.Etag, o.ContentType, string(customAttributes), string(acl), o.NullVersion, o.DeleteMarker,
+		o.SseType, o.EncryptionKey, o.InitializationVector, o.Type, o.StorageClass, string(tagging), o.ReplicationStatus,
+		o.ObjectLockMode, o.retainUntilDateForTidb(), o.LegalHold}
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetAppendSql() (string, []interface{}) {
+// GetAppendSql updates size and modification time of appendable object, only
+// if its data is still where `o` has been appended to, not moved meanwhile
+func (o *Object) GetAppendSql(d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	lastModifiedTime := o.LastModifiedTime.Format(TIME_LAYOUT_TIDB)
-	sql := "update objects set lastmodifiedtime=?, size=?, version=? where bucketname=? and name=?"
-	args := []interface{}{lastModifiedTime, o.Size, version, o.BucketName, o.Name}
-	return sql, args
+	sql := "update objects set lastmodifiedtime=?, size=?, version=? where bucketname=? and name=? " +
+		"and location=? and pool=? and objectid=?"
+	args := []interface{}{lastModifiedTime, o.Size, version, o.BucketName, o.Name,
+		o.Location, o.Pool, o.ObjectId}
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetUpdateSql() (string, []interface{}) {
+func (o *Object) GetUpdateSql(d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	sql := "update objects set location=?,pool=?," +
 		"size=?,objectid=?,etag=?,initializationvector=?,storageclass=? where bucketname=? and name=? and version=?"
 	args := []interface{}{o.Location, o.Pool, o.Size, o.ObjectId, o.Etag, o.InitializationVector, o.StorageClass, o.BucketName, o.Name, version}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetUpdateAclSql() (string, []interface{}) {
+// GetUpdateLocationSql moves data of the object row to location and storage
+// class of `o`, only if the row still refers to data of `source`
+func (o *Object) GetUpdateLocationSql(source *Object, d Dialect) (string, []interface{}) {
+	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
+	sql := "update objects set location=?,pool=?,objectid=?,storageclass=? where bucketname=? and name=? and version=? " +
+		"and location=? and pool=? and objectid=? and size=? and storageclass=?"
+	args := []interface{}{o.Location, o.Pool, o.ObjectId, o.StorageClass, o.BucketName, o.Name, version,
+		source.Location, source.Pool, source.ObjectId, source.Size, source.StorageClass}
+	return d.Bind(sql, args)
+}
+
+func (o *Object) GetUpdateAclSql(d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	acl, _ := json.Marshal(o.ACL)
 	sql := "update objects set acl=? where bucketname=? and name=? and version=?"
-	args := []interface{}{acl, o.BucketName, o.Name, version}
-	return sql, args
+	args := []interface{}{string(acl), o.BucketName, o.Name, version}
+	return d.Bind(sql, args)
+}
+
+func (o *Object) GetUpdateTaggingSql(d Dialect) (string, []interface{}) {
+	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
+	tagging, _ := json.Marshal(o.Tagging)
+	sql := "update objects set tagging=? where bucketname=? and name=? and version=?"
+	args := []interface{}{string(tagging), o.BucketName, o.Name, version}
+	return d.Bind(sql, args)
+}
+
+func (o *Object) GetUpdateReplicationStatusSql(d Dialect) (string, []interface{}) {
+	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
+	sql := "update objects set replicationstatus=? where bucketname=? and name=? and version=?"
+	args := []interface{}{o.ReplicationStatus, o.BucketName, o.Name, version}
+	return d.Bind(sql, args)
+}
+
+func (o *Object) GetUpdateObjectLockSql(d Dialect) (string, []interface{}) {
+	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
+	sql := "update objects set objectlockmode=?,retainuntildate=?,legalhold=? where bucketname=? and name=? and version=?"
+	args := []interface{}{o.ObjectLockMode, o.retainUntilDateForTidb(), o.LegalHold, o.BucketName, o.Name, version}
+	return d.Bind(sql, args)
+}
+
+// retain until date is NULL if object has no retention
+func (o *Object) retainUntilDateForTidb() interface{} {
+	if o.RetainUntilDate.IsZero() {
+		return nil
+	}
+	return o.RetainUntilDate.UTC().Format(TIME_LAYOUT_TIDB)
 }
 
-func (o *Object) GetUpdateAttrsSql() (string, []interface{}) {
+func (o *Object) GetUpdateAttrsSql(d Dialect) (string, []interface{}) {
 	customAttributes, _ := json.Marshal(o.CustomAttributes)
 	sql := "update objects set customattributes=? where bucketname=? and name=?"
-	args := []interface{}{customAttributes, o.BucketName, o.Name}
-	return sql, args
+	args := []interface{}{string(customAttributes), o.BucketName, o.Name}
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetUpdateNameSql(sourceObject string) (string, []interface{}) {
+func (o *Object) GetUpdateNameSql(sourceObject string, d Dialect) (string, []interface{}) {
 	version := math.MaxUint64 - uint64(o.LastModifiedTime.UnixNano())
 	sql := "update objects set name=? where bucketname=? and name=? and version=?"
 	args := []interface{}{o.Name, o.BucketName, sourceObject, version}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetAddUsageSql() (string, []interface{}) {
+func (o *Object) GetAddUsageSql(d Dialect) (string, []interface{}) {
 	sql := "update buckets set usages= usages + ? where bucketname=?"
 	args := []interface{}{o.Size, o.BucketName}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
-func (o *Object) GetSubUsageSql() (string, []interface{}) {
+func (o *Object) GetSubUsageSql(d Dialect) (string, []interface{}) {
 	sql := "update buckets set usages= usages + ? where bucketname=?"
 	args := []interface{}{-o.Size, o.BucketName}
-	return sql, args
+	return d.Bind(sql, args)
 }
 
 // TODO : with Version
-func (o *Object) GetReplaceObjectMetasSql() (string, []interface{}) {
+func (o *Object) GetReplaceObjectMetasSql(d Dialect) (string, []interface{}) {
 	customAttributes, _ := json.Marshal(o.CustomAttributes)
-	sql := "update objects set contenttype=?,customattributes=?,storageclass=? where bucketname=? and name=?"
-	args := []interface{}{o.ContentType, customAttributes, o.StorageClass, o.BucketName, o.Name}
-	return sql, args
+	tagging, _ := json.Marshal(o.Tagging)
+	sql := "update objects set contenttype=?,customattributes=?,storageclass=?,tagging=? where bucketname=? and name=?"
+	args := []interface{}{o.ContentType, string(customAttributes), o.StorageClass, string(tagging), o.BucketName, o.Name}
+	return d.Bind(sql, args)
 }
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/journeymidnight/yig/backend"
+)
+
+// Placement restricts where data of new objects in a bucket is written, e.g.
+// to keep a tenant on its own cluster. Zero value places data like other
+// buckets do.
+type Placement struct {
+	// fsids of clusters data is written into, all clusters if empty
+	Clusters []string `json:",omitempty"`
+	// pools used instead of tiger and turtle, they should be created in
+	// allowed clusters and added into cluster table. Data in them is striped
+	// like tiger, so rabbit can't be used
+	BigFilePool     string `json:",omitempty"`
+	GlacierFilePool string `json:",omitempty"`
+	// objects smaller than it are written into rabbit, the default threshold
+	// is used if 0, and all objects are written into big file pool if negative
+	BigFileThreshold int64 `json:",omitempty"`
+}
+
+func (p Placement) Validate() error {
+	if p.BigFilePool == backend.SMALL_FILE_POOLNAME || p.GlacierFilePool == backend.SMALL_FILE_POOLNAME {
+		return errors.New("pool " + backend.SMALL_FILE_POOLNAME + " can't be used for big files")
+	}
+	for _, fsid := range p.Clusters {
+		if fsid == "" {
+			return errors.New("empty cluster fsid")
+		}
+	}
+	return nil
+}
+
+// AllowCluster returns true if data could be written into cluster `fsid`
+func (p Placement) AllowCluster(fsid string) bool {
+	if len(p.Clusters) == 0 {
+		return true
+	}
+	for _, c := range p.Clusters {
+		if c == fsid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package types
+
+import "testing"
+
+func TestPlacement(t *testing.T) {
+	var placement Placement
+	if placement.Validate() != nil || !placement.AllowCluster("a") {
+		t.Fatal("Empty placement should allow all clusters")
+	}
+	placement.Clusters = []string{"a", "b"}
+	if !placement.AllowCluster("b") || placement.AllowCluster("c") {
+		t.Fatal("AllowCluster:", placement)
+	}
+	placement.BigFilePool = "rabbit"
+	if placement.Validate() == nil {
+		t.Fatal("rabbit should not be used as big file pool")
+	}
+	placement.BigFilePool = "tiger-ssd"
+	placement.Clusters = append(placement.Clusters, "")
+	if placement.Validate() == nil {
+		t.Fatal("Empty fsid should be invalid")
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+type ReplicationOperation int
+
+const (
+	ReplicationOperationPut    ReplicationOperation = 0
+	ReplicationOperationDelete ReplicationOperation = 1
+)
+
+// Replication is an entry of the replication queue, fed by object writes and
+// deletes of buckets with replication rules, and consumed by tools/replicate
+type Replication struct {
+	Rowkey     string // rowkey cache
+	BucketName string
+	ObjectName string
+	// for put operation, this is the version of object in `objects` table,
+	// for delete, it's generated from the deleting time
+	Version      uint64
+	Operation    ReplicationOperation
+	TargetRegion string
+	TargetBucket string
+	StorageClass string
+	Status       string    // status of this entry, in PENDING/FAILED
+	MTime        time.Time // last modify time of status
+	TriedTimes   int
+}
+
+func (r *Replication) GetRowkey() string {
+	if r.Rowkey == "" {
+		r.Rowkey = r.BucketName + ObjectNameSeparator + r.ObjectName +
+			ObjectNameSeparator + strconv.FormatUint(r.Version, 10)
+	}
+	return r.Rowkey
+}
+
+func (r *Replication) GetCreateSql(d Dialect) (string, []interface{}) {
+	mtime := r.MTime.Format(TIME_LAYOUT_TIDB)
+	sql := d.InsertIgnore("insert into replication(bucketname,objectname,version,operation,targetregion,targetbucket," +
+		"storageclass,status,mtime,triedtimes) values(?,?,?,?,?,?,?,?,?,?)")
+	args := []interface{}{r.BucketName, r.ObjectName, r.Version, r.Operation, r.TargetRegion, r.TargetBucket,
+		r.StorageClass, r.Status, mtime, r.TriedTimes}
+	return d.Bind(sql, args)
+}
+
+func (r *Replication) GetUpdateSql(d Dialect) (string, []interface{}) {
+	mtime := r.MTime.Format(TIME_LAYOUT_TIDB)
+	sql := "update replication set status=?,mtime=?,triedtimes=? where bucketname=? and objectname=? and version=?"
+	args := []interface{}{r.Status, mtime, r.TriedTimes, r.BucketName, r.ObjectName, r.Version}
+	return d.Bind(sql, args)
+}
+
+func (r *Replication) GetRemoveSql(d Dialect) (string, []interface{}) {
+	sql := "delete from replication where bucketname=? and objectname=? and version=?"
+	args := []interface{}{r.BucketName, r.ObjectName, r.Version}
+	return d.Bind(sql, args)
+}
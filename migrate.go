@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta"
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/migrate"
+)
+
+func printMigrateHelp() {
+	fmt.Println("Usage: yig migrate <command> [version]")
+	fmt.Println("Commands:")
+	fmt.Println("    status          show applied and pending migrations of meta schema")
+	fmt.Println("    up [version]    apply migrations up to version, default to the latest")
+	fmt.Println("    down [version]  revert migrations down to version, default to the previous one")
+}
+
+// checkMetaSchema refuses to start if schema of meta store is older than
+// this binary expects
+func checkMetaSchema(c client.Client) {
+	migrator, err := migrate.New(c)
+	if err == migrate.ErrUnsupportedStore {
+		return
+	}
+	version, err := migrator.Check()
+	if err != nil {
+		helper.Logger.Error("Failed to check meta schema, err:", err)
+		panic("meta schema is out of date")
+	}
+	if version > migrate.LatestVersion() {
+		helper.Logger.Warn("Meta schema version", version, "is newer than",
+			migrate.LatestVersion(), "of this binary")
+	}
+}
+
+// runMigrate handles `yig migrate` and returns exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		printMigrateHelp()
+		return 1
+	}
+	helper.Logger = log.NewLogger(os.Stdout, log.InfoLevel)
+	migrator, err := migrate.New(meta.NewClient())
+	if err != nil {
+		fmt.Println("Failed to migrate", helper.CONFIG.MetaStore, "meta store:", err)
+		return 1
+	}
+	version, err := migrator.Version()
+	if err != nil {
+		fmt.Println("Failed to get schema version:", err)
+		return 1
+	}
+	target := -1
+	if len(args) == 2 {
+		target, err = strconv.Atoi(args[1])
+		if err != nil || target < 0 {
+			fmt.Println("Invalid version:", args[1])
+			return 1
+		}
+	}
+	progress := func(m migrate.Migration) {
+		fmt.Println("Migrating", m.Version, m.Name)
+	}
+	switch args[0] {
+	case "status":
+		applied, err := migrator.Applied()
+		if err != nil {
+			fmt.Println("Failed to get applied migrations:", err)
+			return 1
+		}
+		isApplied := make(map[int]bool)
+		for _, v := range applied {
+			isApplied[v] = true
+		}
+		fmt.Println("Schema version:", version, "latest:", migrate.LatestVersion())
+		for _, m := range migrate.Migrations {
+			state := "pending"
+			if isApplied[m.Version] {
+				state = "applied"
+			}
+			fmt.Printf("%4d  %-8s %s\n", m.Version, state, m.Name)
+		}
+		return 0
+	case "up":
+		if target == -1 {
+			target = migrate.LatestVersion()
+		}
+		err = migrator.Up(target, progress)
+	case "down":
+		if target == -1 {
+			target = version - 1
+		}
+		err = migrator.Down(target, progress)
+	default:
+		printMigrateHelp()
+		return 1
+	}
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	version, _ = migrator.Version()
+	fmt.Println("Schema version:", version)
+	return 0
+}
@@ -12,6 +12,7 @@ import (
 * the PluginType is for different interface type
 * such as:
 * IAM_PLUGIN => IamClient interface
+* BACKEND_PLUGIN => backend.Plugin interface
 * UNKNOWN_PLUGIN=> other interface
  */
 type YigPlugin struct {
@@ -27,6 +28,7 @@ const (
 	MQ_PLUGIN
 	KMS_PLUGIN
 	COMPRESS_PLUGIN
+	BACKEND_PLUGIN //backend.Plugin interface
 	NUMS_PLUGIN
 )
 
@@ -14,6 +14,13 @@ type MessageSender interface {
 	Close()
 }
 
+// TopicSender is implemented by message senders which could deliver
+// a message to a topic other than the one they are configured with.
+type TopicSender interface {
+	// send the message async to `topic`
+	AsyncSendToTopic(topic string, value []byte) error
+}
+
 var MsgSender MessageSender
 
 // create the singleton MessageSender
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/journeymidnight/yig/api/datatype/policy"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam/common"
 	"github.com/journeymidnight/yig/mods"
@@ -21,14 +25,30 @@ func GetIamClient(config map[string]interface{}) (interface{}, error) {
 	helper.Logger.Info("Get plugin config:", config)
 
 	c := DebugIamClient{
-		IamUrl: config["url"].(string),
+		IamUrl:   config["url"].(string),
+		Policies: make(map[string]policy.Policy),
+	}
+	// optional identity-based policies, access key -> policy document
+	if policies, ok := config["policies"].(map[string]interface{}); ok {
+		for accessKey, document := range policies {
+			s, ok := document.(string)
+			if !ok {
+				return nil, fmt.Errorf("policy of %s is not a string", accessKey)
+			}
+			p, err := policy.ParseIdentityPolicy(strings.NewReader(s))
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy of %s: %v", accessKey, err)
+			}
+			c.Policies[accessKey] = *p
+		}
 	}
 
 	return interface{}(c), nil
 }
 
 type DebugIamClient struct {
-	IamUrl string
+	IamUrl   string
+	Policies map[string]policy.Policy
 }
 
 func (d DebugIamClient) GetKeysByUid(uid string) (c []common.Credential, err error) {
@@ -43,3 +63,10 @@ func (d DebugIamClient) GetCredential(accessKey string) (c common.Credential, er
 		SecretAccessKey: "hehehehe",
 	}, nil // For test now
 }
+
+func (d DebugIamClient) GetPolicies(credential common.Credential) ([]policy.Policy, error) {
+	if p, ok := d.Policies[credential.AccessKeyID]; ok {
+		return []policy.Policy{p}, nil
+	}
+	return nil, nil
+}
@@ -47,3 +47,8 @@ func (mb *dummyMsgQueue) AsyncSend(value []byte) error {
 	fmt.Println("Send message succeed! url is:", mb.Url, "topic is:", mb.Topic, "value is：", value)
 	return nil
 }
+
+func (mb *dummyMsgQueue) AsyncSendToTopic(topic string, value []byte) error {
+	fmt.Println("Send message succeed! url is:", mb.Url, "topic is:", topic, "value is：", value)
+	return nil
+}
@@ -131,12 +131,16 @@ func (kf *Kafka) Close() {
 }
 
 func (kf *Kafka) AsyncSend(value []byte) error {
+	return kf.AsyncSendToTopic(kf.Topic, value)
+}
+
+func (kf *Kafka) AsyncSendToTopic(topic string, value []byte) error {
 	if nil == kf.producer {
 		return errors.New("Kafka is not created correctly yet.")
 	}
-	if nil == value || "" == kf.Topic {
+	if nil == value || "" == topic {
 		return errors.New(fmt.Sprintf("input message[%v] is invalid.", value))
 	}
-	kf.producer.ProduceChannel() <- &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &kf.Topic, Partition: kafka.PartitionAny}, Key: []byte(""), Value: value, Opaque: nil}
+	kf.producer.ProduceChannel() <- &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny}, Key: []byte(""), Value: value, Opaque: nil}
 	return nil
 }
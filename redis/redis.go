@@ -218,6 +218,58 @@ func GetUsage(key string) (value string, err error) {
 	return value, nil
 }
 
+// Keys of usage per storage class, values are like
+// <Storage-Class1>:<usagenumber>,<Storage-Class2>:<usagenumber>
+const (
+	UserUsagePrefix   = "u_p_" // eg. u_p_hehehehe
+	BucketUsagePrefix = "u_b_" // eg. u_b_test
+)
+
+// moveUsageScript moves ARGV[3] bytes from storage class ARGV[1] to ARGV[2]
+// in the usage value of KEYS[1], keys not counted yet are left alone
+var moveUsageScript = redigo.NewScript(1, `
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return 0
+end
+local classes, usages = {}, {}
+for class, usage in string.gmatch(value, "([^,:]+):(-?%d+)") do
+	table.insert(classes, class)
+	usages[class] = tonumber(usage)
+end
+local size = tonumber(ARGV[3])
+if usages[ARGV[2]] == nil then
+	table.insert(classes, ARGV[2])
+	usages[ARGV[2]] = 0
+end
+usages[ARGV[1]] = (usages[ARGV[1]] or 0) - size
+usages[ARGV[2]] = usages[ARGV[2]] + size
+local items = {}
+for _, class in ipairs(classes) do
+	table.insert(items, class .. ":" .. string.format("%d", usages[class]))
+end
+redis.call("SET", KEYS[1], table.concat(items, ","))
+return 1
+`)
+
+// MoveUsage moves `size` bytes of usage under `key` from storage class
+// `from` to `to`
+func MoveUsage(key string, from, to string, size int64) (err error) {
+	return CacheCircuit.Execute(
+		context.Background(),
+		func(ctx context.Context) (err error) {
+			c, err := GetClient(ctx)
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+			_, err = moveUsageScript.Do(c, key, from, to, size)
+			return err
+		},
+		nil,
+	)
+}
+
 // Get file bytes
 // `start` and `end` are inclusive
 // FIXME: this API causes an extra memory copy, need to patch radix to fix it
@@ -0,0 +1,35 @@
+package scrub
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics counts scrubbed objects and bytes, register it to a prometheus
+// registry to export them
+type Metrics struct {
+	objects *prometheus.CounterVec
+	bytes   prometheus.Counter
+}
+
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		objects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrub_objects_total",
+			Help:      "Object versions checked by scrubber, by result(ok, missing or corrupt)",
+		}, []string{"result"}),
+		bytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrub_read_bytes_total",
+			Help:      "Bytes read from backend clusters by scrubber",
+		}),
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.objects.Describe(ch)
+	m.bytes.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.objects.Collect(ch)
+	m.bytes.Collect(ch)
+}
@@ -0,0 +1,235 @@
+package scrub
+
+import (
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+// Orphan is an object in backend cluster which no metadata refers to,
+// usually left by crashes between writing data and committing metadata
+type Orphan struct {
+	Location     string
+	Pool         string
+	ObjectId     string
+	Size         uint64
+	LastModified time.Time
+}
+
+// OrphanFinder lists objects in pools of clusters and diffs them against
+// ids referenced by objects, multipart uploads, restored copies and gc.
+// Ids referenced are kept in memory, and metadata is scanned twice to
+// rule out objects moved around during the first scan, e.g. parts of
+// multipart uploads completed in the meantime.
+type OrphanFinder struct {
+	Client   client.Client
+	Clusters map[string]backend.Cluster // fsid -> cluster
+	Pools    []string
+	// objects modified within MinAge before scanning are never orphans,
+	// their metadata may be not committed yet
+	MinAge time.Duration
+}
+
+// location -> pool -> object id
+type references map[string]map[string]map[string]bool
+
+func (r references) add(location, pool, objectId string) {
+	if objectId == "" {
+		return
+	}
+	pools, ok := r[location]
+	if !ok {
+		pools = make(map[string]map[string]bool)
+		r[location] = pools
+	}
+	ids, ok := pools[pool]
+	if !ok {
+		ids = make(map[string]bool)
+		pools[pool] = ids
+	}
+	ids[objectId] = true
+}
+
+func (r references) addParts(location, pool string, parts map[int]*types.Part) {
+	for _, p := range parts {
+		r.add(location, pool, p.ObjectId)
+	}
+}
+
+func (r references) has(location, pool, objectId string) bool {
+	return r[location][pool][objectId]
+}
+
+// Find calls `fn` for every orphan found, and stops if `fn` returns an error
+func (f *OrphanFinder) Find(fn func(Orphan) error) error {
+	start := time.Now()
+	refs, err := f.scanReferences()
+	if err != nil {
+		return err
+	}
+	var candidates []Orphan
+	for location, cluster := range f.Clusters {
+		for _, pool := range f.Pools {
+			err = cluster.List(pool, func(objectId string) error {
+				if refs.has(location, pool, objectId) {
+					return nil
+				}
+				info, err := cluster.Stat(pool, objectId)
+				if err != nil {
+					// removed after listed
+					return nil
+				}
+				if info.LastModified.After(start.Add(-f.MinAge)) {
+					return nil
+				}
+				candidates = append(candidates, Orphan{
+					Location:     location,
+					Pool:         pool,
+					ObjectId:     objectId,
+					Size:         info.Size,
+					LastModified: info.LastModified,
+				})
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	refs, err = f.scanReferences()
+	if err != nil {
+		return err
+	}
+	for _, o := range candidates {
+		if refs.has(o.Location, o.Pool, o.ObjectId) {
+			continue
+		}
+		err = fn(o)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *OrphanFinder) scanReferences() (references, error) {
+	refs := make(references)
+	buckets, err := f.Client.GetBuckets()
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		err = f.scanObjects(bucket.Name, refs)
+		if err != nil {
+			return nil, err
+		}
+		err = f.scanMultiparts(bucket.Name, refs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = f.scanGarbageCollection(refs)
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (f *OrphanFinder) scanObjects(bucketName string, refs references) error {
+	var marker string
+	for {
+		names, err := f.Client.ScanObjectNames(bucketName, marker, listLimit)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			objects, err := f.Client.GetAllObject(bucketName, name, "")
+			if err != nil {
+				return err
+			}
+			restored := false
+			for _, o := range objects {
+				refs.add(o.Location, o.Pool, o.ObjectId)
+				refs.addParts(o.Location, o.Pool, o.Parts)
+				if o.StorageClass == types.ObjectStorageClassGlacier {
+					restored = true
+				}
+			}
+			if !restored {
+				continue
+			}
+			freezer, err := f.Client.GetFreezer(bucketName, name, "")
+			if err == ErrNoSuchKey {
+				continue
+			} else if err != nil {
+				return err
+			}
+			refs.add(freezer.Location, freezer.Pool, freezer.ObjectId)
+			refs.addParts(freezer.Location, freezer.Pool, freezer.Parts)
+		}
+		if len(names) < listLimit {
+			return nil
+		}
+		marker = names[len(names)-1]
+	}
+}
+
+func (f *OrphanFinder) scanMultiparts(bucketName string, refs references) error {
+	var keyMarker, uploadIdMarker string
+	for {
+		uploads, _, truncated, nextKeyMarker, nextUploadIdMarker, err :=
+			f.Client.ListMultipartUploads(bucketName, keyMarker, uploadIdMarker, "", "", "", listLimit)
+		if err != nil {
+			return err
+		}
+		for _, upload := range uploads {
+			multipart, err := f.Client.GetMultipart(bucketName, upload.Key, upload.UploadId)
+			if err != nil {
+				return err
+			}
+			refs.addParts(multipart.Metadata.Location, multipart.Metadata.Pool, multipart.Parts)
+		}
+		if !truncated {
+			return nil
+		}
+		keyMarker, uploadIdMarker = nextKeyMarker, nextUploadIdMarker
+	}
+}
+
+// objects in gc are removed by gc, so they're not orphans either
+func (f *OrphanFinder) scanGarbageCollection(refs references) error {
+	var startRowKey string
+	for {
+		garbages, err := f.Client.ScanGarbageCollection(listLimit, startRowKey)
+		if err != nil {
+			return err
+		}
+		for _, g := range garbages {
+			refs.add(g.Location, g.Pool, g.ObjectId)
+			refs.addParts(g.Location, g.Pool, g.Parts)
+		}
+		if len(garbages) < listLimit {
+			return nil
+		}
+		// startRowKey is included in next scan
+		startRowKey = garbages[len(garbages)-1].Rowkey
+	}
+}
+
+// Reclaim puts orphan `o` into gc table, so it's removed by gc
+func (f *OrphanFinder) Reclaim(o Orphan) error {
+	return f.Client.PutObjectToGarbageCollection(&types.Object{
+		Name:             o.ObjectId,
+		Location:         o.Location,
+		Pool:             o.Pool,
+		ObjectId:         o.ObjectId,
+		LastModifiedTime: o.LastModified,
+	}, nil)
+}
@@ -0,0 +1,158 @@
+package scrub_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/meta/client/memclient"
+	. "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/scrub"
+)
+
+// poolCluster maps pool -> object id -> last modified time
+type poolCluster map[string]map[string]time.Time
+
+func (c poolCluster) ID() string                       { return "fsid" }
+func (c poolCluster) GetUsage() (backend.Usage, error) { return backend.Usage{}, nil }
+func (c poolCluster) Put(poolname string, data io.Reader) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c poolCluster) Append(poolName, existName string, objectChunk io.Reader,
+	offset int64) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c poolCluster) GetReader(poolName, objectName string,
+	offset int64, length uint64) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+func (c poolCluster) Remove(poolName, objectName string) error { return nil }
+func (c poolCluster) List(poolName string, fn func(objectName string) error) error {
+	for oid := range c[poolName] {
+		err := fn(oid)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c poolCluster) Stat(poolName, objectName string) (backend.ObjectInfo, error) {
+	mtime, ok := c[poolName][objectName]
+	if !ok {
+		return backend.ObjectInfo{}, errors.New("no such object")
+	}
+	return backend.ObjectInfo{Size: 4, LastModified: mtime}, nil
+}
+
+func prepareReferences(t *testing.T) *memclient.MemClient {
+	client, err := memclient.NewMemClientWithPath("")
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	_, err = client.CheckAndPutBucket(Bucket{Name: "hehe", OwnerId: "haha", Versioning: VersionEnabled})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	now := time.Now()
+	objects := []*Object{
+		{Name: "a", Pool: "rabbit", ObjectId: "oid-a-old", LastModifiedTime: now.Add(-time.Second)},
+		{Name: "a", Pool: "rabbit", ObjectId: "oid-a", LastModifiedTime: now},
+		{Name: "b", Pool: "tiger", LastModifiedTime: now, Parts: map[int]*Part{
+			1: {PartNumber: 1, ObjectId: "oid-b1"},
+		}},
+		{Name: "c", Pool: "turtle", ObjectId: "oid-c", LastModifiedTime: now,
+			StorageClass: ObjectStorageClassGlacier},
+	}
+	for _, o := range objects {
+		o.BucketName = "hehe"
+		o.Location = "fsid"
+		err = client.PutObject(o, nil)
+		if err != nil {
+			t.Fatal("PutObject err:", err)
+		}
+	}
+
+	freezer := &Freezer{BucketName: "hehe", Name: "c", Status: ObjectNeedRestore}
+	err = client.CreateFreezer(freezer)
+	if err != nil {
+		t.Fatal("CreateFreezer err:", err)
+	}
+	freezer.Location, freezer.Pool, freezer.ObjectId = "fsid", "tiger", "oid-c-restored"
+	err = client.UpdateFreezer(freezer, ObjectHasRestored, nil)
+	if err != nil {
+		t.Fatal("UpdateFreezer err:", err)
+	}
+
+	multipart := Multipart{
+		BucketName:  "hehe",
+		ObjectName:  "d",
+		InitialTime: now,
+		Metadata:    MultipartMetadata{Location: "fsid", Pool: "tiger"},
+	}
+	err = client.CreateMultipart(multipart)
+	if err != nil {
+		t.Fatal("CreateMultipart err:", err)
+	}
+	err = client.PutObjectPart(&multipart, &Part{PartNumber: 1, ObjectId: "oid-d1",
+		LastModified: now.UTC().Format(CREATE_TIME_LAYOUT)}, nil)
+	if err != nil {
+		t.Fatal("PutObjectPart err:", err)
+	}
+
+	err = client.PutObjectToGarbageCollection(&Object{
+		BucketName: "hehe",
+		Name:       "e",
+		Location:   "fsid",
+		Pool:       "rabbit",
+		ObjectId:   "oid-e",
+	}, nil)
+	if err != nil {
+		t.Fatal("PutObjectToGarbageCollection err:", err)
+	}
+	return client
+}
+
+func TestOrphanFinder(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	cluster := poolCluster{
+		"rabbit": {"oid-a-old": old, "oid-a": old, "oid-e": old, "orphan-old": old, "orphan-new": time.Now()},
+		"tiger":  {"oid-b1": old, "oid-c-restored": old, "oid-d1": old, "orphan-tiger": old},
+		"turtle": {"oid-c": old},
+	}
+	finder := scrub.OrphanFinder{
+		Client:   prepareReferences(t),
+		Clusters: map[string]backend.Cluster{"fsid": cluster},
+		Pools:    []string{"rabbit", "tiger", "turtle"},
+		MinAge:   time.Minute,
+	}
+	orphans := make(map[string]scrub.Orphan)
+	err := finder.Find(func(o scrub.Orphan) error {
+		orphans[o.ObjectId] = o
+		return nil
+	})
+	if err != nil || len(orphans) != 2 ||
+		orphans["orphan-old"].Pool != "rabbit" || orphans["orphan-tiger"].Pool != "tiger" {
+		t.Fatal("Find:", orphans, err)
+	}
+
+	for _, o := range orphans {
+		err = finder.Reclaim(o)
+		if err != nil {
+			t.Fatal("Reclaim err:", err)
+		}
+	}
+	// orphans in gc are not reported again
+	err = finder.Find(func(o scrub.Orphan) error {
+		t.Fatal("Orphan reported again:", o)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Find again err:", err)
+	}
+	garbages, err := finder.Client.ScanGarbageCollection(10, "")
+	if err != nil || len(garbages) != 3 {
+		t.Fatal("ScanGarbageCollection:", garbages, err)
+	}
+}
@@ -0,0 +1,291 @@
+// Package scrub verifies object data in backend clusters against metadata,
+// so data lost or damaged in clusters is found before users notice it, and
+// finds objects in clusters which metadata doesn't refer to.
+package scrub
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/meta/client"
+	"github.com/journeymidnight/yig/meta/types"
+)
+
+const listLimit = 1000
+
+type Problem string
+
+const (
+	// data can't be read from its cluster
+	Missing Problem = "missing"
+	// data size or MD5 differs from metadata
+	Corrupt Problem = "corrupt"
+)
+
+// Result describes a problem of an object id, the scrubber writes one
+// Result per line of report in JSON
+type Result struct {
+	Bucket   string
+	Object   string
+	Version  string // version in meta store
+	Part     int    `json:",omitempty"` // part number of multipart objects
+	Location string
+	Pool     string
+	ObjectId string
+	Problem  Problem
+	Detail   string
+}
+
+type Stats struct {
+	Objects int64 // object versions checked
+	Bytes   int64 // bytes read from clusters
+	Missing int64 // object versions with missing data
+	Corrupt int64 // object versions with corrupt data
+}
+
+func (s *Stats) add(o Stats) {
+	s.Objects += o.Objects
+	s.Bytes += o.Bytes
+	s.Missing += o.Missing
+	s.Corrupt += o.Corrupt
+}
+
+// Scrubber reads data of objects and checks it against metadata. MD5 is
+// recomputed for normal objects which are not encrypted, only size is
+// checked for multipart, appendable and encrypted objects.
+type Scrubber struct {
+	Client   client.Client
+	Clusters map[string]backend.Cluster // fsid -> cluster
+	// checking speed limits, 0 for unlimited
+	ObjectsPerSecond int64
+	BytesPerSecond   int64
+	Report           io.Writer // could be nil
+	Metrics          *Metrics  // could be nil
+
+	objectThrottle *throttle
+	byteThrottle   *throttle
+}
+
+func (s *Scrubber) prepare() {
+	if s.objectThrottle == nil {
+		s.objectThrottle = &throttle{rate: s.ObjectsPerSecond}
+		s.byteThrottle = &throttle{rate: s.BytesPerSecond}
+	}
+}
+
+// ScrubAll scrubs every bucket in meta store
+func (s *Scrubber) ScrubAll() (stats Stats, err error) {
+	buckets, err := s.Client.GetBuckets()
+	if err != nil {
+		return
+	}
+	for _, bucket := range buckets {
+		var bucketStats Stats
+		bucketStats, err = s.ScrubBucket(bucket.Name)
+		stats.add(bucketStats)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ScrubBucket scrubs every object version in bucket `bucketName`
+func (s *Scrubber) ScrubBucket(bucketName string) (stats Stats, err error) {
+	s.prepare()
+	bucket, err := s.Client.GetBucket(bucketName)
+	if err != nil {
+		return
+	}
+	if bucket.Versioning == types.VersionDisabled {
+		err = s.listObjects(bucketName, &stats)
+	} else {
+		err = s.scanVersions(bucketName, &stats)
+	}
+	return
+}
+
+// listObjects walks buckets which never enabled versioning, every object
+// has only one version there
+func (s *Scrubber) listObjects(bucketName string, stats *Stats) error {
+	var marker string
+	for {
+		objects, _, truncated, nextMarker, _, err := s.Client.ListObjects(bucketName,
+			marker, "", "", "", false, listLimit)
+		if err != nil {
+			return err
+		}
+		for _, o := range objects {
+			err = s.scrubObject(o, stats)
+			if err != nil {
+				return err
+			}
+		}
+		if !truncated || nextMarker == "" {
+			return nil
+		}
+		marker = nextMarker
+	}
+}
+
+// scanVersions walks every version of objects, ListObjects returns neither
+// noncurrent versions nor objects whose latest version is a delete marker
+func (s *Scrubber) scanVersions(bucketName string, stats *Stats) error {
+	var marker string
+	for {
+		names, err := s.Client.ScanObjectNames(bucketName, marker, listLimit)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			objects, err := s.Client.GetAllObject(bucketName, name, "")
+			if err != nil {
+				return err
+			}
+			for _, o := range objects {
+				err = s.scrubObject(o, stats)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if len(names) < listLimit {
+			return nil
+		}
+		marker = names[len(names)-1]
+	}
+}
+
+// scrubObject checks data of object `o` and reports problems found
+func (s *Scrubber) scrubObject(o *types.Object, stats *Stats) error {
+	if o.DeleteMarker {
+		return nil
+	}
+	s.objectThrottle.wait(1)
+	result := Result{
+		Bucket:   o.BucketName,
+		Object:   o.Name,
+		Version:  strconv.FormatUint(math.MaxUint64-uint64(o.LastModifiedTime.UnixNano()), 10),
+		Location: o.Location,
+		Pool:     o.Pool,
+	}
+	var results []Result
+	if len(o.Parts) != 0 {
+		partNumbers := make([]int, 0, len(o.Parts))
+		for n := range o.Parts {
+			partNumbers = append(partNumbers, n)
+		}
+		sort.Ints(partNumbers)
+		for _, n := range partNumbers {
+			part := o.Parts[n]
+			result.Part = n
+			result.ObjectId = part.ObjectId
+			if s.check(&result, part.Size, "", stats) {
+				results = append(results, result)
+			}
+		}
+	} else if o.Size != 0 {
+		var etag string
+		if o.Type == types.ObjectTypeNormal && o.SseType == "" && !strings.Contains(o.Etag, "-") {
+			etag = o.Etag
+		}
+		result.ObjectId = o.ObjectId
+		if s.check(&result, o.Size, etag, stats) {
+			results = append(results, result)
+		}
+	}
+
+	stats.Objects += 1
+	label := "ok"
+	if len(results) != 0 {
+		label = string(results[0].Problem)
+		if results[0].Problem == Missing {
+			stats.Missing += 1
+		} else {
+			stats.Corrupt += 1
+		}
+	}
+	if s.Metrics != nil {
+		s.Metrics.objects.WithLabelValues(label).Inc()
+	}
+	if s.Report == nil {
+		return nil
+	}
+	for _, r := range results {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = s.Report.Write(append(b, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check reads `size` bytes of `r.ObjectId` and compares MD5 with `etag`
+// if it's not empty, returns true and fills problem of `r` if data is bad
+func (s *Scrubber) check(r *Result, size int64, etag string, stats *Stats) bool {
+	cluster, ok := s.Clusters[r.Location]
+	if !ok {
+		r.Problem, r.Detail = Missing, "unknown cluster"
+		return true
+	}
+	reader, err := cluster.GetReader(r.Pool, r.ObjectId, 0, uint64(size))
+	if err != nil {
+		r.Problem, r.Detail = Missing, err.Error()
+		return true
+	}
+	defer reader.Close()
+
+	var w io.Writer = ioutil.Discard
+	var md5Writer hash.Hash
+	if etag != "" {
+		md5Writer = md5.New()
+		w = md5Writer
+	}
+	n, err := io.Copy(w, &throttledReader{reader, s})
+	stats.Bytes += n
+	if err != nil {
+		r.Problem, r.Detail = Missing, err.Error()
+		return true
+	}
+	if n != size {
+		r.Problem, r.Detail = Corrupt, fmt.Sprintf("size %d, expected %d", n, size)
+		return true
+	}
+	if md5Writer != nil {
+		calculatedMd5 := hex.EncodeToString(md5Writer.Sum(nil))
+		if calculatedMd5 != etag {
+			r.Problem, r.Detail = Corrupt, fmt.Sprintf("md5 %s, expected %s", calculatedMd5, etag)
+			return true
+		}
+	}
+	return false
+}
+
+// throttledReader limits reading speed with BytesPerSecond of scrubber
+type throttledReader struct {
+	reader   io.Reader
+	scrubber *Scrubber
+}
+
+func (t *throttledReader) Read(p []byte) (n int, err error) {
+	n, err = t.reader.Read(p)
+	t.scrubber.byteThrottle.wait(int64(n))
+	if t.scrubber.Metrics != nil {
+		t.scrubber.Metrics.bytes.Add(float64(n))
+	}
+	return
+}
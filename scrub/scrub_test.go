@@ -0,0 +1,214 @@
+package scrub_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/meta/client/memclient"
+	. "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/scrub"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cluster maps object ids to their data
+type cluster map[string][]byte
+
+func (c cluster) ID() string                       { return "fsid" }
+func (c cluster) GetUsage() (backend.Usage, error) { return backend.Usage{}, nil }
+func (c cluster) Put(poolname string, data io.Reader) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c cluster) Append(poolName, existName string, objectChunk io.Reader,
+	offset int64) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c cluster) GetReader(poolName, objectName string,
+	offset int64, length uint64) (io.ReadCloser, error) {
+	data, ok := c[objectName]
+	if !ok {
+		return nil, errors.New("no such object")
+	}
+	if uint64(len(data)) > length {
+		data = data[:length]
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+func (c cluster) Remove(poolName, objectName string) error { return nil }
+func (c cluster) List(poolName string, fn func(objectName string) error) error {
+	return errors.New("not implemented")
+}
+func (c cluster) Stat(poolName, objectName string) (backend.ObjectInfo, error) {
+	return backend.ObjectInfo{}, errors.New("not implemented")
+}
+
+func md5Of(data string) string {
+	sum := md5.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func prepare(t *testing.T, versioning string) *memclient.MemClient {
+	client, err := memclient.NewMemClientWithPath("")
+	if err != nil {
+		t.Fatal("NewMemClientWithPath err:", err)
+	}
+	_, err = client.CheckAndPutBucket(Bucket{Name: "hehe", OwnerId: "haha", Versioning: versioning})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	now := time.Now()
+	objects := []*Object{
+		{Name: "good", Size: 4, ObjectId: "oid-good", Etag: md5Of("good")},
+		{Name: "bad-md5", Size: 4, ObjectId: "oid-bad-md5", Etag: md5Of("good")},
+		{Name: "short", Size: 8, ObjectId: "oid-short", Etag: md5Of("short")},
+		{Name: "missing", Size: 4, ObjectId: "oid-missing", Etag: md5Of("miss")},
+		{Name: "encrypted", Size: 4, ObjectId: "oid-encrypted", Etag: md5Of("good"), SseType: "S3"},
+		{Name: "empty", Etag: md5Of("")},
+		{Name: "multipart", Size: 8, Etag: "whatever-2", Type: ObjectTypeMultipart,
+			Parts: map[int]*Part{
+				1: {PartNumber: 1, Size: 4, ObjectId: "oid-part1"},
+				2: {PartNumber: 2, Size: 4, ObjectId: "oid-part2"},
+			}},
+	}
+	for _, o := range objects {
+		o.BucketName = "hehe"
+		o.Location = "fsid"
+		o.LastModifiedTime = now
+		err = client.PutObject(o, nil)
+		if err != nil {
+			t.Fatal("PutObject err:", err)
+		}
+	}
+	return client
+}
+
+var data = cluster{
+	"oid-good":      []byte("good"),
+	"oid-bad-md5":   []byte("baad"),
+	"oid-short":     []byte("short"),
+	"oid-encrypted": []byte("encr"),
+	"oid-part1":     []byte("part"),
+}
+
+func readReport(t *testing.T, report *bytes.Buffer) map[string]scrub.Result {
+	results := make(map[string]scrub.Result)
+	decoder := json.NewDecoder(report)
+	for decoder.More() {
+		var r scrub.Result
+		err := decoder.Decode(&r)
+		if err != nil {
+			t.Fatal("Decode report err:", err)
+		}
+		results[r.Object] = r
+	}
+	return results
+}
+
+// gather returns counter values by "name/label value"
+func gather(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal("Gather err:", err)
+	}
+	counters := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range m.GetLabel() {
+				key += "/" + label.GetValue()
+			}
+			counters[key] = m.GetCounter().GetValue()
+		}
+	}
+	return counters
+}
+
+func TestScrubBucket(t *testing.T) {
+	for _, versioning := range []string{VersionDisabled, VersionEnabled} {
+		var report bytes.Buffer
+		metrics := scrub.NewMetrics("yig")
+		scrubber := scrub.Scrubber{
+			Client:   prepare(t, versioning),
+			Clusters: map[string]backend.Cluster{"fsid": data},
+			Report:   &report,
+			Metrics:  metrics,
+		}
+		stats, err := scrubber.ScrubBucket("hehe")
+		if err != nil {
+			t.Fatal(versioning, "ScrubBucket err:", err)
+		}
+		if stats.Objects != 7 || stats.Missing != 2 || stats.Corrupt != 2 {
+			t.Fatal(versioning, "Unexpected stats:", stats)
+		}
+		results := readReport(t, &report)
+		if len(results) != 4 ||
+			results["bad-md5"].Problem != scrub.Corrupt ||
+			results["short"].Problem != scrub.Corrupt ||
+			results["missing"].Problem != scrub.Missing ||
+			results["multipart"].Problem != scrub.Missing ||
+			results["multipart"].Part != 2 ||
+			results["multipart"].ObjectId != "oid-part2" {
+			t.Fatal(versioning, "Unexpected report:", results)
+		}
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(metrics)
+		counters := gather(t, registry)
+		if counters["yig_scrub_objects_total/ok"] != 3 ||
+			counters["yig_scrub_objects_total/missing"] != 2 ||
+			counters["yig_scrub_objects_total/corrupt"] != 2 ||
+			counters["yig_scrub_read_bytes_total"] != 21 {
+			t.Fatal(versioning, "Unexpected metrics:", counters)
+		}
+	}
+}
+
+func TestScrubNoncurrentVersions(t *testing.T) {
+	client := prepare(t, VersionEnabled)
+	deleteMarker := &Object{
+		Name:             "missing",
+		BucketName:       "hehe",
+		LastModifiedTime: time.Now().Add(time.Second),
+		DeleteMarker:     true,
+	}
+	err := client.PutObject(deleteMarker, nil)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	var report bytes.Buffer
+	scrubber := scrub.Scrubber{
+		Client:   client,
+		Clusters: map[string]backend.Cluster{"fsid": data},
+		Report:   &report,
+	}
+	stats, err := scrubber.ScrubAll()
+	if err != nil || stats.Objects != 7 || stats.Missing != 2 {
+		t.Fatal("ScrubAll:", stats, err)
+	}
+	if _, ok := readReport(t, &report)["missing"]; !ok {
+		t.Fatal("Object behind delete marker is not scrubbed")
+	}
+}
+
+func TestScrubRateLimit(t *testing.T) {
+	scrubber := scrub.Scrubber{
+		Client:           prepare(t, VersionDisabled),
+		Clusters:         map[string]backend.Cluster{"fsid": data},
+		ObjectsPerSecond: 50,
+	}
+	start := time.Now()
+	_, err := scrubber.ScrubBucket("hehe")
+	if err != nil {
+		t.Fatal("ScrubBucket err:", err)
+	}
+	// 7 objects at 50 per second take at least 120ms
+	if elapsed := time.Since(start); elapsed < 120*time.Millisecond {
+		t.Fatal("Scrubbing is not throttled:", elapsed)
+	}
+}
@@ -0,0 +1,23 @@
+package scrub
+
+import "time"
+
+// throttle limits events to `rate` per second, no limit if `rate` <= 0
+type throttle struct {
+	rate int64
+	next time.Time
+}
+
+// wait blocks until `n` more events are allowed
+func (t *throttle) wait(n int64) {
+	if t.rate <= 0 {
+		return
+	}
+	now := time.Now()
+	if t.next.After(now) {
+		time.Sleep(t.next.Sub(now))
+	} else {
+		t.next = now
+	}
+	t.next = t.next.Add(time.Duration(n) * time.Second / time.Duration(t.rate))
+}
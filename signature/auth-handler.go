@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/iam"
 	"github.com/journeymidnight/yig/iam/common"
 )
 
@@ -122,8 +123,31 @@ func sumMD5(data []byte) []byte {
 	return hash.Sum(nil)
 }
 
+// getCredential gets credential of access key for V4 signed requests,
+// temporary credentials are verified by their session tokens
+func getCredential(accessKey, sessionToken string) (credential common.Credential, err error) {
+	if sessionToken != "" {
+		return iam.GetTemporaryCredential(accessKey, sessionToken)
+	}
+	credential, err = iam.GetCredential(accessKey)
+	if err != nil {
+		return credential, ErrInvalidAccessKeyID
+	}
+	return credential, nil
+}
+
 // A helper function to verify if request has valid AWS Signature
 func IsReqAuthenticated(r *http.Request) (c common.Credential, e error) {
+	return isReqAuthenticated(r, serviceS3)
+}
+
+// IsStsReqAuthenticated is IsReqAuthenticated for STS requests, V4 signatures
+// of which are scoped to "sts" instead of "s3"
+func IsStsReqAuthenticated(r *http.Request) (c common.Credential, e error) {
+	return isReqAuthenticated(r, serviceSTS)
+}
+
+func isReqAuthenticated(r *http.Request, service string) (c common.Credential, e error) {
 	payload, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return c, ErrInternalError
@@ -139,9 +163,9 @@ func IsReqAuthenticated(r *http.Request) (c common.Credential, e error) {
 	validateRegion := false // TODO: Validate region.
 	switch GetRequestAuthType(r) {
 	case AuthTypePresignedV4:
-		return DoesPresignedSignatureMatchV4(r, validateRegion)
+		return doesPresignedSignatureMatchV4(r, validateRegion, service)
 	case AuthTypeSignedV4:
-		return DoesSignatureMatchV4(hex.EncodeToString(sum256(payload)), r, validateRegion)
+		return doesSignatureMatchV4(hex.EncodeToString(sum256(payload)), r, validateRegion, service)
 	case AuthTypePresignedV2:
 		return DoesPresignedSignatureMatchV2(r)
 	case AuthTypeSignedV2:
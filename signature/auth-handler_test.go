@@ -0,0 +1,184 @@
+package signature
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws/credentials"
+	v4 "github.com/journeymidnight/aws-sdk-go/aws/signer/v4"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam"
+	"github.com/journeymidnight/yig/iam/cache"
+	"github.com/journeymidnight/yig/iam/common"
+	"github.com/journeymidnight/yig/log"
+)
+
+var parent = common.Credential{
+	UserId:          "hehe",
+	DisplayName:     "hehe",
+	AccessKeyID:     "sigparentkey",
+	SecretAccessKey: "sigparentsecret",
+}
+
+// temporaryCredential issues temporary credentials of `parent`, which is
+// found in iam cache
+func temporaryCredential(t *testing.T) common.Credential {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	helper.CONFIG.StsKey = "sts_secret"
+	cache.InitializeIamCache()
+	cache.IamCache.Set(parent.AccessKeyID, parent)
+	temporary, err := iam.NewTemporaryCredential(parent, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	return temporary
+}
+
+func newRequest(t *testing.T, body string) *http.Request {
+	r, err := http.NewRequest("PUT", "http://s3.test.com:8080/hehe/a", bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatal("NewRequest err:", err)
+	}
+	return r
+}
+
+func signV4(t *testing.T, r *http.Request, body, accessKey, secretKey, sessionToken string) {
+	signer := v4.NewSigner(credentials.NewStaticCredentials(accessKey, secretKey, sessionToken))
+	_, err := signer.Sign(r, bytes.NewReader([]byte(body)), "s3", "us-east-1", time.Now())
+	if err != nil {
+		t.Fatal("Sign err:", err)
+	}
+}
+
+func TestSignedWithSessionToken(t *testing.T) {
+	temporary := temporaryCredential(t)
+
+	r := newRequest(t, "data")
+	signV4(t, r, "data", parent.AccessKeyID, parent.SecretAccessKey, "")
+	credential, err := IsReqAuthenticated(r)
+	if err != nil || credential.AccessKeyID != parent.AccessKeyID || credential.IsTemporary() {
+		t.Fatal("Signed by permanent credential:", credential, err)
+	}
+
+	r = newRequest(t, "data")
+	signV4(t, r, "data", temporary.AccessKeyID, temporary.SecretAccessKey, temporary.SessionToken)
+	if r.Header.Get("X-Amz-Security-Token") != temporary.SessionToken {
+		t.Fatal("Session token is not sent:", r.Header)
+	}
+	credential, err = IsReqAuthenticated(r)
+	if err != nil || credential.UserId != parent.UserId ||
+		credential.AccessKeyID != temporary.AccessKeyID || !credential.IsTemporary() {
+		t.Fatal("Signed by temporary credential:", credential, err)
+	}
+
+	r = newRequest(t, "")
+	signer := v4.NewSigner(credentials.NewStaticCredentials(temporary.AccessKeyID,
+		temporary.SecretAccessKey, temporary.SessionToken))
+	_, err = signer.Presign(r, nil, "s3", "us-east-1", 15*time.Minute, time.Now())
+	if err != nil {
+		t.Fatal("Presign err:", err)
+	}
+	if r.URL.Query().Get("X-Amz-Security-Token") != temporary.SessionToken {
+		t.Fatal("Session token is not in presigned url:", r.URL)
+	}
+	credential, err = IsReqAuthenticated(r)
+	if err != nil || credential.AccessKeyID != temporary.AccessKeyID {
+		t.Fatal("Presigned by temporary credential:", credential, err)
+	}
+}
+
+func tamper(sessionToken string) string {
+	sealed, _ := base64.RawURLEncoding.DecodeString(sessionToken)
+	sealed[len(sealed)-1] ^= 1
+	return base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+func TestSignedWithInvalidSessionToken(t *testing.T) {
+	temporary := temporaryCredential(t)
+	other, err := iam.NewTemporaryCredential(parent, time.Hour, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+
+	for _, c := range []struct {
+		name         string
+		accessKey    string
+		secretKey    string
+		sessionToken string
+		err          error
+	}{
+		{"token of other access key", temporary.AccessKeyID, temporary.SecretAccessKey,
+			other.SessionToken, ErrInvalidToken},
+		{"tampered token", temporary.AccessKeyID, temporary.SecretAccessKey,
+			tamper(temporary.SessionToken), ErrInvalidToken},
+		{"permanent key with token", parent.AccessKeyID, parent.SecretAccessKey,
+			temporary.SessionToken, ErrInvalidToken},
+		{"signed by parent secret", temporary.AccessKeyID, parent.SecretAccessKey,
+			temporary.SessionToken, ErrSignatureDoesNotMatch},
+	} {
+		r := newRequest(t, "data")
+		signV4(t, r, "data", c.accessKey, c.secretKey, c.sessionToken)
+		_, err = IsReqAuthenticated(r)
+		if err != c.err {
+			t.Fatal(c.name, "IsReqAuthenticated:", err)
+		}
+	}
+
+	expired, err := iam.NewTemporaryCredential(parent, -time.Minute, "")
+	if err != nil {
+		t.Fatal("NewTemporaryCredential err:", err)
+	}
+	r := newRequest(t, "data")
+	signV4(t, r, "data", expired.AccessKeyID, expired.SecretAccessKey, expired.SessionToken)
+	_, err = IsReqAuthenticated(r)
+	if err != ErrExpiredToken {
+		t.Fatal("Signed by expired credential:", err)
+	}
+}
+
+// "sts" in credential scope is accepted by STS requests only, requests
+// signed for "s3" still need X-Amz-Content-Sha256
+func TestServiceScope(t *testing.T) {
+	temporaryCredential(t)
+	sign := func(service string) *http.Request {
+		r := newRequest(t, "data")
+		signer := v4.NewSigner(credentials.NewStaticCredentials(parent.AccessKeyID,
+			parent.SecretAccessKey, ""))
+		_, err := signer.Sign(r, bytes.NewReader([]byte("data")), service, "us-east-1", time.Now())
+		if err != nil {
+			t.Fatal("Sign err:", err)
+		}
+		return r
+	}
+
+	_, err := IsReqAuthenticated(sign("sts"))
+	if err != ErrInvalidService {
+		t.Fatal("IsReqAuthenticated signed for sts:", err)
+	}
+	c, err := IsStsReqAuthenticated(sign("sts"))
+	if err != nil || c.UserId != parent.UserId {
+		t.Fatal("IsStsReqAuthenticated signed for sts:", c, err)
+	}
+	_, err = IsStsReqAuthenticated(sign("s3"))
+	if err != ErrInvalidService {
+		t.Fatal("IsStsReqAuthenticated signed for s3:", err)
+	}
+	r := sign("s3")
+	r.Header.Del("X-Amz-Content-Sha256")
+	_, err = IsReqAuthenticated(r)
+	if err != ErrContentSHA256Mismatch {
+		t.Fatal("IsReqAuthenticated without X-Amz-Content-Sha256:", err)
+	}
+
+	_, err = DoesPolicySignatureMatchV4(map[string]string{
+		"X-Amz-Credential": parent.AccessKeyID + "/20200101/us-east-1/sts/aws4_request",
+	})
+	if err != ErrInvalidService {
+		t.Fatal("DoesPolicySignatureMatchV4 signed for sts:", err)
+	}
+}
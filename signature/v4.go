@@ -43,6 +43,9 @@ import (
 // AWS Signature Version '4' constants.
 const (
 	signV4Algorithm = "AWS4-HMAC-SHA256"
+	// services in credential scope
+	serviceS3  = "s3"
+	serviceSTS = "sts"
 )
 
 // getSignedHeaders generate a string i.e alphabetically sorted,
@@ -107,31 +110,31 @@ func getCanonicalHeaders(signedHeaders http.Header) string {
 }
 
 // getScope generate a string of a specific date, an AWS region, and a service.
-func getScope(t time.Time, region string) string {
+func getScope(t time.Time, region, service string) string {
 	scope := strings.Join([]string{
 		t.Format(YYYYMMDD),
 		region,
-		"s3",
+		service,
 		"aws4_request",
 	}, "/")
 	return scope
 }
 
 // getStringToSign a string based on selected query values.
-func getStringToSign(canonicalRequest string, t time.Time, region string) string {
+func getStringToSign(canonicalRequest string, t time.Time, region, service string) string {
 	stringToSign := signV4Algorithm + "\n" + t.Format(Iso8601Format) + "\n"
-	stringToSign = stringToSign + getScope(t, region) + "\n"
+	stringToSign = stringToSign + getScope(t, region, service) + "\n"
 	canonicalRequestBytes := sum256([]byte(canonicalRequest))
 	stringToSign = stringToSign + hex.EncodeToString(canonicalRequestBytes[:])
 	return stringToSign
 }
 
 // getSigningKey hmac seed to calculate final signature.
-func getSigningKey(secretKey string, t time.Time, region string) []byte {
+func getSigningKey(secretKey string, t time.Time, region, service string) []byte {
 	date := sumHMAC([]byte("AWS4"+secretKey), []byte(t.Format(YYYYMMDD)))
 	regionBytes := sumHMAC(date, []byte(region))
-	service := sumHMAC(regionBytes, []byte("s3"))
-	signingKey := sumHMAC(service, []byte("aws4_request"))
+	serviceBytes := sumHMAC(regionBytes, []byte(service))
+	signingKey := sumHMAC(serviceBytes, []byte("aws4_request"))
 	return signingKey
 }
 
@@ -145,7 +148,7 @@ func getSignature(signingKey []byte, stringToSign string) string {
 // returns true if matches, false otherwise. if error is not nil then it is always false
 func DoesPolicySignatureMatchV4(formValues map[string]string) (credential common.Credential, err error) {
 	// Parse credential tag.
-	credHeader, err := parseCredential(formValues["X-Amz-Credential"])
+	credHeader, err := parseCredential(formValues["X-Amz-Credential"], serviceS3)
 	if err != nil {
 		return credential, err
 	}
@@ -167,7 +170,7 @@ func DoesPolicySignatureMatchV4(formValues map[string]string) (credential common
 		return credential, ErrInvalidAccessKeyID
 	}
 	// Get signing key.
-	signingKey := getSigningKey(credential.SecretAccessKey, t, region)
+	signingKey := getSigningKey(credential.SecretAccessKey, t, region, credHeader.scope.service)
 
 	// Get signature.
 	newSignature := getSignature(signingKey, formValues["Policy"])
@@ -184,15 +187,23 @@ func DoesPolicySignatureMatchV4(formValues map[string]string) (credential common
 // returns true if matches, false otherwise. if error is not nil then it is always false
 func DoesPresignedSignatureMatchV4(r *http.Request,
 	validateRegion bool) (credential common.Credential, err error) {
+	return doesPresignedSignatureMatchV4(r, validateRegion, serviceS3)
+}
+
+// doesPresignedSignatureMatchV4 is DoesPresignedSignatureMatchV4 for requests
+// of `service`
+func doesPresignedSignatureMatchV4(r *http.Request, validateRegion bool,
+	service string) (credential common.Credential, err error) {
 	// Parse request query string.
-	preSignValues, err := parsePreSignV4(r.URL.Query(), r.Header)
+	preSignValues, err := parsePreSignV4(r.URL.Query(), r.Header, service)
 	if err != nil {
 		return credential, err
 	}
 
-	credential, e := iam.GetCredential(preSignValues.Credential.accessKey)
-	if e != nil {
-		return credential, ErrInvalidAccessKeyID
+	credential, err = getCredential(preSignValues.Credential.accessKey,
+		r.URL.Query().Get("X-Amz-Security-Token"))
+	if err != nil {
+		return credential, err
 	}
 
 	if preSignValues.Expires > PresignedUrlExpireLimit {
@@ -237,10 +248,10 @@ func DoesPresignedSignatureMatchV4(r *http.Request,
 		query.Encode(), r.URL.Path, r.Method)
 
 	// Get string to sign from canonical request.
-	presignedStringToSign := getStringToSign(presignedCanonicalReq, preSignValues.Date, region)
+	presignedStringToSign := getStringToSign(presignedCanonicalReq, preSignValues.Date, region, service)
 
 	// Get hmac presigned signing key.
-	presignedSigningKey := getSigningKey(credential.SecretAccessKey, preSignValues.Date, region)
+	presignedSigningKey := getSigningKey(credential.SecretAccessKey, preSignValues.Date, region, service)
 
 	// Get new signature.
 	newSignature := getSignature(presignedSigningKey, presignedStringToSign)
@@ -256,17 +267,12 @@ func DoesPresignedSignatureMatchV4(r *http.Request,
 func getCredentialUnverified(r *http.Request) (credential common.Credential, err error) {
 	v4Auth := r.Header.Get("Authorization")
 
-	signV4Values, err := parseSignV4(v4Auth, r.Header)
+	signV4Values, err := parseSignV4(v4Auth, r.Header, serviceS3)
 	if err != nil {
 		return credential, err
 	}
 
-	credential, e := iam.GetCredential(signV4Values.Credential.accessKey)
-	if e != nil {
-		return credential, ErrInvalidAccessKeyID
-	}
-
-	return credential, nil
+	return getCredential(signV4Values.Credential.accessKey, r.Header.Get("X-Amz-Security-Token"))
 }
 
 // doesSignatureMatch - Verify authorization header with calculated header in accordance with
@@ -274,11 +280,17 @@ func getCredentialUnverified(r *http.Request) (credential common.Credential, err
 // returns true if matches, false otherwise. if error is not nil then it is always false
 func DoesSignatureMatchV4(hashedPayload string, r *http.Request,
 	validateRegion bool) (credential common.Credential, err error) {
+	return doesSignatureMatchV4(hashedPayload, r, validateRegion, serviceS3)
+}
+
+// doesSignatureMatchV4 is DoesSignatureMatchV4 for requests of `service`
+func doesSignatureMatchV4(hashedPayload string, r *http.Request, validateRegion bool,
+	service string) (credential common.Credential, err error) {
 	// Save authorization header.
 	v4Auth := r.Header.Get("Authorization")
 
 	// Parse signature version '4' header.
-	signV4Values, err := parseSignV4(v4Auth, r.Header)
+	signV4Values, err := parseSignV4(v4Auth, r.Header, service)
 	if err != nil {
 		return credential, err
 	}
@@ -289,6 +301,11 @@ func DoesSignatureMatchV4(hashedPayload string, r *http.Request,
 	// It provides a hash of the request payload. If there is no payload, you must provide
 	// the hash of an empty string.
 	hashedPayloadReceived := r.Header.Get("X-Amz-Content-Sha256")
+	// only s3 requires the header, requests of other services like STS are
+	// signed with hash of their payload
+	if hashedPayloadReceived == "" && service != serviceS3 {
+		hashedPayloadReceived = hashedPayload
+	}
 	if hashedPayloadReceived != "UNSIGNED-PAYLOAD" && hashedPayloadReceived != hashedPayload {
 		return credential, ErrContentSHA256Mismatch
 	}
@@ -332,14 +349,14 @@ func DoesSignatureMatchV4(hashedPayload string, r *http.Request,
 		r.URL.Path, r.Method)
 
 	// Get string to sign from canonical request.
-	stringToSign := getStringToSign(canonicalRequest, t, region)
+	stringToSign := getStringToSign(canonicalRequest, t, region, service)
 
-	credential, e := iam.GetCredential(signV4Values.Credential.accessKey)
-	if e != nil {
-		return credential, ErrInvalidAccessKeyID
+	credential, err = getCredential(signV4Values.Credential.accessKey, r.Header.Get("X-Amz-Security-Token"))
+	if err != nil {
+		return credential, err
 	}
 	// Get hmac signing key.
-	signingKey := getSigningKey(credential.SecretAccessKey, t, region)
+	signingKey := getSigningKey(credential.SecretAccessKey, t, region, service)
 
 	// Calculate signature.
 	newSignature := getSignature(signingKey, stringToSign)
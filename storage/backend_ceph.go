@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"github.com/journeymidnight/yig/api/datatype"
 	"github.com/journeymidnight/yig/backend"
-	"github.com/journeymidnight/yig/ceph"
 	"github.com/journeymidnight/yig/crypto"
 	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
@@ -29,12 +28,17 @@ func New(metaCacheType int, enableDataCache bool, kms crypto.KMS) *YigStorage {
 		WaitGroup:   new(sync.WaitGroup),
 	}
 
-	yig.DataStorage = ceph.Initialize(helper.CONFIG)
-	if len(yig.DataStorage) == 0 {
+	clusters := backend.Initialize(&helper.Logger, helper.CONFIG)
+	if len(clusters) == 0 {
 		panic("No data storage can be used!")
 	}
+	yig.Topology = NewTopology(yig.MetaStorage, clusters)
+	yig.DataStorage = yig.Topology.Monitor(clusters)
+	yig.Topology.Refresh()
 
 	initializeRecycler(&yig)
+	yig.WaitGroup.Add(1)
+	go refreshTopology(&yig)
 	return &yig
 }
 
@@ -70,7 +74,7 @@ func (yig *YigStorage) AppendObject(bucketName string, objectName string, creden
 	if objInfo != nil {
 		cephCluster = yig.DataStorage[objInfo.Location]
 		// Every appendable file must be treated as a big file
-		poolName = backend.BIG_FILE_POOLNAME
+		poolName = objInfo.Pool
 		oid = objInfo.ObjectId
 		initializationVector = objInfo.InitializationVector
 		objSize = objInfo.Size
@@ -78,8 +82,11 @@ func (yig *YigStorage) AppendObject(bucketName string, objectName string, creden
 		helper.Logger.Println(20, "request append oid:", oid, "iv:", initializationVector, "size:", objSize)
 	} else {
 		// New appendable object
-		cephCluster, poolName = yig.pickClusterAndPool(bucketName, objectName, storageClass, size, true)
-		if cephCluster == nil || poolName != backend.BIG_FILE_POOLNAME {
+		cephCluster, poolName, err = yig.pickClusterAndPool(bucketName, objectName, storageClass, size, true)
+		if err != nil {
+			return
+		}
+		if poolName == backend.SMALL_FILE_POOLNAME {
 			helper.Logger.Warn("PickOneClusterAndPool error")
 			return result, ErrInternalError
 		}
@@ -160,5 +167,11 @@ func (yig *YigStorage) AppendObject(bucketName string, objectName string, creden
 		yig.MetaStorage.Cache.Remove(redis.ObjectTable, bucketName+":"+objectName+":")
 		yig.DataCache.Remove(bucketName + ":" + objectName + ":" + object.GetVersionId())
 	}
+	if bucket, err := yig.MetaStorage.GetBucket(bucketName, true); err == nil {
+		yig.enqueueReplication(bucket, objectName, object.LastModifiedTime,
+			types.ReplicationOperationPut)
+		yig.sendNotification(bucket, datatype.EventObjectCreatedAppend, credential.UserId,
+			eventObjectFromMeta(object))
+	}
 	return result, nil
 }
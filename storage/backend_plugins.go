@@ -0,0 +1,6 @@
+package storage
+
+import (
+	// built-in backends register themselves into backend package
+	_ "github.com/journeymidnight/yig/fs"
+)
@@ -0,0 +1,9 @@
+//go:build !noceph
+// +build !noceph
+
+package storage
+
+import (
+	// ceph backend requires librados, build with `-tags noceph` to leave it out
+	_ "github.com/journeymidnight/yig/ceph"
+)
@@ -9,6 +9,7 @@ import (
 	"github.com/journeymidnight/yig/api"
 	"github.com/journeymidnight/yig/api/datatype"
 	"github.com/journeymidnight/yig/api/datatype/policy"
+	"github.com/journeymidnight/yig/backend"
 	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam"
@@ -19,7 +20,7 @@ import (
 )
 
 func (yig *YigStorage) MakeBucket(bucketName string, acl datatype.Acl,
-	credential common.Credential) error {
+	credential common.Credential, objectLockEnabled bool) error {
 	// Input validation.
 	if err := api.CheckValidBucketName(bucketName); err != nil {
 		return err
@@ -33,6 +34,11 @@ func (yig *YigStorage) MakeBucket(bucketName string, acl datatype.Acl,
 		ACL:        acl,
 		Versioning: meta.VersionDisabled, // it's the default
 	}
+	// object lock works only with versioning enabled
+	if objectLockEnabled {
+		bucket.Versioning = meta.VersionEnabled
+		bucket.ObjectLock.ObjectLockEnabled = datatype.ObjectLockEnabled
+	}
 	processed, err := yig.MetaStorage.Client.CheckAndPutBucket(bucket)
 	if err != nil {
 		helper.Logger.Error("Error making CheckAndPut:", err)
@@ -82,6 +88,9 @@ func (yig *YigStorage) SetBucketAcl(bucketName string, policy datatype.AccessCon
 	if bucket.OwnerId != credential.UserId {
 		return ErrBucketAccessForbidden
 	}
+	if datatype.IsPublicAcl(acl) && bucket.PublicAccessBlock.Effective().BlockPublicAcls {
+		return ErrPublicAccessBlocked
+	}
 	bucket.ACL = acl
 	err = yig.MetaStorage.Client.PutBucket(*bucket)
 	if err != nil {
@@ -109,6 +118,45 @@ func (yig *YigStorage) SetBucketLogging(bucketName string, bl datatype.BucketLog
 	return nil
 }
 
+// SetBucketPlacement changes where data of new objects in bucket is written,
+// existing objects are not moved
+func (yig *YigStorage) SetBucketPlacement(bucketName string, placement meta.Placement) error {
+	err := placement.Validate()
+	if err != nil {
+		return err
+	}
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, false)
+	if err != nil {
+		return err
+	}
+	bucket.Placement = placement
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucketName)
+	return nil
+}
+
+// IsPlacementPool returns whether new data could be written into pool `pool`,
+// i.e. it's one of default pools or used by placement of any bucket
+func (yig *YigStorage) IsPlacementPool(pool string) (bool, error) {
+	switch pool {
+	case backend.SMALL_FILE_POOLNAME, backend.BIG_FILE_POOLNAME, backend.GLACIER_FILE_POOLNAME:
+		return true, nil
+	}
+	buckets, err := yig.MetaStorage.GetBuckets()
+	if err != nil {
+		return false, err
+	}
+	for _, b := range buckets {
+		if b.Placement.BigFilePool == pool || b.Placement.GlacierFilePool == pool {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (yig *YigStorage) GetBucketLogging(bucketName string) (bl datatype.BucketLoggingStatus,
 	err error) {
 	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
@@ -256,6 +304,9 @@ func (yig *YigStorage) SetBucketVersioning(bucketName string, versioning datatyp
 	if bucket.OwnerId != credential.UserId {
 		return ErrBucketAccessForbidden
 	}
+	if bucket.ObjectLock.IsEnabled() && versioning.Status != meta.VersionEnabled {
+		return ErrInvalidBucketState
+	}
 	bucket.Versioning = versioning.Status
 	err = yig.MetaStorage.Client.PutBucket(*bucket)
 	if err != nil {
@@ -315,7 +366,7 @@ func (yig *YigStorage) GetBucketInfo(bucketName string,
 
 	if !credential.AllowOtherUserAccess {
 		if bucket.OwnerId != credential.UserId {
-			switch bucket.ACL.CannedAcl {
+			switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 			case "public-read", "public-read-write", "authenticated-read":
 				break
 			default:
@@ -337,7 +388,7 @@ func (yig *YigStorage) GetBucketInfoByCtx(ctx api.RequestContext,
 	}
 	if !credential.AllowOtherUserAccess {
 		if bucket.OwnerId != credential.UserId {
-			switch bucket.ACL.CannedAcl {
+			switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 			case "public-read", "public-read-write", "authenticated-read":
 				break
 			default:
@@ -358,6 +409,9 @@ func (yig *YigStorage) SetBucketPolicy(credential common.Credential, bucketName
 	if bucket.OwnerId != credential.UserId {
 		return ErrBucketAccessForbidden
 	}
+	if bucketPolicy.IsPublic() && bucket.PublicAccessBlock.Effective().BlockPublicPolicy {
+		return ErrPublicAccessBlocked
+	}
 	data, err := bucketPolicy.MarshalJSON()
 	if err != nil {
 		return
@@ -606,7 +660,7 @@ func (yig *YigStorage) ListObjects(credential common.Credential, bucketName stri
 		return
 	}
 
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read", "public-read-write":
 		break
 	case "authenticated-read":
@@ -680,7 +734,7 @@ func (yig *YigStorage) ListVersionedObjects(credential common.Credential, bucket
 		return
 	}
 
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read", "public-read-write":
 		break
 	case "authenticated-read":
@@ -746,3 +800,138 @@ func (yig *YigStorage) ListVersionedObjects(credential common.Credential, bucket
 
 	return
 }
+
+func (yig *YigStorage) SetBucketTagging(bucket *meta.Bucket, tagging map[string]string) (err error) {
+	bucket.Tagging = tagging
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucket.Name)
+	return nil
+}
+
+func (yig *YigStorage) GetBucketTagging(bucketName string) (tagging datatype.Tagging, err error) {
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	if len(bucket.Tagging) == 0 {
+		return tagging, ErrNoSuchTagSet
+	}
+	return datatype.TaggingFromMap(bucket.Tagging), nil
+}
+
+func (yig *YigStorage) DeleteBucketTagging(bucket *meta.Bucket) error {
+	return yig.SetBucketTagging(bucket, nil)
+}
+
+func (yig *YigStorage) SetBucketNotification(bucket *meta.Bucket,
+	config datatype.NotificationConfiguration) (err error) {
+
+	bucket.Notification = config
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucket.Name)
+	return nil
+}
+
+func (yig *YigStorage) GetBucketNotification(bucketName string) (config datatype.NotificationConfiguration,
+	err error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	return bucket.Notification, nil
+}
+
+func (yig *YigStorage) SetBucketReplication(bucket *meta.Bucket,
+	config datatype.ReplicationConfiguration) (err error) {
+
+	bucket.Replication = config
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucket.Name)
+	return nil
+}
+
+func (yig *YigStorage) GetBucketReplication(bucketName string) (config datatype.ReplicationConfiguration,
+	err error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	if len(bucket.Replication.Rules) == 0 {
+		return config, ErrReplicationConfigurationNotFound
+	}
+	return bucket.Replication, nil
+}
+
+func (yig *YigStorage) DeleteBucketReplication(bucket *meta.Bucket) error {
+	return yig.SetBucketReplication(bucket, datatype.ReplicationConfiguration{})
+}
+
+// SetBucketObjectLock updates default retention of bucket, object lock
+// could only be enabled when creating bucket
+func (yig *YigStorage) SetBucketObjectLock(bucket *meta.Bucket,
+	config datatype.ObjectLockConfiguration) (err error) {
+
+	if !bucket.ObjectLock.IsEnabled() {
+		return ErrInvalidBucketState
+	}
+	bucket.ObjectLock = config
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucket.Name)
+	return nil
+}
+
+func (yig *YigStorage) GetBucketObjectLock(bucketName string) (config datatype.ObjectLockConfiguration,
+	err error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	if !bucket.ObjectLock.IsEnabled() {
+		return config, ErrObjectLockConfigurationNotFound
+	}
+	return bucket.ObjectLock, nil
+}
+
+func (yig *YigStorage) SetBucketPublicAccessBlock(bucket *meta.Bucket,
+	config datatype.PublicAccessBlockConfiguration) (err error) {
+
+	bucket.PublicAccessBlock = config
+	err = yig.MetaStorage.Client.PutBucket(*bucket)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.BucketTable, bucket.Name)
+	return nil
+}
+
+func (yig *YigStorage) GetBucketPublicAccessBlock(bucketName string) (config datatype.PublicAccessBlockConfiguration,
+	err error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	if bucket.PublicAccessBlock.IsEmpty() {
+		return config, ErrNoSuchPublicAccessBlockConfiguration
+	}
+	return bucket.PublicAccessBlock, nil
+}
+
+func (yig *YigStorage) DeleteBucketPublicAccessBlock(bucket *meta.Bucket) error {
+	return yig.SetBucketPublicAccessBlock(bucket, datatype.PublicAccessBlockConfiguration{})
+}
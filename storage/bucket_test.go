@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta"
+	types "github.com/journeymidnight/yig/meta/types"
+)
+
+// newTestStorage creates storage on an in-memory meta store and `clusters`
+func newTestStorage(t *testing.T, clusters map[string]backend.Cluster) *YigStorage {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	helper.CONFIG.MetaStore = "sqlite"
+	helper.CONFIG.MetaStorePath = ""
+	metaStorage := meta.New(meta.NoCache)
+	topology := NewTopology(metaStorage, clusters)
+	return &YigStorage{
+		DataStorage: topology.Monitor(clusters),
+		Topology:    topology,
+		MetaStorage: metaStorage,
+	}
+}
+
+func TestIsPlacementPool(t *testing.T) {
+	yig := newTestStorage(t, nil)
+	_, err := yig.MetaStorage.Client.CheckAndPutBucket(types.Bucket{
+		Name:       "hehe",
+		CreateTime: time.Now(),
+		Placement:  types.Placement{BigFilePool: "big", GlacierFilePool: "cold"},
+	})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	for pool, expected := range map[string]bool{
+		backend.SMALL_FILE_POOLNAME:   true,
+		backend.BIG_FILE_POOLNAME:     true,
+		backend.GLACIER_FILE_POOLNAME: true,
+		"big":                         true,
+		"cold":                        true,
+		"typo":                        false,
+	} {
+		known, err := yig.IsPlacementPool(pool)
+		if err != nil || known != expected {
+			t.Fatal("IsPlacementPool", pool, known, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/journeymidnight/yig/helper"
+	meta "github.com/journeymidnight/yig/meta/types"
+)
+
+var ErrNoClusterToMove = errors.New("no active cluster to move data into")
+
+// MoveObject moves data of an object version out of its cluster into another
+// active cluster of the same pool allowed by bucket placement, used to drain
+// clusters to be retired. Data is copied as stored, then the object row is
+// switched to the copy only if it still refers to the original data, and the
+// original data is put into gc. Returns false if the object is changed or
+// deleted while copying, and the copy is recycled.
+func (yig *YigStorage) MoveObject(object *meta.Object) (moved bool, err error) {
+	if object.DeleteMarker || (object.ObjectId == "" && len(object.Parts) == 0) {
+		return false, nil
+	}
+	sourceCluster, ok := yig.DataStorage[object.Location]
+	if !ok {
+		return false, errors.New("Cannot find specified ceph cluster: " + object.Location)
+	}
+	var placement meta.Placement
+	if bucket, err := yig.MetaStorage.GetBucket(object.BucketName, true); err == nil {
+		placement = bucket.Placement
+	}
+	cluster := yig.pickCluster(object.Pool, object.Location, placement)
+	if cluster == nil {
+		return false, ErrNoClusterToMove
+	}
+
+	var copied []objectToRecycle
+	defer func() {
+		if err != nil || !moved {
+			for _, c := range copied {
+				RecycleQueue <- c
+			}
+		}
+	}()
+	target := *object
+	if len(object.Parts) == 0 {
+		target.ObjectId, err = copyRawData(sourceCluster, object.Pool, object.ObjectId,
+			object.Size, cluster, object.Pool)
+		if err != nil {
+			return false, err
+		}
+		copied = append(copied, objectToRecycle{
+			location: cluster.ID(),
+			pool:     object.Pool,
+			objectId: target.ObjectId,
+		})
+	} else {
+		target.Parts = make(map[int]*meta.Part, len(object.Parts))
+		for number, part := range object.Parts {
+			targetPart := *part
+			targetPart.ObjectId, err = copyRawData(sourceCluster, object.Pool, part.ObjectId,
+				part.Size, cluster, object.Pool)
+			if err != nil {
+				return false, err
+			}
+			copied = append(copied, objectToRecycle{
+				location: cluster.ID(),
+				pool:     object.Pool,
+				objectId: targetPart.ObjectId,
+			})
+			target.Parts[number] = &targetPart
+		}
+	}
+	target.Location = cluster.ID()
+
+	moved, err = yig.MetaStorage.MoveObject(&target, object)
+	if err != nil {
+		helper.Logger.Error("Move object, sql fails:", err)
+		return false, err
+	}
+	if moved {
+		yig.removeTransitionedObjectCache(&target)
+	}
+	return moved, nil
+}
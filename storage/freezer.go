@@ -1,6 +1,12 @@
 package storage
 
 import (
+	"errors"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
 	meta "github.com/journeymidnight/yig/meta/types"
 )
 
@@ -9,7 +15,18 @@ func (yig *YigStorage) GetFreezerStatus(bucketName string, objectName string, ve
 }
 
 func (yig *YigStorage) CreateFreezer(freezer *meta.Freezer) (err error) {
-	return yig.MetaStorage.CreateFreezer(freezer)
+	err = yig.MetaStorage.CreateFreezer(freezer)
+	if err != nil {
+		return
+	}
+	if bucket, err := yig.MetaStorage.GetBucket(freezer.BucketName, true); err == nil {
+		yig.sendNotification(bucket, datatype.EventObjectRestorePost, "", datatype.EventObject{
+			Key:       freezer.Name,
+			VersionId: freezer.VersionId,
+			Sequencer: eventSequencer(time.Now().UTC()),
+		})
+	}
+	return nil
 }
 
 func (yig *YigStorage) GetFreezer(bucketName string, objectName string, version string) (freezer *meta.Freezer, err error) {
@@ -36,3 +53,100 @@ func (yig *YigStorage) UpdateFreezerDate(freezer *meta.Freezer, date int, isIncr
 	freezer.LifeTime = lifeTime
 	return yig.MetaStorage.UpdateFreezerDate(freezer)
 }
+
+// RestoreObject copies data of a GLACIER object into a STANDARD pool as a
+// temporary copy, and marks the freezer as restored.
+// `freezer` should have been claimed in RESTORING status by caller.
+func (yig *YigStorage) RestoreObject(freezer *meta.Freezer) (err error) {
+	object, err := yig.getObjectWithOptionalVersion(freezer.BucketName, freezer.Name, "")
+	if err != nil {
+		return err
+	}
+	if object.DeleteMarker || object.StorageClass != meta.ObjectStorageClassGlacier {
+		return ErrInvalidObjectState
+	}
+	sourceCluster, ok := yig.DataStorage[object.Location]
+	if !ok {
+		return errors.New("Cannot find specified ceph cluster: " + object.Location)
+	}
+	cluster, poolName, err := yig.pickClusterAndPool(object.BucketName, object.Name,
+		meta.ObjectStorageClassStandard, object.Size, false)
+	if err != nil {
+		// no writable cluster for now, the restore fails and is retried later
+		return err
+	}
+
+	restored := *freezer
+	var copied []objectToRecycle
+	recycleCopied := func() {
+		for _, c := range copied {
+			RecycleQueue <- c
+		}
+	}
+	if len(object.Parts) == 0 {
+		restored.ObjectId, err = copyRawData(sourceCluster, object.Pool, object.ObjectId,
+			object.Size, cluster, poolName)
+		if err != nil {
+			return err
+		}
+		copied = append(copied, objectToRecycle{
+			location: cluster.ID(),
+			pool:     poolName,
+			objectId: restored.ObjectId,
+		})
+	} else {
+		restored.Parts = make(map[int]*meta.Part, len(object.Parts))
+		for number, part := range object.Parts {
+			restoredPart := *part
+			restoredPart.ObjectId, err = copyRawData(sourceCluster, object.Pool, part.ObjectId,
+				part.Size, cluster, poolName)
+			if err != nil {
+				recycleCopied()
+				return err
+			}
+			copied = append(copied, objectToRecycle{
+				location: cluster.ID(),
+				pool:     poolName,
+				objectId: restoredPart.ObjectId,
+			})
+			restored.Parts[number] = &restoredPart
+		}
+	}
+	restored.Location = cluster.ID()
+	restored.Pool = poolName
+	restored.OwnerId = object.OwnerId
+	restored.Size = object.Size
+	restored.Etag = object.Etag
+	// lifetime of restored copy counts from now on
+	restored.LastModifiedTime = time.Now().UTC()
+
+	err = yig.MetaStorage.UpdateFreezer(&restored, meta.ObjectHasRestored)
+	if err != nil {
+		helper.Logger.Error("Restore object, sql fails:", err)
+		recycleCopied()
+		return ErrInternalError
+	}
+	if bucket, err := yig.MetaStorage.GetBucket(freezer.BucketName, true); err == nil {
+		yig.sendNotification(bucket, datatype.EventObjectRestoreCompleted, "", datatype.EventObject{
+			Key:       object.Name,
+			Size:      object.Size,
+			ETag:      object.Etag,
+			VersionId: object.GetVersionId(),
+			Sequencer: eventSequencer(restored.LastModifiedTime),
+		})
+	}
+	return nil
+}
+
+// ExpireRestoredObject removes freezer whose lifetime has passed, and puts the
+// restored copy into gc
+func (yig *YigStorage) ExpireRestoredObject(bucketName, objectName string) error {
+	freezer, err := yig.MetaStorage.GetFreezer(bucketName, objectName, "")
+	if err != nil {
+		return err
+	}
+	if freezer.Status != meta.ObjectHasRestored {
+		return nil
+	}
+	return yig.MetaStorage.DeleteFreezer(freezer)
+}
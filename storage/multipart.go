@@ -34,7 +34,7 @@ func (yig *YigStorage) ListMultipartUploads(credential common.Credential, bucket
 	if err != nil {
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read", "public-read-write":
 		break
 	case "authenticated-read":
@@ -83,14 +83,15 @@ func (yig *YigStorage) ListMultipartUploads(credential common.Credential, bucket
 }
 
 func (yig *YigStorage) NewMultipartUpload(credential common.Credential, bucketName, objectName string,
-	metadata map[string]string, acl datatype.Acl,
-	sseRequest datatype.SseRequest, storageClass meta.StorageClass) (uploadId string, err error) {
+	metadata map[string]string, acl datatype.Acl, sseRequest datatype.SseRequest,
+	storageClass meta.StorageClass, tagging map[string]string,
+	lock datatype.ObjectLockRequest) (uploadId string, err error) {
 
 	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
 	if err != nil {
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -99,13 +100,19 @@ func (yig *YigStorage) NewMultipartUpload(credential common.Credential, bucketNa
 		}
 	}
 	// TODO policy and fancy ACL
+	if !bucket.ObjectLock.IsEnabled() && (lock.Mode != "" || lock.LegalHold) {
+		return "", ErrObjectLockNotEnabled
+	}
 
 	contentType, ok := metadata["Content-Type"]
 	if !ok {
 		contentType = "application/octet-stream"
 	}
 
-	cephCluster, pool := yig.pickClusterAndPool(bucketName, objectName, storageClass, -1, false)
+	cephCluster, pool, err := yig.pickClusterAndPool(bucketName, objectName, storageClass, -1, false)
+	if err != nil {
+		return "", err
+	}
 	multipartMetadata := meta.MultipartMetadata{
 		InitiatorId:  credential.UserId,
 		OwnerId:      bucket.OwnerId,
@@ -116,6 +123,8 @@ func (yig *YigStorage) NewMultipartUpload(credential common.Credential, bucketNa
 		SseRequest:   sseRequest,
 		Attrs:        metadata,
 		StorageClass: storageClass,
+		Tagging:      tagging,
+		ObjectLock:   lock,
 	}
 	if sseRequest.Type == crypto.S3.String() {
 		multipartMetadata.EncryptionKey, multipartMetadata.CipherKey, err = yig.encryptionKeyFromSseRequest(sseRequest, bucketName, objectName)
@@ -231,7 +240,7 @@ func (yig *YigStorage) PutObjectPart(bucketName, objectName string, credential c
 		RecycleQueue <- maybeObjectToRecycle
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -348,7 +357,7 @@ func (yig *YigStorage) CopyObjectPart(bucketName, objectName, uploadId string, p
 		RecycleQueue <- maybeObjectToRecycle
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -402,7 +411,11 @@ func (yig *YigStorage) ListObjectParts(credential common.Credential, bucketName,
 	initiatorId := multipart.Metadata.InitiatorId
 	ownerId := multipart.Metadata.OwnerId
 
-	switch multipart.Metadata.Acl.CannedAcl {
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(multipart.Metadata.Acl) {
 	case "public-read", "public-read-write":
 		break
 	case "authenticated-read":
@@ -411,11 +424,6 @@ func (yig *YigStorage) ListObjectParts(credential common.Credential, bucketName,
 			return
 		}
 	case "bucket-owner-read", "bucket-owner-full-controll":
-		var bucket *meta.Bucket
-		bucket, err = yig.MetaStorage.GetBucket(bucketName, true)
-		if err != nil {
-			return
-		}
 		if bucket.OwnerId != credential.UserId {
 			err = ErrAccessDenied
 			return
@@ -482,7 +490,7 @@ func (yig *YigStorage) AbortMultipartUpload(credential common.Credential,
 	if err != nil {
 		return err
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -496,20 +504,11 @@ func (yig *YigStorage) AbortMultipartUpload(credential common.Credential,
 		return err
 	}
 
+	// parts in Ceph are put into gc along with removing the upload
 	err = yig.MetaStorage.DeleteMultipart(multipart)
 	if err != nil {
 		return err
 	}
-	// remove parts in Ceph
-	var removedSize int64 = 0
-	for _, p := range multipart.Parts {
-		RecycleQueue <- objectToRecycle{
-			location: multipart.Metadata.Location,
-			pool:     multipart.Metadata.Pool,
-			objectId: p.ObjectId,
-		}
-		removedSize += p.Size
-	}
 
 	return nil
 }
@@ -522,7 +521,7 @@ func (yig *YigStorage) CompleteMultipartUpload(credential common.Credential, buc
 	if err != nil {
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -608,6 +607,12 @@ func (yig *YigStorage) CompleteMultipartUpload(credential common.Credential, buc
 		CustomAttributes: multipart.Metadata.Attrs,
 		Type:             meta.ObjectTypeMultipart,
 		StorageClass:     multipart.Metadata.StorageClass,
+		Tagging:          multipart.Metadata.Tagging,
+	}
+	markReplicationPending(bucket, object)
+	err = applyObjectLock(bucket, object, multipart.Metadata.ObjectLock)
+	if err != nil {
+		return
 	}
 
 	var nullVerNum uint64
@@ -655,6 +660,9 @@ func (yig *YigStorage) CompleteMultipartUpload(credential common.Credential, buc
 	if err == nil {
 		yig.MetaStorage.Cache.Remove(redis.ObjectTable, bucketName+":"+objectName+":")
 		yig.DataCache.Remove(bucketName + ":" + objectName + ":" + object.GetVersionId())
+		yig.enqueueReplication(bucket, objectName, object.LastModifiedTime, meta.ReplicationOperationPut)
+		yig.sendNotification(bucket, datatype.EventObjectCreatedCompleteMultipartUpload,
+			credential.UserId, eventObjectFromMeta(object))
 	}
 
 	return
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/helper"
+	meta "github.com/journeymidnight/yig/meta/types"
+	bus "github.com/journeymidnight/yig/mq"
+)
+
+const (
+	NotificationEventVersion  = "2.1"
+	NotificationEventSource   = "yig:s3"
+	NotificationSchemaVersion = "1.0"
+)
+
+// eventSequencer orders events of the same object key
+func eventSequencer(t time.Time) string {
+	return fmt.Sprintf("%016X", t.UnixNano())
+}
+
+func eventObjectFromMeta(object *meta.Object) datatype.EventObject {
+	eventObject := datatype.EventObject{
+		Key:       object.Name,
+		Size:      object.Size,
+		ETag:      object.Etag,
+		Sequencer: eventSequencer(object.LastModifiedTime),
+	}
+	if !object.NullVersion {
+		eventObject.VersionId = object.GetVersionId()
+	}
+	return eventObject
+}
+
+// sendNotification emits one S3 style event record for every notification
+// rule of `bucket` which subscribes `eventName` on `object`, records are
+// sent as JSON to the topic named by the destination ARN of the rule.
+func (yig *YigStorage) sendNotification(bucket *meta.Bucket, eventName string,
+	principalId string, object datatype.EventObject) {
+
+	if bus.MsgSender == nil {
+		return
+	}
+	targets := bucket.Notification.Targets()
+	if len(targets) == 0 {
+		return
+	}
+	sender, ok := bus.MsgSender.(bus.TopicSender)
+	if !ok {
+		helper.Logger.Error("Message queue plugin could not route notifications of",
+			bucket.Name, "to their destinations")
+		return
+	}
+	now := time.Now().UTC()
+	for _, rule := range targets {
+		if !rule.Match(eventName, object.Key) {
+			continue
+		}
+		topic, ok := datatype.DestinationTopic(rule.Destination)
+		if !ok {
+			helper.Logger.Error("Invalid notification destination", rule.Destination,
+				"of", bucket.Name)
+			continue
+		}
+		message := datatype.EventMessage{
+			Records: []datatype.EventRecord{{
+				EventVersion: NotificationEventVersion,
+				EventSource:  NotificationEventSource,
+				AwsRegion:    helper.CONFIG.Region,
+				EventTime:    now.Format(meta.CREATE_TIME_LAYOUT),
+				EventName:    strings.TrimPrefix(eventName, "s3:"),
+				UserIdentity: datatype.EventIdentity{
+					PrincipalId: principalId,
+				},
+				RequestParameters: map[string]string{},
+				ResponseElements:  map[string]string{},
+				S3: datatype.EventS3Entity{
+					SchemaVersion:   NotificationSchemaVersion,
+					ConfigurationId: rule.Id,
+					Bucket: datatype.EventBucket{
+						Name: bucket.Name,
+						OwnerIdentity: datatype.EventIdentity{
+							PrincipalId: bucket.OwnerId,
+						},
+						Arn: "arn:aws:s3:::" + bucket.Name,
+					},
+					Object: object,
+				},
+			}},
+		}
+		value, err := json.Marshal(message)
+		if err != nil {
+			helper.Logger.Error("Failed to marshal notification for", bucket.Name,
+				object.Key, "err:", err)
+			continue
+		}
+		err = sender.AsyncSendToTopic(topic, value)
+		if err != nil {
+			helper.Logger.Error("Failed to send notification for", bucket.Name,
+				object.Key, "err:", err)
+		}
+	}
+}
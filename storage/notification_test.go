@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	meta "github.com/journeymidnight/yig/meta/types"
+	bus "github.com/journeymidnight/yig/mq"
+)
+
+type sentMessage struct {
+	topic string
+	value []byte
+}
+
+type fakeSender struct {
+	sent []sentMessage
+}
+
+func (s *fakeSender) AsyncSend(value []byte) error {
+	s.sent = append(s.sent, sentMessage{"", value})
+	return nil
+}
+
+func (s *fakeSender) AsyncSendToTopic(topic string, value []byte) error {
+	s.sent = append(s.sent, sentMessage{topic, value})
+	return nil
+}
+
+func (s *fakeSender) Flush(timeout int) error { return nil }
+
+func (s *fakeSender) Close() {}
+
+func TestSendNotification(t *testing.T) {
+	helper.Logger = log.NewLogger(os.Stdout, log.ErrorLevel)
+	sender := new(fakeSender)
+	bus.MsgSender = sender
+	defer func() { bus.MsgSender = nil }()
+
+	bucket := &meta.Bucket{
+		Name:    "notified",
+		OwnerId: "owner",
+		Notification: datatype.NotificationConfiguration{
+			QueueConfigurations: []datatype.QueueConfiguration{{
+				NotificationRule: datatype.NotificationRule{
+					Id:     "created",
+					Events: []string{datatype.EventObjectCreatedAll},
+				},
+				Queue: "arn:yig:sqs:::created-events",
+			}},
+			TopicConfigurations: []datatype.TopicConfiguration{{
+				NotificationRule: datatype.NotificationRule{
+					Id:     "removed",
+					Events: []string{datatype.EventObjectRemovedAll},
+				},
+				Topic: "arn:yig:sns:::removed-events",
+			}},
+		},
+	}
+	object := &meta.Object{
+		Name:             "a.jpg",
+		Size:             42,
+		Etag:             "etag",
+		LastModifiedTime: time.Now(),
+		NullVersion:      true,
+	}
+	yig := new(YigStorage)
+	yig.sendNotification(bucket, datatype.EventObjectCreatedPost, "user",
+		eventObjectFromMeta(object))
+
+	if len(sender.sent) != 1 {
+		t.Fatal("Expected one event, got", len(sender.sent))
+	}
+	if sender.sent[0].topic != "created-events" {
+		t.Fatal("Event sent to wrong topic:", sender.sent[0].topic)
+	}
+	var message datatype.EventMessage
+	err := json.Unmarshal(sender.sent[0].value, &message)
+	if err != nil {
+		t.Fatal("Unmarshal event:", err)
+	}
+	if len(message.Records) != 1 {
+		t.Fatal("Expected one record:", message)
+	}
+	record := message.Records[0]
+	if record.EventName != "ObjectCreated:Post" || record.S3.ConfigurationId != "created" ||
+		record.S3.Bucket.Name != "notified" || record.S3.Object.Key != "a.jpg" ||
+		record.S3.Object.Size != 42 || record.UserIdentity.PrincipalId != "user" {
+		t.Fatal("Unexpected record:", record)
+	}
+
+	yig.sendNotification(bucket, datatype.EventObjectRestorePost, "",
+		eventObjectFromMeta(object))
+	if len(sender.sent) != 1 {
+		t.Fatal("Event not subscribed should not be sent")
+	}
+	yig.sendNotification(bucket, datatype.EventObjectRemovedDelete, "user",
+		datatype.EventObject{Key: "a.jpg"})
+	if len(sender.sent) != 2 || sender.sent[1].topic != "removed-events" {
+		t.Fatal("Delete event not routed to its topic:", sender.sent)
+	}
+}
@@ -23,83 +23,85 @@ import (
 	"github.com/journeymidnight/yig/signature"
 )
 
-var latestQueryTime [3]time.Time // 0 is for SMALL_FILE_POOLNAME, 1 is for BIG_FILE_POOLNAME, 2 is for GLACIER_FILE_POOLNAME
 const (
 	CLUSTER_MAX_USED_SPACE_PERCENT = 85
 	BIG_FILE_THRESHOLD             = 128 << 10 /* 128K */
 )
 
-func (yig *YigStorage) pickRandomCluster() (cluster backend.Cluster) {
-	helper.Logger.Warn("Error picking cluster from table cluster in DB, " +
-		"use first cluster in config to write.")
-	for _, c := range yig.DataStorage {
-		cluster = c
-		break
-	}
-	return
-}
-
+// pickClusterAndPool picks cluster and pool for new data of an object,
+// placement of bucket `bucket` is honored. Returns ErrInternalError if no
+// cluster of the pool could be written into
 func (yig *YigStorage) pickClusterAndPool(bucket string, object string, storageClass meta.StorageClass,
-	size int64, isAppend bool) (cluster backend.Cluster, poolName string) {
+	size int64, isAppend bool) (cluster backend.Cluster, poolName string, err error) {
+
+	var placement meta.Placement
+	if b, err := yig.MetaStorage.GetBucket(bucket, true); err == nil {
+		placement = b.Placement
+	}
+	threshold := int64(BIG_FILE_THRESHOLD)
+	if placement.BigFileThreshold != 0 {
+		threshold = placement.BigFileThreshold
+	}
 
-	var idx int
 	if storageClass == meta.ObjectStorageClassGlacier {
 		poolName = backend.GLACIER_FILE_POOLNAME
-		idx = 2
+		if placement.GlacierFilePool != "" {
+			poolName = placement.GlacierFilePool
+		}
+	} else if !isAppend && size >= 0 && size < threshold {
+		// request.ContentLength is -1 if length is unknown
+		poolName = backend.SMALL_FILE_POOLNAME
 	} else {
-		if isAppend {
-			poolName = backend.BIG_FILE_POOLNAME
-			idx = 1
-		} else if size < 0 { // request.ContentLength is -1 if length is unknown
-			poolName = backend.BIG_FILE_POOLNAME
-			idx = 1
-		} else if size < BIG_FILE_THRESHOLD {
-			poolName = backend.SMALL_FILE_POOLNAME
-			idx = 0
-		} else {
-			poolName = backend.BIG_FILE_POOLNAME
-			idx = 1
+		poolName = backend.BIG_FILE_POOLNAME
+		if placement.BigFilePool != "" {
+			poolName = placement.BigFilePool
 		}
 	}
-	var needCheck bool
-	queryTime := latestQueryTime[idx]
-	if time.Since(queryTime).Hours() > 24 { // check used space every 24 hours
-		latestQueryTime[idx] = time.Now()
-		needCheck = true
+	cluster = yig.pickCluster(poolName, "", placement)
+	if cluster == nil {
+		helper.Logger.Error("No cluster of pool", poolName, "could be picked for",
+			bucket, object)
+		return nil, "", ErrInternalError
 	}
+	return
+}
+
+// pickCluster picks a cluster of pool `poolName` by weight from topology,
+// skipping draining, unhealthy and nearly full clusters, cluster `excluded`
+// and clusters not allowed by `placement`. Returns nil if there's no candidate.
+func (yig *YigStorage) pickCluster(poolName string, excluded string,
+	placement meta.Placement) (cluster backend.Cluster) {
+
+	topology := yig.Topology.Snapshot()
 	var totalWeight int
 	clusterWeights := make(map[string]int, len(yig.DataStorage))
-	metaClusters, err := yig.MetaStorage.GetClusters()
-	if err != nil {
-		cluster = yig.pickRandomCluster()
-		return
-	}
-	for _, cluster := range metaClusters {
+	for _, cluster := range topology.Clusters {
 		if cluster.Weight == 0 {
 			continue
 		}
 		if cluster.Pool != poolName {
 			continue
 		}
-		if needCheck {
-			usage, err := yig.DataStorage[cluster.Fsid].GetUsage()
-			if err != nil {
-				helper.Logger.Warn("Error getting used space: ", err,
-					"fsid: ", cluster.Fsid)
-				continue
-			}
-			if usage.UsedSpacePercent > CLUSTER_MAX_USED_SPACE_PERCENT {
-				helper.Logger.Warn("Cluster used space exceed ",
-					CLUSTER_MAX_USED_SPACE_PERCENT, cluster.Fsid)
-				continue
-			}
+		if cluster.Status == meta.ClusterStatusDraining || cluster.Fsid == excluded ||
+			!placement.AllowCluster(cluster.Fsid) {
+			continue
+		}
+		// cluster of other backends
+		health, ok := topology.Health[cluster.Fsid]
+		if !ok {
+			continue
+		}
+		if !health.Healthy {
+			continue
+		}
+		if health.UsedSpacePercent > CLUSTER_MAX_USED_SPACE_PERCENT {
+			continue
 		}
 		totalWeight += cluster.Weight
 		clusterWeights[cluster.Fsid] = cluster.Weight
 	}
 	if len(clusterWeights) == 0 || totalWeight == 0 {
-		cluster = yig.pickRandomCluster()
-		return
+		return nil
 	}
 	N := rand.Intn(totalWeight)
 	n := 0
@@ -336,7 +338,7 @@ func (yig *YigStorage) GetObjectInfo(bucketName string, objectName string,
 	}
 
 	if !credential.AllowOtherUserAccess {
-		switch object.ACL.CannedAcl {
+		switch bucket.PublicAccessBlock.Effective().CannedAcl(object.ACL) {
 		case "public-read", "public-read-write":
 			break
 		case "authenticated-read":
@@ -378,7 +380,7 @@ func (yig *YigStorage) GetObjectInfoByCtx(ctx api.RequestContext,
 	}
 
 	if !credential.AllowOtherUserAccess {
-		switch object.ACL.CannedAcl {
+		switch bucket.PublicAccessBlock.Effective().CannedAcl(object.ACL) {
 		case "public-read", "public-read-write":
 			break
 		case "authenticated-read":
@@ -472,6 +474,9 @@ func (yig *YigStorage) SetObjectAcl(bucketName string, objectName string, versio
 			return ErrAccessDenied
 		}
 	} // TODO policy and fancy ACL
+	if datatype.IsPublicAcl(acl) && bucket.PublicAccessBlock.Effective().BlockPublicAcls {
+		return ErrPublicAccessBlocked
+	}
 	var object *meta.Object
 	if version == "" {
 		object, err = yig.MetaStorage.GetObject(bucketName, objectName, false)
@@ -493,6 +498,71 @@ func (yig *YigStorage) SetObjectAcl(bucketName string, objectName string, versio
 	return nil
 }
 
+func (yig *YigStorage) getObjectWithOptionalVersion(bucketName, objectName, version string) (*meta.Object, error) {
+	if version == "" {
+		return yig.MetaStorage.GetObject(bucketName, objectName, false)
+	}
+	return yig.getObjWithVersion(bucketName, objectName, version)
+}
+
+func (yig *YigStorage) PutObjectTagging(bucketName, objectName, version string,
+	tagging map[string]string, credential common.Credential) error {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return err
+	}
+	object, err := yig.getObjectWithOptionalVersion(bucketName, objectName, version)
+	if err != nil {
+		return err
+	}
+	if object.DeleteMarker {
+		return ErrNoSuchKey
+	}
+	if !credential.AllowOtherUserAccess &&
+		bucket.OwnerId != credential.UserId && object.OwnerId != credential.UserId {
+		return ErrAccessDenied
+	}
+	object.Tagging = tagging
+	err = yig.MetaStorage.UpdateObjectTagging(object)
+	if err != nil {
+		helper.Logger.Error("Update object tagging, sql fails:", err)
+		return ErrInternalError
+	}
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable, bucketName+":"+objectName+":")
+	if version != "" {
+		yig.MetaStorage.Cache.Remove(redis.ObjectTable, bucketName+":"+objectName+":"+version)
+	}
+	return nil
+}
+
+func (yig *YigStorage) GetObjectTagging(bucketName, objectName, version string,
+	credential common.Credential) (tagging datatype.Tagging, err error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return
+	}
+	object, err := yig.getObjectWithOptionalVersion(bucketName, objectName, version)
+	if err != nil {
+		return
+	}
+	if object.DeleteMarker {
+		return tagging, ErrNoSuchKey
+	}
+	if !credential.AllowOtherUserAccess &&
+		bucket.OwnerId != credential.UserId && object.OwnerId != credential.UserId {
+		return tagging, ErrAccessDenied
+	}
+	return datatype.TaggingFromMap(object.Tagging), nil
+}
+
+func (yig *YigStorage) DeleteObjectTagging(bucketName, objectName, version string,
+	credential common.Credential) error {
+
+	return yig.PutObjectTagging(bucketName, objectName, version, nil, credential)
+}
+
 // Write path:
 //                                           +-----------+
 // PUT object/part                           |           |   Ceph
@@ -506,7 +576,29 @@ func (yig *YigStorage) SetObjectAcl(bucketName string, objectName string, versio
 // Encryptor is enabled when user set SSE headers
 func (yig *YigStorage) PutObject(bucketName string, objectName string, credential common.Credential,
 	size int64, data io.ReadCloser, metadata map[string]string, acl datatype.Acl,
-	sseRequest datatype.SseRequest, storageClass meta.StorageClass) (result datatype.PutObjectResult, err error) {
+	sseRequest datatype.SseRequest, storageClass meta.StorageClass,
+	tagging map[string]string, lock datatype.ObjectLockRequest) (result datatype.PutObjectResult, err error) {
+
+	return yig.putObject(bucketName, objectName, credential, size, data, metadata, acl,
+		sseRequest, storageClass, tagging, lock, datatype.EventObjectCreatedPut)
+}
+
+// PostObject stores an object uploaded by browser based POST form, it is the same as
+// PutObject except for the notification event emitted.
+func (yig *YigStorage) PostObject(bucketName string, objectName string, credential common.Credential,
+	size int64, data io.ReadCloser, metadata map[string]string, acl datatype.Acl,
+	sseRequest datatype.SseRequest, storageClass meta.StorageClass,
+	tagging map[string]string, lock datatype.ObjectLockRequest) (result datatype.PutObjectResult, err error) {
+
+	return yig.putObject(bucketName, objectName, credential, size, data, metadata, acl,
+		sseRequest, storageClass, tagging, lock, datatype.EventObjectCreatedPost)
+}
+
+func (yig *YigStorage) putObject(bucketName string, objectName string, credential common.Credential,
+	size int64, data io.ReadCloser, metadata map[string]string, acl datatype.Acl,
+	sseRequest datatype.SseRequest, storageClass meta.StorageClass,
+	tagging map[string]string, lock datatype.ObjectLockRequest,
+	eventName string) (result datatype.PutObjectResult, err error) {
 
 	defer data.Close()
 	encryptionKey, cipherKey, err := yig.encryptionKeyFromSseRequest(sseRequest, bucketName, objectName)
@@ -521,7 +613,7 @@ func (yig *YigStorage) PutObject(bucketName string, objectName string, credentia
 		return
 	}
 
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -540,9 +632,9 @@ func (yig *YigStorage) PutObject(bucketName string, objectName string, credentia
 		limitedDataReader = data
 	}
 
-	cluster, poolName := yig.pickClusterAndPool(bucketName, objectName, storageClass, size, false)
-	if cluster == nil {
-		return result, ErrInternalError
+	cluster, poolName, err := yig.pickClusterAndPool(bucketName, objectName, storageClass, size, false)
+	if err != nil {
+		return
 	}
 
 	dataReader := io.TeeReader(limitedDataReader, md5Writer)
@@ -617,6 +709,13 @@ func (yig *YigStorage) PutObject(bucketName string, objectName string, credentia
 		CustomAttributes:     metadata,
 		Type:                 meta.ObjectTypeNormal,
 		StorageClass:         storageClass,
+		Tagging:              tagging,
+	}
+	markReplicationPending(bucket, object)
+	err = applyObjectLock(bucket, object, lock)
+	if err != nil {
+		RecycleQueue <- maybeObjectToRecycle
+		return
 	}
 
 	result.LastModified = object.LastModifiedTime
@@ -665,11 +764,13 @@ func (yig *YigStorage) PutObject(bucketName string, objectName string, credentia
 		yig.MetaStorage.Cache.Remove(redis.ObjectTable, bucketName+":"+objectName+":")
 		yig.DataCache.Remove(bucketName + ":" + objectName + ":" + object.GetVersionId())
 	}
+	yig.enqueueReplication(bucket, objectName, object.LastModifiedTime, meta.ReplicationOperationPut)
+	yig.sendNotification(bucket, eventName, credential.UserId, eventObjectFromMeta(object))
 	return result, nil
 }
 
 func (yig *YigStorage) PutObjectMeta(bucket *meta.Bucket, targetObject *meta.Object, credential common.Credential) (err error) {
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -696,7 +797,7 @@ func (yig *YigStorage) RenameObject(targetObject *meta.Object, sourceObject stri
 	if err != nil {
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -704,6 +805,11 @@ func (yig *YigStorage) RenameObject(targetObject *meta.Object, sourceObject stri
 			return result, ErrBucketAccessForbidden
 		}
 	}
+	// renaming removes the source object
+	err = checkObjectLock(targetObject, false)
+	if err != nil {
+		return
+	}
 
 	if len(targetObject.Parts) != 0 {
 		err = yig.MetaStorage.RenameObjectPart(targetObject, sourceObject)
@@ -719,15 +825,34 @@ func (yig *YigStorage) RenameObject(targetObject *meta.Object, sourceObject stri
 		}
 	}
 
+	oldReplicationStatus := targetObject.ReplicationStatus
+	markReplicationPending(bucket, targetObject)
+	if targetObject.ReplicationStatus != oldReplicationStatus {
+		err = yig.MetaStorage.UpdateObjectReplicationStatus(targetObject)
+		if err != nil {
+			helper.Logger.Error("Update replication status of", targetObject.Name, "err:", err)
+		}
+	}
+
 	result.LastModified = targetObject.LastModifiedTime
 	yig.MetaStorage.Cache.Remove(redis.ObjectTable, targetObject.BucketName+":"+targetObject.Name+":")
 	yig.DataCache.Remove(targetObject.BucketName + ":" + targetObject.Name + ":" + targetObject.GetVersionId())
 
+	yig.enqueueReplication(bucket, targetObject.Name, targetObject.LastModifiedTime,
+		meta.ReplicationOperationPut)
+	yig.enqueueReplication(bucket, sourceObject, time.Now().UTC(), meta.ReplicationOperationDelete)
+	yig.sendNotification(bucket, datatype.EventObjectRemovedDelete, credential.UserId,
+		datatype.EventObject{
+			Key:       sourceObject,
+			Sequencer: eventSequencer(time.Now().UTC()),
+		})
+	yig.sendNotification(bucket, datatype.EventObjectCreatedRename, credential.UserId,
+		eventObjectFromMeta(targetObject))
 	return result, nil
 }
 
 func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.Object, source io.Reader, credential common.Credential,
-	sseRequest datatype.SseRequest, isMetadataOnly bool) (result datatype.PutObjectResult, err error) {
+	sseRequest datatype.SseRequest, lock datatype.ObjectLockRequest, isMetadataOnly bool) (result datatype.PutObjectResult, err error) {
 
 	var oid string
 	var maybeObjectToRecycle objectToRecycle
@@ -742,7 +867,7 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 		return
 	}
 
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -765,6 +890,8 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 			}
 			yig.MetaStorage.Cache.Remove(redis.ObjectTable, targetObject.BucketName+":"+targetObject.Name+":")
 			yig.DataCache.Remove(targetObject.BucketName + ":" + targetObject.Name + ":" + targetObject.GetVersionId())
+			yig.sendNotification(bucket, datatype.EventObjectCreatedCopy, credential.UserId,
+				eventObjectFromMeta(targetObject))
 			return result, nil
 		}
 		err = yig.MetaStorage.ReplaceObjectMetas(targetObject)
@@ -779,6 +906,8 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 		}
 		yig.MetaStorage.Cache.Remove(redis.ObjectTable, targetObject.BucketName+":"+targetObject.Name+":")
 		yig.DataCache.Remove(targetObject.BucketName + ":" + targetObject.Name + ":" + targetObject.GetVersionId())
+		yig.sendNotification(bucket, datatype.EventObjectCreatedCopy, credential.UserId,
+			eventObjectFromMeta(targetObject))
 		return result, nil
 	}
 
@@ -786,8 +915,11 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 	var limitedDataReader io.Reader
 	limitedDataReader = io.LimitReader(source, targetObject.Size)
 
-	cephCluster, poolName := yig.pickClusterAndPool(targetObject.BucketName,
+	cephCluster, poolName, err := yig.pickClusterAndPool(targetObject.BucketName,
 		targetObject.Name, targetObject.StorageClass, targetObject.Size, false)
+	if err != nil {
+		return
+	}
 
 	if len(targetObject.Parts) != 0 {
 		var targetParts map[int]*meta.Part = make(map[int]*meta.Part, len(targetObject.Parts))
@@ -907,6 +1039,12 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 	targetObject.SseType = sseRequest.Type
 	targetObject.EncryptionKey = helper.Ternary(sseRequest.Type == crypto.S3.String(),
 		cipherKey, []byte("")).([]byte)
+	markReplicationPending(bucket, targetObject)
+	err = applyObjectLock(bucket, targetObject, lock)
+	if err != nil {
+		RecycleQueue <- maybeObjectToRecycle
+		return
+	}
 
 	result.LastModified = targetObject.LastModifiedTime
 
@@ -950,6 +1088,10 @@ func (yig *YigStorage) CopyObject(targetObject *meta.Object, sourceObject *meta.
 	yig.MetaStorage.Cache.Remove(redis.ObjectTable, targetObject.BucketName+":"+targetObject.Name+":")
 	yig.DataCache.Remove(targetObject.BucketName + ":" + targetObject.Name + ":" + targetObject.GetVersionId())
 
+	yig.enqueueReplication(bucket, targetObject.Name, targetObject.LastModifiedTime,
+		meta.ReplicationOperationPut)
+	yig.sendNotification(bucket, datatype.EventObjectCreatedCopy, credential.UserId,
+		eventObjectFromMeta(targetObject))
 	return result, nil
 }
 
@@ -974,7 +1116,8 @@ func (yig *YigStorage) getObjWithVersion(bucketName, objectName, version string)
 
 }
 
-func (yig *YigStorage) removeAllObjectsEntryByName(bucketName, objectName string) (err error) {
+func (yig *YigStorage) removeAllObjectsEntryByName(bucketName, objectName string,
+	bypassGovernance bool) (err error) {
 
 	objs, err := yig.MetaStorage.GetAllObject(bucketName, objectName)
 	if err == ErrNoSuchKey {
@@ -983,6 +1126,11 @@ func (yig *YigStorage) removeAllObjectsEntryByName(bucketName, objectName string
 	if err != nil {
 		return err
 	}
+	for _, obj := range objs {
+		if err = checkObjectLock(obj, bypassGovernance); err != nil {
+			return err
+		}
+	}
 	for _, obj := range objs {
 		if obj.StorageClass == meta.ObjectStorageClassGlacier {
 			freezer, err := yig.GetFreezer(bucketName, objectName, "")
@@ -1008,7 +1156,7 @@ func (yig *YigStorage) removeAllObjectsEntryByName(bucketName, objectName string
 func (yig *YigStorage) checkOldObject(bucketName, objectName, versioning string) (version uint64, err error) {
 
 	if versioning == meta.VersionDisabled {
-		err = yig.removeAllObjectsEntryByName(bucketName, objectName)
+		err = yig.removeAllObjectsEntryByName(bucketName, objectName, false)
 		return
 	}
 
@@ -1056,6 +1204,9 @@ func (yig *YigStorage) checkOldObject(bucketName, objectName, versioning string)
 		} else {
 			helper.Logger.Info("object.NullVersion:", object.NullVersion)
 			if objectExist && object.NullVersion {
+				if err = checkObjectLock(object, false); err != nil {
+					return
+				}
 				err = yig.MetaStorage.DeleteObject(object, object.DeleteMarker, nil)
 				if err != nil {
 					return
@@ -1068,7 +1219,12 @@ func (yig *YigStorage) checkOldObject(bucketName, objectName, versioning string)
 	return 0, errors.New("No Such versioning status!")
 }
 
-func (yig *YigStorage) removeObjectVersion(bucketName, objectName, version string) error {
+// removeObjectVersion removes the object version `version` permanently, which
+// could be "null" or the version id of any other version, current or not.
+// Removing the current version makes the previous one current.
+func (yig *YigStorage) removeObjectVersion(bucketName, objectName, version string,
+	bypassGovernance bool) error {
+
 	object, err := yig.getObjWithVersion(bucketName, objectName, version)
 	if err == ErrNoSuchKey {
 		return nil
@@ -1076,6 +1232,10 @@ func (yig *YigStorage) removeObjectVersion(bucketName, objectName, version strin
 	if err != nil {
 		return err
 	}
+	err = checkObjectLock(object, bypassGovernance)
+	if err != nil {
+		return err
+	}
 
 	if version == "null" {
 		objMap := &meta.ObjMap{
@@ -1086,6 +1246,11 @@ func (yig *YigStorage) removeObjectVersion(bucketName, objectName, version strin
 		if err != nil {
 			return err
 		}
+	} else {
+		err = yig.removeByObject(object, nil)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -1127,14 +1292,17 @@ func (yig *YigStorage) addDeleteMarker(bucket meta.Bucket, objectName string,
 // |           |                              | null version delete marker                             |
 //
 // See http://docs.aws.amazon.com/AmazonS3/latest/dev/Versioning.html
+//
+// Object versions protected by object lock could not be removed, unless they are
+// in GOVERNANCE mode and `bypassGovernance` is set.
 func (yig *YigStorage) DeleteObject(bucketName string, objectName string, version string,
-	credential common.Credential) (result datatype.DeleteObjectResult, err error) {
+	bypassGovernance bool, credential common.Credential) (result datatype.DeleteObjectResult, err error) {
 
 	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
 	if err != nil {
 		return
 	}
-	switch bucket.ACL.CannedAcl {
+	switch bucket.PublicAccessBlock.Effective().CannedAcl(bucket.ACL) {
 	case "public-read-write":
 		break
 	default:
@@ -1143,12 +1311,20 @@ func (yig *YigStorage) DeleteObject(bucketName string, objectName string, versio
 		}
 	} // TODO policy and fancy ACL
 
+	// removing a noncurrent version leaves what replica holds unchanged, so
+	// only deletes which change the current version are replicated
+	replicateDelete := version == "" || bucket.Versioning == meta.VersionDisabled
+	if !replicateDelete && bucket.Replication.MatchRule(objectName) != nil {
+		current, err := yig.MetaStorage.Client.GetObject(bucketName, objectName, "")
+		replicateDelete = err == nil && current.GetVersionId() == version
+	}
+
 	switch bucket.Versioning {
 	case meta.VersionDisabled:
 		if version != "" && version != "null" {
 			return result, ErrNoSuchVersion
 		}
-		err = yig.removeAllObjectsEntryByName(bucketName, objectName)
+		err = yig.removeAllObjectsEntryByName(bucketName, objectName, bypassGovernance)
 		if err != nil {
 			return
 		}
@@ -1160,7 +1336,7 @@ func (yig *YigStorage) DeleteObject(bucketName string, objectName string, versio
 			}
 			result.DeleteMarker = true
 		} else {
-			err = yig.removeObjectVersion(bucketName, objectName, version)
+			err = yig.removeObjectVersion(bucketName, objectName, version, bypassGovernance)
 			if err != nil {
 				return
 			}
@@ -1168,7 +1344,7 @@ func (yig *YigStorage) DeleteObject(bucketName string, objectName string, versio
 		}
 	case meta.VersionSuspended:
 		if version == "" {
-			err = yig.removeObjectVersion(bucketName, objectName, "null")
+			err = yig.removeObjectVersion(bucketName, objectName, "null", bypassGovernance)
 			if err != nil {
 				return
 			}
@@ -1178,7 +1354,7 @@ func (yig *YigStorage) DeleteObject(bucketName string, objectName string, versio
 			}
 			result.DeleteMarker = true
 		} else {
-			err = yig.removeObjectVersion(bucketName, objectName, version)
+			err = yig.removeObjectVersion(bucketName, objectName, version, bypassGovernance)
 			if err != nil {
 				return
 			}
@@ -1199,5 +1375,18 @@ func (yig *YigStorage) DeleteObject(bucketName string, objectName string, versio
 			yig.DataCache.Remove(bucketName + ":" + objectName + ":" + version)
 		}
 	}
+
+	if replicateDelete {
+		yig.enqueueReplication(bucket, objectName, time.Now().UTC(), meta.ReplicationOperationDelete)
+	}
+	eventName := datatype.EventObjectRemovedDelete
+	if result.DeleteMarker {
+		eventName = datatype.EventObjectRemovedDeleteMarkerCreated
+	}
+	yig.sendNotification(bucket, eventName, credential.UserId, datatype.EventObject{
+		Key:       objectName,
+		VersionId: result.VersionId,
+		Sequencer: eventSequencer(time.Now().UTC()),
+	})
 	return result, nil
 }
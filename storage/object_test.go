@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	. "github.com/journeymidnight/yig/error"
+	meta "github.com/journeymidnight/yig/meta/types"
+)
+
+// usageCluster only reports its usage
+type usageCluster struct {
+	fsid  string
+	usage backend.Usage
+	err   error
+}
+
+func (c *usageCluster) ID() string                       { return c.fsid }
+func (c *usageCluster) GetUsage() (backend.Usage, error) { return c.usage, c.err }
+func (c *usageCluster) Put(poolname string, data io.Reader) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c *usageCluster) Append(poolName, existName string, objectChunk io.Reader,
+	offset int64) (string, uint64, error) {
+	return "", 0, errors.New("not implemented")
+}
+func (c *usageCluster) GetReader(poolName, objectName string,
+	offset int64, length uint64) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *usageCluster) Remove(poolName, objectName string) error { return nil }
+func (c *usageCluster) List(poolName string, fn func(objectName string) error) error {
+	return errors.New("not implemented")
+}
+func (c *usageCluster) Stat(poolName, objectName string) (backend.ObjectInfo, error) {
+	return backend.ObjectInfo{}, errors.New("not implemented")
+}
+
+// newPickStorage creates storage with clusters:
+// a and b of tiger weighted 1 and 3, c of tiger draining, d of tiger nearly
+// full, e of tiger failing usage checks, f of rabbit, and g of tiger weighted 0
+func newPickStorage(t *testing.T) *YigStorage {
+	clusters := map[string]backend.Cluster{
+		"a": &usageCluster{fsid: "a"},
+		"b": &usageCluster{fsid: "b"},
+		"c": &usageCluster{fsid: "c"},
+		"d": &usageCluster{fsid: "d", usage: backend.Usage{UsedSpacePercent: 90}},
+		"e": &usageCluster{fsid: "e", err: errors.New("timeout")},
+		"f": &usageCluster{fsid: "f"},
+		"g": &usageCluster{fsid: "g"},
+	}
+	yig := newTestStorage(t, clusters)
+	for _, c := range []meta.Cluster{
+		{Fsid: "a", Pool: backend.BIG_FILE_POOLNAME, Weight: 1},
+		{Fsid: "b", Pool: backend.BIG_FILE_POOLNAME, Weight: 3},
+		{Fsid: "c", Pool: backend.BIG_FILE_POOLNAME, Weight: 10},
+		{Fsid: "d", Pool: backend.BIG_FILE_POOLNAME, Weight: 10},
+		{Fsid: "e", Pool: backend.BIG_FILE_POOLNAME, Weight: 10},
+		{Fsid: "f", Pool: backend.SMALL_FILE_POOLNAME, Weight: 10},
+		{Fsid: "g", Pool: backend.BIG_FILE_POOLNAME, Weight: 0},
+	} {
+		err := yig.MetaStorage.PutCluster(c)
+		if err != nil {
+			t.Fatal("PutCluster err:", err)
+		}
+	}
+	err := yig.MetaStorage.Client.SetClusterStatus("c", meta.ClusterStatusDraining)
+	if err != nil {
+		t.Fatal("SetClusterStatus err:", err)
+	}
+	yig.Topology.Refresh()
+	return yig
+}
+
+// clusters are picked in proportion to their weights, skipping clusters
+// which can't be written into
+func TestPickClusterWeighted(t *testing.T) {
+	yig := newPickStorage(t)
+	picked := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		cluster := yig.pickCluster(backend.BIG_FILE_POOLNAME, "", meta.Placement{})
+		if cluster == nil {
+			t.Fatal("No cluster picked")
+		}
+		picked[cluster.ID()] += 1
+	}
+	if len(picked) != 2 || picked["a"] < 800 || picked["a"] > 1200 {
+		t.Fatal("Picked clusters:", picked)
+	}
+}
+
+func TestPickClusterExcluded(t *testing.T) {
+	yig := newPickStorage(t)
+	for i := 0; i < 100; i++ {
+		cluster := yig.pickCluster(backend.BIG_FILE_POOLNAME, "b", meta.Placement{})
+		if cluster == nil || cluster.ID() != "a" {
+			t.Fatal("Picked cluster excluding b:", cluster)
+		}
+		cluster = yig.pickCluster(backend.BIG_FILE_POOLNAME, "", meta.Placement{Clusters: []string{"b", "c"}})
+		if cluster == nil || cluster.ID() != "b" {
+			t.Fatal("Picked cluster in placement:", cluster)
+		}
+	}
+	for _, placement := range []meta.Placement{
+		{Clusters: []string{"c"}}, // draining
+		{Clusters: []string{"d"}}, // nearly full
+		{Clusters: []string{"e"}}, // unhealthy
+		{Clusters: []string{"f"}}, // other pool
+		{Clusters: []string{"g"}}, // weight 0
+		{Clusters: []string{"z"}}, // not in cluster table
+	} {
+		cluster := yig.pickCluster(backend.BIG_FILE_POOLNAME, "", placement)
+		if cluster != nil {
+			t.Fatal("Picked cluster", cluster.ID(), "for placement", placement)
+		}
+	}
+}
+
+// a cluster failing data requests stays unhealthy while its usage checks
+// succeed, and is picked again after CLUSTER_RETRY_INTERVAL
+func TestPickClusterFailingPuts(t *testing.T) {
+	yig := newPickStorage(t)
+	placement := meta.Placement{Clusters: []string{"a"}}
+	for i := 0; i < CLUSTER_MAX_CONSECUTIVE_ERRORS; i++ {
+		_, _, err := yig.DataStorage["a"].Put(backend.BIG_FILE_POOLNAME, strings.NewReader("hehe"))
+		if err == nil {
+			t.Fatal("Put to a succeeded")
+		}
+	}
+	for i := 0; i < 3; i++ {
+		cluster := yig.pickCluster(backend.BIG_FILE_POOLNAME, "", placement)
+		if cluster != nil {
+			t.Fatal("Picked cluster with failing puts after", i, "refreshes")
+		}
+		yig.Topology.Refresh()
+	}
+
+	yig.Topology.lock.Lock()
+	yig.Topology.health["a"].LastErrorAt = time.Now().Add(-CLUSTER_RETRY_INTERVAL)
+	yig.Topology.lock.Unlock()
+	cluster := yig.pickCluster(backend.BIG_FILE_POOLNAME, "", placement)
+	if cluster == nil || cluster.ID() != "a" {
+		t.Fatal("Cluster not retried after interval:", cluster)
+	}
+	// another failure makes it unhealthy for another interval
+	yig.DataStorage["a"].Put(backend.BIG_FILE_POOLNAME, strings.NewReader("hehe"))
+	cluster = yig.pickCluster(backend.BIG_FILE_POOLNAME, "", placement)
+	if cluster != nil {
+		t.Fatal("Picked cluster failing again after retry")
+	}
+}
+
+// no cluster is picked when none could be written into, instead of
+// falling back to any cluster
+func TestPickClusterAndPool(t *testing.T) {
+	yig := newPickStorage(t)
+	cluster, pool, err := yig.pickClusterAndPool("hehe", "a", meta.ObjectStorageClassStandard, 1, false)
+	if err != nil || cluster.ID() != "f" || pool != backend.SMALL_FILE_POOLNAME {
+		t.Fatal("pickClusterAndPool small object:", cluster, pool, err)
+	}
+	_, err = yig.MetaStorage.Client.CheckAndPutBucket(meta.Bucket{
+		Name:       "hehe",
+		CreateTime: time.Now(),
+		Placement:  meta.Placement{Clusters: []string{"c", "d", "e"}},
+	})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	cluster, _, err = yig.pickClusterAndPool("hehe", "a", meta.ObjectStorageClassStandard,
+		BIG_FILE_THRESHOLD, false)
+	if err != ErrInternalError || cluster != nil {
+		t.Fatal("pickClusterAndPool without candidate:", cluster, err)
+	}
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/common"
+	meta "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/redis"
+)
+
+// applyObjectLock sets retention and legal hold of a newly written object,
+// the bucket default retention applies if request has no explicit retention
+func applyObjectLock(bucket *meta.Bucket, object *meta.Object, lock datatype.ObjectLockRequest) error {
+	if !bucket.ObjectLock.IsEnabled() {
+		if lock.Mode != "" || lock.LegalHold {
+			return ErrObjectLockNotEnabled
+		}
+		return nil
+	}
+	if lock.Mode != "" {
+		object.ObjectLockMode = lock.Mode
+		object.RetainUntilDate = lock.RetainUntilDate
+	} else {
+		object.ObjectLockMode, object.RetainUntilDate =
+			bucket.ObjectLock.DefaultRetainUntilDate(object.LastModifiedTime)
+	}
+	object.LegalHold = lock.LegalHold
+	return nil
+}
+
+func checkObjectLock(object *meta.Object, bypassGovernance bool) error {
+	if object.IsLocked(bypassGovernance) {
+		helper.Logger.Info("Object is locked:", object.BucketName, object.Name,
+			object.ObjectLockMode, object.RetainUntilDate, object.LegalHold)
+		return ErrObjectLocked
+	}
+	return nil
+}
+
+func (yig *YigStorage) getLockedObject(bucketName, objectName, version string,
+	credential common.Credential) (*meta.Object, error) {
+
+	bucket, err := yig.MetaStorage.GetBucket(bucketName, true)
+	if err != nil {
+		return nil, err
+	}
+	if !bucket.ObjectLock.IsEnabled() {
+		return nil, ErrObjectLockNotEnabled
+	}
+	object, err := yig.getObjectWithOptionalVersion(bucketName, objectName, version)
+	if err != nil {
+		return nil, err
+	}
+	if object.DeleteMarker {
+		return nil, ErrNoSuchKey
+	}
+	if !credential.AllowOtherUserAccess &&
+		bucket.OwnerId != credential.UserId && object.OwnerId != credential.UserId {
+		return nil, ErrAccessDenied
+	}
+	return object, nil
+}
+
+func (yig *YigStorage) updateObjectLock(object *meta.Object, version string) error {
+	err := yig.MetaStorage.UpdateObjectLock(object)
+	if err != nil {
+		helper.Logger.Error("Update object lock, sql fails:", err)
+		return ErrInternalError
+	}
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable, object.BucketName+":"+object.Name+":")
+	if version != "" {
+		yig.MetaStorage.Cache.Remove(redis.ObjectTable, object.BucketName+":"+object.Name+":"+version)
+	}
+	return nil
+}
+
+// PutObjectRetention places a retention on object version, an empty `mode`
+// removes the retention.
+// Retention in COMPLIANCE mode could only be extended, retention in
+// GOVERNANCE mode could be shortened or removed only when `bypassGovernance`
+// is set.
+func (yig *YigStorage) PutObjectRetention(bucketName, objectName, version string,
+	mode string, retainUntilDate time.Time, bypassGovernance bool,
+	credential common.Credential) error {
+
+	object, err := yig.getLockedObject(bucketName, objectName, version, credential)
+	if err != nil {
+		return err
+	}
+	if object.IsRetained() {
+		weakened := mode == "" || retainUntilDate.Before(object.RetainUntilDate)
+		switch object.ObjectLockMode {
+		case datatype.ObjectLockModeCompliance:
+			if weakened || mode != datatype.ObjectLockModeCompliance {
+				return ErrObjectLocked
+			}
+		case datatype.ObjectLockModeGovernance:
+			if weakened && !bypassGovernance {
+				return ErrObjectLocked
+			}
+		}
+	}
+	object.ObjectLockMode = mode
+	object.RetainUntilDate = retainUntilDate
+	return yig.updateObjectLock(object, version)
+}
+
+func (yig *YigStorage) GetObjectRetention(bucketName, objectName, version string,
+	credential common.Credential) (retention datatype.ObjectRetention, err error) {
+
+	object, err := yig.getLockedObject(bucketName, objectName, version, credential)
+	if err != nil {
+		return
+	}
+	if object.ObjectLockMode == "" {
+		return retention, ErrNoSuchObjectLockConfiguration
+	}
+	retention.Mode = object.ObjectLockMode
+	retention.RetainUntilDate = object.RetainUntilDate.UTC().Format(meta.CREATE_TIME_LAYOUT)
+	return retention, nil
+}
+
+func (yig *YigStorage) PutObjectLegalHold(bucketName, objectName, version string,
+	legalHold bool, credential common.Credential) error {
+
+	object, err := yig.getLockedObject(bucketName, objectName, version, credential)
+	if err != nil {
+		return err
+	}
+	object.LegalHold = legalHold
+	return yig.updateObjectLock(object, version)
+}
+
+func (yig *YigStorage) GetObjectLegalHold(bucketName, objectName, version string,
+	credential common.Credential) (legalHold datatype.ObjectLegalHold, err error) {
+
+	object, err := yig.getLockedObject(bucketName, objectName, version, credential)
+	if err != nil {
+		return
+	}
+	legalHold.Status = helper.Ternary(object.LegalHold,
+		datatype.LegalHoldOn, datatype.LegalHoldOff).(string)
+	return legalHold, nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/iam/common"
+	meta "github.com/journeymidnight/yig/meta/types"
+)
+
+// newPublicStorage creates bucket "hehe" owned by "hehe" with `acl` and
+// `publicAccessBlock`, object "a" and a multipart upload of "b" in it, both
+// with `acl` too
+func newPublicStorage(t *testing.T, acl string,
+	publicAccessBlock datatype.PublicAccessBlockConfiguration) (yig *YigStorage, uploadId string) {
+
+	yig = newTestStorage(t, nil)
+	_, err := yig.MetaStorage.Client.CheckAndPutBucket(meta.Bucket{
+		Name:              "hehe",
+		CreateTime:        time.Now(),
+		OwnerId:           "hehe",
+		ACL:               datatype.Acl{CannedAcl: acl},
+		PublicAccessBlock: publicAccessBlock,
+	})
+	if err != nil {
+		t.Fatal("CheckAndPutBucket err:", err)
+	}
+	err = yig.MetaStorage.Client.PutObject(&meta.Object{
+		BucketName:       "hehe",
+		Name:             "a",
+		OwnerId:          "hehe",
+		ACL:              datatype.Acl{CannedAcl: acl},
+		LastModifiedTime: time.Now(),
+	}, nil)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	multipart := meta.Multipart{
+		BucketName:  "hehe",
+		ObjectName:  "b",
+		InitialTime: time.Now().UTC(),
+		Metadata: meta.MultipartMetadata{
+			InitiatorId: "hehe",
+			OwnerId:     "hehe",
+			Acl:         datatype.Acl{CannedAcl: acl},
+		},
+	}
+	uploadId, err = multipart.GetUploadId()
+	if err != nil {
+		t.Fatal("GetUploadId err:", err)
+	}
+	err = yig.MetaStorage.Client.CreateMultipart(multipart)
+	if err != nil {
+		t.Fatal("CreateMultipart err:", err)
+	}
+	return yig, uploadId
+}
+
+// checkPublicReads checks ACL checks of reads by `credential`, `denied` tells
+// whether they should be denied
+func checkPublicReads(t *testing.T, yig *YigStorage, uploadId string,
+	credential common.Credential, denied bool) {
+
+	expect := func(name string, err, deniedErr error) {
+		if denied && err != deniedErr || !denied && err != nil {
+			t.Fatal(name, credential.UserId, err)
+		}
+	}
+	_, err := yig.GetBucketInfo("hehe", credential)
+	expect("GetBucketInfo", err, ErrBucketAccessForbidden)
+	_, err = yig.ListObjects(credential, "hehe", datatype.ListObjectsRequest{MaxKeys: 1000})
+	expect("ListObjects", err, ErrBucketAccessForbidden)
+	_, err = yig.ListVersionedObjects(credential, "hehe",
+		datatype.ListObjectsRequest{Versioned: true, MaxKeys: 1000})
+	expect("ListVersionedObjects", err, ErrBucketAccessForbidden)
+	_, err = yig.ListMultipartUploads(credential, "hehe", datatype.ListUploadsRequest{MaxUploads: 1000})
+	expect("ListMultipartUploads", err, ErrBucketAccessForbidden)
+	_, err = yig.ListObjectParts(credential, "hehe", "b",
+		datatype.ListPartsRequest{UploadId: uploadId, MaxParts: 1000})
+	expect("ListObjectParts", err, ErrAccessDenied)
+	_, err = yig.GetObjectInfo("hehe", "a", "", credential)
+	expect("GetObjectInfo", err, ErrAccessDenied)
+}
+
+func TestIgnorePublicAcls(t *testing.T) {
+	owner := common.Credential{UserId: "hehe", AccessKeyID: "hehekey"}
+	other := common.Credential{UserId: "haha", AccessKeyID: "hahakey"}
+
+	for _, acl := range []string{"public-read", "public-read-write", "authenticated-read"} {
+		yig, uploadId := newPublicStorage(t, acl, datatype.PublicAccessBlockConfiguration{})
+		checkPublicReads(t, yig, uploadId, owner, false)
+		checkPublicReads(t, yig, uploadId, other, false)
+
+		yig, uploadId = newPublicStorage(t, acl,
+			datatype.PublicAccessBlockConfiguration{IgnorePublicAcls: true})
+		checkPublicReads(t, yig, uploadId, owner, false)
+		checkPublicReads(t, yig, uploadId, other, true)
+	}
+
+	// settings in config apply to all buckets
+	helper.CONFIG.PublicAccessBlock.IgnorePublicAcls = true
+	defer func() { helper.CONFIG.PublicAccessBlock.IgnorePublicAcls = false }()
+	yig, uploadId := newPublicStorage(t, "public-read", datatype.PublicAccessBlockConfiguration{})
+	checkPublicReads(t, yig, uploadId, owner, false)
+	checkPublicReads(t, yig, uploadId, other, true)
+}
+
+func TestIgnorePublicAclsWrites(t *testing.T) {
+	other := common.Credential{UserId: "haha", AccessKeyID: "hahakey"}
+	yig, _ := newPublicStorage(t, "public-read-write",
+		datatype.PublicAccessBlockConfiguration{IgnorePublicAcls: true})
+	bucket, err := yig.MetaStorage.GetBucket("hehe", false)
+	if err != nil {
+		t.Fatal("GetBucket err:", err)
+	}
+	object, err := yig.MetaStorage.GetObject("hehe", "a", false)
+	if err != nil {
+		t.Fatal("GetObject err:", err)
+	}
+
+	err = yig.PutObjectMeta(bucket, object, other)
+	if err != ErrBucketAccessForbidden {
+		t.Fatal("PutObjectMeta", err)
+	}
+	_, err = yig.DeleteObject("hehe", "a", "", false, other)
+	if err != ErrBucketAccessForbidden {
+		t.Fatal("DeleteObject", err)
+	}
+	_, err = yig.NewMultipartUpload(other, "hehe", "c", nil, datatype.Acl{CannedAcl: "private"},
+		datatype.SseRequest{}, meta.ObjectStorageClassStandard, nil, datatype.ObjectLockRequest{})
+	if err != ErrBucketAccessForbidden {
+		t.Fatal("NewMultipartUpload", err)
+	}
+	err = yig.AbortMultipartUpload(other, "hehe", "b", "")
+	if err != ErrBucketAccessForbidden {
+		t.Fatal("AbortMultipartUpload", err)
+	}
+	// the object is kept
+	_, err = yig.MetaStorage.GetObject("hehe", "a", false)
+	if err != nil {
+		t.Fatal("GetObject after denied writes err:", err)
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"math"
+	"time"
+
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/helper"
+	meta "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/redis"
+)
+
+// markReplicationPending sets replication status of a newly written object,
+// should be called before the object is put into meta storage
+func markReplicationPending(bucket *meta.Bucket, object *meta.Object) {
+	if bucket.Replication.MatchRule(object.Name) != nil {
+		object.ReplicationStatus = datatype.ReplicationStatusPending
+	} else {
+		object.ReplicationStatus = ""
+	}
+}
+
+// enqueueReplication adds an entry into replication queue if `objectName`
+// matches any replication rule of `bucket`, the entry would be processed
+// by tools/replicate asynchronously
+func (yig *YigStorage) enqueueReplication(bucket *meta.Bucket, objectName string,
+	modifiedTime time.Time, operation meta.ReplicationOperation) {
+
+	rule := bucket.Replication.MatchRule(objectName)
+	if rule == nil {
+		return
+	}
+	region, targetBucket, err := datatype.ParseReplicationDestination(rule.Destination.Bucket)
+	if err != nil {
+		helper.Logger.Error("Invalid replication destination of bucket", bucket.Name,
+			rule.Destination.Bucket, "err:", err)
+		return
+	}
+	replication := &meta.Replication{
+		BucketName:   bucket.Name,
+		ObjectName:   objectName,
+		Version:      math.MaxUint64 - uint64(modifiedTime.UnixNano()),
+		Operation:    operation,
+		TargetRegion: region,
+		TargetBucket: targetBucket,
+		StorageClass: rule.Destination.StorageClass,
+		Status:       datatype.ReplicationStatusPending,
+		MTime:        time.Now().UTC(),
+	}
+	err = yig.MetaStorage.PutObjectToReplication(replication)
+	if err != nil {
+		helper.Logger.Error("Failed to put replication entry for", bucket.Name, objectName,
+			"err:", err)
+	}
+}
+
+// UpdateObjectReplicationStatus records the result of replicating `object`,
+// used by tools/replicate
+func (yig *YigStorage) UpdateObjectReplicationStatus(object *meta.Object, status string) error {
+	object.ReplicationStatus = status
+	err := yig.MetaStorage.UpdateObjectReplicationStatus(object)
+	if err != nil {
+		return err
+	}
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable, object.BucketName+":"+object.Name+":")
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable,
+		object.BucketName+":"+object.Name+":"+object.GetVersionId())
+	return nil
+}
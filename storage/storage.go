@@ -28,6 +28,7 @@ const (
 // *YigStorage implements api.ObjectLayer
 type YigStorage struct {
 	DataStorage map[string]backend.Cluster
+	Topology    *Topology
 	DataCache   DataCache
 	MetaStorage *meta.Meta
 	KMS         crypto.KMS
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/meta"
+	"github.com/journeymidnight/yig/meta/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	TOPOLOGY_REFRESH_INTERVAL = 30 * time.Second
+	CLUSTER_USAGE_TIMEOUT     = 10 * time.Second
+	// a cluster is unhealthy after this many data requests to it fail in a
+	// row, until a data request succeeds
+	CLUSTER_MAX_CONSECUTIVE_ERRORS = 5
+	// an unhealthy cluster could be picked again this long after its last
+	// failed data request, so it recovers once a request to it succeeds, or
+	// stays unhealthy for another interval if the request fails
+	CLUSTER_RETRY_INTERVAL = 5 * time.Minute
+	// weight of the latest request in moving average latency
+	clusterLatencyWeight = 0.2
+)
+
+var errClusterUsageTimeout = errors.New("get cluster usage timeout")
+
+// ClusterHealth is the state of a cluster seen by this yig instance
+type ClusterHealth struct {
+	Healthy           bool
+	UsedSpacePercent  int
+	UsageError        string        // error of last usage check, empty if succeeded
+	ConsecutiveErrors int           // data requests failed in a row
+	LastErrorAt       time.Time     // time of last failed data request
+	Requests          uint64        // data requests since start
+	Errors            uint64        // failed data requests since start
+	Latency           time.Duration // moving average latency of data requests
+}
+
+// TopologySnapshot is a consistent view of clusters, it's never modified
+// after returned by Topology.Snapshot
+type TopologySnapshot struct {
+	Clusters  []types.Cluster          // rows of cluster table
+	Health    map[string]ClusterHealth // fsid -> health, of configured clusters
+	UpdatedAt time.Time                // last time Clusters is read from meta store
+}
+
+// Topology keeps clusters from meta store with usage and health of them in
+// memory, so picking clusters for new data doesn't query meta store or
+// backends. Clusters and usage are refreshed every TOPOLOGY_REFRESH_INTERVAL,
+// health is updated by every data request through clusters wrapped by Monitor.
+type Topology struct {
+	metaStorage *meta.Meta
+	dataStorage map[string]backend.Cluster // unwrapped clusters
+
+	lock      sync.RWMutex
+	clusters  []types.Cluster
+	health    map[string]*ClusterHealth
+	updatedAt time.Time
+
+	requests        *prometheus.CounterVec
+	durations       *prometheus.HistogramVec
+	usedSpaceDesc   *prometheus.Desc
+	healthyDesc     *prometheus.Desc
+	consecutiveDesc *prometheus.Desc
+}
+
+func NewTopology(metaStorage *meta.Meta, dataStorage map[string]backend.Cluster) *Topology {
+	t := &Topology{
+		metaStorage: metaStorage,
+		dataStorage: dataStorage,
+		health:      make(map[string]*ClusterHealth, len(dataStorage)),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "yig",
+			Name:      "cluster_requests_total",
+			Help:      "Data requests to backend clusters, by result(ok or error)",
+		}, []string{"fsid", "operation", "result"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "yig",
+			Name:      "cluster_request_duration_seconds",
+			Help:      "Latency of data requests to backend clusters",
+		}, []string{"fsid", "operation"}),
+		usedSpaceDesc: prometheus.NewDesc("yig_cluster_used_space_percent",
+			"Used space of backend clusters at last usage check", []string{"fsid"}, nil),
+		healthyDesc: prometheus.NewDesc("yig_cluster_healthy",
+			"1 if backend cluster could be picked for new data, 0 otherwise", []string{"fsid"}, nil),
+		consecutiveDesc: prometheus.NewDesc("yig_cluster_consecutive_errors",
+			"Data requests to backend clusters failed in a row", []string{"fsid"}, nil),
+	}
+	for fsid := range dataStorage {
+		t.health[fsid] = new(ClusterHealth)
+	}
+	return t
+}
+
+// Monitor wraps all clusters to record results of their data requests
+func (t *Topology) Monitor(clusters map[string]backend.Cluster) map[string]backend.Cluster {
+	monitored := make(map[string]backend.Cluster, len(clusters))
+	for fsid, c := range clusters {
+		monitored[fsid] = &monitoredCluster{Cluster: c, topology: t}
+	}
+	return monitored
+}
+
+func isHealthy(h *ClusterHealth, now time.Time) bool {
+	return h.UsageError == "" && (h.ConsecutiveErrors < CLUSTER_MAX_CONSECUTIVE_ERRORS ||
+		now.Sub(h.LastErrorAt) >= CLUSTER_RETRY_INTERVAL)
+}
+
+// Snapshot returns current clusters and health of them
+func (t *Topology) Snapshot() TopologySnapshot {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	snapshot := TopologySnapshot{
+		Clusters:  t.clusters,
+		Health:    make(map[string]ClusterHealth, len(t.health)),
+		UpdatedAt: t.updatedAt,
+	}
+	now := time.Now()
+	for fsid, h := range t.health {
+		health := *h
+		health.Healthy = isHealthy(h, now)
+		snapshot.Health[fsid] = health
+	}
+	return snapshot
+}
+
+func clusterUsage(cluster backend.Cluster) (backend.Usage, error) {
+	type result struct {
+		usage backend.Usage
+		err   error
+	}
+	// buffered so the goroutine exits after timeout
+	c := make(chan result, 1)
+	go func() {
+		usage, err := cluster.GetUsage()
+		c <- result{usage: usage, err: err}
+	}()
+	select {
+	case r := <-c:
+		return r.usage, r.err
+	case <-time.After(CLUSTER_USAGE_TIMEOUT):
+		return backend.Usage{}, errClusterUsageTimeout
+	}
+}
+
+// Refresh reads clusters from meta store and checks usage of all clusters,
+// clusters are kept unchanged if meta store fails
+func (t *Topology) Refresh() {
+	clusters, err := t.metaStorage.GetClusters()
+	if err != nil {
+		helper.Logger.Error("Refresh topology, get clusters failed:", err)
+	}
+
+	usages := make(map[string]backend.Usage, len(t.dataStorage))
+	usageErrors := make(map[string]error)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for fsid, cluster := range t.dataStorage {
+		wg.Add(1)
+		go func(fsid string, cluster backend.Cluster) {
+			defer wg.Done()
+			usage, err := clusterUsage(cluster)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				helper.Logger.Warn("Refresh topology, get usage of cluster", fsid,
+					"failed:", err)
+				usageErrors[fsid] = err
+				return
+			}
+			usages[fsid] = usage
+		}(fsid, cluster)
+	}
+	wg.Wait()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err == nil {
+		t.clusters = clusters
+		t.updatedAt = time.Now()
+	}
+	for fsid, h := range t.health {
+		if usageErr, ok := usageErrors[fsid]; ok {
+			h.UsageError = usageErr.Error()
+			continue
+		}
+		h.UsageError = ""
+		h.UsedSpacePercent = usages[fsid].UsedSpacePercent
+	}
+}
+
+// refreshTopology refreshes topology in background until yig is stopping
+func refreshTopology(yig *YigStorage) {
+	defer yig.WaitGroup.Done()
+	lastRefresh := time.Now()
+	for !yig.Stopping {
+		time.Sleep(time.Second)
+		if time.Since(lastRefresh) < TOPOLOGY_REFRESH_INTERVAL {
+			continue
+		}
+		yig.Topology.Refresh()
+		lastRefresh = time.Now()
+	}
+}
+
+func (t *Topology) record(fsid, operation string, start time.Time, err error) {
+	latency := time.Since(start)
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	t.requests.WithLabelValues(fsid, operation, result).Inc()
+	t.durations.WithLabelValues(fsid, operation).Observe(latency.Seconds())
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	h, ok := t.health[fsid]
+	if !ok {
+		return
+	}
+	h.Requests += 1
+	if h.Latency == 0 {
+		h.Latency = latency
+	} else {
+		h.Latency += time.Duration(clusterLatencyWeight * float64(latency-h.Latency))
+	}
+	if err != nil {
+		h.Errors += 1
+		h.ConsecutiveErrors += 1
+		h.LastErrorAt = time.Now()
+		if h.ConsecutiveErrors == CLUSTER_MAX_CONSECUTIVE_ERRORS {
+			helper.Logger.Warn("Cluster", fsid, "is unhealthy after",
+				CLUSTER_MAX_CONSECUTIVE_ERRORS, "failed requests, last error:", err)
+		}
+	} else {
+		h.ConsecutiveErrors = 0
+	}
+}
+
+func (t *Topology) Describe(ch chan<- *prometheus.Desc) {
+	t.requests.Describe(ch)
+	t.durations.Describe(ch)
+	ch <- t.usedSpaceDesc
+	ch <- t.healthyDesc
+	ch <- t.consecutiveDesc
+}
+
+func (t *Topology) Collect(ch chan<- prometheus.Metric) {
+	t.requests.Collect(ch)
+	t.durations.Collect(ch)
+	for fsid, h := range t.Snapshot().Health {
+		var healthy float64
+		if h.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(t.usedSpaceDesc, prometheus.GaugeValue,
+			float64(h.UsedSpacePercent), fsid)
+		ch <- prometheus.MustNewConstMetric(t.healthyDesc, prometheus.GaugeValue,
+			healthy, fsid)
+		ch <- prometheus.MustNewConstMetric(t.consecutiveDesc, prometheus.GaugeValue,
+			float64(h.ConsecutiveErrors), fsid)
+	}
+}
+
+// sourceReader remembers errors reading data to be written, failures
+// caused by clients are not counted as failures of clusters
+type sourceReader struct {
+	io.Reader
+	err error
+}
+
+func (r *sourceReader) Read(p []byte) (n int, err error) {
+	n, err = r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return
+}
+
+// monitoredCluster records results and latency of Put, Append and
+// GetReader into topology. Latency of GetReader doesn't include reading data.
+type monitoredCluster struct {
+	backend.Cluster
+	topology *Topology
+}
+
+func (c *monitoredCluster) Put(poolName string, data io.Reader) (oid string,
+	size uint64, err error) {
+
+	start := time.Now()
+	source := &sourceReader{Reader: data}
+	oid, size, err = c.Cluster.Put(poolName, source)
+	if source.err == nil {
+		c.topology.record(c.ID(), "put", start, err)
+	}
+	return
+}
+
+func (c *monitoredCluster) Append(poolName, existName string, objectChunk io.Reader,
+	offset int64) (objectName string, bytesWritten uint64, err error) {
+
+	start := time.Now()
+	source := &sourceReader{Reader: objectChunk}
+	objectName, bytesWritten, err = c.Cluster.Append(poolName, existName, source, offset)
+	if source.err == nil {
+		c.topology.record(c.ID(), "append", start, err)
+	}
+	return
+}
+
+func (c *monitoredCluster) GetReader(poolName, objectName string,
+	offset int64, length uint64) (reader io.ReadCloser, err error) {
+
+	start := time.Now()
+	reader, err = c.Cluster.GetReader(poolName, objectName, offset, length)
+	c.topology.record(c.ID(), "get", start, err)
+	return
+}
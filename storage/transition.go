@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/journeymidnight/yig/backend"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	meta "github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/redis"
+)
+
+// copyRawData copies stored (possibly encrypted) data of an object from one
+// cluster to another without decrypting it, so encryption key and
+// initialization vectors of the object stay valid
+func copyRawData(from backend.Cluster, fromPool, objectId string, size int64,
+	to backend.Cluster, toPool string) (oid string, err error) {
+
+	reader, err := from.GetReader(fromPool, objectId, 0, uint64(size))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	oid, bytesWritten, err := to.Put(toPool, reader)
+	if err != nil {
+		return "", err
+	}
+	if int64(bytesWritten) < size {
+		RecycleQueue <- objectToRecycle{
+			location: to.ID(),
+			pool:     toPool,
+			objectId: oid,
+		}
+		return "", ErrIncompleteBody
+	}
+	return oid, nil
+}
+
+// TransitionObject moves data of an object version into the pool of
+// `storageClass` and updates the object row, the original data is put into
+// gc once the object row is updated. The row is updated only if it still
+// refers to the original data, otherwise the copied data is recycled.
+// Used by lifecycle Transition rules.
+func (yig *YigStorage) TransitionObject(bucketName, objectName, version string,
+	storageClass meta.StorageClass) (err error) {
+
+	object, err := yig.getObjectWithOptionalVersion(bucketName, objectName, version)
+	if err != nil {
+		return err
+	}
+	if object.DeleteMarker || object.StorageClass == storageClass {
+		return nil
+	}
+	// appendable objects should stay in pool which supports append
+	if object.Type == meta.ObjectTypeAppendable {
+		return ErrInvalidObjectState
+	}
+	sourceCluster, ok := yig.DataStorage[object.Location]
+	if !ok {
+		return errors.New("Cannot find specified ceph cluster: " + object.Location)
+	}
+
+	target := *object
+	target.StorageClass = storageClass
+	cluster, poolName, err := yig.pickClusterAndPool(bucketName, objectName, storageClass,
+		object.Size, false)
+	if err != nil {
+		return err
+	}
+
+	var copied []objectToRecycle
+	recycleCopied := func() {
+		for _, c := range copied {
+			RecycleQueue <- c
+		}
+	}
+	switch {
+	case cluster.ID() == object.Location && poolName == object.Pool:
+		// data is already in the right place, e.g. STANDARD to STANDARD_IA
+	case len(object.Parts) == 0:
+		target.ObjectId, err = copyRawData(sourceCluster, object.Pool, object.ObjectId,
+			object.Size, cluster, poolName)
+		if err != nil {
+			return err
+		}
+		copied = append(copied, objectToRecycle{
+			location: cluster.ID(),
+			pool:     poolName,
+			objectId: target.ObjectId,
+		})
+	default:
+		target.Parts = make(map[int]*meta.Part, len(object.Parts))
+		for number, part := range object.Parts {
+			targetPart := *part
+			targetPart.ObjectId, err = copyRawData(sourceCluster, object.Pool, part.ObjectId,
+				part.Size, cluster, poolName)
+			if err != nil {
+				recycleCopied()
+				return err
+			}
+			copied = append(copied, objectToRecycle{
+				location: cluster.ID(),
+				pool:     poolName,
+				objectId: targetPart.ObjectId,
+			})
+			target.Parts[number] = &targetPart
+		}
+	}
+	target.Location = cluster.ID()
+	target.Pool = poolName
+
+	moved, err := yig.MetaStorage.MoveObject(&target, object)
+	if err != nil {
+		helper.Logger.Error("Transition object, sql fails:", err)
+		recycleCopied()
+		return ErrInternalError
+	}
+	if !moved {
+		// object has been overwritten, deleted or moved since read
+		helper.Logger.Info("Transition object, object changed:", bucketName, objectName,
+			object.GetVersionId())
+		recycleCopied()
+		return nil
+	}
+	yig.removeTransitionedObjectCache(&target)
+	yig.moveStorageClassUsage(object, storageClass)
+	return nil
+}
+
+// moveStorageClassUsage moves usage of `object` from its storage class to
+// `storageClass`, in the per storage class usage of its bucket and owner
+func (yig *YigStorage) moveStorageClassUsage(object *meta.Object, storageClass meta.StorageClass) {
+	if redis.Pool() == nil {
+		return
+	}
+	for _, key := range []string{redis.BucketUsagePrefix + object.BucketName,
+		redis.UserUsagePrefix + object.OwnerId} {
+
+		err := redis.MoveUsage(key, object.StorageClass.ToString(), storageClass.ToString(),
+			object.Size)
+		if err != nil {
+			helper.Logger.Error("Move usage of", key, "from", object.StorageClass.ToString(),
+				"to", storageClass.ToString(), "err:", err)
+		}
+	}
+}
+
+func (yig *YigStorage) removeTransitionedObjectCache(object *meta.Object) {
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable, object.BucketName+":"+object.Name+":")
+	yig.MetaStorage.Cache.Remove(redis.ObjectTable,
+		object.BucketName+":"+object.Name+":"+object.GetVersionId())
+	yig.DataCache.Remove(object.BucketName + ":" + object.Name + ":" + object.GetVersionId())
+}
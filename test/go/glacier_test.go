@@ -2,9 +2,13 @@ package _go
 
 import (
 	"encoding/xml"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
 	"github.com/journeymidnight/yig/api/datatype"
 	. "github.com/journeymidnight/yig/test/go/lib"
-	"testing"
 )
 
 const (
@@ -69,3 +73,59 @@ func Test_RestoreObject(t *testing.T) {
 	}
 	t.Log("DeleteBucket Success!")
 }
+
+func Test_RestoreObjectByWorker(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+	}
+	defer sc.DeleteBucket(TEST_BUCKET)
+	err = sc.PutObjectWithStorageClass(TEST_BUCKET, TEST_KEY, TEST_VALUE, TEST_STORAGEGLACIER)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	defer sc.DeleteObject(TEST_BUCKET, TEST_KEY)
+
+	_, err = sc.GetObject(TEST_BUCKET, TEST_KEY)
+	if err == nil {
+		t.Fatal("GetObject should fail before restore")
+	}
+
+	var config = &datatype.Restore{}
+	err = xml.Unmarshal([]byte(RESTOREXML1), config)
+	if err != nil {
+		t.Fatal("Unmarshal restore request err:", err)
+	}
+	err = sc.RestoreObject(TEST_BUCKET, TEST_KEY, TransferToS3AccessRestoreRequest(config))
+	if err != nil {
+		t.Fatal("RestoreObject err:", err)
+	}
+
+	err = os.Chdir("../../")
+	if err != nil {
+		t.Fatal("change dir in restore err:", err)
+	}
+	cmd := exec.Command("make", "runrestore")
+	err = cmd.Run()
+	os.Chdir("../test/go")
+	if err != nil {
+		t.Fatal("restore err:", err)
+	}
+
+	var v string
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second * 3)
+		v, err = sc.GetObject(TEST_BUCKET, TEST_KEY)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatal("GetObject after restore err:", err)
+	}
+	if v != TEST_VALUE {
+		t.Fatal("GetObject after restore err: value is:", v, ", but should be:", TEST_VALUE)
+	}
+	t.Log("GetObject after restore Success!")
+}
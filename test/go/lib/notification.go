@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+)
+
+func (s3client *S3Client) PutBucketNotification(bucketName, id, queueArn, prefix string,
+	events ...string) (err error) {
+
+	queue := &s3.QueueConfiguration{
+		Id:       aws.String(id),
+		QueueArn: aws.String(queueArn),
+		Events:   aws.StringSlice(events),
+	}
+	if prefix != "" {
+		queue.Filter = &s3.NotificationConfigurationFilter{
+			Key: &s3.KeyFilter{
+				FilterRules: []*s3.FilterRule{
+					{Name: aws.String("prefix"), Value: aws.String(prefix)},
+				},
+			},
+		}
+	}
+	params := &s3.PutBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucketName),
+		NotificationConfiguration: &s3.NotificationConfiguration{
+			QueueConfigurations: []*s3.QueueConfiguration{queue},
+		},
+	}
+	if _, err = s3client.Client.PutBucketNotificationConfiguration(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetBucketNotification(bucketName string) (
+	config *s3.NotificationConfiguration, err error) {
+
+	params := &s3.GetBucketNotificationConfigurationRequest{
+		Bucket: aws.String(bucketName),
+	}
+	return s3client.Client.GetBucketNotificationConfiguration(params)
+}
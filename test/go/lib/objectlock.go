@@ -0,0 +1,158 @@
+package lib
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+)
+
+func (s3client *S3Client) MakeBucketWithObjectLock(bucketName string) (err error) {
+	params := &s3.CreateBucketInput{
+		Bucket:                     aws.String(bucketName),
+		ObjectLockEnabledForBucket: aws.Bool(true),
+	}
+	if _, err = s3client.Client.CreateBucket(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) PutObjectLockConfiguration(bucketName, mode string, days int64) (err error) {
+	params := &s3.PutObjectLockConfigurationInput{
+		Bucket: aws.String(bucketName),
+		ObjectLockConfiguration: &s3.ObjectLockConfiguration{
+			ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled),
+			Rule: &s3.ObjectLockRule{
+				DefaultRetention: &s3.DefaultRetention{
+					Mode: aws.String(mode),
+					Days: aws.Int64(days),
+				},
+			},
+		},
+	}
+	if _, err = s3client.Client.PutObjectLockConfiguration(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetObjectLockConfiguration(bucketName string) (
+	config *s3.ObjectLockConfiguration, err error) {
+
+	params := &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucketName),
+	}
+	out, err := s3client.Client.GetObjectLockConfiguration(params)
+	if err != nil {
+		return nil, err
+	}
+	return out.ObjectLockConfiguration, nil
+}
+
+// PutObjectWithVersion puts an object and returns its version id
+func (s3client *S3Client) PutObjectWithVersion(bucketName, key, value string) (version string, err error) {
+	params := &s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader([]byte(value)),
+	}
+	out, err := s3client.Client.PutObject(params)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.VersionId), nil
+}
+
+func (s3client *S3Client) GetObjectWithVersion(bucketName, key, version string) (value string, err error) {
+	params := &s3.GetObjectInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(key),
+		VersionId: aws.String(version),
+	}
+	out, err := s3client.Client.GetObject(params)
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadAll(out.Body)
+	return string(data), err
+}
+
+func (s3client *S3Client) DeleteObjectVersion(bucketName, key, version string,
+	bypassGovernance bool) (err error) {
+
+	params := &s3.DeleteObjectInput{
+		Bucket:                    aws.String(bucketName),
+		Key:                       aws.String(key),
+		VersionId:                 aws.String(version),
+		BypassGovernanceRetention: aws.Bool(bypassGovernance),
+	}
+	if _, err = s3client.Client.DeleteObject(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) PutObjectRetention(bucketName, key, version, mode string,
+	retainUntilDate time.Time, bypassGovernance bool) (err error) {
+
+	params := &s3.PutObjectRetentionInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(key),
+		VersionId: aws.String(version),
+		Retention: &s3.ObjectLockRetention{
+			Mode:            aws.String(mode),
+			RetainUntilDate: aws.Time(retainUntilDate),
+		},
+		BypassGovernanceRetention: aws.Bool(bypassGovernance),
+	}
+	if _, err = s3client.Client.PutObjectRetention(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetObjectRetention(bucketName, key, version string) (
+	retention *s3.ObjectLockRetention, err error) {
+
+	params := &s3.GetObjectRetentionInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(key),
+		VersionId: aws.String(version),
+	}
+	out, err := s3client.Client.GetObjectRetention(params)
+	if err != nil {
+		return nil, err
+	}
+	return out.Retention, nil
+}
+
+func (s3client *S3Client) PutObjectLegalHold(bucketName, key, version, status string) (err error) {
+	params := &s3.PutObjectLegalHoldInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(key),
+		VersionId: aws.String(version),
+		LegalHold: &s3.ObjectLockLegalHold{
+			Status: aws.String(status),
+		},
+	}
+	if _, err = s3client.Client.PutObjectLegalHold(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetObjectLegalHold(bucketName, key, version string) (status string, err error) {
+	params := &s3.GetObjectLegalHoldInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(key),
+		VersionId: aws.String(version),
+	}
+	out, err := s3client.Client.GetObjectLegalHold(params)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.LegalHold.Status), nil
+}
@@ -0,0 +1,42 @@
+package lib
+
+import (
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+)
+
+func (s3client *S3Client) PutPublicAccessBlock(bucketName string,
+	config *s3.PublicAccessBlockConfiguration) (err error) {
+
+	params := &s3.PutPublicAccessBlockInput{
+		Bucket:                         aws.String(bucketName),
+		PublicAccessBlockConfiguration: config,
+	}
+	if _, err = s3client.Client.PutPublicAccessBlock(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetPublicAccessBlock(bucketName string) (
+	config *s3.PublicAccessBlockConfiguration, err error) {
+
+	params := &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	}
+	out, err := s3client.Client.GetPublicAccessBlock(params)
+	if err != nil {
+		return nil, err
+	}
+	return out.PublicAccessBlockConfiguration, nil
+}
+
+func (s3client *S3Client) DeletePublicAccessBlock(bucketName string) (err error) {
+	params := &s3.DeletePublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	}
+	if _, err = s3client.Client.DeletePublicAccessBlock(params); err != nil {
+		return err
+	}
+	return
+}
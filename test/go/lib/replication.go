@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"bytes"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+)
+
+func (s3client *S3Client) PutBucketReplication(bucketName, prefix, destinationArn string) (err error) {
+	params := &s3.PutBucketReplicationInput{
+		Bucket: aws.String(bucketName),
+		ReplicationConfiguration: &s3.ReplicationConfiguration{
+			Role: aws.String(""),
+			Rules: []*s3.ReplicationRule{
+				{
+					ID:     aws.String("replicate-" + bucketName),
+					Status: aws.String(s3.ReplicationRuleStatusEnabled),
+					Prefix: aws.String(prefix),
+					Destination: &s3.Destination{
+						Bucket: aws.String(destinationArn),
+					},
+				},
+			},
+		},
+	}
+	if _, err = s3client.Client.PutBucketReplication(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetBucketReplication(bucketName string) (
+	config *s3.ReplicationConfiguration, err error) {
+
+	params := &s3.GetBucketReplicationInput{
+		Bucket: aws.String(bucketName),
+	}
+	out, err := s3client.Client.GetBucketReplication(params)
+	if err != nil {
+		return nil, err
+	}
+	return out.ReplicationConfiguration, nil
+}
+
+func (s3client *S3Client) DeleteBucketReplication(bucketName string) (err error) {
+	params := &s3.DeleteBucketReplicationInput{
+		Bucket: aws.String(bucketName),
+	}
+	if _, err = s3client.Client.DeleteBucketReplication(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetReplicationStatus(bucketName, key string) (status string, err error) {
+	params := &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}
+	out, err := s3client.Client.HeadObject(params)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ReplicationStatus), nil
+}
+
+func (s3client *S3Client) PutObjectWithMetadata(bucketName, key, value string,
+	metadata map[string]string) (err error) {
+
+	params := &s3.PutObjectInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader([]byte(value)),
+		Metadata: aws.StringMap(metadata),
+	}
+	if _, err = s3client.Client.PutObject(params); err != nil {
+		return err
+	}
+	return
+}
+
+func (s3client *S3Client) GetObjectMetadata(bucketName, key string) (
+	metadata map[string]string, err error) {
+
+	params := &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}
+	out, err := s3client.Client.HeadObject(params)
+	if err != nil {
+		return nil, err
+	}
+	return aws.StringValueMap(out.Metadata), nil
+}
@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/aws/credentials"
+	"github.com/journeymidnight/aws-sdk-go/aws/session"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+	"github.com/journeymidnight/aws-sdk-go/service/sts"
+)
+
+func newSession(creds *credentials.Credentials) *session.Session {
+	return session.Must(session.NewSession(
+		&aws.Config{
+			Credentials: creds,
+			DisableSSL:  aws.Bool(true),
+			Endpoint:    aws.String(Endpoint),
+			Region:      aws.String(Region),
+		},
+	))
+}
+
+// NewSts returns STS client of the permanent test credential
+func NewSts() *sts.STS {
+	return sts.New(newSession(credentials.NewStaticCredentials(AccessKey, SecretKey, "")))
+}
+
+// NewStsWithCredentials returns STS client of temporary credentials
+func NewStsWithCredentials(c *sts.Credentials) *sts.STS {
+	return sts.New(newSession(credentials.NewStaticCredentials(
+		*c.AccessKeyId, *c.SecretAccessKey, *c.SessionToken)))
+}
+
+// NewS3WithCredentials returns s3 client signing requests with temporary
+// credentials and their session token
+func NewS3WithCredentials(c *sts.Credentials) *S3Client {
+	return &S3Client{s3.New(newSession(credentials.NewStaticCredentials(
+		*c.AccessKeyId, *c.SecretAccessKey, *c.SessionToken)))}
+}
@@ -112,6 +112,271 @@ func Test_LifeCycle(t *testing.T) {
 
 }
 
+func Test_LifeCycleTransition(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutObject(TEST_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+
+	//Transition back to STANDARD is not allowed.
+	putInvalid := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(TEST_BUCKET),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					Transitions: []*s3.Transition{
+						{
+							Days:         aws.Int64(1),
+							StorageClass: aws.String("STANDARD"),
+						},
+					},
+					ID:     aws.String("test"),
+					Status: aws.String("Enabled"),
+				},
+			},
+		},
+	}
+	_, err = sc.Client.PutBucketLifecycleConfiguration(putInvalid)
+	if err == nil {
+		t.Fatal("PutBucketLifecycle with STANDARD transition should fail")
+	}
+
+	putTransition := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(TEST_BUCKET),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					Transitions: []*s3.Transition{
+						{
+							Days:         aws.Int64(1),
+							StorageClass: aws.String("GLACIER"),
+						},
+					},
+					ID:     aws.String("test"),
+					Status: aws.String("Enabled"),
+				},
+			},
+		},
+	}
+	_, err = sc.Client.PutBucketLifecycleConfiguration(putTransition)
+	if err != nil {
+		t.Fatal("PutBucketLifecycle err:", err)
+	}
+
+	err = os.Chdir("../../")
+	if err != nil {
+		t.Fatal("change dir in lc err:", err)
+	}
+	cmd := exec.Command("make", "runlc")
+	err = cmd.Run()
+	if err != nil {
+		t.Fatal("lc err:", err)
+	}
+	time.Sleep(time.Second * 3)
+	os.Chdir("../test/go")
+
+	head, err := sc.Client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(TEST_BUCKET),
+		Key:    aws.String(TEST_KEY),
+	})
+	if err != nil {
+		t.Fatal("HeadObject after lc err:", err)
+	}
+	if aws.StringValue(head.StorageClass) != "GLACIER" {
+		t.Fatal("StorageClass after lc is:", aws.StringValue(head.StorageClass), ", but should be: GLACIER")
+	}
+	t.Log("Transition Success!")
+
+	_, err = sc.Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(TEST_BUCKET),
+	})
+	if err != nil {
+		t.Fatal("DeleteBucketLifecycle err:", err)
+	}
+}
+
+func Test_LifeCycleNoncurrentVersion(t *testing.T) {
+	sc := NewS3()
+	_, err := sc.Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(TEST_BUCKET),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String("Enabled"),
+		},
+	})
+	if err != nil {
+		t.Fatal("PutBucketVersioning err:", err)
+	}
+	err = sc.PutObject(TEST_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	err = sc.PutObject(TEST_BUCKET, TEST_KEY, TEST_VALUE+TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+
+	//Expiration should have only one of Days, Date and ExpiredObjectDeleteMarker.
+	putInvalid := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(TEST_BUCKET),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					Expiration: &s3.LifecycleExpiration{
+						Days:                      aws.Int64(1),
+						ExpiredObjectDeleteMarker: aws.Bool(true),
+					},
+					Filter: &s3.LifecycleRuleFilter{
+						Prefix: aws.String(""),
+					},
+					ID:     aws.String("test"),
+					Status: aws.String("Enabled"),
+				},
+			},
+		},
+	}
+	_, err = sc.Client.PutBucketLifecycleConfiguration(putInvalid)
+	if err == nil {
+		t.Fatal("PutBucketLifecycle with invalid Expiration should fail")
+	}
+
+	putNoncurrent := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(TEST_BUCKET),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					NoncurrentVersionExpiration: &s3.NoncurrentVersionExpiration{
+						NoncurrentDays: aws.Int64(1),
+					},
+					Filter: &s3.LifecycleRuleFilter{
+						And: &s3.LifecycleRuleAndOperator{
+							Prefix: aws.String(TEST_KEY),
+						},
+					},
+					ID:     aws.String("noncurrent"),
+					Status: aws.String("Enabled"),
+				},
+			},
+		},
+	}
+	_, err = sc.Client.PutBucketLifecycleConfiguration(putNoncurrent)
+	if err != nil {
+		t.Fatal("PutBucketLifecycle err:", err)
+	}
+
+	err = os.Chdir("../../")
+	if err != nil {
+		t.Fatal("change dir in lc err:", err)
+	}
+	cmd := exec.Command("make", "runlc")
+	err = cmd.Run()
+	if err != nil {
+		t.Fatal("lc err:", err)
+	}
+	time.Sleep(time.Second * 3)
+	os.Chdir("../test/go")
+
+	versions, err := sc.Client.ListObjectVersions(&s3.ListObjectVersionsInput{
+		Bucket: aws.String(TEST_BUCKET),
+		Prefix: aws.String(TEST_KEY),
+	})
+	if err != nil {
+		t.Fatal("ListObjectVersions err:", err)
+	}
+	for _, version := range versions.Versions {
+		if !aws.BoolValue(version.IsLatest) {
+			t.Fatal("Noncurrent version should be expired:", aws.StringValue(version.VersionId))
+		}
+	}
+	v, err := sc.GetObject(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("GetObject after lc err:", err)
+	}
+	if v != TEST_VALUE+TEST_VALUE {
+		t.Fatal("GetObject after lc err: value is:", v, ", but should be:", TEST_VALUE+TEST_VALUE)
+	}
+
+	_, err = sc.Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(TEST_BUCKET),
+	})
+	if err != nil {
+		t.Fatal("DeleteBucketLifecycle err:", err)
+	}
+	_, err = sc.Client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket:    aws.String(TEST_BUCKET),
+		Key:       aws.String(TEST_KEY),
+		VersionId: versions.Versions[0].VersionId,
+	})
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+}
+
+func Test_LifeCycleAbortIncompleteMultipartUpload(t *testing.T) {
+	sc := NewS3()
+	uploadId, err := sc.CreateMultiPartUpload(TEST_BUCKET, TEST_KEY, s3.ObjectStorageClassStandard)
+	if err != nil {
+		t.Fatal("CreateMultiPartUpload err:", err)
+	}
+	_, err = sc.UploadPart(TEST_BUCKET, TEST_KEY, []byte(TEST_VALUE), uploadId, 1)
+	if err != nil {
+		t.Fatal("UploadPart err:", err)
+	}
+
+	putAbort := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(TEST_BUCKET),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					AbortIncompleteMultipartUpload: &s3.AbortIncompleteMultipartUpload{
+						DaysAfterInitiation: aws.Int64(1),
+					},
+					Filter: &s3.LifecycleRuleFilter{
+						Prefix: aws.String(""),
+					},
+					ID:     aws.String("abort"),
+					Status: aws.String("Enabled"),
+				},
+			},
+		},
+	}
+	_, err = sc.Client.PutBucketLifecycleConfiguration(putAbort)
+	if err != nil {
+		t.Fatal("PutBucketLifecycle err:", err)
+	}
+	time.Sleep(time.Second * 2)
+
+	err = os.Chdir("../../")
+	if err != nil {
+		t.Fatal("change dir in lc err:", err)
+	}
+	cmd := exec.Command("make", "runlc")
+	err = cmd.Run()
+	if err != nil {
+		t.Fatal("lc err:", err)
+	}
+	time.Sleep(time.Second * 3)
+	os.Chdir("../test/go")
+
+	uploads, err := sc.Client.ListMultipartUploads(&s3.ListMultipartUploadsInput{
+		Bucket: aws.String(TEST_BUCKET),
+	})
+	if err != nil {
+		t.Fatal("ListMultipartUploads err:", err)
+	}
+	if len(uploads.Uploads) != 0 {
+		sc.AbortMultiPartUpload(TEST_BUCKET, TEST_KEY, uploadId)
+		t.Fatal("Incomplete multipart upload should be aborted:", uploads.Uploads)
+	}
+
+	_, err = sc.Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(TEST_BUCKET),
+	})
+	if err != nil {
+		t.Fatal("DeleteBucketLifecycle err:", err)
+	}
+}
+
 func Test_LC_End(t *testing.T) {
 	sc := NewS3()
 	err := sc.DeleteObject(TEST_BUCKET, TEST_KEY)
@@ -0,0 +1,56 @@
+package _go
+
+import (
+	"testing"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+func Test_BucketNotification_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+}
+
+func Test_BucketNotification(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutBucketNotification(TEST_BUCKET, "created", "arn:yig:sqs:::test",
+		"photos/", "s3:ObjectCreated:*")
+	if err != nil {
+		t.Fatal("PutBucketNotification err:", err)
+	}
+	config, err := sc.GetBucketNotification(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("GetBucketNotification err:", err)
+	}
+	if len(config.QueueConfigurations) != 1 ||
+		aws.StringValue(config.QueueConfigurations[0].Id) != "created" {
+		t.Fatal("GetBucketNotification returns unexpected configuration:", config)
+	}
+	err = sc.PutObject(TEST_BUCKET, "photos/"+TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	err = sc.DeleteObject(TEST_BUCKET, "photos/"+TEST_KEY)
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+
+	err = sc.PutBucketNotification(TEST_BUCKET, "invalid", "arn:yig:sqs:::test",
+		"", "s3:ObjectAccessed:*")
+	if err == nil {
+		t.Fatal("PutBucketNotification should fail with unsupported event")
+	}
+}
+
+func Test_BucketNotification_End(t *testing.T) {
+	sc := NewS3()
+	err := sc.DeleteBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
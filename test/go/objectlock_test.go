@@ -0,0 +1,139 @@
+package _go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+const TEST_OBJECT_LOCK_BUCKET = "myobjectlockbucket"
+
+func Test_ObjectLock_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucketWithObjectLock(TEST_OBJECT_LOCK_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+}
+
+// buckets with object lock enabled are versioned, before any default
+// retention is configured their versions could be removed freely
+func Test_ObjectLock_DeleteVersion(t *testing.T) {
+	sc := NewS3()
+	oldVersion, err := sc.PutObjectWithVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	newVersion, err := sc.PutObjectWithVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, TEST_VALUE+"new")
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	if oldVersion == "" || oldVersion == "null" || oldVersion == newVersion {
+		t.Fatal("Unexpected versions:", oldVersion, newVersion)
+	}
+
+	// noncurrent version
+	err = sc.DeleteObjectVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, oldVersion, false)
+	if err != nil {
+		t.Fatal("DeleteObject noncurrent version err:", err)
+	}
+	_, err = sc.GetObjectWithVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, oldVersion)
+	if err == nil {
+		t.Fatal("Deleted version should not be got")
+	}
+	v, err := sc.GetObject(TEST_OBJECT_LOCK_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("GetObject err:", err)
+	}
+	if v != TEST_VALUE+"new" {
+		t.Fatal("GetObject err: value is:", v, ", but should be:", TEST_VALUE+"new")
+	}
+
+	// current version, which is also the last one
+	err = sc.DeleteObjectVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, newVersion, false)
+	if err != nil {
+		t.Fatal("DeleteObject current version err:", err)
+	}
+	_, err = sc.GetObject(TEST_OBJECT_LOCK_BUCKET, TEST_KEY)
+	if err == nil {
+		t.Fatal("GetObject should fail after all versions deleted")
+	}
+}
+
+func Test_ObjectLock(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutObjectLockConfiguration(TEST_OBJECT_LOCK_BUCKET, "GOVERNANCE", 1)
+	if err != nil {
+		t.Fatal("PutObjectLockConfiguration err:", err)
+	}
+	config, err := sc.GetObjectLockConfiguration(TEST_OBJECT_LOCK_BUCKET)
+	if err != nil {
+		t.Fatal("GetObjectLockConfiguration err:", err)
+	}
+	if aws.StringValue(config.Rule.DefaultRetention.Mode) != "GOVERNANCE" ||
+		aws.Int64Value(config.Rule.DefaultRetention.Days) != 1 {
+		t.Fatal("GetObjectLockConfiguration returns unexpected configuration:", config)
+	}
+
+	version, err := sc.PutObjectWithVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	retention, err := sc.GetObjectRetention(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version)
+	if err != nil {
+		t.Fatal("GetObjectRetention err:", err)
+	}
+	if aws.StringValue(retention.Mode) != "GOVERNANCE" {
+		t.Fatal("Default retention is not applied:", retention)
+	}
+	err = sc.DeleteObjectVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, false)
+	if err == nil {
+		t.Fatal("DeleteObject should fail with retention in GOVERNANCE mode")
+	}
+
+	err = sc.PutObjectRetention(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, "GOVERNANCE",
+		time.Now().Add(48*time.Hour), false)
+	if err != nil {
+		t.Fatal("PutObjectRetention err:", err)
+	}
+	err = sc.PutObjectRetention(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, "GOVERNANCE",
+		time.Now().Add(time.Hour), false)
+	if err == nil {
+		t.Fatal("PutObjectRetention should fail to shorten retention without bypass")
+	}
+
+	err = sc.PutObjectLegalHold(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, "ON")
+	if err != nil {
+		t.Fatal("PutObjectLegalHold err:", err)
+	}
+	status, err := sc.GetObjectLegalHold(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version)
+	if err != nil {
+		t.Fatal("GetObjectLegalHold err:", err)
+	}
+	if status != "ON" {
+		t.Fatal("Unexpected legal hold status:", status)
+	}
+	err = sc.DeleteObjectVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, true)
+	if err == nil {
+		t.Fatal("DeleteObject should fail with legal hold")
+	}
+	err = sc.PutObjectLegalHold(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, "OFF")
+	if err != nil {
+		t.Fatal("PutObjectLegalHold err:", err)
+	}
+	err = sc.DeleteObjectVersion(TEST_OBJECT_LOCK_BUCKET, TEST_KEY, version, true)
+	if err != nil {
+		t.Fatal("DeleteObject with bypass governance retention err:", err)
+	}
+}
+
+func Test_ObjectLock_End(t *testing.T) {
+	sc := NewS3()
+	err := sc.DeleteBucket(TEST_OBJECT_LOCK_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
@@ -0,0 +1,133 @@
+package _go
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+const TEST_PUBLIC_ACCESS_BLOCK_BUCKET = "mypublicaccessblockbucket"
+
+const publicAccessBlockPolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"AWS":["*"]},
+		"Action": ["s3:GetObject"],
+		"Resource": ["arn:aws:s3:::` + TEST_PUBLIC_ACCESS_BLOCK_BUCKET + `/*"]
+	}]
+}`
+
+func Test_PublicAccessBlock_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+	err = sc.PutObject(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+}
+
+func Test_PublicAccessBlock(t *testing.T) {
+	sc := NewS3()
+	_, err := sc.GetPublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err == nil || !strings.Contains(err.Error(), "NoSuchPublicAccessBlockConfiguration") {
+		t.Fatal("GetPublicAccessBlock should fail without configuration:", err)
+	}
+
+	err = sc.PutPublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, &s3.PublicAccessBlockConfiguration{
+		BlockPublicAcls:   aws.Bool(true),
+		BlockPublicPolicy: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatal("PutPublicAccessBlock err:", err)
+	}
+	config, err := sc.GetPublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("GetPublicAccessBlock err:", err)
+	}
+	if !aws.BoolValue(config.BlockPublicAcls) || !aws.BoolValue(config.BlockPublicPolicy) ||
+		aws.BoolValue(config.IgnorePublicAcls) || aws.BoolValue(config.RestrictPublicBuckets) {
+		t.Fatal("GetPublicAccessBlock returns unexpected configuration:", config)
+	}
+
+	err = sc.PutBucketAcl(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, "public-read")
+	if err == nil {
+		t.Fatal("PutBucketAcl should fail with BlockPublicAcls")
+	}
+	err = sc.PutObjectAcl(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, TEST_KEY, "public-read")
+	if err == nil {
+		t.Fatal("PutObjectAcl should fail with BlockPublicAcls")
+	}
+	err = sc.PutBucketPolicy(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, publicAccessBlockPolicy)
+	if err == nil {
+		t.Fatal("PutBucketPolicy should fail with BlockPublicPolicy")
+	}
+
+	err = sc.DeletePublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("DeletePublicAccessBlock err:", err)
+	}
+	err = sc.PutObjectAcl(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, TEST_KEY, "public-read")
+	if err != nil {
+		t.Fatal("PutObjectAcl err:", err)
+	}
+	err = sc.PutBucketPolicy(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, publicAccessBlockPolicy)
+	if err != nil {
+		t.Fatal("PutBucketPolicy err:", err)
+	}
+
+	// public ACL and policy no longer grant anonymous access
+	err = sc.PutPublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, &s3.PublicAccessBlockConfiguration{
+		IgnorePublicAcls:      aws.Bool(true),
+		RestrictPublicBuckets: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatal("PutPublicAccessBlock err:", err)
+	}
+	url := "http://" + *sc.Client.Config.Endpoint + string(os.PathSeparator) +
+		TEST_PUBLIC_ACCESS_BLOCK_BUCKET + string(os.PathSeparator) + TEST_KEY
+	statusCode, _, err := HTTPRequestToGetObject(url)
+	if err != nil {
+		t.Fatal("GetObject err:", err)
+	}
+	if statusCode != http.StatusForbidden {
+		t.Fatal("Anonymous GetObject should be denied, status:", statusCode)
+	}
+
+	err = sc.DeletePublicAccessBlock(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("DeletePublicAccessBlock err:", err)
+	}
+	statusCode, _, err = HTTPRequestToGetObject(url)
+	if err != nil {
+		t.Fatal("GetObject err:", err)
+	}
+	if statusCode != http.StatusOK {
+		t.Fatal("Anonymous GetObject should be allowed, status:", statusCode)
+	}
+}
+
+func Test_PublicAccessBlock_End(t *testing.T) {
+	sc := NewS3()
+	err := sc.DeleteBucketPolicy(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucketPolicy err:", err)
+	}
+	err = sc.DeleteObject(TEST_PUBLIC_ACCESS_BLOCK_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+	err = sc.DeleteBucket(TEST_PUBLIC_ACCESS_BLOCK_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
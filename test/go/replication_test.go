@@ -0,0 +1,149 @@
+package _go
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/aws/awserr"
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+const (
+	TEST_REPLICATION_BUCKET      = "myreplicationbucket"
+	TEST_REPLICATION_DESTINATION = "arn:aws:s3:cn-bj-1::" + TEST_REPLICATION_BUCKET
+)
+
+func Test_BucketReplication_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+	err = sc.MakeBucket(TEST_REPLICATION_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+}
+
+func Test_BucketReplication(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutBucketReplication(TEST_BUCKET, "", TEST_REPLICATION_DESTINATION)
+	if err != nil {
+		t.Fatal("PutBucketReplication err:", err)
+	}
+	config, err := sc.GetBucketReplication(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("GetBucketReplication err:", err)
+	}
+	if len(config.Rules) != 1 ||
+		aws.StringValue(config.Rules[0].Destination.Bucket) != TEST_REPLICATION_DESTINATION {
+		t.Fatal("GetBucketReplication returns unexpected configuration:", config)
+	}
+
+	err = sc.PutObjectWithMetadata(TEST_BUCKET, TEST_KEY, TEST_VALUE,
+		map[string]string{"Origin": TEST_BUCKET})
+	if err != nil {
+		t.Fatal("PutObject err:", err)
+	}
+	status, err := sc.GetReplicationStatus(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("HeadObject err:", err)
+	}
+	if status != "PENDING" && status != "COMPLETED" {
+		t.Fatal("Unexpected replication status:", status)
+	}
+
+	// target region cn-bj-1 of integrate/yigconf is this yig itself, so the
+	// replica is written to TEST_REPLICATION_BUCKET
+	err = os.Chdir("../../")
+	if err != nil {
+		t.Fatal("change dir in replicate err:", err)
+	}
+	cmd := exec.Command("make", "runreplicate")
+	err = cmd.Run()
+	os.Chdir("../test/go")
+	if err != nil {
+		t.Fatal("replicate err:", err)
+	}
+
+	var v string
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second * 3)
+		v, err = sc.GetObject(TEST_REPLICATION_BUCKET, TEST_KEY)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatal("GetObject of replica err:", err)
+	}
+	if v != TEST_VALUE {
+		t.Fatal("GetObject of replica err: value is:", v, ", but should be:", TEST_VALUE)
+	}
+	metadata, err := sc.GetObjectMetadata(TEST_REPLICATION_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("HeadObject of replica err:", err)
+	}
+	if metadata["Origin"] != TEST_BUCKET {
+		t.Fatal("Metadata of replica is not replicated:", metadata)
+	}
+	for i := 0; i < 10; i++ {
+		status, err = sc.GetReplicationStatus(TEST_BUCKET, TEST_KEY)
+		if err != nil || status == "COMPLETED" {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if status != "COMPLETED" {
+		t.Fatal("Unexpected replication status after replicated:", status, err)
+	}
+
+	err = sc.DeleteObject(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second * 3)
+		_, err = sc.GetObject(TEST_REPLICATION_BUCKET, TEST_KEY)
+		if err != nil {
+			break
+		}
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchKey" {
+		t.Fatal("Replica should be deleted, GetObject err:", err)
+	}
+
+	err = sc.DeleteBucketReplication(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucketReplication err:", err)
+	}
+	_, err = sc.GetBucketReplication(TEST_BUCKET)
+	if err == nil {
+		t.Fatal("GetBucketReplication should fail after deletion")
+	}
+
+	err = sc.PutBucketReplication(TEST_BUCKET, "", "arn:aws:s3:unknown-region::"+TEST_REPLICATION_BUCKET)
+	if err == nil {
+		t.Fatal("PutBucketReplication should fail with unknown destination region")
+	}
+}
+
+func Test_BucketReplication_End(t *testing.T) {
+	sc := NewS3()
+	// objects are left only if replication test failed
+	sc.DeleteObject(TEST_BUCKET, TEST_KEY)
+	sc.DeleteObject(TEST_REPLICATION_BUCKET, TEST_KEY)
+	err := sc.DeleteBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+	err = sc.DeleteBucket(TEST_REPLICATION_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
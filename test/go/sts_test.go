@@ -0,0 +1,92 @@
+package _go
+
+import (
+	"testing"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/aws/awserr"
+	"github.com/journeymidnight/aws-sdk-go/service/sts"
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+const TEST_STS_BUCKET = "mystsbucket"
+
+func Test_Sts_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_STS_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+	}
+}
+
+// requests signed with temporary credentials and their session token act as
+// the credential issuing them
+func Test_Sts_GetSessionToken(t *testing.T) {
+	out, err := NewSts().GetSessionToken(&sts.GetSessionTokenInput{
+		DurationSeconds: aws.Int64(900),
+	})
+	if err != nil {
+		t.Fatal("GetSessionToken err:", err)
+	}
+	sc := NewS3WithCredentials(out.Credentials)
+	err = sc.PutObject(TEST_STS_BUCKET, TEST_KEY, TEST_VALUE)
+	if err != nil {
+		t.Fatal("PutObject with temporary credentials err:", err)
+	}
+	value, err := NewS3().GetObject(TEST_STS_BUCKET, TEST_KEY)
+	if err != nil || value != TEST_VALUE {
+		t.Fatal("GetObject put with temporary credentials:", value, err)
+	}
+
+	// temporary credentials could not issue new ones
+	_, err = NewStsWithCredentials(out.Credentials).AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         aws.String("arn:aws:iam::hehehehe:role/reader"),
+		RoleSessionName: aws.String("session"),
+	})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "AccessDenied" {
+		t.Fatal("AssumeRole with temporary credentials:", err)
+	}
+
+	// session token is bound to its access key
+	forged := *out.Credentials
+	other, err := NewSts().GetSessionToken(&sts.GetSessionTokenInput{})
+	if err != nil {
+		t.Fatal("GetSessionToken err:", err)
+	}
+	forged.SessionToken = other.Credentials.SessionToken
+	_, err = NewS3WithCredentials(&forged).GetObject(TEST_STS_BUCKET, TEST_KEY)
+	if err == nil {
+		t.Fatal("GetObject with session token of another access key should fail")
+	}
+}
+
+// session policy of AssumeRole restricts temporary credentials
+func Test_Sts_AssumeRole(t *testing.T) {
+	out, err := NewSts().AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         aws.String("arn:aws:iam::hehehehe:role/reader"),
+		RoleSessionName: aws.String("session"),
+		Policy: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+			`"Action":"s3:GetObject","Resource":"arn:aws:s3:::` + TEST_STS_BUCKET + `/*"}]}`),
+	})
+	if err != nil {
+		t.Fatal("AssumeRole err:", err)
+	}
+	sc := NewS3WithCredentials(out.Credentials)
+	value, err := sc.GetObject(TEST_STS_BUCKET, TEST_KEY)
+	if err != nil || value != TEST_VALUE {
+		t.Fatal("GetObject allowed by session policy:", value, err)
+	}
+	err = sc.PutObject(TEST_STS_BUCKET, TEST_KEY, TEST_VALUE)
+	if err == nil {
+		t.Fatal("PutObject denied by session policy should fail")
+	}
+}
+
+func Test_Sts_End(t *testing.T) {
+	sc := NewS3()
+	sc.DeleteObject(TEST_STS_BUCKET, TEST_KEY)
+	err := sc.DeleteBucket(TEST_STS_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
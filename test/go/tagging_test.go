@@ -0,0 +1,107 @@
+package _go
+
+import (
+	"testing"
+
+	. "github.com/journeymidnight/yig/test/go/lib"
+)
+
+func Test_ObjectTagging_Prepare(t *testing.T) {
+	sc := NewS3()
+	err := sc.MakeBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("MakeBucket err:", err)
+		panic(err)
+	}
+	err = sc.PutObjectWithTagging(TEST_BUCKET, TEST_KEY, TEST_VALUE, "project=yig&env=test")
+	if err != nil {
+		t.Fatal("PutObjectWithTagging err:", err)
+		panic(err)
+	}
+	t.Log("PutObjectWithTagging Success.")
+}
+
+func Test_GetObjectTagging(t *testing.T) {
+	sc := NewS3()
+	tags, err := sc.GetObjectTagging(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("GetObjectTagging err:", err)
+	}
+	if len(tags) != 2 || tags["project"] != "yig" || tags["env"] != "test" {
+		t.Fatal("GetObjectTagging returns unexpected tags:", tags)
+	}
+}
+
+func Test_PutObjectTagging(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutObjectTagging(TEST_BUCKET, TEST_KEY, map[string]string{"owner": "hehehehe"})
+	if err != nil {
+		t.Fatal("PutObjectTagging err:", err)
+	}
+	tags, err := sc.GetObjectTagging(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("GetObjectTagging err:", err)
+	}
+	if len(tags) != 1 || tags["owner"] != "hehehehe" {
+		t.Fatal("GetObjectTagging returns unexpected tags:", tags)
+	}
+
+	tooMany := make(map[string]string)
+	for i := 0; i < 11; i++ {
+		tooMany[string(rune('a'+i))] = "v"
+	}
+	err = sc.PutObjectTagging(TEST_BUCKET, TEST_KEY, tooMany)
+	if err == nil {
+		t.Fatal("PutObjectTagging with 11 tags should fail")
+	}
+}
+
+func Test_DeleteObjectTagging(t *testing.T) {
+	sc := NewS3()
+	err := sc.DeleteObjectTagging(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("DeleteObjectTagging err:", err)
+	}
+	tags, err := sc.GetObjectTagging(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("GetObjectTagging err:", err)
+	}
+	if len(tags) != 0 {
+		t.Fatal("Tags should be empty after DeleteObjectTagging:", tags)
+	}
+}
+
+func Test_BucketTagging(t *testing.T) {
+	sc := NewS3()
+	err := sc.PutBucketTagging(TEST_BUCKET, map[string]string{"team": "storage"})
+	if err != nil {
+		t.Fatal("PutBucketTagging err:", err)
+	}
+	tags, err := sc.GetBucketTagging(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("GetBucketTagging err:", err)
+	}
+	if len(tags) != 1 || tags["team"] != "storage" {
+		t.Fatal("GetBucketTagging returns unexpected tags:", tags)
+	}
+	err = sc.DeleteBucketTagging(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucketTagging err:", err)
+	}
+	_, err = sc.GetBucketTagging(TEST_BUCKET)
+	if err == nil {
+		t.Fatal("GetBucketTagging should return NoSuchTagSet after DeleteBucketTagging")
+	}
+}
+
+func Test_ObjectTagging_End(t *testing.T) {
+	sc := NewS3()
+	err := sc.DeleteObject(TEST_BUCKET, TEST_KEY)
+	if err != nil {
+		t.Fatal("DeleteObject err:", err)
+	}
+	err = sc.DeleteBucket(TEST_BUCKET)
+	if err != nil {
+		t.Fatal("DeleteBucket err:", err)
+	}
+}
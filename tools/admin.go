@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 )
 
 var client = &http.Client{}
@@ -21,11 +22,20 @@ var config Config
 
 func printHelp() {
 	fmt.Println("Usage: admin <commands> [options...] ")
-	fmt.Println("Commands: usage|bucket|object|user|cachehit")
+	fmt.Println("Commands: usage|bucket|object|user|cachehit|multipart|cluster|drain|activate|weight|disable|flushcluster|placement")
 	fmt.Println("Options:")
 	fmt.Println(" -b, --bucket   Specify bucket to operate")
 	fmt.Println(" -u, --uid      Specify user name to operate")
 	fmt.Println(" -o, --object   Specify object to operate")
+	fmt.Println(" -d, --days     List multipart uploads initiated days ago")
+	fmt.Println(" -c, --cluster  Specify cluster fsid to operate")
+	fmt.Println(" -p, --pool     Specify pool of cluster to change weight or disable")
+	fmt.Println(" -w, --weight   Weight of pool, new data is written into pools in proportion to their weights")
+	fmt.Println(" placement options, pin new data of bucket specified by -b, clear placement if none is set:")
+	fmt.Println(" -c             Cluster fsids separated by comma")
+	fmt.Println(" -bigpool       Pool used instead of tiger")
+	fmt.Println(" -glacierpool   Pool used instead of turtle")
+	fmt.Println(" -threshold     Objects smaller than it are written into rabbit, -1 to write all into big pool")
 }
 
 func isParaEmpty(p string) bool {
@@ -212,6 +222,128 @@ func getCacheHit() {
 
 }
 
+func getStaleUploads(days int) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"days": days,
+	})
+
+	tokenString, err := token.SignedString([]byte(config.AdminKey))
+
+	if err == nil {
+		//go use token
+		fmt.Printf("\nHS256 = %v\n", tokenString)
+	} else {
+		fmt.Println("internal error", err)
+		return
+	}
+
+	url := config.RequestUrl + "/admin/multipart"
+	request, _ := http.NewRequest("GET", url, nil)
+	request.Header.Set("Authorization", "Bearer "+tokenString)
+	response, err := client.Do(request)
+	if err != nil {
+		fmt.Println("getStaleUploads failed error:", err.Error())
+		return
+	}
+	if response.StatusCode != 200 {
+		fmt.Println("getStaleUploads failed as status != 200", response.StatusCode)
+		return
+	}
+
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	fmt.Println(string(body))
+}
+
+// sendRequest signs `claims` with admin key, sends them to `path` of admin
+// server and prints the response, `name` is used in error messages
+func sendRequest(method, path string, claims jwt.MapClaims, name string) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(config.AdminKey))
+
+	if err == nil {
+		//go use token
+		fmt.Printf("\nHS256 = %v\n", tokenString)
+	} else {
+		fmt.Println("internal error", err)
+		return
+	}
+
+	url := config.RequestUrl + path
+	request, _ := http.NewRequest(method, url, nil)
+	request.Header.Set("Authorization", "Bearer "+tokenString)
+	response, err := client.Do(request)
+	if err != nil {
+		fmt.Println(name, "failed error:", err.Error())
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		fmt.Println(name, "failed as status != 200", response.StatusCode)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	fmt.Println(string(body))
+}
+
+func getClusters() {
+	sendRequest("GET", "/admin/cluster", jwt.MapClaims{}, "getClusters")
+}
+
+// setClusterStatus sets status of cluster `fsid` to "draining" or "active"
+func setClusterStatus(fsid string, status string) {
+	if isParaEmpty(fsid) {
+		return
+	}
+	sendRequest("PUT", "/admin/cluster/status", jwt.MapClaims{
+		"fsid":   fsid,
+		"status": status,
+	}, "setClusterStatus")
+}
+
+// setClusterWeight adds pool `pool` of cluster `fsid` if it's not in cluster
+// table, or changes its weight. Weight 0 disables writing into the pool
+func setClusterWeight(fsid, pool string, weight int) {
+	if isParaEmpty(fsid) || isParaEmpty(pool) {
+		return
+	}
+	if weight < 0 {
+		fmt.Println("Bad usage, weight should not be negative")
+		return
+	}
+	sendRequest("PUT", "/admin/cluster", jwt.MapClaims{
+		"fsid":   fsid,
+		"pool":   pool,
+		"weight": weight,
+	}, "setClusterWeight")
+}
+
+func removeClustersCache() {
+	sendRequest("DELETE", "/admin/cluster/cache", jwt.MapClaims{}, "removeClustersCache")
+}
+
+// setBucketPlacement pins new data of bucket to clusters `fsids` separated by
+// comma, and to pools if not empty. Placement is cleared if all are empty
+func setBucketPlacement(bucket, fsids, bigPool, glacierPool string, threshold int64) {
+	if isParaEmpty(bucket) {
+		return
+	}
+	placement := map[string]interface{}{
+		"BigFilePool":      bigPool,
+		"GlacierFilePool":  glacierPool,
+		"BigFileThreshold": threshold,
+	}
+	if fsids != "" {
+		placement["Clusters"] = strings.Split(fsids, ",")
+	}
+	sendRequest("PUT", "/admin/bucket/placement", jwt.MapClaims{
+		"bucket":    bucket,
+		"placement": placement,
+	}, "setBucketPlacement")
+}
+
 func main() {
 	f, err := os.Open("./admin.json")
 	if err != nil {
@@ -231,6 +363,13 @@ func main() {
 	bucket := mySet.String("b", "", "bucket name")
 	uid := mySet.String("u", "", "user name")
 	object := mySet.String("o", "", "object name")
+	days := mySet.Int("d", 0, "days after initiation")
+	fsid := mySet.String("c", "", "cluster fsid")
+	pool := mySet.String("p", "", "pool name")
+	weight := mySet.Int("w", -1, "pool weight")
+	bigPool := mySet.String("bigpool", "", "pool used instead of tiger")
+	glacierPool := mySet.String("glacierpool", "", "pool used instead of turtle")
+	threshold := mySet.Int64("threshold", 0, "big file threshold of bucket")
 	mySet.Parse(os.Args[2:])
 	fmt.Println("command:", os.Args[1], "bucket:", *bucket, "user:", *uid, "object:", *object)
 	switch os.Args[1] {
@@ -244,6 +383,22 @@ func main() {
 		getObjectInfo(*bucket, *object)
 	case "cachehit":
 		getCacheHit()
+	case "multipart":
+		getStaleUploads(*days)
+	case "cluster":
+		getClusters()
+	case "drain":
+		setClusterStatus(*fsid, "draining")
+	case "activate":
+		setClusterStatus(*fsid, "active")
+	case "weight":
+		setClusterWeight(*fsid, *pool, *weight)
+	case "disable":
+		setClusterWeight(*fsid, *pool, 0)
+	case "flushcluster":
+		removeClustersCache()
+	case "placement":
+		setBucketPlacement(*bucket, *fsid, *bigPool, *glacierPool, *threshold)
 	default:
 		printHelp()
 		return
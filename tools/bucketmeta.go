@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta/archive"
+	"github.com/journeymidnight/yig/mods"
+	"github.com/journeymidnight/yig/storage"
+)
+
+const DEFAULT_BUCKETMETA_LOG_PATH = "/var/log/yig/bucketmeta.log"
+
+func printHelp() {
+	fmt.Println("Usage: bucketmeta <commands> [options...] ")
+	fmt.Println("Commands: export|import")
+	fmt.Println("Options:")
+	fmt.Println(" -b           Specify bucket to export")
+	fmt.Println(" -f           Specify archive file, stdout/stdin if empty")
+	fmt.Println(" -format      Archive format when exporting, json or msgpack")
+	fmt.Println(" -skip-check  Don't check referenced objects exist in backend clusters")
+	fmt.Println(" -dry-run     Check the archive without importing it")
+}
+
+func printStats(stats archive.Stats) {
+	fmt.Fprintln(os.Stderr, "objects:", stats.Objects, "object maps:", stats.ObjectMaps,
+		"multipart uploads:", stats.Multiparts, "skipped:", stats.Skipped,
+		"gc rows removed:", stats.Garbages)
+	for _, m := range stats.Missing {
+		fmt.Fprintln(os.Stderr, "missing data:", m.String())
+	}
+}
+
+func main() {
+	if len(os.Args) <= 1 {
+		printHelp()
+		return
+	}
+	mySet := flag.NewFlagSet("", flag.ExitOnError)
+	bucket := mySet.String("b", "", "bucket name")
+	file := mySet.String("f", "", "archive file")
+	format := mySet.String("format", archive.FormatJSON, "archive format, json or msgpack")
+	skipCheck := mySet.Bool("skip-check", false, "don't check referenced objects exist in backend clusters")
+	dryRun := mySet.Bool("dry-run", false, "check the archive without importing it")
+	mySet.Parse(os.Args[2:])
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+	helper.Logger = log.NewFileLogger(DEFAULT_BUCKETMETA_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+	yig := storage.New(0, false, kms)
+	var checker *archive.DataChecker
+	if !*skipCheck {
+		checker = &archive.DataChecker{Clusters: yig.DataStorage}
+	}
+
+	var stats archive.Stats
+	var err error
+	switch os.Args[1] {
+	case "export":
+		if *bucket == "" {
+			printHelp()
+			os.Exit(2)
+		}
+		out := os.Stdout
+		if *file != "" {
+			out, err = os.Create(*file)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to create archive:", err)
+				os.Exit(1)
+			}
+		}
+		exporter := archive.Exporter{Client: yig.MetaStorage.Client, Checker: checker}
+		stats, err = exporter.Export(*bucket, out, *format)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	case "import":
+		in := os.Stdin
+		if *file != "" {
+			in, err = os.Open(*file)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to open archive:", err)
+				os.Exit(1)
+			}
+		}
+		importer := archive.Importer{Client: yig.MetaStorage.Client, Checker: checker, DryRun: *dryRun}
+		stats, err = importer.Import(in)
+		in.Close()
+	default:
+		printHelp()
+		os.Exit(2)
+	}
+	printStats(stats)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, os.Args[1], "failed:", err)
+		os.Exit(1)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"github.com/journeymidnight/yig/backend"
 	"github.com/journeymidnight/yig/crypto"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/log"
@@ -136,6 +137,7 @@ func main() {
 
 	// Read all *.so from plugins directory, and fill the variable allPlugins
 	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
 	kms := crypto.NewKMS(allPluginMap)
 
 	numOfWorkers := helper.CONFIG.GcThread
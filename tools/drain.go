@@ -0,0 +1,275 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/mods"
+	"github.com/journeymidnight/yig/redis"
+	"github.com/journeymidnight/yig/storage"
+)
+
+const (
+	SCAN_OBJECT_LIMIT      = 1000
+	DEFAULT_DRAIN_LOG_PATH = "/var/log/yig/drain.log"
+)
+
+var (
+	yig  *storage.YigStorage
+	stop = make(chan struct{})
+)
+
+func stopping() bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// mover moves objects out of draining clusters in one pass over all buckets
+type mover struct {
+	draining map[string]bool // fsid -> true
+	tasks    chan *types.Object
+	limiter  <-chan time.Time // nil for unlimited
+	wg       sync.WaitGroup
+
+	lock      sync.Mutex
+	moved     int
+	remaining map[string]int // fsid -> objects, restored copies and uploads left in cluster
+}
+
+func (m *mover) addRemaining(fsid string) {
+	m.lock.Lock()
+	m.remaining[fsid] += 1
+	m.lock.Unlock()
+}
+
+func (m *mover) work() {
+	defer m.wg.Done()
+	for object := range m.tasks {
+		if m.limiter != nil {
+			<-m.limiter
+		}
+		moved, err := yig.MoveObject(object)
+		if err != nil {
+			helper.Logger.Error("Move object", object.BucketName, object.Name,
+				object.GetVersionId(), "from", object.Location, "failed:", err)
+		}
+		if err != nil || !moved {
+			// changed while moving, moved in next pass
+			m.addRemaining(object.Location)
+			continue
+		}
+		m.lock.Lock()
+		m.moved += 1
+		m.lock.Unlock()
+	}
+}
+
+func (m *mover) scanObjects(bucketName string) error {
+	var marker string
+	for !stopping() {
+		names, err := yig.MetaStorage.Client.ScanObjectNames(bucketName, marker, SCAN_OBJECT_LIMIT)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			objects, err := yig.MetaStorage.Client.GetAllObject(bucketName, name, "")
+			if err != nil {
+				return err
+			}
+			var glacier bool
+			for _, o := range objects {
+				if o.StorageClass == types.ObjectStorageClassGlacier {
+					glacier = true
+				}
+				if !m.draining[o.Location] || o.DeleteMarker ||
+					(o.ObjectId == "" && len(o.Parts) == 0) {
+					continue
+				}
+				m.tasks <- o
+			}
+			if glacier {
+				err = m.checkFreezer(bucketName, name)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if len(names) < SCAN_OBJECT_LIMIT {
+			return nil
+		}
+		marker = names[len(names)-1]
+	}
+	return nil
+}
+
+// restored copies of glacier objects are not moved, they stay in draining
+// cluster until expired and removed by restore
+func (m *mover) checkFreezer(bucketName, name string) error {
+	freezer, err := yig.MetaStorage.Client.GetFreezer(bucketName, name, "")
+	if err == ErrNoSuchKey {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !m.draining[freezer.Location] {
+		return nil
+	}
+	helper.Logger.Info("Restored copy of", bucketName, name,
+		"is in draining cluster", freezer.Location)
+	m.addRemaining(freezer.Location)
+	return nil
+}
+
+// uploads in progress can't be moved, their parts are written into the
+// draining cluster until they're completed or aborted
+func (m *mover) scanUploads(bucketName string) error {
+	var keyMarker, uploadIdMarker string
+	for !stopping() {
+		uploads, _, truncated, nextKeyMarker, nextUploadIdMarker, err :=
+			yig.MetaStorage.Client.ListMultipartUploads(bucketName, keyMarker, uploadIdMarker,
+				"", "", "", SCAN_OBJECT_LIMIT)
+		if err != nil {
+			return err
+		}
+		for _, upload := range uploads {
+			multipart, err := yig.MetaStorage.Client.GetMultipart(bucketName, upload.Key, upload.UploadId)
+			if err != nil {
+				return err
+			}
+			if !m.draining[multipart.Metadata.Location] {
+				continue
+			}
+			helper.Logger.Warn("Upload", bucketName, upload.Key, upload.UploadId,
+				"is in progress in draining cluster", multipart.Metadata.Location)
+			m.addRemaining(multipart.Metadata.Location)
+		}
+		if !truncated {
+			return nil
+		}
+		keyMarker, uploadIdMarker = nextKeyMarker, nextUploadIdMarker
+	}
+	return nil
+}
+
+func (m *mover) run(workers int) error {
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	defer m.wg.Wait()
+	defer close(m.tasks)
+
+	buckets, err := yig.MetaStorage.Client.GetBuckets()
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		err = m.scanObjects(bucket.Name)
+		if err != nil {
+			return err
+		}
+		err = m.scanUploads(bucket.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func drainingClusters() (map[string]bool, error) {
+	// read from meta store directly, cached clusters may be stale
+	clusters, err := yig.MetaStorage.Client.GetClusters()
+	if err != nil {
+		return nil, err
+	}
+	draining := make(map[string]bool)
+	for _, c := range clusters {
+		if c.Status == types.ClusterStatusDraining {
+			draining[c.Fsid] = true
+		}
+	}
+	return draining, nil
+}
+
+func main() {
+	interval := flag.Duration("interval", time.Minute, "time to wait between passes over all buckets")
+	workers := flag.Int("workers", 4, "objects moved concurrently")
+	objectsRate := flag.Int("objects-rate", 50, "objects moved per second, 0 for unlimited")
+	flag.Parse()
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+	helper.Logger = log.NewFileLogger(DEFAULT_DRAIN_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+	if helper.CONFIG.MetaCacheType > 0 || helper.CONFIG.EnableDataCache {
+		redis.Initialize()
+		defer redis.Close()
+	}
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+	yig = storage.New(helper.CONFIG.MetaCacheType, helper.CONFIG.EnableDataCache, kms)
+
+	signalQueue := make(chan os.Signal, 1)
+	signal.Notify(signalQueue, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	go func() {
+		<-signalQueue
+		helper.Logger.Info("shutting down...")
+		close(stop)
+	}()
+
+	drained := make(map[string]bool) // fsid -> true, reported once
+	for !stopping() {
+		draining, err := drainingClusters()
+		if err != nil {
+			helper.Logger.Error("Get clusters failed:", err)
+		} else if len(draining) != 0 {
+			m := &mover{
+				draining:  draining,
+				tasks:     make(chan *types.Object, SCAN_OBJECT_LIMIT),
+				remaining: make(map[string]int),
+			}
+			if *objectsRate > 0 {
+				ticker := time.NewTicker(time.Second / time.Duration(*objectsRate))
+				m.limiter = ticker.C
+				err = m.run(*workers)
+				ticker.Stop()
+			} else {
+				err = m.run(*workers)
+			}
+			helper.Logger.Info("Drain pass done, moved:", m.moved, "remaining:", m.remaining)
+			if err != nil {
+				helper.Logger.Error("Drain pass failed:", err)
+			} else if !stopping() {
+				for fsid := range draining {
+					if m.remaining[fsid] == 0 && !drained[fsid] {
+						drained[fsid] = true
+						helper.Logger.Info("Cluster", fsid, "is drained, "+
+							"it could be removed from config once gc has finished")
+					}
+				}
+			}
+		}
+		select {
+		case <-stop:
+		case <-time.After(*interval):
+		}
+	}
+	yig.Stop()
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/backend"
 	"github.com/journeymidnight/yig/crypto"
+	. "github.com/journeymidnight/yig/error"
 	"github.com/journeymidnight/yig/helper"
 	"github.com/journeymidnight/yig/iam/common"
 	"github.com/journeymidnight/yig/log"
@@ -12,8 +14,7 @@ import (
 	"github.com/journeymidnight/yig/storage"
 	"os"
 	"os/signal"
-	"strconv"
-	"strings"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -21,6 +22,7 @@ import (
 
 const (
 	SCAN_LIMIT          = 50
+	SCAN_OBJECT_LIMIT   = 1000
 	DEFAULT_LC_LOG_PATH = "/var/log/yig/lc.log"
 )
 
@@ -72,127 +74,255 @@ func checkIfExpiration(updateTime time.Time, days int) bool {
 	}
 }
 
-// If a rule has an empty prefix, the days in it will be consider as a default days for all objects that not specified in
-// other rules. For this reason, we have two conditions to check if a object has expired and should be deleted
-//  if defaultConfig == true
-//                    for each object           check if object name has a prifix
-//  list all objects --------------->loop rules---------------------------------->
-//                                                                      |     NO
-//                                                                      |--------> days = default days ---
-//                                                                      |     YES                         |->delete object if expired
-//                                                                      |--------> days = specify days ---
-//
-//  if defaultConfig == false
-//                 for each rule get objects by prefix
-//  iterator rules ----------------------------------> loop objects-------->delete object if expired
+// dueTransition returns the transition with the latest due time among
+// `transitions` which have taken effect on object version created or became
+// noncurrent at `since`, or nil if none of them is due
+func dueTransition(since time.Time, transitions []datatype.LifecycleTransition) *datatype.LifecycleTransition {
+	var due *datatype.LifecycleTransition
+	var dueTime time.Time
+	for i, transition := range transitions {
+		var transitionTime time.Time
+		if transition.Date != "" {
+			date, err := transition.ParseDate()
+			if err != nil || time.Now().Before(date) {
+				continue
+			}
+			transitionTime = date
+		} else {
+			if !checkIfExpiration(since, transition.Days) {
+				continue
+			}
+			transitionTime = since.AddDate(0, 0, transition.Days)
+		}
+		if due == nil || transitionTime.After(dueTime) {
+			due = &transitions[i]
+			dueTime = transitionTime
+		}
+	}
+	return due
+}
+
+func isExpired(since time.Time, expiration *datatype.LifecycleExpiration) bool {
+	if expiration == nil {
+		return false
+	}
+	if expiration.Days > 0 {
+		return checkIfExpiration(since, expiration.Days)
+	}
+	if expiration.Date != "" {
+		date, err := expiration.ParseDate()
+		return err == nil && !time.Now().Before(date)
+	}
+	return false
+}
+
+func deleteObject(object *types.Object, version string) {
+	_, err := yig.DeleteObject(object.BucketName, object.Name, version, false, common.Credential{})
+	if err == ErrObjectLocked {
+		helper.Logger.Info("Skip locked object:", object.BucketName, object.Name, version)
+		return
+	}
+	if err != nil {
+		helper.Logger.Error(object.BucketName, object.Name, version, "failed:", err)
+		return
+	}
+	helper.Logger.Info("Deleted:", object.BucketName, object.Name, version)
+}
+
+func transitionObject(object *types.Object, version string, since time.Time,
+	transitions []datatype.LifecycleTransition) {
+
+	transition := dueTransition(since, transitions)
+	if transition == nil {
+		return
+	}
+	storageClass, err := types.MatchStorageClassIndex(transition.StorageClass)
+	if err != nil {
+		helper.Logger.Error("Invalid transition storage class:", transition.StorageClass)
+		return
+	}
+	if object.StorageClass == storageClass {
+		return
+	}
+	err = yig.TransitionObject(object.BucketName, object.Name, version, storageClass)
+	if err != nil {
+		helper.Logger.Error(object.BucketName, object.Name, version,
+			"transition to", transition.StorageClass, "failed:", err)
+		return
+	}
+	helper.Logger.Info("Transitioned:", object.BucketName, object.Name, version,
+		"to", transition.StorageClass)
+}
+
+// processCurrentVersion expires the current version of object, or transitions
+// it to another storage class.
+// A delete marker is removed if it is the only version left and
+// ExpiredObjectDeleteMarker is set.
+func processCurrentVersion(object *types.Object, onlyVersion bool, rules []datatype.LifecycleRule) {
+	if object.DeleteMarker {
+		if !onlyVersion {
+			return
+		}
+		for _, rule := range rules {
+			if rule.Expiration != nil && rule.Expiration.ExpiredObjectDeleteMarker {
+				deleteObject(object, object.GetVersionId())
+				return
+			}
+		}
+		return
+	}
+	var transitions []datatype.LifecycleTransition
+	for _, rule := range rules {
+		if isExpired(object.LastModifiedTime, rule.Expiration) {
+			// a delete marker is added instead in versioned bucket
+			deleteObject(object, "")
+			return
+		}
+		transitions = append(transitions, rule.Transitions...)
+	}
+	transitionObject(object, "", object.LastModifiedTime, transitions)
+}
+
+// processNoncurrentVersion removes a noncurrent object version or transitions
+// it to another storage class, `noncurrentSince` is the time when a newer
+// version was created
+func processNoncurrentVersion(object *types.Object, noncurrentSince time.Time,
+	rules []datatype.LifecycleRule) {
+
+	var transitions []datatype.LifecycleTransition
+	for _, rule := range rules {
+		expiration := rule.NoncurrentVersionExpiration
+		if expiration != nil && checkIfExpiration(noncurrentSince, expiration.NoncurrentDays) {
+			deleteObject(object, object.GetVersionId())
+			return
+		}
+		for _, transition := range rule.NoncurrentVersionTransitions {
+			transitions = append(transitions, datatype.LifecycleTransition{
+				Days:         transition.NoncurrentDays,
+				StorageClass: transition.StorageClass,
+			})
+		}
+	}
+	if object.DeleteMarker {
+		return
+	}
+	transitionObject(object, object.GetVersionId(), noncurrentSince, transitions)
+}
+
+// processObject applies rules to all versions of object `objectName`
+func processObject(bucketName, objectName string, rules []datatype.LifecycleRule) {
+	versions, err := yig.MetaStorage.GetAllObject(bucketName, objectName)
+	if err == ErrNoSuchKey {
+		return
+	}
+	if err != nil {
+		helper.Logger.Error("Get versions of", bucketName, objectName, "failed:", err)
+		return
+	}
+	// newest version first
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].LastModifiedTime.After(versions[j].LastModifiedTime)
+	})
+	for i, object := range versions {
+		var matched []datatype.LifecycleRule
+		for _, rule := range rules {
+			if rule.Match(object.Name, object.Size, object.Tagging) {
+				matched = append(matched, rule)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		if i == 0 {
+			processCurrentVersion(object, len(versions) == 1, matched)
+		} else {
+			processNoncurrentVersion(object, versions[i-1].LastModifiedTime, matched)
+		}
+	}
+}
+
+// retrieveBucket scans all object names of bucket, and checks every version of
+// each object against enabled lifecycle rules whose Filter selects it.
+// When several rules apply, expiration takes precedence over transition.
 func retrieveBucket(lc types.LifeCycle) error {
-	defaultConfig := false
-	defaultDays := 0
 	bucket, err := yig.MetaStorage.GetBucket(lc.BucketName, false)
 	if err != nil {
 		return err
 	}
-	rules := bucket.Lifecycle.Rule
+	var rules []datatype.LifecycleRule
+	for _, rule := range bucket.Lifecycle.Rule {
+		if rule.IsEnabled() {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	var marker string
+	for {
+		names, err := yig.MetaStorage.ScanObjectNames(bucket.Name, marker, SCAN_OBJECT_LIMIT)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			processObject(bucket.Name, name, rules)
+		}
+		if len(names) < SCAN_OBJECT_LIMIT {
+			break
+		}
+		marker = names[len(names)-1]
+	}
+	return abortIncompleteUploads(bucket, rules)
+}
+
+// abortIncompleteUploads aborts multipart uploads of bucket initiated
+// DaysAfterInitiation days ago, parts uploaded are moved into gc
+func abortIncompleteUploads(bucket *types.Bucket, rules []datatype.LifecycleRule) error {
+	var abortRules []datatype.LifecycleRule
 	for _, rule := range rules {
-		if rule.Prefix == "" {
-			defaultConfig = true
-			defaultDays, err = strconv.Atoi(rule.Expiration)
-			if err != nil {
-				return err
-			}
+		if rule.AbortIncompleteMultipartUpload != nil {
+			abortRules = append(abortRules, rule)
 		}
 	}
-	var request datatype.ListObjectsRequest
-	request.Versioned = false
-	request.MaxKeys = 1000
-	if defaultConfig == true {
-		for {
-			retObjects, _, truncated, nextMarker, nextVerIdMarker, err := yig.ListObjectsInternal(bucket.Name, request)
+	if len(abortRules) == 0 {
+		return nil
+	}
+	credential := common.Credential{UserId: bucket.OwnerId}
+	var keyMarker, uploadIdMarker string
+	for {
+		uploads, _, truncated, nextKeyMarker, nextUploadIdMarker, err :=
+			yig.MetaStorage.Client.ListMultipartUploads(bucket.Name, keyMarker, uploadIdMarker,
+				"", "", "", SCAN_OBJECT_LIMIT)
+		if err != nil {
+			return err
+		}
+		for _, upload := range uploads {
+			initiated, err := time.Parse(types.CREATE_TIME_LAYOUT, upload.Initiated)
 			if err != nil {
-				return err
+				helper.Logger.Error("Invalid initiated time of upload", bucket.Name, upload.Key,
+					upload.UploadId, "err:", err)
+				continue
 			}
-
-			for _, object := range retObjects {
-				prefixMatch := false
-				matchDays := 0
-				for _, rule := range rules {
-					if rule.Prefix == "" {
-						continue
-					}
-					if strings.HasPrefix(object.Name, rule.Prefix) == false {
-						continue
-					}
-					prefixMatch = true
-					matchDays, err = strconv.Atoi(rule.Expiration)
-					if err != nil {
-						return err
-					}
+			for _, rule := range abortRules {
+				if !rule.Match(upload.Key, 0, nil) ||
+					!checkIfExpiration(initiated, rule.AbortIncompleteMultipartUpload.DaysAfterInitiation) {
+					continue
 				}
-				days := 0
-				if prefixMatch == true {
-					days = matchDays
+				err = yig.AbortMultipartUpload(credential, bucket.Name, upload.Key, upload.UploadId)
+				if err != nil {
+					helper.Logger.Error("Abort upload", bucket.Name, upload.Key, upload.UploadId,
+						"failed:", err)
 				} else {
-					days = defaultDays
-				}
-				helper.Logger.Info("inteval:", time.Since(object.LastModifiedTime).Seconds())
-				if checkIfExpiration(object.LastModifiedTime, days) {
-					helper.Logger.Info("come here")
-					if object.NullVersion {
-						object.VersionId = ""
-					}
-					_, err = yig.DeleteObject(object.BucketName, object.Name, object.VersionId, common.Credential{})
-					if err != nil {
-						helper.Logger.Error(object.BucketName, object.Name, object.VersionId, err)
-						continue
-					}
-					helper.Logger.Info("Deleted:", object.BucketName, object.Name, object.VersionId)
+					helper.Logger.Info("Aborted upload:", bucket.Name, upload.Key, upload.UploadId)
 				}
-			}
-			if truncated == true {
-				request.KeyMarker = nextMarker
-				request.VersionIdMarker = nextVerIdMarker
-			} else {
 				break
 			}
 		}
-	} else {
-		for _, rule := range rules {
-			if rule.Prefix == "" {
-				continue
-			}
-			days, _ := strconv.Atoi(rule.Expiration)
-			if err != nil {
-				return err
-			}
-			request.Prefix = rule.Prefix
-			for {
-
-				retObjects, _, truncated, nextMarker, nextVerIdMarker, err := yig.ListObjectsInternal(bucket.Name, request)
-				if err != nil {
-					return err
-				}
-				for _, object := range retObjects {
-					if checkIfExpiration(object.LastModifiedTime, days) {
-						_, err = yig.DeleteObject(object.BucketName, object.Name, object.VersionId, common.Credential{})
-						if err != nil {
-							helper.Logger.Error(object.BucketName, object.Name, object.VersionId, "failed:", err)
-							continue
-						}
-						helper.Logger.Info("Deleted:", object.BucketName, object.Name, object.VersionId)
-					}
-				}
-				if truncated == true {
-					request.KeyMarker = nextMarker
-					request.VersionIdMarker = nextVerIdMarker
-				} else {
-					break
-				}
-
-			}
+		if !truncated {
+			return nil
 		}
-
+		keyMarker, uploadIdMarker = nextKeyMarker, nextUploadIdMarker
 	}
-	return nil
 }
 
 func processLifecycle() {
@@ -239,6 +369,7 @@ func main() {
 
 	// Read all *.so from plugins directory, and fill the variable allPlugins
 	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
 	kms := crypto.NewKMS(allPluginMap)
 
 	yig = storage.New(helper.CONFIG.MetaCacheType, helper.CONFIG.EnableDataCache, kms)
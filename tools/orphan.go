@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/mods"
+	"github.com/journeymidnight/yig/scrub"
+	"github.com/journeymidnight/yig/storage"
+)
+
+const DEFAULT_ORPHAN_LOG_PATH = "/var/log/yig/orphan.log"
+
+func main() {
+	pools := flag.String("pools", strings.Join([]string{backend.SMALL_FILE_POOLNAME,
+		backend.BIG_FILE_POOLNAME, backend.GLACIER_FILE_POOLNAME}, ","), "pools to check, separated by comma")
+	minAge := flag.Duration("min-age", 24*time.Hour, "objects modified more recently are never orphans")
+	reclaim := flag.Bool("reclaim", false, "put orphans into gc table, so they are removed by gc")
+	flag.Parse()
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+	helper.Logger = log.NewFileLogger(DEFAULT_ORPHAN_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+	yig := storage.New(0, false, kms)
+
+	finder := scrub.OrphanFinder{
+		Client:   yig.MetaStorage.Client,
+		Clusters: yig.DataStorage,
+		Pools:    strings.Split(*pools, ","),
+		MinAge:   *minAge,
+	}
+	var count, size uint64
+	encoder := json.NewEncoder(os.Stdout)
+	err := finder.Find(func(o scrub.Orphan) error {
+		count += 1
+		size += o.Size
+		err := encoder.Encode(o)
+		if err != nil || !*reclaim {
+			return err
+		}
+		helper.Logger.Info("Reclaim orphan", o.Location, o.Pool, o.ObjectId)
+		return finder.Reclaim(o)
+	})
+	fmt.Fprintln(os.Stderr, "orphans:", count, "bytes:", size)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "find orphans failed:", err)
+		os.Exit(1)
+	}
+}
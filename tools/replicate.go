@@ -0,0 +1,351 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/journeymidnight/aws-sdk-go/aws"
+	"github.com/journeymidnight/aws-sdk-go/aws/credentials"
+	"github.com/journeymidnight/aws-sdk-go/aws/session"
+	"github.com/journeymidnight/aws-sdk-go/service/s3"
+	"github.com/journeymidnight/aws-sdk-go/service/s3/s3manager"
+	"github.com/journeymidnight/yig/api/datatype"
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/mods"
+	"github.com/journeymidnight/yig/redis"
+	"github.com/journeymidnight/yig/storage"
+)
+
+const (
+	SCAN_LIMIT                 = 50
+	MAX_TRIED_TIMES            = 10
+	RETRY_INTERVAL             = 30 * time.Second
+	SCAN_INTERVAL              = 10 * time.Second
+	DEFAULT_REPLICATE_LOG_PATH = "/var/log/yig/replicate.log"
+)
+
+var (
+	yig         *storage.YigStorage
+	taskQ       chan types.Replication
+	signalQueue chan os.Signal
+	waitgroup   sync.WaitGroup
+	passGroup   sync.WaitGroup
+	stop        bool
+
+	clientsLock sync.Mutex
+	clients     = make(map[string]*s3.S3)
+
+	// errors that could never be fixed by retrying
+	errNotReplicable = errors.New("object could not be replicated")
+)
+
+// getClient returns a V4 signing S3 client of target region
+func getClient(region string) (*s3.S3, error) {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+	if client, ok := clients[region]; ok {
+		return client, nil
+	}
+	target, ok := helper.CONFIG.ReplicationTargets[region]
+	if !ok {
+		return nil, errors.New("replication target is not configured: " + region)
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials(target.AccessKey, target.SecretKey, ""),
+		Endpoint:         aws.String(target.Endpoint),
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := s3.New(sess)
+	clients[region] = client
+	return client, nil
+}
+
+func uploadInputFromObject(object *types.Object, entry types.Replication,
+	body io.Reader) *s3manager.UploadInput {
+
+	input := &s3manager.UploadInput{
+		Bucket:   aws.String(entry.TargetBucket),
+		Key:      aws.String(object.Name),
+		Body:     body,
+		Metadata: make(map[string]*string),
+	}
+	if object.ContentType != "" {
+		input.ContentType = aws.String(object.ContentType)
+	}
+	for k, v := range object.CustomAttributes {
+		switch {
+		case strings.HasPrefix(strings.ToLower(k), "x-amz-meta-"):
+			input.Metadata[k[len("x-amz-meta-"):]] = aws.String(v)
+		case k == "Cache-Control":
+			input.CacheControl = aws.String(v)
+		case k == "Content-Disposition":
+			input.ContentDisposition = aws.String(v)
+		case k == "Content-Encoding":
+			input.ContentEncoding = aws.String(v)
+		}
+	}
+	if len(object.Tagging) != 0 {
+		tags := url.Values{}
+		for k, v := range object.Tagging {
+			tags.Set(k, v)
+		}
+		input.Tagging = aws.String(tags.Encode())
+	}
+	if entry.StorageClass != "" {
+		input.StorageClass = aws.String(entry.StorageClass)
+	} else if object.StorageClass != types.ObjectStorageClassStandard {
+		input.StorageClass = aws.String(object.StorageClass.ToString())
+	}
+	return input
+}
+
+func replicatePut(client *s3.S3, entry types.Replication) (*types.Object, error) {
+	object, err := yig.MetaStorage.Client.GetObject(entry.BucketName, entry.ObjectName,
+		strconv.FormatUint(entry.Version, 10))
+	if err == ErrNoSuchKey {
+		// object has been overwritten or removed, which would be replicated
+		// by another entry
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if object.DeleteMarker {
+		return nil, nil
+	}
+	return object, uploadObject(client, entry, object)
+}
+
+func uploadObject(client *s3.S3, entry types.Replication, object *types.Object) error {
+	if object.SseType == crypto.SSEC.String() {
+		// we don't have the customer key to decrypt it
+		return errNotReplicable
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		err := yig.GetObject(object, 0, object.Size, writer, datatype.SseRequest{})
+		writer.CloseWithError(err)
+	}()
+	defer reader.Close()
+
+	uploader := s3manager.NewUploaderWithClient(client)
+	_, err := uploader.Upload(uploadInputFromObject(object, entry, reader))
+	return err
+}
+
+// replicateDelete makes replica follow the current version of source object,
+// which is an older version if the current one was removed by its version id
+func replicateDelete(client *s3.S3, entry types.Replication) (*types.Object, error) {
+	object, err := yig.MetaStorage.Client.GetObject(entry.BucketName, entry.ObjectName, "")
+	if err == nil && !object.DeleteMarker {
+		return object, uploadObject(client, entry, object)
+	}
+	if err != nil && err != ErrNoSuchKey {
+		return nil, err
+	}
+	_, err = client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(entry.TargetBucket),
+		Key:    aws.String(entry.ObjectName),
+	})
+	return nil, err
+}
+
+func replicate(entry types.Replication) {
+	var object *types.Object
+	client, err := getClient(entry.TargetRegion)
+	if err == nil {
+		switch entry.Operation {
+		case types.ReplicationOperationPut:
+			object, err = replicatePut(client, entry)
+		case types.ReplicationOperationDelete:
+			object, err = replicateDelete(client, entry)
+		default:
+			err = errNotReplicable
+		}
+	}
+
+	if err == nil {
+		helper.Logger.Info("Replicated:", entry.BucketName, entry.ObjectName, entry.Version,
+			"to", entry.TargetRegion, entry.TargetBucket)
+		if object != nil {
+			err = yig.UpdateObjectReplicationStatus(object, datatype.ReplicationStatusCompleted)
+			if err != nil {
+				helper.Logger.Error("Update replication status failed:", entry.BucketName,
+					entry.ObjectName, err)
+				return
+			}
+		}
+		err = yig.MetaStorage.RemoveReplication(&entry)
+		if err != nil {
+			helper.Logger.Error("Remove replication entry failed:", entry.BucketName,
+				entry.ObjectName, err)
+		}
+		return
+	}
+
+	helper.Logger.Error("Replicate failed:", entry.BucketName, entry.ObjectName, entry.Version,
+		"to", entry.TargetRegion, entry.TargetBucket, "err:", err)
+	entry.TriedTimes += 1
+	entry.MTime = time.Now().UTC()
+	if err == errNotReplicable || entry.TriedTimes >= MAX_TRIED_TIMES {
+		entry.Status = datatype.ReplicationStatusFailed
+		if object != nil {
+			err = yig.UpdateObjectReplicationStatus(object, datatype.ReplicationStatusFailed)
+			if err != nil {
+				helper.Logger.Error("Update replication status failed:", entry.BucketName,
+					entry.ObjectName, err)
+			}
+		}
+	}
+	err = yig.MetaStorage.UpdateReplication(&entry)
+	if err != nil {
+		helper.Logger.Error("Update replication entry failed:", entry.BucketName,
+			entry.ObjectName, err)
+	}
+}
+
+func processReplication() {
+	for {
+		entry, ok := <-taskQ
+		if !ok {
+			return
+		}
+		replicate(entry)
+		passGroup.Done()
+	}
+}
+
+// scanReplication walks through all PENDING entries of replication queue in
+// passes, a new pass starts only after all entries of the last pass are done.
+// Entries are sorted by key with the newest first, only the newest entry of
+// a key is handled in a pass and the older ones are dropped since replica
+// would be overwritten by it anyway, so entries of one key are never handled
+// by two workers at the same time, nor could an older entry win over a newer one.
+func scanReplication() {
+	waitgroup.Add(1)
+	defer waitgroup.Done()
+	defer close(taskQ)
+	for {
+		var startRowKey, lastKey string
+		for {
+			if stop {
+				helper.Logger.Info("Shutting down...")
+				passGroup.Wait()
+				return
+			}
+			entries, err := yig.MetaStorage.ScanReplication(SCAN_LIMIT, startRowKey,
+				datatype.ReplicationStatusPending)
+			if err != nil {
+				helper.Logger.Error("ScanReplication failed:", err)
+				break
+			}
+			for i, entry := range entries {
+				// the last one is the start of next page
+				if len(entries) == SCAN_LIMIT && i == len(entries)-1 {
+					startRowKey = entry.Rowkey
+					break
+				}
+				key := replicationKey(entry)
+				if key == lastKey {
+					supersede(entry)
+					continue
+				}
+				lastKey = key
+				// back off failed entries
+				if entry.TriedTimes > 0 &&
+					time.Since(entry.MTime) < time.Duration(entry.TriedTimes)*RETRY_INTERVAL {
+					continue
+				}
+				passGroup.Add(1)
+				taskQ <- entry
+			}
+			if len(entries) < SCAN_LIMIT {
+				break
+			}
+		}
+		passGroup.Wait()
+		time.Sleep(SCAN_INTERVAL)
+	}
+}
+
+func replicationKey(entry types.Replication) string {
+	return entry.BucketName + types.ObjectNameSeparator + entry.ObjectName +
+		types.ObjectNameSeparator + entry.TargetRegion + types.ObjectNameSeparator + entry.TargetBucket
+}
+
+// supersede removes an entry which has a newer one of the same key
+func supersede(entry types.Replication) {
+	helper.Logger.Info("Superseded:", entry.BucketName, entry.ObjectName, entry.Version)
+	err := yig.MetaStorage.RemoveReplication(&entry)
+	if err != nil {
+		helper.Logger.Error("Remove replication entry failed:", entry.BucketName,
+			entry.ObjectName, err)
+	}
+}
+
+func main() {
+	stop = false
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+
+	helper.Logger = log.NewFileLogger(DEFAULT_REPLICATE_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+	if helper.CONFIG.MetaCacheType > 0 || helper.CONFIG.EnableDataCache {
+		redis.Initialize()
+		defer redis.Close()
+	}
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+
+	yig = storage.New(helper.CONFIG.MetaCacheType, helper.CONFIG.EnableDataCache, kms)
+	taskQ = make(chan types.Replication, SCAN_LIMIT)
+	signal.Ignore()
+	signalQueue = make(chan os.Signal)
+
+	numOfWorkers := helper.CONFIG.ReplicationThread
+	helper.Logger.Info("start replication thread:", numOfWorkers)
+	for i := 0; i < numOfWorkers; i++ {
+		go processReplication()
+	}
+	go scanReplication()
+	signal.Notify(signalQueue, syscall.SIGINT, syscall.SIGTERM,
+		syscall.SIGQUIT, syscall.SIGHUP)
+	for {
+		s := <-signalQueue
+		switch s {
+		case syscall.SIGHUP:
+			// reload config file, and rebuild clients of targets
+			helper.SetupConfig()
+			clientsLock.Lock()
+			clients = make(map[string]*s3.S3)
+			clientsLock.Unlock()
+		default:
+			// stop, order matters
+			stop = true
+			waitgroup.Wait()
+			return
+		}
+	}
+}
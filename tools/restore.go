@@ -0,0 +1,226 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	. "github.com/journeymidnight/yig/error"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/meta/types"
+	"github.com/journeymidnight/yig/mods"
+	bus "github.com/journeymidnight/yig/mq"
+	"github.com/journeymidnight/yig/redis"
+	"github.com/journeymidnight/yig/storage"
+)
+
+const (
+	SCAN_LIMIT               = 50
+	SCAN_INTERVAL            = 10 * time.Second
+	DEFAULT_RESTORE_LOG_PATH = "/var/log/yig/restore.log"
+)
+
+var (
+	yig         *storage.YigStorage
+	taskQ       chan types.Freezer
+	resultQ     chan bool
+	signalQueue chan os.Signal
+	waitgroup   sync.WaitGroup
+	stop        bool
+)
+
+func restore(freezer types.Freezer) bool {
+	err := yig.RestoreObject(&freezer)
+	if err == nil {
+		helper.Logger.Info("Restored:", freezer.BucketName, freezer.Name)
+		return true
+	}
+	if err == ErrNoSuchKey || err == ErrInvalidObjectState {
+		// object has been removed or is not in GLACIER any more
+		helper.Logger.Info("Drop restore request:", freezer.BucketName, freezer.Name, err)
+		err = yig.MetaStorage.DeleteFreezer(&freezer)
+		if err != nil {
+			helper.Logger.Error("Delete freezer failed:", freezer.BucketName, freezer.Name, err)
+			return false
+		}
+		return true
+	}
+	helper.Logger.Error("Restore failed:", freezer.BucketName, freezer.Name, "err:", err)
+	// give it back, so it would be retried in next pass
+	_, err = yig.MetaStorage.UpdateFreezerStatus(&freezer, types.ObjectNeedRestore)
+	if err != nil {
+		helper.Logger.Error("Update freezer status failed:", freezer.BucketName, freezer.Name, err)
+	}
+	return false
+}
+
+func processRestore() {
+	for {
+		freezer, ok := <-taskQ
+		if !ok {
+			return
+		}
+		resultQ <- restore(freezer)
+	}
+}
+
+// resetRestoring gives freezers left in RESTORING status by last run back
+// to READY status
+func resetRestoring() {
+	for {
+		freezers, err := yig.MetaStorage.ListFreezers(types.ObjectRestoring, SCAN_LIMIT)
+		if err != nil {
+			helper.Logger.Error("ListFreezers failed:", err)
+			return
+		}
+		for _, freezer := range freezers {
+			_, err = yig.MetaStorage.UpdateFreezerStatus(&freezer, types.ObjectNeedRestore)
+			if err != nil {
+				helper.Logger.Error("Update freezer status failed:", freezer.BucketName,
+					freezer.Name, err)
+				return
+			}
+		}
+		if len(freezers) < SCAN_LIMIT {
+			return
+		}
+	}
+}
+
+// restorePending restores READY freezers batch by batch, in the order of
+// tier. Each freezer is claimed by changing its status to RESTORING before
+// being handed to workers. Returns when all freezers are handled or a batch
+// makes no progress at all.
+func restorePending() {
+	for !stop {
+		freezers, err := yig.MetaStorage.ListFreezers(types.ObjectNeedRestore, SCAN_LIMIT)
+		if err != nil {
+			helper.Logger.Error("ListFreezers failed:", err)
+			return
+		}
+		var claimed int
+		for _, freezer := range freezers {
+			ok, err := yig.MetaStorage.UpdateFreezerStatus(&freezer, types.ObjectRestoring)
+			if err != nil {
+				helper.Logger.Error("Update freezer status failed:", freezer.BucketName,
+					freezer.Name, err)
+				continue
+			}
+			if !ok {
+				// changed by others, e.g. the object is deleted
+				continue
+			}
+			freezer.Status = types.ObjectRestoring
+			taskQ <- freezer
+			claimed++
+		}
+		var progressed bool
+		for i := 0; i < claimed; i++ {
+			if <-resultQ {
+				progressed = true
+			}
+		}
+		if len(freezers) < SCAN_LIMIT || !progressed {
+			return
+		}
+	}
+}
+
+// expireRestored removes restored copies whose lifetime has passed
+func expireRestored() {
+	for !stop {
+		freezers, err := yig.MetaStorage.ListExpiredFreezers(time.Now().UTC(), SCAN_LIMIT)
+		if err != nil {
+			helper.Logger.Error("ListExpiredFreezers failed:", err)
+			return
+		}
+		for _, freezer := range freezers {
+			err = yig.ExpireRestoredObject(freezer.BucketName, freezer.Name)
+			if err != nil {
+				helper.Logger.Error("Expire restored object failed:", freezer.BucketName,
+					freezer.Name, err)
+				return
+			}
+			helper.Logger.Info("Restored copy expired:", freezer.BucketName, freezer.Name)
+		}
+		if len(freezers) < SCAN_LIMIT {
+			return
+		}
+	}
+}
+
+func scanFreezers() {
+	waitgroup.Add(1)
+	defer waitgroup.Done()
+	defer close(taskQ)
+	resetRestoring()
+	for !stop {
+		restorePending()
+		expireRestored()
+		time.Sleep(SCAN_INTERVAL)
+	}
+	helper.Logger.Info("Shutting down...")
+}
+
+func main() {
+	stop = false
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+
+	helper.Logger = log.NewFileLogger(DEFAULT_RESTORE_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+	if helper.CONFIG.MetaCacheType > 0 || helper.CONFIG.EnableDataCache {
+		redis.Initialize()
+		defer redis.Close()
+	}
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+	yig = storage.New(helper.CONFIG.MetaCacheType, helper.CONFIG.EnableDataCache, kms)
+	// ObjectRestore:Completed events are sent only if message queue plugin
+	// is configured
+	for _, p := range allPluginMap {
+		if p.PluginType == mods.MQ_PLUGIN {
+			_, err := bus.InitMessageSender(allPluginMap)
+			if err != nil {
+				helper.Logger.Error("Failed to create message queue sender, err:", err)
+			}
+			break
+		}
+	}
+
+	taskQ = make(chan types.Freezer, SCAN_LIMIT)
+	resultQ = make(chan bool, SCAN_LIMIT)
+	signal.Ignore()
+	signalQueue = make(chan os.Signal)
+
+	numOfWorkers := helper.CONFIG.RestoreThread
+	helper.Logger.Info("start restore thread:", numOfWorkers)
+	for i := 0; i < numOfWorkers; i++ {
+		go processRestore()
+	}
+	go scanFreezers()
+	signal.Notify(signalQueue, syscall.SIGINT, syscall.SIGTERM,
+		syscall.SIGQUIT, syscall.SIGHUP)
+	for {
+		s := <-signalQueue
+		switch s {
+		case syscall.SIGHUP:
+			// reload config file
+			helper.SetupConfig()
+		default:
+			// stop, order matters
+			stop = true
+			waitgroup.Wait()
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/journeymidnight/yig/backend"
+	"github.com/journeymidnight/yig/crypto"
+	"github.com/journeymidnight/yig/helper"
+	"github.com/journeymidnight/yig/log"
+	"github.com/journeymidnight/yig/mods"
+	"github.com/journeymidnight/yig/scrub"
+	"github.com/journeymidnight/yig/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const DEFAULT_SCRUB_LOG_PATH = "/var/log/yig/scrub.log"
+
+func main() {
+	bucket := flag.String("b", "", "bucket to scrub, all buckets if empty")
+	reportPath := flag.String("report", "", "file to append problems found, stdout if empty")
+	objectsRate := flag.Int64("objects-rate", 100, "objects checked per second, 0 for unlimited")
+	bytesRate := flag.Int64("bytes-rate", 50<<20, "bytes read per second, 0 for unlimited")
+	metricsAddress := flag.String("metrics", "", "address to export prometheus metrics, e.g. 0.0.0.0:9100")
+	interval := flag.Duration("interval", 0, "run scrubber repeatedly with this interval, run once if 0")
+	flag.Parse()
+
+	helper.SetupConfig()
+	logLevel := log.ParseLevel(helper.CONFIG.LogLevel)
+	helper.Logger = log.NewFileLogger(DEFAULT_SCRUB_LOG_PATH, logLevel)
+	defer helper.Logger.Close()
+
+	// Read all *.so from plugins directory, and fill the variable allPlugins
+	allPluginMap := mods.InitialPlugins()
+	backend.LoadPlugins(allPluginMap)
+	kms := crypto.NewKMS(allPluginMap)
+	yig := storage.New(0, false, kms)
+
+	report := os.Stdout
+	if *reportPath != "" {
+		var err error
+		report, err = os.OpenFile(*reportPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open report:", err)
+			os.Exit(1)
+		}
+		defer report.Close()
+	}
+	scrubber := scrub.Scrubber{
+		Client:           yig.MetaStorage.Client,
+		Clusters:         yig.DataStorage,
+		ObjectsPerSecond: *objectsRate,
+		BytesPerSecond:   *bytesRate,
+		Report:           report,
+		Metrics:          scrub.NewMetrics("yig"),
+	}
+	if *metricsAddress != "" {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(scrubber.Metrics)
+		http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		go func() {
+			err := http.ListenAndServe(*metricsAddress, nil)
+			helper.Logger.Error("Metrics server stopped:", err)
+		}()
+	}
+
+	for {
+		helper.Logger.Info("Scrub start, bucket:", *bucket)
+		var stats scrub.Stats
+		var err error
+		if *bucket == "" {
+			stats, err = scrubber.ScrubAll()
+		} else {
+			stats, err = scrubber.ScrubBucket(*bucket)
+		}
+		helper.Logger.Info("Scrub done, objects:", stats.Objects, "bytes:", stats.Bytes,
+			"missing:", stats.Missing, "corrupt:", stats.Corrupt)
+		if err != nil {
+			helper.Logger.Error("Scrub failed:", err)
+		}
+		if *interval == 0 {
+			fmt.Fprintln(os.Stderr, "objects:", stats.Objects, "bytes:", stats.Bytes,
+				"missing:", stats.Missing, "corrupt:", stats.Corrupt)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "scrub failed:", err)
+				os.Exit(1)
+			}
+			if stats.Missing != 0 || stats.Corrupt != 0 {
+				os.Exit(2)
+			}
+			return
+		}
+		time.Sleep(*interval)
+	}
+}